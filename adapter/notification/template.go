@@ -0,0 +1,169 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/protobuf/shared/golang/errdetailspb"
+	"github.com/syntaxfa/quick-connect/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TemplateAdapter acts as a client adapter for the notification admin HTTP API, errors are returned
+// as gRPC status errors like the other service adapters.
+type TemplateAdapter struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewTemplateAdapter(baseURL string, timeout time.Duration) *TemplateAdapter {
+	return &TemplateAdapter{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (ta *TemplateAdapter) TemplateList(ctx context.Context, req service.ListTemplateRequest) (service.ListTemplateResponse, error) {
+	var resp service.ListTemplateResponse
+	err := ta.do(ctx, http.MethodPost, "/v1/templates/list", req, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) GetTemplate(ctx context.Context, templateID types.ID) (service.Template, error) {
+	var resp service.Template
+	err := ta.do(ctx, http.MethodGet, fmt.Sprintf("/v1/templates/%s", templateID), nil, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) ListTemplateVersions(ctx context.Context, templateID types.ID) (service.ListTemplateVersionResponse, error) {
+	var resp service.ListTemplateVersionResponse
+	err := ta.do(ctx, http.MethodGet, fmt.Sprintf("/v1/templates/%s/versions", templateID), nil, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) RollbackTemplate(ctx context.Context, templateID types.ID, version int) (service.Template, error) {
+	var resp service.Template
+	err := ta.do(ctx, http.MethodPost, fmt.Sprintf("/v1/templates/%s/versions/%d/rollback", templateID, version), nil, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) PreviewTemplate(ctx context.Context, templateID types.ID,
+	req service.PreviewTemplateRequest) (service.PreviewTemplateResponse, error) {
+	var resp service.PreviewTemplateResponse
+	err := ta.do(ctx, http.MethodPost, fmt.Sprintf("/v1/templates/%s/preview", templateID), req, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) SendTestNotification(ctx context.Context, templateID types.ID,
+	req service.SendTestNotificationRequest) (service.Notification, error) {
+	var resp service.Notification
+	err := ta.do(ctx, http.MethodPost, fmt.Sprintf("/v1/templates/%s/test", templateID), req, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) GetUserPreferences(ctx context.Context, externalUserID string) (service.UserPreferences, error) {
+	var resp service.UserPreferences
+	err := ta.do(ctx, http.MethodGet, fmt.Sprintf("/v1/settings/%s/preferences", url.PathEscape(externalUserID)), nil, &resp)
+
+	return resp, err
+}
+
+func (ta *TemplateAdapter) do(ctx context.Context, method, path string, body, dest any) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, mErr := json.Marshal(body)
+		if mErr != nil {
+			return status.Error(codes.Internal, servermsg.MsgSomethingWentWrong)
+		}
+
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	httpReq, nErr := http.NewRequestWithContext(ctx, method, ta.baseURL+path, reqBody)
+	if nErr != nil {
+		return status.Error(codes.Internal, servermsg.MsgSomethingWentWrong)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, dErr := ta.client.Do(httpReq)
+	if dErr != nil {
+		return status.Error(codes.Unavailable, dErr.Error())
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		return convertHTTPErrorToStatus(httpResp)
+	}
+
+	if decErr := json.NewDecoder(httpResp.Body).Decode(dest); decErr != nil {
+		return status.Error(codes.Internal, servermsg.MsgSomethingWentWrong)
+	}
+
+	return nil
+}
+
+// convertHTTPErrorToStatus converts a servermsg.ErrorResponse into a gRPC status, field errors are added as
+// BadRequest details the same as servermsg.GRPCMsg.
+func convertHTTPErrorToStatus(resp *http.Response) error {
+	code := httpStatusCodeToGRPCCode(resp.StatusCode)
+
+	var errResp servermsg.ErrorResponse
+	if dErr := json.NewDecoder(resp.Body).Decode(&errResp); dErr != nil || errResp.Message == "" {
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+
+	st := status.New(code, errResp.Message)
+	if len(errResp.Errors) == 0 {
+		return st.Err()
+	}
+
+	badRequestDetails := &errdetailspb.BadRequest{}
+	for field, desc := range errResp.Errors {
+		badRequestDetails.FieldViolations = append(badRequestDetails.FieldViolations, &errdetailspb.FieldViolation{
+			Field:       field,
+			Description: desc,
+		})
+	}
+
+	stWithDetails, detailErr := st.WithDetails(badRequestDetails)
+	if detailErr != nil {
+		return st.Err()
+	}
+
+	return stWithDetails.Err()
+}
+
+func httpStatusCodeToGRPCCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/pkg/translation"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+type TemplateLocalAdapter struct {
+	notificationSvc *service.Service
+	t               *translation.Translate
+	logger          *slog.Logger
+}
+
+func NewTemplateLocalAdapter(notificationSvc *service.Service, t *translation.Translate, logger *slog.Logger) *TemplateLocalAdapter {
+	return &TemplateLocalAdapter{
+		notificationSvc: notificationSvc,
+		t:               t,
+		logger:          logger,
+	}
+}
+
+func (tla *TemplateLocalAdapter) TemplateList(ctx context.Context, req service.ListTemplateRequest) (service.ListTemplateResponse, error) {
+	resp, sErr := tla.notificationSvc.TemplateList(ctx, req)
+	if sErr != nil {
+		return service.ListTemplateResponse{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) GetTemplate(ctx context.Context, templateID types.ID) (service.Template, error) {
+	resp, sErr := tla.notificationSvc.GetTemplate(ctx, templateID)
+	if sErr != nil {
+		return service.Template{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) ListTemplateVersions(ctx context.Context,
+	templateID types.ID) (service.ListTemplateVersionResponse, error) {
+	resp, sErr := tla.notificationSvc.ListTemplateVersions(ctx, templateID)
+	if sErr != nil {
+		return service.ListTemplateVersionResponse{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) RollbackTemplate(ctx context.Context, templateID types.ID, version int) (service.Template, error) {
+	resp, sErr := tla.notificationSvc.RollbackTemplate(ctx, templateID, version)
+	if sErr != nil {
+		return service.Template{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) PreviewTemplate(ctx context.Context, templateID types.ID,
+	req service.PreviewTemplateRequest) (service.PreviewTemplateResponse, error) {
+	resp, sErr := tla.notificationSvc.PreviewTemplate(ctx, templateID, req)
+	if sErr != nil {
+		return service.PreviewTemplateResponse{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) SendTestNotification(ctx context.Context, templateID types.ID,
+	req service.SendTestNotificationRequest) (service.Notification, error) {
+	resp, sErr := tla.notificationSvc.SendTestNotification(ctx, templateID, req)
+	if sErr != nil {
+		return service.Notification{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
+
+func (tla *TemplateLocalAdapter) GetUserPreferences(ctx context.Context, externalUserID string) (service.UserPreferences, error) {
+	resp, sErr := tla.notificationSvc.GetUserPreferences(ctx, externalUserID)
+	if sErr != nil {
+		return service.UserPreferences{}, servermsg.GRPCMsg(sErr, tla.t, tla.logger)
+	}
+
+	return resp, nil
+}
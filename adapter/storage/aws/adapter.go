@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
@@ -10,10 +11,27 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 )
 
-var _ service.Storage = (*Adapter)(nil)
+var (
+	_ service.Storage      = (*Adapter)(nil)
+	_ service.RangeStorage = (*Adapter)(nil)
+)
+
+// the codes of the S3 errors that are not modeled by all of the operations, e.g. ListParts returns NoSuchUpload
+// as a generic error.
+const (
+	errCodeNoSuchKey    = "NoSuchKey"
+	errCodeNoSuchUpload = "NoSuchUpload"
+)
+
+func hasErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
 
 type Adapter struct {
 	cfg           Config
@@ -110,7 +128,8 @@ func (a *Adapter) GetURL(ctx context.Context, key string) (string, error) {
 	return req.URL, nil
 }
 
-func (a *Adapter) GetPresignedURL(ctx context.Context, key string) (string, error) {
+// GetPresignedURL S3 urls can't be bound to a user, so opts.UserID is ignored.
+func (a *Adapter) GetPresignedURL(ctx context.Context, key string, _ service.PresignOptions) (string, error) {
 	req, pErr := a.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(a.bucketName),
 		Key:    aws.String(key),
@@ -135,3 +154,34 @@ func (a *Adapter) Exists(ctx context.Context, key string) (bool, error) {
 
 	return true, nil
 }
+
+func (a *Adapter) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, gErr := a.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucketName),
+		Key:    aws.String(key),
+	})
+	if gErr != nil {
+		return nil, fmt.Errorf("s3 get object failed: %w", gErr)
+	}
+
+	return out.Body, nil
+}
+
+// OpenRange reads the object from offset to its end.
+func (a *Adapter) OpenRange(ctx context.Context, key string, offset int64) (io.ReadCloser, error) {
+	out, gErr := a.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-", offset)),
+	})
+	if gErr != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(gErr, &noSuchKey) {
+			return nil, fmt.Errorf("%w: %s", service.ErrObjectNotFound, key)
+		}
+
+		return nil, fmt.Errorf("s3 get object range failed: %w", gErr)
+	}
+
+	return out.Body, nil
+}
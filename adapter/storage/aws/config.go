@@ -23,4 +23,7 @@ type Config struct {
 	// Example values: "168h" (7 days), "24h" (1 day).
 	PresignPublicExpire  time.Duration `koanf:"presign_public_expire"`
 	PresignPrivateExpire time.Duration `koanf:"presign_private_expire"`
+	// MultipartPartSize is the size of the parts of the resumable uploads in bytes, the chunks of an upload are
+	// buffered in memory until they fill a part. It is at least 5MB, the minimum part size of S3.
+	MultipartPartSize int `koanf:"multipart_part_size"`
 }
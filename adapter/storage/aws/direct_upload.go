@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+)
+
+var _ service.DirectUploadStorage = (*Adapter)(nil)
+
+// PresignUpload presigns a PUT request of the key, the content length, the content type, the acl and the checksum
+// are signed headers, so S3 rejects an upload that doesn't send them with the same values.
+func (a *Adapter) PresignUpload(ctx context.Context, key string, opts service.PresignUploadOptions) (service.PresignedUpload, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(a.bucketName),
+		Key:           aws.String(key),
+		ContentLength: aws.Int64(opts.Size),
+		ContentType:   aws.String(opts.ContentType),
+	}
+
+	if opts.IsPublic {
+		input.ACL = types.ObjectCannedACLPublicRead
+	} else {
+		input.ACL = types.ObjectCannedACLPrivate
+	}
+
+	if opts.SHA256 != "" {
+		checksum, dErr := hex.DecodeString(opts.SHA256)
+		if dErr != nil {
+			return service.PresignedUpload{}, fmt.Errorf("decode sha256 checksum failed: %w", dErr)
+		}
+
+		input.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(checksum))
+	}
+
+	req, pErr := a.presignClient.PresignPutObject(ctx, input, func(o *s3.PresignOptions) {
+		o.Expires = opts.Expire
+	})
+	if pErr != nil {
+		return service.PresignedUpload{}, fmt.Errorf("presign upload failed: %w", pErr)
+	}
+
+	// the host header is set by the http client of the uploader.
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name, values := range req.SignedHeader {
+		if name == "Host" || len(values) == 0 {
+			continue
+		}
+
+		headers[name] = values[0]
+	}
+
+	return service.PresignedUpload{
+		Method:    http.MethodPut,
+		URL:       req.URL,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(opts.Expire),
+	}, nil
+}
+
+// Copy copies the object in S3 and calculates the sha256 checksum of the copy, so Stat of the copy returns the
+// checksum of its content. An object is copied by a single request, so it can't be larger than 5GB.
+func (a *Adapter) Copy(ctx context.Context, srcKey, dstKey string, isPublic bool) error {
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(a.bucketName),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String((&url.URL{Path: a.bucketName + "/" + srcKey}).EscapedPath()),
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+	}
+
+	if isPublic {
+		input.ACL = types.ObjectCannedACLPublicRead
+	} else {
+		input.ACL = types.ObjectCannedACLPrivate
+	}
+
+	if _, cErr := a.client.CopyObject(ctx, input); cErr != nil {
+		if hasErrorCode(cErr, errCodeNoSuchKey) {
+			return fmt.Errorf("%w: %s", service.ErrObjectNotFound, srcKey)
+		}
+
+		return fmt.Errorf("s3 copy object failed: %w", cErr)
+	}
+
+	return nil
+}
+
+// Stat returns the sha256 checksum of the object when it is uploaded with a checksum.
+func (a *Adapter) Stat(ctx context.Context, key string) (service.ObjectInfo, error) {
+	out, hErr := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(a.bucketName),
+		Key:          aws.String(key),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if hErr != nil {
+		var notFound *types.NotFound
+		if errors.As(hErr, &notFound) {
+			return service.ObjectInfo{}, fmt.Errorf("%w: %s", service.ErrObjectNotFound, key)
+		}
+
+		return service.ObjectInfo{}, fmt.Errorf("s3 head object failed: %w", hErr)
+	}
+
+	info := service.ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}
+
+	// the checksum of a multipart object is a checksum of the checksums of its parts, it is not decoded to 32 bytes.
+	if checksum, dErr := base64.StdEncoding.DecodeString(aws.ToString(out.ChecksumSHA256)); dErr == nil && len(checksum) == 32 {
+		info.SHA256 = hex.EncodeToString(checksum)
+	}
+
+	return info, nil
+}
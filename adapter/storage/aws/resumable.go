@@ -0,0 +1,332 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// minPartSize is the minimum size of the parts of a multipart upload except the last part.
+	minPartSize     = 5 << 20
+	defaultPartSize = 8 << 20
+)
+
+// CreateResumableUpload starts a multipart upload of the key. The chunks of a resumable upload can be smaller than
+// a part, so the rest of a chunk that doesn't fill a part is kept in an incomplete part object until the next chunk.
+func (a *Adapter) CreateResumableUpload(ctx context.Context, key, contentType string, isPublic bool) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(a.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	}
+
+	if isPublic {
+		input.ACL = types.ObjectCannedACLPublicRead
+	} else {
+		input.ACL = types.ObjectCannedACLPrivate
+	}
+
+	out, cErr := a.client.CreateMultipartUpload(ctx, input)
+	if cErr != nil {
+		return "", fmt.Errorf("s3 create multipart upload failed: %w", cErr)
+	}
+
+	return aws.ToString(out.UploadId), nil
+}
+
+// WriteChunk uploads the incomplete part and the chunk as parts of PartSize, the rest is stored as the new
+// incomplete part. The rest of an interrupted chunk is stored too, so the upload is resumed from it.
+func (a *Adapter) WriteChunk(ctx context.Context, key, uploadID string, chunk io.Reader) error {
+	parts, lErr := a.listParts(ctx, key, uploadID)
+	if lErr != nil {
+		return lErr
+	}
+
+	partNumber := int32(len(parts)) + 1 //nolint:gosec // G115: number of parts is less than 10000
+
+	incomplete, gErr := a.getIncompletePart(ctx, key, partNumber)
+	if gErr != nil {
+		return gErr
+	}
+
+	reader := io.MultiReader(bytes.NewReader(incomplete), chunk)
+	buf := make([]byte, a.partSize())
+
+	for {
+		n, rErr := io.ReadFull(reader, buf)
+		if n == len(buf) {
+			if uErr := a.uploadPart(ctx, key, uploadID, partNumber, buf); uErr != nil {
+				return uErr
+			}
+
+			// the incomplete part of a previous part number is ignored, so a failed removal doesn't change the upload.
+			if incomplete != nil {
+				if dErr := a.Delete(ctx, incompletePartKey(key, partNumber)); dErr != nil {
+					return dErr
+				}
+				incomplete = nil
+			}
+
+			partNumber++
+
+			continue
+		}
+
+		if n > 0 {
+			// the request context is canceled when the chunk is interrupted, the received bytes are still stored.
+			if pErr := a.putIncompletePart(context.WithoutCancel(ctx), key, partNumber, buf[:n]); pErr != nil {
+				return pErr
+			}
+		}
+
+		if errors.Is(rErr, io.EOF) || errors.Is(rErr, io.ErrUnexpectedEOF) {
+			return nil
+		}
+
+		return fmt.Errorf("s3 read chunk failed: %w", rErr)
+	}
+}
+
+// GetResumableOffset returns the size of the uploaded parts and the incomplete part, or the size of the object
+// when the upload is completed.
+func (a *Adapter) GetResumableOffset(ctx context.Context, key, uploadID string) (int64, error) {
+	parts, lErr := a.listParts(ctx, key, uploadID)
+	if lErr != nil {
+		if !hasErrorCode(lErr, errCodeNoSuchUpload) {
+			return 0, lErr
+		}
+
+		out, hErr := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(a.bucketName),
+			Key:    aws.String(key),
+		})
+		if hErr != nil {
+			return 0, fmt.Errorf("s3 head object failed: %w", hErr)
+		}
+
+		return aws.ToInt64(out.ContentLength), nil
+	}
+
+	var offset int64
+	for _, part := range parts {
+		offset += aws.ToInt64(part.Size)
+	}
+
+	partNumber := int32(len(parts)) + 1 //nolint:gosec // G115: number of parts is less than 10000
+
+	incompleteSize, iErr := a.incompletePartSize(ctx, key, partNumber)
+	if iErr != nil {
+		return 0, iErr
+	}
+
+	return offset + incompleteSize, nil
+}
+
+// CompleteResumableUpload uploads the incomplete part as the last part and completes the multipart upload.
+func (a *Adapter) CompleteResumableUpload(ctx context.Context, key, uploadID string) error {
+	parts, lErr := a.listParts(ctx, key, uploadID)
+	if lErr != nil {
+		if hasErrorCode(lErr, errCodeNoSuchUpload) {
+			if exists, _ := a.Exists(ctx, key); exists {
+				return nil
+			}
+		}
+
+		return lErr
+	}
+
+	partNumber := int32(len(parts)) + 1 //nolint:gosec // G115: number of parts is less than 10000
+
+	incomplete, gErr := a.getIncompletePart(ctx, key, partNumber)
+	if gErr != nil {
+		return gErr
+	}
+
+	// a multipart upload needs at least one part, the last part can be smaller than the minimum part size.
+	if incomplete != nil || len(parts) == 0 {
+		if uErr := a.uploadPart(ctx, key, uploadID, partNumber, incomplete); uErr != nil {
+			return uErr
+		}
+
+		if parts, lErr = a.listParts(ctx, key, uploadID); lErr != nil {
+			return lErr
+		}
+	}
+
+	completedParts := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completedParts = append(completedParts, types.CompletedPart{
+			ETag:              part.ETag,
+			PartNumber:        part.PartNumber,
+			ChecksumCRC32:     part.ChecksumCRC32,
+			ChecksumCRC32C:    part.ChecksumCRC32C,
+			ChecksumCRC64NVME: part.ChecksumCRC64NVME,
+			ChecksumSHA1:      part.ChecksumSHA1,
+			ChecksumSHA256:    part.ChecksumSHA256,
+		})
+	}
+
+	if _, cErr := a.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(a.bucketName),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	}); cErr != nil {
+		return fmt.Errorf("s3 complete multipart upload failed: %w", cErr)
+	}
+
+	return a.deleteIncompleteParts(ctx, key)
+}
+
+// AbortResumableUpload aborts the multipart upload and removes its incomplete parts, the object of a completed
+// upload is not removed.
+func (a *Adapter) AbortResumableUpload(ctx context.Context, key, uploadID string) error {
+	if _, aErr := a.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(a.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}); aErr != nil {
+		if !hasErrorCode(aErr, errCodeNoSuchUpload) {
+			return fmt.Errorf("s3 abort multipart upload failed: %w", aErr)
+		}
+	}
+
+	return a.deleteIncompleteParts(ctx, key)
+}
+
+func (a *Adapter) partSize() int {
+	if a.cfg.MultipartPartSize <= 0 {
+		return defaultPartSize
+	}
+
+	return max(a.cfg.MultipartPartSize, minPartSize)
+}
+
+// listParts returns the uploaded parts ordered by their number.
+func (a *Adapter) listParts(ctx context.Context, key, uploadID string) ([]types.Part, error) {
+	paginator := s3.NewListPartsPaginator(a.client, &s3.ListPartsInput{
+		Bucket:   aws.String(a.bucketName),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+
+	parts := make([]types.Part, 0)
+	for paginator.HasMorePages() {
+		page, pErr := paginator.NextPage(ctx)
+		if pErr != nil {
+			return nil, fmt.Errorf("s3 list parts failed: %w", pErr)
+		}
+
+		parts = append(parts, page.Parts...)
+	}
+
+	return parts, nil
+}
+
+func (a *Adapter) uploadPart(ctx context.Context, key, uploadID string, partNumber int32, data []byte) error {
+	if _, uErr := a.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(a.bucketName),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(partNumber),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	}); uErr != nil {
+		return fmt.Errorf("s3 upload part %d failed: %w", partNumber, uErr)
+	}
+
+	return nil
+}
+
+// incompletePartKey is the key of the incomplete part that precedes the part number.
+func incompletePartKey(key string, partNumber int32) string {
+	return fmt.Sprintf("%s.%d.part", key, partNumber)
+}
+
+// getIncompletePart returns nil when the incomplete part doesn't exist.
+func (a *Adapter) getIncompletePart(ctx context.Context, key string, partNumber int32) ([]byte, error) {
+	out, gErr := a.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucketName),
+		Key:    aws.String(incompletePartKey(key, partNumber)),
+	})
+	if gErr != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(gErr, &noSuchKey) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("s3 get incomplete part failed: %w", gErr)
+	}
+	defer func() {
+		_ = out.Body.Close()
+	}()
+
+	data, rErr := io.ReadAll(out.Body)
+	if rErr != nil {
+		return nil, fmt.Errorf("s3 read incomplete part failed: %w", rErr)
+	}
+
+	return data, nil
+}
+
+func (a *Adapter) incompletePartSize(ctx context.Context, key string, partNumber int32) (int64, error) {
+	out, hErr := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(a.bucketName),
+		Key:    aws.String(incompletePartKey(key, partNumber)),
+	})
+	if hErr != nil {
+		var notFound *types.NotFound
+		if errors.As(hErr, &notFound) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("s3 head incomplete part failed: %w", hErr)
+	}
+
+	return aws.ToInt64(out.ContentLength), nil
+}
+
+func (a *Adapter) putIncompletePart(ctx context.Context, key string, partNumber int32, data []byte) error {
+	if _, pErr := a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(a.bucketName),
+		Key:           aws.String(incompletePartKey(key, partNumber)),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ACL:           types.ObjectCannedACLPrivate,
+	}); pErr != nil {
+		return fmt.Errorf("s3 put incomplete part failed: %w", pErr)
+	}
+
+	return nil
+}
+
+// deleteIncompleteParts removes the incomplete parts of the key, including the parts of the previous part numbers
+// which are not removed after they are uploaded.
+func (a *Adapter) deleteIncompleteParts(ctx context.Context, key string) error {
+	paginator := s3.NewListObjectsV2Paginator(a.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(a.bucketName),
+		Prefix: aws.String(key + "."),
+	})
+
+	for paginator.HasMorePages() {
+		page, pErr := paginator.NextPage(ctx)
+		if pErr != nil {
+			return fmt.Errorf("s3 list incomplete parts failed: %w", pErr)
+		}
+
+		for _, object := range page.Contents {
+			if dErr := a.Delete(ctx, aws.ToString(object.Key)); dErr != nil {
+				return dErr
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResumableUploadAssemblesIncompleteParts(t *testing.T) {
+	const partSize = minPartSize
+
+	content := bytes.Repeat([]byte("0123456789"), partSize/4)
+
+	tests := []struct {
+		name       string
+		chunks     []int
+		wantParts  int
+		wantOffset int64
+	}{
+		{name: "single small chunk", chunks: []int{1024}, wantParts: 0, wantOffset: 1024},
+		{name: "chunks smaller than a part", chunks: []int{partSize / 2, partSize / 4}, wantParts: 0,
+			wantOffset: partSize * 3 / 4},
+		{name: "chunks across a part", chunks: []int{partSize * 3 / 4, partSize / 2}, wantParts: 1,
+			wantOffset: partSize * 5 / 4},
+		{name: "chunk of two parts", chunks: []int{partSize * 2}, wantParts: 2, wantOffset: partSize * 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			adapter, store := newTestAdapter(t, partSize)
+			const key = "uploads/file.bin"
+
+			uploadID, cErr := adapter.CreateResumableUpload(ctx, key, "application/octet-stream", false)
+			if cErr != nil {
+				t.Fatalf("create upload: %v", cErr)
+			}
+
+			var offset int
+			for _, size := range test.chunks {
+				if wErr := adapter.WriteChunk(ctx, key, uploadID, bytes.NewReader(content[offset:offset+size])); wErr != nil {
+					t.Fatalf("write chunk: %v", wErr)
+				}
+				offset += size
+			}
+
+			if parts := len(store.uploads[uploadID]); parts != test.wantParts {
+				t.Fatalf("expected %d uploaded parts, got %d", test.wantParts, parts)
+			}
+
+			gotOffset, oErr := adapter.GetResumableOffset(ctx, key, uploadID)
+			if oErr != nil {
+				t.Fatalf("get offset: %v", oErr)
+			}
+			if gotOffset != test.wantOffset {
+				t.Fatalf("expected offset %d, got %d", test.wantOffset, gotOffset)
+			}
+
+			if cErr := adapter.CompleteResumableUpload(ctx, key, uploadID); cErr != nil {
+				t.Fatalf("complete upload: %v", cErr)
+			}
+
+			if !bytes.Equal(store.objects[key], content[:offset]) {
+				t.Fatalf("expected the object of %d bytes, got %d bytes", offset, len(store.objects[key]))
+			}
+
+			for objectKey := range store.objects {
+				if strings.HasSuffix(objectKey, ".part") {
+					t.Fatalf("expected the incomplete parts to be removed, found %q", objectKey)
+				}
+			}
+
+			// the offset of a completed upload is the size of the object.
+			if gotOffset, oErr = adapter.GetResumableOffset(ctx, key, uploadID); oErr != nil || gotOffset != int64(offset) {
+				t.Fatalf("expected the offset of the completed upload %d, got %d %v", offset, gotOffset, oErr)
+			}
+		})
+	}
+}
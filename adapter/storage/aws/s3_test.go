@@ -0,0 +1,199 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const testBucket = "bucket"
+
+// memS3 is an in-memory S3 of the tests, it serves the requests of the multipart uploads and the objects.
+type memS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int32][]byte
+	nextID  int
+}
+
+func newTestAdapter(t *testing.T, partSize int) (*Adapter, *memS3) {
+	t.Helper()
+
+	store := &memS3{objects: make(map[string][]byte), uploads: make(map[string]map[int32][]byte)}
+	server := httptest.NewServer(store)
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:                     "us-east-1",
+		BaseEndpoint:               aws.String(server.URL),
+		UsePathStyle:               true,
+		Credentials:                aws.AnonymousCredentials{},
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+	})
+
+	return &Adapter{cfg: Config{MultipartPartSize: partSize}, client: client, bucketName: testBucket}, store
+}
+
+func (m *memS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+testBucket), "/")
+	query := r.URL.Query()
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		m.listObjects(w, query.Get("prefix"))
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		m.nextID++
+		uploadID := strconv.Itoa(m.nextID)
+		m.uploads[uploadID] = make(map[int32][]byte)
+		writeXML(w, http.StatusOK, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Key      string
+			UploadID string `xml:"UploadId"`
+		}{Key: key, UploadID: uploadID})
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		m.completeUpload(w, key, query.Get("uploadId"))
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := m.uploads[query.Get("uploadId")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchUpload")
+
+			return
+		}
+
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		data, _ := io.ReadAll(r.Body)
+		parts[int32(partNumber)] = data //nolint:gosec // G115: the part numbers of the tests are small
+		w.Header().Set("ETag", fmt.Sprintf("%q", strconv.Itoa(partNumber)))
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		m.listParts(w, query.Get("uploadId"))
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		m.objects[key] = data
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := m.objects[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(m.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(m.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (m *memS3) listObjects(w http.ResponseWriter, prefix string) {
+	type object struct {
+		Key  string
+		Size int
+	}
+
+	result := struct {
+		XMLName  xml.Name `xml:"ListBucketResult"`
+		Contents []object
+	}{}
+
+	for key, data := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, object{Key: key, Size: len(data)})
+		}
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+func (m *memS3) listParts(w http.ResponseWriter, uploadID string) {
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+
+		return
+	}
+
+	type part struct {
+		PartNumber int32
+		ETag       string
+		Size       int
+	}
+
+	result := struct {
+		XMLName xml.Name `xml:"ListPartsResult"`
+		Parts   []part   `xml:"Part"`
+	}{}
+
+	for _, number := range sortedPartNumbers(parts) {
+		result.Parts = append(result.Parts, part{PartNumber: number, ETag: fmt.Sprintf("%q", strconv.Itoa(int(number))),
+			Size: len(parts[number])})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+func (m *memS3) completeUpload(w http.ResponseWriter, key, uploadID string) {
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+
+		return
+	}
+
+	var object []byte
+	for _, number := range sortedPartNumbers(parts) {
+		object = append(object, parts[number]...)
+	}
+
+	m.objects[key] = object
+	delete(m.uploads, uploadID)
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Key     string
+	}{Key: key})
+}
+
+func sortedPartNumbers(parts map[int32][]byte) []int32 {
+	numbers := make([]int32, 0, len(parts))
+	for number := range parts {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	return numbers
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeXML(w, status, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
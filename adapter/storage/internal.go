@@ -32,3 +32,8 @@ func (id *InternalAdapter) ConfirmFile(ctx context.Context, req *storagepb.Confi
 	opts ...grpc.CallOption) (*empty.Empty, error) {
 	return id.client.ConfirmFile(ctx, req, opts...)
 }
+
+func (id *InternalAdapter) GetStorageUsages(ctx context.Context, req *storagepb.GetStorageUsagesRequest,
+	opts ...grpc.CallOption) (*storagepb.GetStorageUsagesResponse, error) {
+	return id.client.GetStorageUsages(ctx, req, opts...)
+}
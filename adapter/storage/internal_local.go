@@ -27,7 +27,7 @@ func NewInternalLocalAdapter(svc *service.Service) *InternalLocalAdapter {
 
 func (idl *InternalLocalAdapter) GetLink(ctx context.Context, req *storagepb.GetLinkRequest,
 	_ ...grpc.CallOption) (*storagepb.GetLinkResponse, error) {
-	resp, sErr := idl.svc.GetLink(ctx, types.ID(req.GetFileId()))
+	resp, sErr := idl.svc.GetLink(ctx, types.ID(req.GetFileId()), types.ID(req.GetUserId()), req.GetVariant())
 	if sErr != nil {
 		return nil, servermsg.GRPCMsg(sErr, idl.t, idl.logger)
 	}
@@ -53,3 +53,13 @@ func (idl *InternalLocalAdapter) ConfirmFile(ctx context.Context, req *storagepb
 
 	return &empty.Empty{}, nil
 }
+
+func (idl *InternalLocalAdapter) GetStorageUsages(ctx context.Context, req *storagepb.GetStorageUsagesRequest,
+	_ ...grpc.CallOption) (*storagepb.GetStorageUsagesResponse, error) {
+	resp, sErr := idl.svc.GetStorageUsages(ctx, convertStorageUsersFromPB(req.GetUsers()))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, idl.t, idl.logger)
+	}
+
+	return convertStorageUsagesToPB(resp), nil
+}
@@ -7,18 +7,24 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/urlsigner"
 )
 
 var _ service.Storage = (*Adapter)(nil)
 
 const (
-	defaultDirPerm = 0750 // Owner: RWX, Group: R-X, Other: ---
+	defaultDirPerm       = 0750 // Owner: RWX, Group: R-X, Other: ---
+	defaultFilePerm      = 0640 // Owner: RW-, Group: R--, Other: ---
+	defaultPresignExpire = 15 * time.Minute
+	defaultResumableDir  = ".resumable"
 )
 
 type Adapter struct {
 	cfg    Config
+	signer *urlsigner.Signer
 	logger *slog.Logger
 }
 
@@ -27,8 +33,30 @@ func New(cfg Config, logger *slog.Logger) (*Adapter, error) {
 		return nil, fmt.Errorf("failed to create local storage root path %s: %s", cfg.RootPath, mErr.Error())
 	}
 
+	signer, sErr := urlsigner.New(urlsigner.Config{
+		BaseURL:      cfg.BaseURL,
+		SigningKeyID: cfg.SigningKeyID,
+		SigningKeys:  cfg.SigningKeys,
+	})
+	if sErr != nil {
+		return nil, fmt.Errorf("failed to create signer of the local storage: %w", sErr)
+	}
+
+	if cfg.PresignExpire <= 0 {
+		cfg.PresignExpire = defaultPresignExpire
+	}
+
+	if cfg.ResumablePath == "" {
+		cfg.ResumablePath = filepath.Join(cfg.RootPath, defaultResumableDir)
+	}
+
+	if mErr := os.MkdirAll(cfg.ResumablePath, defaultDirPerm); mErr != nil {
+		return nil, fmt.Errorf("failed to create local storage resumable path %s: %s", cfg.ResumablePath, mErr.Error())
+	}
+
 	return &Adapter{
 		cfg:    cfg,
+		signer: signer,
 		logger: logger,
 	}, nil
 }
@@ -72,11 +100,13 @@ func (a *Adapter) Delete(_ context.Context, key string) error {
 }
 
 func (a *Adapter) GetURL(_ context.Context, key string) (string, error) {
-	return fmt.Sprintf("%s/%s", a.cfg.BaseURL, key), nil
+	return a.signer.URL(key), nil
 }
 
-func (a *Adapter) GetPresignedURL(ctx context.Context, key string) (string, error) {
-	return a.GetURL(ctx, key)
+// GetPresignedURL returns a signed url which is valid for PresignExpire, the files are served by the storage app
+// only when the signature is valid.
+func (a *Adapter) GetPresignedURL(_ context.Context, key string, opts service.PresignOptions) (string, error) {
+	return a.GetSignedURL(key, time.Now().Add(a.cfg.PresignExpire), opts.UserID)
 }
 
 func (a *Adapter) Exists(_ context.Context, key string) (bool, error) {
@@ -93,3 +123,12 @@ func (a *Adapter) Exists(_ context.Context, key string) (bool, error) {
 
 	return false, sErr
 }
+
+func (a *Adapter) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	file, oErr := os.Open(filepath.Join(a.cfg.RootPath, key))
+	if oErr != nil {
+		return nil, fmt.Errorf("failed to open local file: %w", oErr)
+	}
+
+	return file, nil
+}
@@ -1,6 +1,16 @@
 package local
 
+import "time"
+
+// Config SigningKeys are the keys of the signed urls by their id, new urls are signed by the key of SigningKeyID and
+// the other keys are only used to verify the urls that are signed before a key rotation.
+// ResumablePath is the directory of the partial resumable uploads, it must be on the file system of RootPath,
+// so the completed uploads are moved by a rename. It is .resumable in RootPath when it is empty.
 type Config struct {
-	RootPath string `koanf:"root_path"`
-	BaseURL  string `koanf:"base_url"`
+	RootPath      string            `koanf:"root_path"`
+	BaseURL       string            `koanf:"base_url"`
+	SigningKeyID  string            `koanf:"signing_key_id"`
+	SigningKeys   map[string]string `koanf:"signing_keys"`
+	PresignExpire time.Duration     `koanf:"presign_expire"`
+	ResumablePath string            `koanf:"resumable_path"`
 }
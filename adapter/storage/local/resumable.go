@@ -0,0 +1,97 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// CreateResumableUpload creates the temp file of the upload in ResumablePath, the chunks are appended to it and
+// it is moved to the key when the upload is completed.
+func (a *Adapter) CreateResumableUpload(_ context.Context, _, _ string, _ bool) (string, error) {
+	uploadID := ulid.Make().String()
+
+	file, cErr := os.OpenFile(a.resumablePath(uploadID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, defaultFilePerm)
+	if cErr != nil {
+		return "", fmt.Errorf("failed to create resumable upload file: %s", cErr.Error())
+	}
+
+	if closeErr := file.Close(); closeErr != nil {
+		return "", fmt.Errorf("failed to close resumable upload file: %s", closeErr.Error())
+	}
+
+	return uploadID, nil
+}
+
+// WriteChunk appends the chunk to the temp file, the bytes of an interrupted chunk that are read are kept.
+func (a *Adapter) WriteChunk(_ context.Context, _, uploadID string, chunk io.Reader) error {
+	dst, oErr := os.OpenFile(a.resumablePath(uploadID), os.O_WRONLY|os.O_APPEND, defaultFilePerm)
+	if oErr != nil {
+		return fmt.Errorf("failed to open resumable upload file: %s", oErr.Error())
+	}
+	defer func() {
+		if cErr := dst.Close(); cErr != nil {
+			a.logger.Error("resumable upload file can't close", slog.String("error", cErr.Error()))
+		}
+	}()
+
+	if _, copyErr := io.Copy(dst, chunk); copyErr != nil {
+		return fmt.Errorf("failed to write chunk content: %s", copyErr.Error())
+	}
+
+	return nil
+}
+
+// GetResumableOffset returns the size of the temp file, or the size of the file when the upload is completed.
+func (a *Adapter) GetResumableOffset(_ context.Context, key, uploadID string) (int64, error) {
+	info, sErr := os.Stat(a.resumablePath(uploadID))
+	if errors.Is(sErr, os.ErrNotExist) {
+		info, sErr = os.Stat(filepath.Join(a.cfg.RootPath, key))
+	}
+
+	if sErr != nil {
+		return 0, fmt.Errorf("failed to get resumable upload size: %s", sErr.Error())
+	}
+
+	return info.Size(), nil
+}
+
+// CompleteResumableUpload moves the temp file to the key.
+func (a *Adapter) CompleteResumableUpload(_ context.Context, key, uploadID string) error {
+	fullPath := filepath.Join(a.cfg.RootPath, key)
+
+	dir := filepath.Dir(fullPath)
+	if mErr := os.MkdirAll(dir, defaultDirPerm); mErr != nil {
+		return fmt.Errorf("failed to create directory structure for file with dir %s, error: %s", dir, mErr.Error())
+	}
+
+	if rErr := os.Rename(a.resumablePath(uploadID), fullPath); rErr != nil {
+		if errors.Is(rErr, os.ErrNotExist) {
+			if _, sErr := os.Stat(fullPath); sErr == nil {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("failed to complete resumable upload: %s", rErr.Error())
+	}
+
+	return nil
+}
+
+func (a *Adapter) AbortResumableUpload(_ context.Context, _, uploadID string) error {
+	if rErr := os.Remove(a.resumablePath(uploadID)); rErr != nil && !errors.Is(rErr, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete resumable upload file: %s", rErr.Error())
+	}
+
+	return nil
+}
+
+func (a *Adapter) resumablePath(uploadID string) string {
+	return filepath.Join(a.cfg.ResumablePath, filepath.Base(uploadID))
+}
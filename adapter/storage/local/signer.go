@@ -0,0 +1,20 @@
+package local
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// GetSignedURL returns the url of the key which is valid until expiresAt, when userID is not empty
+// the url is only valid for the requests of the user.
+func (a *Adapter) GetSignedURL(key string, expiresAt time.Time, userID types.ID) (string, error) {
+	return a.signer.SignedURL(key, expiresAt, userID), nil
+}
+
+// VerifySignedURL verifies the query of a signed url of the key, userID is the user of the request
+// and it is empty for the anonymous requests.
+func (a *Adapter) VerifySignedURL(key string, query url.Values, userID types.ID) error {
+	return a.signer.Verify(key, query, userID)
+}
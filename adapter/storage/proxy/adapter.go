@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/urlsigner"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const defaultPresignExpire = 15 * time.Minute
+
+// Storage is a storage whose objects are served by the storage app, its objects are read by ranges.
+type Storage interface {
+	service.Storage
+	service.DirectUploadStorage
+	service.RangeStorage
+}
+
+var _ Storage = (*Adapter)(nil)
+
+// Adapter serves the objects of the storage by the storage app, so the urls of the storage, such as the bucket urls
+// of S3, are not exposed. The links of the files are signed urls of the storage app like the links of the local driver.
+type Adapter struct {
+	Storage
+	cfg    Config
+	signer *urlsigner.Signer
+}
+
+func New(storage Storage, cfg Config) (*Adapter, error) {
+	signer, sErr := urlsigner.New(urlsigner.Config{
+		BaseURL:      cfg.BaseURL,
+		SigningKeyID: cfg.SigningKeyID,
+		SigningKeys:  cfg.SigningKeys,
+	})
+	if sErr != nil {
+		return nil, fmt.Errorf("failed to create signer of the storage proxy: %w", sErr)
+	}
+
+	if cfg.PresignExpire <= 0 {
+		cfg.PresignExpire = defaultPresignExpire
+	}
+
+	return &Adapter{
+		Storage: storage,
+		cfg:     cfg,
+		signer:  signer,
+	}, nil
+}
+
+func (a *Adapter) GetURL(_ context.Context, key string) (string, error) {
+	return a.signer.URL(key), nil
+}
+
+// GetPresignedURL returns a signed url which is valid for PresignExpire, when opts.UserID is not empty
+// the url is only valid for the requests of the user.
+func (a *Adapter) GetPresignedURL(_ context.Context, key string, opts service.PresignOptions) (string, error) {
+	return a.signer.SignedURL(key, time.Now().Add(a.cfg.PresignExpire), opts.UserID), nil
+}
+
+// VerifySignedURL verifies the query of a signed url of the key, userID is the user of the request
+// and it is empty for the anonymous requests.
+func (a *Adapter) VerifySignedURL(key string, query url.Values, userID types.ID) error {
+	return a.signer.Verify(key, query, userID)
+}
@@ -0,0 +1,13 @@
+package proxy
+
+import "time"
+
+// Config BaseURL is the url of the downloads of the storage app, the urls are signed like the urls of the local
+// driver. SigningKeys are the keys of the signed urls by their id, new urls are signed by the key of SigningKeyID.
+type Config struct {
+	Enabled       bool              `koanf:"enabled"`
+	BaseURL       string            `koanf:"base_url"`
+	SigningKeyID  string            `koanf:"signing_key_id"`
+	SigningKeys   map[string]string `koanf:"signing_keys"`
+	PresignExpire time.Duration     `koanf:"presign_expire"`
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/syntaxfa/quick-connect/adapter/chat"
 	"github.com/syntaxfa/quick-connect/adapter/manager"
+	"github.com/syntaxfa/quick-connect/adapter/notification"
+	"github.com/syntaxfa/quick-connect/adapter/storage"
 	"github.com/syntaxfa/quick-connect/app/adminapp/delivery/http"
 	"github.com/syntaxfa/quick-connect/app/adminapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/errlog"
@@ -28,10 +30,12 @@ type Application struct {
 	httpServer        http.Server
 	managerGRPCClient *grpcclient.Client
 	chatGRPCClient    *grpcclient.Client
+	storageGRPCClient *grpcclient.Client
 }
 
 func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translation.Translate, authLocalAdapter service.AuthService,
-	userLocalAdapter service.UserService, conversationLocalAdapter service.ConversationService) Application {
+	userLocalAdapter service.UserService, conversationLocalAdapter service.ConversationService,
+	notificationLocalAdapter service.NotificationService, storageLocalAdapter service.StorageService) Application {
 	const op = "Setup"
 
 	var authAdapter service.AuthService
@@ -70,7 +74,31 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 		conversationAdapter = chat.NewConversationAdapter(chatGRPCClient.Conn())
 	}
 
-	handler := http.NewHandler(logger, t, authAdapter, userAdapter, conversationAdapter, cfg.ChatWsURL)
+	var notificationAdapter service.NotificationService
+	if notificationLocalAdapter != nil {
+		notificationAdapter = notificationLocalAdapter
+	} else {
+		notificationAdapter = notification.NewTemplateAdapter(cfg.NotificationAdminURL, cfg.NotificationAdminTimeout)
+	}
+
+	var storageAdapter service.StorageService
+	var storageGRPCClient *grpcclient.Client
+	if storageLocalAdapter != nil {
+		storageAdapter = storageLocalAdapter
+	} else {
+		var storageErr error
+		storageGRPCClient, storageErr = grpcclient.New(cfg.StorageAppGRPC, grpc.WithUnaryInterceptor(grpcauth.AuthClientInterceptor))
+		if storageErr != nil {
+			logger.Error("failed to create storage gRPC client", slog.String("error", storageErr.Error()))
+
+			panic(storageErr)
+		}
+
+		storageAdapter = storage.NewInternalAdapter(storageGRPCClient.Conn())
+	}
+
+	handler := http.NewHandler(logger, t, authAdapter, userAdapter, conversationAdapter, notificationAdapter, storageAdapter,
+		cfg.ChatWsURL)
 
 	getPuResp, gpuErr := authAdapter.GetPublicKey(context.Background(), nil)
 	if gpuErr != nil {
@@ -88,6 +116,7 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 		httpServer:        http.New(httpserver.New(cfg.HTTPServer, logger), handler, cfg.TemplatePath, jwtValidator),
 		managerGRPCClient: managerGRPCClient,
 		chatGRPCClient:    chatGRPCClient,
+		storageGRPCClient: storageGRPCClient,
 	}
 }
 
@@ -133,6 +162,9 @@ func (a Application) Stop(ctx context.Context) bool {
 		shutdownWg.Add(1)
 		go a.stopChatGRPCClient(&shutdownWg)
 
+		shutdownWg.Add(1)
+		go a.stopStorageGRPCClient(&shutdownWg)
+
 		shutdownWg.Wait()
 		close(shutdownDone)
 	}()
@@ -173,3 +205,14 @@ func (a Application) stopChatGRPCClient(wg *sync.WaitGroup) {
 		a.logger.Error("failed to close chat gRPC client", slog.String("error", cErr.Error()))
 	}
 }
+
+func (a Application) stopStorageGRPCClient(wg *sync.WaitGroup) {
+	defer wg.Done()
+	if a.storageGRPCClient == nil {
+		return
+	}
+
+	if cErr := a.storageGRPCClient.Close(); cErr != nil {
+		a.logger.Error("failed to close storage gRPC client", slog.String("error", cErr.Error()))
+	}
+}
@@ -15,5 +15,9 @@ type Config struct {
 	TemplatePath    string            `koanf:"template_path"`
 	ManagerAppGRPC  grpcclient.Config `koanf:"manager_app_grpc"`
 	ChatAppGRPC     grpcclient.Config `koanf:"chat_app_grpc"`
+	StorageAppGRPC  grpcclient.Config `koanf:"storage_app_grpc"`
 	ChatWsURL       string            `koanf:"chat_ws_url"`
+	// NotificationAdminURL is the base url of the notification admin HTTP server.
+	NotificationAdminURL     string        `koanf:"notification_admin_url"`
+	NotificationAdminTimeout time.Duration `koanf:"notification_admin_timeout"`
 }
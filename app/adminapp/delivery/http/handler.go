@@ -22,17 +22,22 @@ type Handler struct {
 	authSvc         service.AuthService
 	userSvc         service.UserService
 	conversationSvc service.ConversationService
+	notificationSvc service.NotificationService
+	storageSvc      service.StorageService
 	chatWSURL       string
 }
 
 func NewHandler(logger *slog.Logger, t *translation.Translate, authSvc service.AuthService, userSvc service.UserService,
-	conversationSvc service.ConversationService, chatWSURL string) Handler {
+	conversationSvc service.ConversationService, notificationSvc service.NotificationService, storageSvc service.StorageService,
+	chatWSURL string) Handler {
 	return Handler{
 		t:               t,
 		logger:          logger,
 		authSvc:         authSvc,
 		userSvc:         userSvc,
 		conversationSvc: conversationSvc,
+		notificationSvc: notificationSvc,
+		storageSvc:      storageSvc,
 		chatWSURL:       chatWSURL,
 	}
 }
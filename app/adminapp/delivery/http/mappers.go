@@ -16,6 +16,7 @@ type User struct {
 	Avatar       string
 	Roles        []string
 	LastOnlineAt string
+	StorageUsage string
 }
 
 // RoleInfo struct helper for templates.
@@ -0,0 +1,273 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	notificationservice "github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	paginate "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// checkNotificationAccess the notification admin API is internal, so only superusers and notification admins
+// can use the notification page.
+func (h Handler) checkNotificationAccess(c echo.Context) (User, bool) {
+	user, ok := getUserFromContext(c)
+	if !ok {
+		return User{}, false
+	}
+
+	return user, HasRole(user.Roles, string(types.RoleSuperUser)) || HasRole(user.Roles, string(types.RoleNotification))
+}
+
+// ShowNotificationService renders the notification service page.
+func (h Handler) ShowNotificationService(c echo.Context) error {
+	user, hasAccess := h.checkNotificationAccess(c)
+	if !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	data := map[string]interface{}{
+		"Title":        "Notification Management",
+		"TemplateName": "notification_page",
+		"User":         user,
+	}
+
+	if isHTMX(c) {
+		return c.Render(http.StatusOK, "notification_page", data)
+	}
+
+	return c.Render(http.StatusOK, "main_layout", data)
+}
+
+// ListNotificationTemplatesPartial renders the templates table (called by HTMX).
+func (h Handler) ListNotificationTemplatesPartial(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	page, _ := strconv.ParseUint(c.QueryParam("page"), 10, 64)
+	if page == 0 {
+		page = 1
+	}
+
+	name := c.QueryParam("name")
+
+	resp, err := h.notificationSvc.TemplateList(c.Request().Context(), notificationservice.ListTemplateRequest{
+		Name: name,
+		Paginated: paginate.RequestBase{
+			CurrentPage: page,
+			PageSize:    defaultPageSize,
+			Descending:  true,
+		},
+	})
+	if err != nil {
+		return h.renderGRPCError(c, "ListNotificationTemplatesPartial", err)
+	}
+
+	data := map[string]interface{}{
+		"Templates": resp.Results,
+		"Name":      name,
+		"Page":      page,
+		"HasPrev":   page > 1,
+		"PrevPage":  page - 1,
+		"HasNext":   len(resp.Results) == defaultPageSize,
+		"NextPage":  page + 1,
+	}
+
+	return c.Render(http.StatusOK, "notification_templates_partial", data)
+}
+
+// ShowNotificationTemplateModal renders the template modal with versions, preview and test send forms.
+func (h Handler) ShowNotificationTemplateModal(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	template, err := h.notificationSvc.GetTemplate(c.Request().Context(), types.ID(c.Param("id")))
+	if err != nil {
+		return h.renderGRPCError(c, "ShowNotificationTemplateModal", err)
+	}
+
+	channels := make([]notificationservice.ChannelType, 0, len(template.Contents))
+	langs := make([]string, 0)
+	seenLangs := make(map[string]bool)
+	for _, content := range template.Contents {
+		channels = append(channels, content.Channel)
+
+		for _, body := range content.Bodies {
+			if !seenLangs[body.Lang] {
+				seenLangs[body.Lang] = true
+				langs = append(langs, body.Lang)
+			}
+		}
+	}
+
+	data := map[string]interface{}{
+		"Template": template,
+		"Channels": channels,
+		"Langs":    langs,
+	}
+
+	return c.Render(http.StatusOK, "notification_template_modal", data)
+}
+
+// ListNotificationTemplateVersionsPartial renders the versions of a template (called by HTMX).
+func (h Handler) ListNotificationTemplateVersionsPartial(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	resp, err := h.notificationSvc.ListTemplateVersions(c.Request().Context(), types.ID(c.Param("id")))
+	if err != nil {
+		return h.renderGRPCError(c, "ListNotificationTemplateVersionsPartial", err)
+	}
+
+	return c.Render(http.StatusOK, "notification_template_versions_partial", resp)
+}
+
+// RollbackNotificationTemplate handles rolling back a template to an old version.
+func (h Handler) RollbackNotificationTemplate(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	version, pErr := strconv.Atoi(c.Param("version"))
+	if pErr != nil {
+		return h.renderErrorPartial(c, http.StatusBadRequest, servermsg.MsgInvalidInput)
+	}
+
+	_, err := h.notificationSvc.RollbackTemplate(c.Request().Context(), types.ID(c.Param("id")), version)
+	if err != nil {
+		return h.renderGRPCError(c, "RollbackNotificationTemplate", err)
+	}
+
+	setHxTrigger(c, "templateVersionsChanged")
+
+	setTriggerAfterSettle(c, h.t.TranslateMessage(servermsg.MsgTemplateRolledBackSuccessfully))
+
+	return c.NoContent(http.StatusOK)
+}
+
+// PreviewNotificationTemplate renders the preview of a template with the sample data of the form.
+func (h Handler) PreviewNotificationTemplate(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	req := notificationservice.PreviewTemplateRequest{
+		Channel:          notificationservice.ChannelType(c.FormValue("channel")),
+		Lang:             c.FormValue("lang"),
+		DynamicTitleData: parseKeyValueLines(c.FormValue("title_data")),
+		DynamicBodyData:  parseKeyValueLines(c.FormValue("body_data")),
+	}
+
+	if versionStr := c.FormValue("version"); versionStr != "" {
+		version, pErr := strconv.Atoi(versionStr)
+		if pErr != nil {
+			return h.renderErrorPartial(c, http.StatusBadRequest, servermsg.MsgInvalidInput)
+		}
+
+		req.Version = &version
+	}
+
+	resp, err := h.notificationSvc.PreviewTemplate(c.Request().Context(), types.ID(c.Param("id")), req)
+	if err != nil {
+		return h.renderGRPCError(c, "PreviewNotificationTemplate", err)
+	}
+
+	data := map[string]interface{}{
+		"Preview": resp,
+		"IsHTML":  resp.Channel == notificationservice.ChannelTypeEmail,
+	}
+
+	return c.Render(http.StatusOK, "notification_preview_partial", data)
+}
+
+// SendTestNotification sends the template to the current admin.
+func (h Handler) SendTestNotification(c echo.Context) error {
+	user, hasAccess := h.checkNotificationAccess(c)
+	if !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	if fErr := c.Request().ParseForm(); fErr != nil {
+		return h.renderErrorPartial(c, http.StatusBadRequest, servermsg.MsgInvalidInput)
+	}
+
+	channels := make([]notificationservice.ChannelDeliveryRequest, 0)
+	for _, channel := range c.Request().Form["channels"] {
+		channels = append(channels, notificationservice.ChannelDeliveryRequest{Channel: notificationservice.ChannelType(channel)})
+	}
+
+	_, err := h.notificationSvc.SendTestNotification(c.Request().Context(), types.ID(c.Param("id")),
+		notificationservice.SendTestNotificationRequest{
+			ExternalUserID:    user.ID,
+			ChannelDeliveries: channels,
+			DynamicTitleData:  parseKeyValueLines(c.FormValue("title_data")),
+			DynamicBodyData:   parseKeyValueLines(c.FormValue("body_data")),
+		})
+	if err != nil {
+		return h.renderGRPCError(c, "SendTestNotification", err)
+	}
+
+	setTriggerAfterSettle(c, h.t.TranslateMessage(servermsg.MsgTestNotificationSent))
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ShowNotificationUserPreferencesPartial renders the effective topic preferences of a user (called by HTMX).
+func (h Handler) ShowNotificationUserPreferencesPartial(c echo.Context) error {
+	if _, hasAccess := h.checkNotificationAccess(c); !hasAccess {
+		return h.renderErrorPartial(c, http.StatusForbidden, servermsg.MsgServiceAccessDenied)
+	}
+
+	externalUserID := strings.TrimSpace(c.QueryParam("external_user_id"))
+	if externalUserID == "" {
+		return h.renderErrorPartial(c, http.StatusBadRequest, servermsg.MsgFieldRequired)
+	}
+
+	resp, err := h.notificationSvc.GetUserPreferences(c.Request().Context(), externalUserID)
+	if err != nil {
+		return h.renderGRPCError(c, "ShowNotificationUserPreferencesPartial", err)
+	}
+
+	data := map[string]interface{}{
+		"Preferences": resp,
+		"Channels":    notificationservice.AllChannelType,
+	}
+
+	return c.Render(http.StatusOK, "notification_preferences_partial", data)
+}
+
+// parseKeyValueLines parses the textarea sample data, each line is a key=value pair. JSON values such as numbers,
+// booleans, lists and objects are decoded, other values are kept as strings.
+func parseKeyValueLines(value string) notificationservice.DynamicData {
+	data := make(notificationservice.DynamicData)
+
+	for _, line := range strings.Split(value, "\n") {
+		key, val, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.TrimSpace(key) == "" {
+			continue
+		}
+
+		val = strings.TrimSpace(val)
+
+		var decoded any
+		decoder := json.NewDecoder(strings.NewReader(val))
+		decoder.UseNumber()
+		if decoder.Decode(&decoded) == nil && !decoder.More() {
+			data[strings.TrimSpace(key)] = decoded
+
+			continue
+		}
+
+		data[strings.TrimSpace(key)] = val
+	}
+
+	return data
+}
@@ -55,9 +55,19 @@ func (s Server) registerRoutes() {
 	// Dashboard - Main hub
 	dashGr := rootGr.Group("")
 	dashGr.GET("/dashboard", s.handler.ShowDashboard)
-	dashGr.GET("/notification", s.handler.ShowNotificationService)
 	dashGr.GET("/story", s.handler.ShowStoryService)
 
+	// Notification Group
+	notificationGr := rootGr.Group("/notification")
+	notificationGr.GET("", s.handler.ShowNotificationService)
+	notificationGr.GET("/templates", s.handler.ListNotificationTemplatesPartial)
+	notificationGr.GET("/templates/:id", s.handler.ShowNotificationTemplateModal)
+	notificationGr.GET("/templates/:id/versions", s.handler.ListNotificationTemplateVersionsPartial)
+	notificationGr.POST("/templates/:id/versions/:version/rollback", s.handler.RollbackNotificationTemplate)
+	notificationGr.POST("/templates/:id/preview", s.handler.PreviewNotificationTemplate)
+	notificationGr.POST("/templates/:id/test", s.handler.SendTestNotification)
+	notificationGr.GET("/preferences", s.handler.ShowNotificationUserPreferencesPartial)
+
 	// Users Management Group
 	userGr := rootGr.Group("/users")
 	userGr.GET("", s.handler.ShowUsersPage)         // Renders the main page shell (users_page.html)
@@ -7,14 +7,11 @@ import (
 )
 
 const (
-	storyActive           = 456
-	storyViews            = 2300000
-	notificationSent      = 1234
-	notificationDelivered = 98
-	notificationFailed    = 2
-	supportActive         = 89
-	supportPending        = 12
-	supportClosed         = 456
+	storyActive    = 456
+	storyViews     = 2300000
+	supportActive  = 89
+	supportPending = 12
+	supportClosed  = 456
 )
 
 // ShowDashboard renders the main dashboard page with service hub.
@@ -33,29 +30,6 @@ func (h Handler) ShowDashboard(c echo.Context) error {
 	return c.Render(http.StatusOK, "main_layout", data)
 }
 
-// ShowNotificationService renders the notification service page.
-func (h Handler) ShowNotificationService(c echo.Context) error {
-	user, _ := getUserFromContext(c)
-
-	// TODO: Fetch notification data
-	data := map[string]interface{}{
-		"Title": "Notification Management",
-		"Stats": map[string]interface{}{
-			"Sent":      notificationSent,
-			"Delivered": notificationDelivered,
-			"Failed":    notificationFailed,
-		},
-		"TemplateName": "notification_page",
-		"User":         user,
-	}
-
-	if isHTMX(c) {
-		return c.Render(http.StatusOK, "notification_page", data)
-	}
-
-	return c.Render(http.StatusOK, "main_layout", data)
-}
-
 // ShowStoryService renders the story service page.
 func (h Handler) ShowStoryService(c echo.Context) error {
 	user, _ := getUserFromContext(c)
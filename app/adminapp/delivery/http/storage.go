@@ -0,0 +1,61 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
+)
+
+// setStorageUsages sets the storage usage of the users, the list is still rendered when the storage service fails.
+func (h Handler) setStorageUsages(c echo.Context, users []User) {
+	if len(users) == 0 {
+		return
+	}
+
+	req := &storagepb.GetStorageUsagesRequest{Users: make([]*storagepb.StorageUser, len(users))}
+	for i, user := range users {
+		req.Users[i] = &storagepb.StorageUser{UserId: user.ID, Roles: user.Roles}
+	}
+
+	resp, err := h.storageSvc.GetStorageUsages(grpcContext(c), req)
+	if err != nil {
+		h.logger.Error("failed to get storage usages", slog.String("error", err.Error()))
+
+		return
+	}
+
+	usages := make(map[string]*storagepb.StorageUsage, len(resp.GetUsages()))
+	for _, usage := range resp.GetUsages() {
+		usages[usage.GetUserId()] = usage
+	}
+
+	for i := range users {
+		usage, ok := usages[users[i].ID]
+		if !ok {
+			continue
+		}
+
+		if usage.GetLimit() > 0 {
+			users[i].StorageUsage = fmt.Sprintf("%s / %s", formatBytes(usage.GetUsedBytes()), formatBytes(usage.GetLimit()))
+		} else {
+			users[i].StorageUsage = formatBytes(usage.GetUsedBytes())
+		}
+	}
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -109,6 +109,8 @@ func (h Handler) ListUsersPartial(c echo.Context) error {
 		users[i] = convertUserPbToUser(pbUser)
 	}
 
+	h.setStorageUsages(c, users)
+
 	pagination := h.buildPaginationData(listResp, usernameQuery, int(sortDirInt), roleStrings)
 
 	data := map[string]interface{}{
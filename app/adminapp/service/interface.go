@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 
+	notificationservice "github.com/syntaxfa/quick-connect/app/notificationapp/service"
 	"github.com/syntaxfa/quick-connect/protobuf/chat/golang/conversationpb"
 	"github.com/syntaxfa/quick-connect/protobuf/manager/golang/authpb"
 	"github.com/syntaxfa/quick-connect/protobuf/manager/golang/userpb"
+	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
+	"github.com/syntaxfa/quick-connect/types"
 	"google.golang.org/grpc"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 )
@@ -41,3 +44,20 @@ type ConversationService interface {
 	CloseConversation(ctx context.Context, req *conversationpb.CloseConversationRequest,
 		opts ...grpc.CallOption) (*conversationpb.Conversation, error)
 }
+
+type NotificationService interface {
+	TemplateList(ctx context.Context, req notificationservice.ListTemplateRequest) (notificationservice.ListTemplateResponse, error)
+	GetTemplate(ctx context.Context, templateID types.ID) (notificationservice.Template, error)
+	ListTemplateVersions(ctx context.Context, templateID types.ID) (notificationservice.ListTemplateVersionResponse, error)
+	RollbackTemplate(ctx context.Context, templateID types.ID, version int) (notificationservice.Template, error)
+	PreviewTemplate(ctx context.Context, templateID types.ID,
+		req notificationservice.PreviewTemplateRequest) (notificationservice.PreviewTemplateResponse, error)
+	SendTestNotification(ctx context.Context, templateID types.ID,
+		req notificationservice.SendTestNotificationRequest) (notificationservice.Notification, error)
+	GetUserPreferences(ctx context.Context, externalUserID string) (notificationservice.UserPreferences, error)
+}
+
+type StorageService interface {
+	GetStorageUsages(ctx context.Context, req *storagepb.GetStorageUsagesRequest,
+		opts ...grpc.CallOption) (*storagepb.GetStorageUsagesResponse, error)
+}
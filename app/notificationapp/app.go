@@ -10,16 +10,32 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/syntaxfa/quick-connect/adapter/manager"
 	"github.com/syntaxfa/quick-connect/adapter/postgres"
 	"github.com/syntaxfa/quick-connect/adapter/pubsub/redispubsub"
 	"github.com/syntaxfa/quick-connect/adapter/redis"
+	"github.com/syntaxfa/quick-connect/adapter/storage"
+	grpcdelivery "github.com/syntaxfa/quick-connect/app/notificationapp/delivery/grpc"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/delivery/http"
 	postgres2 "github.com/syntaxfa/quick-connect/app/notificationapp/repository/postgres"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/grpcauth"
+	"github.com/syntaxfa/quick-connect/pkg/grpcclient"
+	"github.com/syntaxfa/quick-connect/pkg/grpcserver"
 	"github.com/syntaxfa/quick-connect/pkg/httpserver"
+	"github.com/syntaxfa/quick-connect/pkg/identityresolver"
+	"github.com/syntaxfa/quick-connect/pkg/jwtvalidator"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/rolemanager"
+	"github.com/syntaxfa/quick-connect/pkg/tokenmanager"
 	"github.com/syntaxfa/quick-connect/pkg/translation"
 	"github.com/syntaxfa/quick-connect/pkg/websocket"
+	"github.com/syntaxfa/quick-connect/protobuf/manager/golang/authpb"
+	"github.com/syntaxfa/quick-connect/types"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
 const (
@@ -28,15 +44,28 @@ const (
 )
 
 type Application struct {
-	cfg              Config
-	trap             <-chan os.Signal
-	logger           *slog.Logger
-	clientHTTPServer http.ClientServer
-	adminHTTPServer  http.AdminServer
+	cfg               Config
+	trap              <-chan os.Signal
+	logger            *slog.Logger
+	clientHTTPServer  http.ClientServer
+	adminHTTPServer   http.AdminServer
+	notificationSvc   service.Service
+	pubSub            *redispubsub.PubSub
+	storageGRPCClient *grpcclient.Client
+	managerGRPCClient *grpcclient.Client
+	grpcServer        grpcdelivery.Server
 }
 
-func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, re *redis.Adapter, pg *postgres.Database) (
-	Application, service.Service) {
+type AuthService interface {
+	GetPublicKey(ctx context.Context, req *empty.Empty, opts ...grpc.CallOption) (*authpb.GetPublicKeyResponse, error)
+	Login(ctx context.Context, req *authpb.LoginRequest, opts ...grpc.CallOption) (*authpb.LoginResponse, error)
+	TokenRefresh(ctx context.Context, req *authpb.TokenRefreshRequest, opts ...grpc.CallOption) (*authpb.TokenRefreshResponse, error)
+}
+
+func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, re *redis.Adapter, pg *postgres.Database,
+	storageInternalAd service.StorageService, authInternalAd AuthService) (Application, service.Service) {
+	const op = "Setup"
+
 	t, tErr := translation.New(translation.DefaultLanguages...)
 	if tErr != nil {
 		panic(tErr)
@@ -44,8 +73,52 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, re *redis.Ada
 
 	cfg.Notification.PingPeriod = (cfg.Notification.PongWait * pingPeriodNumerator) / pingPeriodDenominator
 
+	// the idempotency keys would expire as soon as they are claimed, so the retries of a request would be sent again.
+	if cfg.Notification.IdempotencyKeyTTL <= 0 {
+		ttlErr := fmt.Errorf("notification.idempotency_key_ttl must be positive, got %s", cfg.Notification.IdempotencyKeyTTL)
+		errlog.WithoutErr(richerror.New(op).WithWrapError(ttlErr).WithKind(richerror.KindInvalid), logger)
+
+		panic(ttlErr)
+	}
+
 	cache := cachemanager.New(re, logger)
 
+	var storageAd service.StorageService
+	var storageGRPCClient *grpcclient.Client
+
+	if storageInternalAd != nil {
+		storageAd = storageInternalAd
+	} else {
+		var grpcErr error
+		storageGRPCClient, grpcErr = grpcclient.New(cfg.StorageAppGRPC, grpc.WithUnaryInterceptor(grpcauth.AuthClientInterceptor))
+		if grpcErr != nil {
+			errlog.WithoutErr(richerror.New(op).WithWrapError(grpcErr).WithKind(richerror.KindUnexpected), logger)
+
+			panic(grpcErr)
+		}
+
+		storageAd = storage.NewInternalAdapter(storageGRPCClient.Conn())
+	}
+
+	var authAd AuthService
+	var managerGRPCClient *grpcclient.Client
+
+	if authInternalAd != nil {
+		authAd = authInternalAd
+	} else {
+		var grpcErr error
+		managerGRPCClient, grpcErr = grpcclient.New(cfg.ManagerAppGRPC)
+		if grpcErr != nil {
+			errlog.WithoutErr(richerror.New(op).WithWrapError(grpcErr).WithKind(richerror.KindUnexpected), logger)
+
+			panic(grpcErr)
+		}
+
+		authAd = manager.NewAuthAdapter(managerGRPCClient.Conn())
+	}
+
+	tokenManager := tokenmanager.NewTokenManager(cfg.ServiceAuthInfo.Username, cfg.ServiceAuthInfo.Password, authAd)
+
 	notificationVld := service.NewValidate(t)
 	notificationRepo := postgres2.New(pg)
 
@@ -53,25 +126,79 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, re *redis.Ada
 
 	hub := service.NewHub(cfg.Notification, logger, pubSub)
 	upgrader := websocket.NewGorillaUpgrader(cfg.Websocket, checkOrigin(cfg.ClientHTTPServer.Cors.AllowOrigins, logger))
-	notificationSvc := service.New(cfg.Notification, notificationVld, cache, notificationRepo, logger, hub, pubSub)
+	notificationSvc := service.New(cfg.Notification, notificationVld, cache, notificationRepo, logger, hub, pubSub,
+		storageAd, tokenManager)
+
+	resp, pubErr := authAd.GetPublicKey(context.Background(), nil)
+	if pubErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(pubErr).WithKind(richerror.KindUnexpected), logger)
+
+		panic(pubErr)
+	}
+
+	jwtValidator := jwtvalidator.New(resp.GetPublicKey(), logger)
+
+	identityResolver, irErr := identityresolver.New(cfg.IdentityResolver, cache, jwtValidator, logger)
+	if irErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(irErr).WithKind(richerror.KindUnexpected), logger)
+
+		panic(irErr)
+	}
 
 	handler := http.NewHandler(notificationSvc, t, upgrader)
-	clientHTTPServer := http.NewClientServer(httpserver.New(cfg.ClientHTTPServer, logger), handler, cfg.GetUserIDURL, logger)
+	clientHTTPServer := http.NewClientServer(httpserver.New(cfg.ClientHTTPServer, logger), handler, identityResolver, logger)
 
 	adminHTTPServer := http.NewAdminServer(httpserver.New(cfg.AdminHTTPServer, logger), handler, logger)
 
+	authInterceptor := grpcauth.NewAuthInterceptor(jwtValidator, SetupRoleManager())
+	grpcHandler := grpcdelivery.NewHandler(notificationSvc, t, logger)
+	grpcServer := grpcdelivery.New(grpcserver.New(cfg.GRPCServer, logger, grpc.UnaryInterceptor(authInterceptor)), grpcHandler, logger)
+
 	return Application{
-		cfg:              cfg,
-		trap:             trap,
-		logger:           logger,
-		clientHTTPServer: clientHTTPServer,
-		adminHTTPServer:  adminHTTPServer,
+		cfg:               cfg,
+		trap:              trap,
+		logger:            logger,
+		clientHTTPServer:  clientHTTPServer,
+		adminHTTPServer:   adminHTTPServer,
+		notificationSvc:   notificationSvc,
+		pubSub:            pubSub,
+		storageGRPCClient: storageGRPCClient,
+		managerGRPCClient: managerGRPCClient,
+		grpcServer:        grpcServer,
 	}, notificationSvc
 }
 
 func (a Application) Start() {
 	clientHTTPServerChan := make(chan error, 1)
 	adminHTTPServerChan := make(chan error, 1)
+	grpcServerChan := make(chan error, 1)
+
+	schedulerCtx, cancelScheduler := context.WithCancel(context.Background())
+	defer cancelScheduler()
+
+	go func() {
+		a.logger.Info("notification scheduler started")
+
+		a.notificationSvc.RunScheduler(schedulerCtx)
+	}()
+
+	go func() {
+		a.logger.Info("campaign worker started")
+
+		a.notificationSvc.RunCampaignWorker(schedulerCtx)
+	}()
+
+	go func() {
+		a.logger.Info("digest worker started")
+
+		a.notificationSvc.RunDigestWorker(schedulerCtx)
+	}()
+
+	go func() {
+		a.logger.Info("template cache invalidator started")
+
+		a.notificationSvc.RunTemplateCacheInvalidator(schedulerCtx, a.pubSub)
+	}()
 
 	go func() {
 		a.logger.Info(fmt.Sprintf("client http server started on %d port", a.cfg.ClientHTTPServer.Port))
@@ -89,15 +216,25 @@ func (a Application) Start() {
 		}
 	}()
 
+	go func() {
+		if sErr := a.grpcServer.Start(); sErr != nil {
+			grpcServerChan <- sErr
+		}
+	}()
+
 	select {
 	case err := <-clientHTTPServerChan:
 		a.logger.Error(fmt.Sprintf("error in client http server on %d", a.cfg.ClientHTTPServer.Port), slog.String("error", err.Error()))
 	case err := <-adminHTTPServerChan:
 		a.logger.Error(fmt.Sprintf("error in admin http server on %d", a.cfg.AdminHTTPServer.Port), slog.String("error", err.Error()))
+	case err := <-grpcServerChan:
+		a.logger.Error(fmt.Sprintf("error in grpc server on %d", a.cfg.GRPCServer.Port), slog.String("error", err.Error()))
 	case <-a.trap:
 		a.logger.Info("received http server shutdown signal!!!")
 	}
 
+	cancelScheduler()
+
 	shutdownTimeoutCtx, cancel := context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
 	defer cancel()
 
@@ -118,6 +255,15 @@ func (a Application) Stop(ctx context.Context) bool {
 		shutdownWg.Add(1)
 		go a.StopHTTPServer(ctx, &shutdownWg)
 
+		shutdownWg.Add(1)
+		go a.stopGRPCServer(&shutdownWg)
+
+		shutdownWg.Add(1)
+		go a.stopManagerGRPCClient(ctx, &shutdownWg)
+
+		shutdownWg.Add(1)
+		go a.stopStorageGRPCClient(ctx, &shutdownWg)
+
 		shutdownWg.Wait()
 		close(shutdownDone)
 	}()
@@ -142,6 +288,36 @@ func (a Application) StopHTTPServer(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+func (a Application) stopGRPCServer(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	a.grpcServer.Stop()
+}
+
+func (a Application) stopManagerGRPCClient(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if a.managerGRPCClient == nil {
+		return
+	}
+
+	if cErr := a.managerGRPCClient.Close(); cErr != nil {
+		a.logger.ErrorContext(ctx, "grpc manager client gracefully shutdown failed", slog.String("error", cErr.Error()))
+	}
+}
+
+func (a Application) stopStorageGRPCClient(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if a.storageGRPCClient == nil {
+		return
+	}
+
+	if cErr := a.storageGRPCClient.Close(); cErr != nil {
+		a.logger.ErrorContext(ctx, "grpc storage client gracefully shutdown failed", slog.String("error", cErr.Error()))
+	}
+}
+
 func checkOrigin(allowedOrigins []string, logger *slog.Logger) func(r *http2.Request) bool {
 	return func(r *http2.Request) bool {
 		origin := r.Header.Get("Origin")
@@ -190,3 +366,20 @@ func checkOrigin(allowedOrigins []string, logger *slog.Logger) func(r *http2.Req
 		return false
 	}
 }
+
+func SetupRoleManager() *rolemanager.RoleManager {
+	methodRoles := map[string][]types.Role{
+		"/notification.NotificationService/SendNotification":     {types.RoleService},
+		"/notification.NotificationService/SendBulk":             {types.RoleService},
+		"/notification.NotificationService/GetUserSetting":       {types.RoleService},
+		"/notification.NotificationService/ListNotifications":    {types.RoleService},
+		"/notification.NotificationService/ListTemplates":        {types.RoleSuperUser, types.RoleNotification},
+		"/notification.NotificationService/GetTemplate":          {types.RoleSuperUser, types.RoleNotification},
+		"/notification.NotificationService/ListTemplateVersions": {types.RoleSuperUser, types.RoleNotification},
+		"/notification.NotificationService/RollbackTemplate":     {types.RoleSuperUser, types.RoleNotification},
+		"/notification.NotificationService/PreviewTemplate":      {types.RoleSuperUser, types.RoleNotification},
+		"/notification.NotificationService/SendTestNotification": {types.RoleSuperUser, types.RoleNotification},
+	}
+
+	return rolemanager.NewRoleManager(methodRoles)
+}
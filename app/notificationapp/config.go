@@ -6,19 +6,31 @@ import (
 	"github.com/syntaxfa/quick-connect/adapter/postgres"
 	"github.com/syntaxfa/quick-connect/adapter/redis"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/grpcclient"
+	"github.com/syntaxfa/quick-connect/pkg/grpcserver"
 	"github.com/syntaxfa/quick-connect/pkg/httpserver"
+	"github.com/syntaxfa/quick-connect/pkg/identityresolver"
 	"github.com/syntaxfa/quick-connect/pkg/logger"
 	"github.com/syntaxfa/quick-connect/pkg/websocket"
 )
 
+type ServiceAuthInfo struct {
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
+}
+
 type Config struct {
-	ShutdownTimeout  time.Duration     `koanf:"shutdown_timeout"`
-	ClientHTTPServer httpserver.Config `koanf:"client_http_server"`
-	AdminHTTPServer  httpserver.Config `koanf:"admin_http_server"`
-	Logger           logger.Config     `koanf:"logger"`
-	Postgres         postgres.Config   `koanf:"postgres"`
-	Notification     service.Config    `koanf:"notification"`
-	Redis            redis.Config      `koanf:"redis"`
-	Websocket        websocket.Config  `koanf:"websocket"`
-	GetUserIDURL     string            `koanf:"get_user_id_url"`
+	ShutdownTimeout  time.Duration           `koanf:"shutdown_timeout"`
+	ClientHTTPServer httpserver.Config       `koanf:"client_http_server"`
+	AdminHTTPServer  httpserver.Config       `koanf:"admin_http_server"`
+	Logger           logger.Config           `koanf:"logger"`
+	Postgres         postgres.Config         `koanf:"postgres"`
+	Notification     service.Config          `koanf:"notification"`
+	Redis            redis.Config            `koanf:"redis"`
+	Websocket        websocket.Config        `koanf:"websocket"`
+	IdentityResolver identityresolver.Config `koanf:"identity_resolver"`
+	StorageAppGRPC   grpcclient.Config       `koanf:"storage_app_grpc"`
+	ManagerAppGRPC   grpcclient.Config       `koanf:"manager_app_grpc"`
+	ServiceAuthInfo  ServiceAuthInfo         `koanf:"service_auth_info"`
+	GRPCServer       grpcserver.Config       `koanf:"grpc_server"`
 }
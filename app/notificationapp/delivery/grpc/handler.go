@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"log/slog"
+
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/translation"
+	"github.com/syntaxfa/quick-connect/protobuf/notification/golang/notificationpb"
+)
+
+type Handler struct {
+	notificationpb.UnimplementedNotificationServiceServer
+
+	svc    service.Service
+	t      *translation.Translate
+	logger *slog.Logger
+}
+
+func NewHandler(svc service.Service, t *translation.Translate, logger *slog.Logger) Handler {
+	return Handler{
+		svc:    svc,
+		t:      t,
+		logger: logger,
+	}
+}
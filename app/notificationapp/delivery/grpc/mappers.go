@@ -0,0 +1,423 @@
+package grpc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	paginate "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset"
+	"github.com/syntaxfa/quick-connect/protobuf/notification/golang/notificationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func convertTimeToEntity(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	value := t.AsTime()
+
+	return &value
+}
+
+func convertTimeToPB(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func convertDynamicDataToEntity(data []byte) (service.DynamicData, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var dynamicData service.DynamicData
+	if uErr := json.Unmarshal(data, &dynamicData); uErr != nil {
+		return nil, uErr
+	}
+
+	return dynamicData, nil
+}
+
+func convertDynamicDataToPB(data service.DynamicData) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	jsonData, mErr := json.Marshal(data)
+	if mErr != nil {
+		return nil
+	}
+
+	return jsonData
+}
+
+func convertChannelsToEntity(channels []string) []service.ChannelDeliveryRequest {
+	channelDeliveries := make([]service.ChannelDeliveryRequest, 0, len(channels))
+	for _, channel := range channels {
+		channelDeliveries = append(channelDeliveries, service.ChannelDeliveryRequest{Channel: service.ChannelType(channel)})
+	}
+
+	return channelDeliveries
+}
+
+func convertSendNotificationRequestToEntity(req *notificationpb.SendNotificationRequest) (service.SendNotificationRequest, error) {
+	dynamicBodyData, bErr := convertDynamicDataToEntity(req.GetDynamicBodyData())
+	if bErr != nil {
+		return service.SendNotificationRequest{}, bErr
+	}
+
+	dynamicTitleData, tErr := convertDynamicDataToEntity(req.GetDynamicTitleData())
+	if tErr != nil {
+		return service.SendNotificationRequest{}, tErr
+	}
+
+	return service.SendNotificationRequest{
+		ExternalUserID:    req.GetExternalUserId(),
+		Type:              service.NotificationType(req.GetType()),
+		Data:              req.GetData(),
+		TemplateName:      req.GetTemplateName(),
+		DynamicBodyData:   dynamicBodyData,
+		DynamicTitleData:  dynamicTitleData,
+		ChannelDeliveries: convertChannelsToEntity(req.GetChannels()),
+		SendAt:            convertTimeToEntity(req.GetSendAt()),
+		IdempotencyKey:    req.GetIdempotencyKey(),
+	}, nil
+}
+
+func convertSendBulkRequestToEntity(req *notificationpb.SendBulkRequest) (service.SendBulkNotificationRequest, error) {
+	notifications := make([]service.SendNotificationRequest, 0, len(req.GetNotifications()))
+	for _, notification := range req.GetNotifications() {
+		request, cErr := convertSendNotificationRequestToEntity(notification)
+		if cErr != nil {
+			return service.SendBulkNotificationRequest{}, cErr
+		}
+
+		notifications = append(notifications, request)
+	}
+
+	return service.SendBulkNotificationRequest{Notifications: notifications}, nil
+}
+
+// convertSendBulkResponseToPB errorMessage converts the error of a failed notification to its message.
+func convertSendBulkResponseToPB(resp service.SendBulkNotificationResponse,
+	errorMessage func(err error) string) *notificationpb.SendBulkResponse {
+	results := make([]*notificationpb.SendBulkResult, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		pbResult := &notificationpb.SendBulkResult{Index: int32(result.Index)} //nolint:gosec // G115: bounded by SendBulkMaxSize
+		if result.Notification != nil {
+			pbResult.Notification = convertNotificationToPB(*result.Notification)
+		}
+
+		if result.Err != nil {
+			pbResult.Error = errorMessage(result.Err)
+		}
+
+		results = append(results, pbResult)
+	}
+
+	return &notificationpb.SendBulkResponse{
+		SentCount:   int32(resp.SentCount),   //nolint:gosec // G115: bounded by SendBulkMaxSize
+		FailedCount: int32(resp.FailedCount), //nolint:gosec // G115: bounded by SendBulkMaxSize
+		Results:     results,
+	}
+}
+
+func convertChannelDeliveryToPB(delivery service.ChannelDelivery) *notificationpb.ChannelDelivery {
+	var deliveryErr string
+	if delivery.Error != nil {
+		deliveryErr = *delivery.Error
+	}
+
+	return &notificationpb.ChannelDelivery{
+		Channel:       string(delivery.Channel),
+		Status:        string(delivery.Status),
+		LastAttemptAt: convertTimeToPB(delivery.LastAttemptAt),
+		AttemptCount:  int32(delivery.AttemptCount), //nolint:gosec // G115: attempt count is small
+		Error:         deliveryErr,
+		DeliveredAt:   convertTimeToPB(delivery.DeliveredAt),
+		OpenedAt:      convertTimeToPB(delivery.OpenedAt),
+		ClickedAt:     convertTimeToPB(delivery.ClickedAt),
+	}
+}
+
+func convertNotificationToPB(notification service.Notification) *notificationpb.Notification {
+	channelDeliveries := make([]*notificationpb.ChannelDelivery, 0, len(notification.ChannelDeliveries))
+	for _, delivery := range notification.ChannelDeliveries {
+		channelDeliveries = append(channelDeliveries, convertChannelDeliveryToPB(delivery))
+	}
+
+	var templateVersion int32
+	if notification.TemplateVersion != nil {
+		templateVersion = int32(*notification.TemplateVersion) //nolint:gosec // G115: template versions are small
+	}
+
+	return &notificationpb.Notification{
+		Id:                string(notification.ID),
+		UserId:            string(notification.UserID),
+		Type:              string(notification.Type),
+		Data:              notification.Data,
+		TemplateName:      notification.TemplateName,
+		TemplateVersion:   templateVersion,
+		DynamicBodyData:   convertDynamicDataToPB(notification.DynamicBodyData),
+		DynamicTitleData:  convertDynamicDataToPB(notification.DynamicTitleData),
+		IsRead:            notification.IsRead,
+		IsInApp:           notification.IsInApp,
+		CreatedAt:         timestamppb.New(notification.CreatedAt),
+		ChannelDeliveries: channelDeliveries,
+		OverallStatus:     string(notification.OverallStatus),
+		SendAt:            convertTimeToPB(notification.SendAt),
+		DispatchedAt:      convertTimeToPB(notification.DispatchedAt),
+		Category:          notification.Category,
+		IsArchived:        notification.IsArchived,
+		Topic:             notification.Topic,
+	}
+}
+
+func convertNotificationTypesToPB(notificationTypes []service.NotificationType) []string {
+	pbTypes := make([]string, 0, len(notificationTypes))
+	for _, notificationType := range notificationTypes {
+		pbTypes = append(pbTypes, string(notificationType))
+	}
+
+	return pbTypes
+}
+
+func convertUserSettingToPB(setting service.UserSetting) *notificationpb.UserSetting {
+	ignoreChannels := make([]*notificationpb.IgnoreChannel, 0, len(setting.IgnoreChannels))
+	for _, ignore := range setting.IgnoreChannels {
+		ignoreChannels = append(ignoreChannels, &notificationpb.IgnoreChannel{
+			Channel:           string(ignore.Channel),
+			NotificationTypes: convertNotificationTypesToPB(ignore.NotificationTypes),
+		})
+	}
+
+	digestPreferences := make([]*notificationpb.DigestPreference, 0, len(setting.DigestPreferences))
+	for _, preference := range setting.DigestPreferences {
+		digestPreferences = append(digestPreferences, &notificationpb.DigestPreference{
+			Channel:           string(preference.Channel),
+			NotificationTypes: convertNotificationTypesToPB(preference.NotificationTypes),
+			Frequency:         string(preference.Frequency),
+		})
+	}
+
+	quietHours := make([]*notificationpb.QuietHours, 0, len(setting.QuietHours))
+	for _, quiet := range setting.QuietHours {
+		quietHours = append(quietHours, &notificationpb.QuietHours{
+			Channel: string(quiet.Channel),
+			Start:   quiet.Start,
+			End:     quiet.End,
+		})
+	}
+
+	return &notificationpb.UserSetting{
+		Id:                string(setting.ID),
+		UserId:            string(setting.UserID),
+		Lang:              setting.Lang,
+		IgnoreChannels:    ignoreChannels,
+		DigestPreferences: digestPreferences,
+		Timezone:          setting.Timezone,
+		QuietHours:        quietHours,
+	}
+}
+
+func convertListNotificationsRequestToEntity(req *notificationpb.ListNotificationsRequest) service.ListNotificationRequest {
+	types := make([]service.NotificationType, 0, len(req.GetTypes()))
+	for _, notificationType := range req.GetTypes() {
+		types = append(types, service.NotificationType(notificationType))
+	}
+
+	return service.ListNotificationRequest{
+		ExternalUserID: req.GetExternalUserId(),
+		IsRead:         req.IsRead,
+		IsArchived:     req.GetIsArchived(),
+		Types:          types,
+		Category:       req.GetCategory(),
+		From:           convertTimeToEntity(req.GetFrom()),
+		To:             convertTimeToEntity(req.GetTo()),
+		Paginated: paginate.RequestBase{
+			CurrentPage: req.GetCurrentPage(),
+			PageSize:    req.GetPageSize(),
+			Descending:  true,
+		},
+	}
+}
+
+func convertListNotificationsResponseToPB(resp service.ListNotificationResponse) *notificationpb.ListNotificationsResponse {
+	results := make([]*notificationpb.NotificationMessage, 0, len(resp.Results))
+	for _, message := range resp.Results {
+		results = append(results, &notificationpb.NotificationMessage{
+			Id:         string(message.ID),
+			UserId:     string(message.UserID),
+			Type:       string(message.Type),
+			Data:       message.Data,
+			Title:      message.Title,
+			Body:       message.Body,
+			IsRead:     message.IsRead,
+			Timestamp:  message.Timestamp,
+			Category:   message.Category,
+			IsArchived: message.IsArchived,
+			Dir:        string(message.Dir),
+		})
+	}
+
+	return &notificationpb.ListNotificationsResponse{
+		CurrentPage: resp.Paginate.CurrentPage,
+		PageSize:    resp.Paginate.PageSize,
+		TotalNumber: resp.Paginate.TotalNumbers,
+		TotalPage:   resp.Paginate.TotalPage,
+		Results:     results,
+	}
+}
+
+func convertTemplateContentsToPB(contents []service.TemplateContent) []*notificationpb.TemplateContent {
+	pbContents := make([]*notificationpb.TemplateContent, 0, len(contents))
+	for _, content := range contents {
+		bodies := make([]*notificationpb.ContentBody, 0, len(content.Bodies))
+		for _, body := range content.Bodies {
+			bodies = append(bodies, &notificationpb.ContentBody{
+				Lang:  body.Lang,
+				Body:  body.Body,
+				Title: body.Title,
+			})
+		}
+
+		pbContents = append(pbContents, &notificationpb.TemplateContent{
+			Channel: string(content.Channel),
+			Layout:  content.Layout,
+			Bodies:  bodies,
+		})
+	}
+
+	return pbContents
+}
+
+func convertTemplateToPB(template service.Template) *notificationpb.Template {
+	return &notificationpb.Template{
+		Id:                 string(template.ID),
+		Name:               template.Name,
+		Kind:               string(template.Kind),
+		Version:            int32(template.Version), //nolint:gosec // G115: template versions are small
+		Contents:           convertTemplateContentsToPB(template.Contents),
+		CreatedAt:          timestamppb.New(template.CreatedAt),
+		UpdatedAt:          timestamppb.New(template.UpdatedAt),
+		Category:           template.Category,
+		Topic:              template.Topic,
+		DedupWindowSeconds: int32(template.DedupWindowSeconds), //nolint:gosec // G115: dedup window is at most one day
+	}
+}
+
+func convertListTemplatesRequestToEntity(req *notificationpb.ListTemplatesRequest) service.ListTemplateRequest {
+	return service.ListTemplateRequest{
+		Name: req.GetName(),
+		Kind: service.TemplateKind(req.GetKind()),
+		Paginated: paginate.RequestBase{
+			CurrentPage: req.GetCurrentPage(),
+			PageSize:    req.GetPageSize(),
+			Descending:  true,
+		},
+	}
+}
+
+func convertListTemplatesResponseToPB(resp service.ListTemplateResponse) *notificationpb.ListTemplatesResponse {
+	results := make([]*notificationpb.TemplateSummary, 0, len(resp.Results))
+	for _, template := range resp.Results {
+		results = append(results, &notificationpb.TemplateSummary{
+			Id:        string(template.ID),
+			Name:      template.Name,
+			Kind:      string(template.Kind),
+			Version:   int32(template.Version), //nolint:gosec // G115: template versions are small
+			CreatedAt: timestamppb.New(template.CreatedAt),
+			UpdatedAt: timestamppb.New(template.UpdatedAt),
+		})
+	}
+
+	return &notificationpb.ListTemplatesResponse{
+		CurrentPage: resp.Paginate.CurrentPage,
+		PageSize:    resp.Paginate.PageSize,
+		TotalNumber: resp.Paginate.TotalNumbers,
+		TotalPage:   resp.Paginate.TotalPage,
+		Results:     results,
+	}
+}
+
+func convertListTemplateVersionsResponseToPB(resp service.ListTemplateVersionResponse) *notificationpb.ListTemplateVersionsResponse {
+	results := make([]*notificationpb.TemplateVersion, 0, len(resp.Results))
+	for _, version := range resp.Results {
+		results = append(results, &notificationpb.TemplateVersion{
+			Id:         string(version.ID),
+			TemplateId: string(version.TemplateID),
+			Version:    int32(version.Version), //nolint:gosec // G115: template versions are small
+			Contents:   convertTemplateContentsToPB(version.Contents),
+			CreatedAt:  timestamppb.New(version.CreatedAt),
+		})
+	}
+
+	return &notificationpb.ListTemplateVersionsResponse{
+		TemplateId: string(resp.TemplateID),
+		Version:    int32(resp.Version), //nolint:gosec // G115: template versions are small
+		Results:    results,
+	}
+}
+
+func convertPreviewTemplateRequestToEntity(req *notificationpb.PreviewTemplateRequest) (service.PreviewTemplateRequest, error) {
+	dynamicTitleData, tErr := convertDynamicDataToEntity(req.GetDynamicTitleData())
+	if tErr != nil {
+		return service.PreviewTemplateRequest{}, tErr
+	}
+
+	dynamicBodyData, bErr := convertDynamicDataToEntity(req.GetDynamicBodyData())
+	if bErr != nil {
+		return service.PreviewTemplateRequest{}, bErr
+	}
+
+	var version *int
+	if req.Version != nil {
+		v := int(req.GetVersion())
+		version = &v
+	}
+
+	return service.PreviewTemplateRequest{
+		Version:          version,
+		Channel:          service.ChannelType(req.GetChannel()),
+		Lang:             req.GetLang(),
+		DynamicTitleData: dynamicTitleData,
+		DynamicBodyData:  dynamicBodyData,
+	}, nil
+}
+
+func convertPreviewTemplateResponseToPB(resp service.PreviewTemplateResponse) *notificationpb.PreviewTemplateResponse {
+	return &notificationpb.PreviewTemplateResponse{
+		TemplateId:       string(resp.TemplateID),
+		Name:             resp.Name,
+		Version:          int32(resp.Version), //nolint:gosec // G115: template versions are small
+		Channel:          string(resp.Channel),
+		Lang:             resp.Lang,
+		Title:            resp.Title,
+		Body:             resp.Body,
+		MissingVariables: resp.MissingVariables,
+		Dir:              string(resp.Dir),
+	}
+}
+
+func convertSendTestNotificationRequestToEntity(req *notificationpb.SendTestNotificationRequest) (
+	service.SendTestNotificationRequest, error) {
+	dynamicTitleData, tErr := convertDynamicDataToEntity(req.GetDynamicTitleData())
+	if tErr != nil {
+		return service.SendTestNotificationRequest{}, tErr
+	}
+
+	dynamicBodyData, bErr := convertDynamicDataToEntity(req.GetDynamicBodyData())
+	if bErr != nil {
+		return service.SendTestNotificationRequest{}, bErr
+	}
+
+	return service.SendTestNotificationRequest{
+		ExternalUserID:    req.GetExternalUserId(),
+		ChannelDeliveries: convertChannelsToEntity(req.GetChannels()),
+		DynamicTitleData:  dynamicTitleData,
+		DynamicBodyData:   dynamicBodyData,
+	}, nil
+}
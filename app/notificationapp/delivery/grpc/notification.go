@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/protobuf/notification/golang/notificationpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h Handler) SendNotification(ctx context.Context, req *notificationpb.SendNotificationRequest) (
+	*notificationpb.Notification, error) {
+	request, cErr := convertSendNotificationRequestToEntity(req)
+	if cErr != nil {
+		return nil, status.Error(codes.InvalidArgument, h.t.TranslateMessage(servermsg.MsgInvalidInput))
+	}
+
+	resp, sErr := h.svc.SendNotification(ctx, request)
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertNotificationToPB(resp), nil
+}
+
+func (h Handler) SendBulk(ctx context.Context, req *notificationpb.SendBulkRequest) (*notificationpb.SendBulkResponse, error) {
+	request, cErr := convertSendBulkRequestToEntity(req)
+	if cErr != nil {
+		return nil, status.Error(codes.InvalidArgument, h.t.TranslateMessage(servermsg.MsgInvalidInput))
+	}
+
+	resp, sErr := h.svc.SendBulkNotification(ctx, request)
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertSendBulkResponseToPB(resp, func(err error) string {
+		return status.Convert(servermsg.GRPCMsg(err, h.t, h.logger)).Message()
+	}), nil
+}
+
+func (h Handler) GetUserSetting(ctx context.Context, req *notificationpb.GetUserSettingRequest) (*notificationpb.UserSetting, error) {
+	resp, sErr := h.svc.GetUserSetting(ctx, req.GetExternalUserId())
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertUserSettingToPB(resp), nil
+}
+
+func (h Handler) ListNotifications(ctx context.Context, req *notificationpb.ListNotificationsRequest) (
+	*notificationpb.ListNotificationsResponse, error) {
+	resp, sErr := h.svc.FindNotificationByUserID(ctx, convertListNotificationsRequestToEntity(req))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertListNotificationsResponseToPB(resp), nil
+}
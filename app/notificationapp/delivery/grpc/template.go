@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/protobuf/notification/golang/notificationpb"
+	"github.com/syntaxfa/quick-connect/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h Handler) ListTemplates(ctx context.Context, req *notificationpb.ListTemplatesRequest) (
+	*notificationpb.ListTemplatesResponse, error) {
+	resp, sErr := h.svc.TemplateList(ctx, convertListTemplatesRequestToEntity(req))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertListTemplatesResponseToPB(resp), nil
+}
+
+func (h Handler) GetTemplate(ctx context.Context, req *notificationpb.GetTemplateRequest) (*notificationpb.Template, error) {
+	resp, sErr := h.svc.GetTemplate(ctx, types.ID(req.GetTemplateId()))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertTemplateToPB(resp), nil
+}
+
+func (h Handler) ListTemplateVersions(ctx context.Context, req *notificationpb.ListTemplateVersionsRequest) (
+	*notificationpb.ListTemplateVersionsResponse, error) {
+	resp, sErr := h.svc.ListTemplateVersions(ctx, types.ID(req.GetTemplateId()))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertListTemplateVersionsResponseToPB(resp), nil
+}
+
+func (h Handler) RollbackTemplate(ctx context.Context, req *notificationpb.RollbackTemplateRequest) (*notificationpb.Template, error) {
+	resp, sErr := h.svc.RollbackTemplate(ctx, types.ID(req.GetTemplateId()), int(req.GetVersion()))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertTemplateToPB(resp), nil
+}
+
+func (h Handler) PreviewTemplate(ctx context.Context, req *notificationpb.PreviewTemplateRequest) (
+	*notificationpb.PreviewTemplateResponse, error) {
+	request, cErr := convertPreviewTemplateRequestToEntity(req)
+	if cErr != nil {
+		return nil, status.Error(codes.InvalidArgument, h.t.TranslateMessage(servermsg.MsgInvalidInput))
+	}
+
+	resp, sErr := h.svc.PreviewTemplate(ctx, types.ID(req.GetTemplateId()), request)
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertPreviewTemplateResponseToPB(resp), nil
+}
+
+func (h Handler) SendTestNotification(ctx context.Context, req *notificationpb.SendTestNotificationRequest) (
+	*notificationpb.Notification, error) {
+	request, cErr := convertSendTestNotificationRequestToEntity(req)
+	if cErr != nil {
+		return nil, status.Error(codes.InvalidArgument, h.t.TranslateMessage(servermsg.MsgInvalidInput))
+	}
+
+	resp, sErr := h.svc.SendTestNotification(ctx, types.ID(req.GetTemplateId()), request)
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertNotificationToPB(resp), nil
+}
@@ -42,16 +42,41 @@ func (s AdminServer) registerRoutes() {
 
 	notifications := v1.Group("/notifications")
 	notifications.POST("", s.handler.sendNotification)
+	notifications.POST("/scheduled/list", s.handler.listScheduledNotifications)
+	notifications.POST("/:notificationID/cancel", s.handler.cancelScheduledNotification)
+	notifications.POST("/:notificationID/reschedule", s.handler.rescheduleNotification)
+	notifications.GET("/:notificationID/timeline", s.handler.getNotificationTimeline)
+
+	campaigns := v1.Group("/campaigns")
+	campaigns.POST("", s.handler.broadcast)
+	campaigns.POST("/list", s.handler.listCampaigns)
+	campaigns.GET("/:campaignID", s.handler.getCampaign)
+	campaigns.POST("/:campaignID/cancel", s.handler.cancelCampaign)
 
 	templates := v1.Group("/templates")
 	templates.POST("", s.handler.createTemplate)
 	templates.POST("/list", s.handler.ListTemplate)
 	templates.PUT("/:templateID", s.handler.updateTemplate)
 	templates.GET("/:templateID", s.handler.getDetailTemplate)
+	templates.GET("/:templateID/language-warnings", s.handler.getTemplateLanguageWarnings)
+	templates.GET("/:templateID/versions", s.handler.listTemplateVersions)
+	templates.POST("/:templateID/versions/:version/rollback", s.handler.rollbackTemplate)
+	templates.POST("/:templateID/preview", s.handler.previewTemplate)
+	templates.POST("/:templateID/test", s.handler.sendTestNotification)
+
+	webhooks := v1.Group("/webhooks")
+	webhooks.POST("/:provider", s.handler.providerWebhook)
 
 	settings := v1.Group("/settings")
 	settings.POST("/:externalUserID", s.handler.updateUserSettingAdmin)
 	settings.GET("/:externalUserID", s.handler.getUserSettingAdmin)
+	settings.GET("/:externalUserID/preferences", s.handler.getUserPreferencesAdmin)
+
+	topics := v1.Group("/topics")
+	topics.POST("", s.handler.createTopic)
+	topics.GET("", s.handler.listTopics)
+	topics.PUT("/:topicID", s.handler.updateTopic)
+	topics.DELETE("/:topicID", s.handler.deleteTopic)
 }
 
 func (s AdminServer) registerSwagger() {
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// broadcast docs
+// @Router /v1/campaigns [POST]
+// @Summary broadcast notification
+// @Description This API endpoint creates a campaign that sends a notification to a list of users or to users of a CSV file.
+// @Description The first column of the CSV file must be the external user id.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param Request body service.BroadcastRequest true "broadcast campaign"
+// @Success 201 {object} service.Campaign
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} template not found
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) broadcast(c echo.Context) error {
+	var req service.BroadcastRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.Broadcast(c.Request().Context(), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// listCampaigns docs
+// @Router /v1/campaigns/list [POST]
+// @Summary list campaigns
+// @Description This API endpoint lists broadcast campaigns, optionally filtered by status.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param Request body service.ListCampaignRequest true "campaign list"
+// @Success 200 {object} service.ListCampaignResponse
+// @Failure 400 {string} string Bad Request
+// @Failure 500 {string} something went wrong.
+func (h Handler) listCampaigns(c echo.Context) error {
+	var req service.ListCampaignRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.CampaignList(c.Request().Context(), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getCampaign docs
+// @Router /v1/campaigns/{campaignID} [GET]
+// @Summary campaign progress
+// @Description This API endpoint returns a campaign with its queued, sent, failed and skipped counts.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param campaignID path string true "ID of the campaign"
+// @Success 200 {object} service.Campaign
+// @Failure 404 {string} the campaign with this campaignID does not exist
+// @Failure 500 {string} something went wrong.
+func (h Handler) getCampaign(c echo.Context) error {
+	resp, sErr := h.svc.GetCampaign(c.Request().Context(), types.ID(c.Param("campaignID")))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// cancelCampaign docs
+// @Router /v1/campaigns/{campaignID}/cancel [POST]
+// @Summary cancel campaign
+// @Description This API endpoint cancels a queued or running campaign, remaining recipients are not processed.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param campaignID path string true "ID of the campaign"
+// @Success 200 {object} service.Campaign
+// @Failure 404 {string} the campaign with this campaignID does not exist
+// @Failure 409 {string} the campaign is not queued or running
+// @Failure 500 {string} something went wrong.
+func (h Handler) cancelCampaign(c echo.Context) error {
+	resp, sErr := h.svc.CancelCampaign(c.Request().Context(), types.ID(c.Param("campaignID")))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
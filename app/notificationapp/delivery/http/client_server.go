@@ -3,31 +3,27 @@ package http
 import (
 	"context"
 	"log/slog"
-	"net/http"
-	"time"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/docs"
 	"github.com/syntaxfa/quick-connect/pkg/httpserver"
-)
-
-const (
-	clientTimeoutInSeconds = 10
+	"github.com/syntaxfa/quick-connect/pkg/identityresolver"
 )
 
 type ClientServer struct {
-	httpServer        httpserver.Server
-	handler           Handler
-	getExternalUserID string
-	logger            *slog.Logger
+	httpServer       httpserver.Server
+	handler          Handler
+	identityResolver identityresolver.Resolver
+	logger           *slog.Logger
 }
 
-func NewClientServer(httpServer httpserver.Server, handler Handler, getExternalUserID string, logger *slog.Logger) ClientServer {
+func NewClientServer(httpServer httpserver.Server, handler Handler, identityResolver identityresolver.Resolver,
+	logger *slog.Logger) ClientServer {
 	return ClientServer{
-		httpServer:        httpServer,
-		handler:           handler,
-		getExternalUserID: getExternalUserID,
-		logger:            logger,
+		httpServer:       httpServer,
+		handler:          handler,
+		identityResolver: identityResolver,
+		logger:           logger,
 	}
 }
 
@@ -48,19 +44,31 @@ func (s ClientServer) registerRoutes() {
 
 	v1 := s.httpServer.Router.Group("/v1")
 
-	httpClient := &http.Client{Timeout: time.Second * clientTimeoutInSeconds}
+	identified := validateExternalToken(s.identityResolver)
 
 	notifications := v1.Group("/notifications")
-	notifications.POST("/list", s.handler.findNotifications, validateExternalToken(s.getExternalUserID, s.logger, httpClient))
-	notifications.GET("/:notificationID/mark-as-read", s.handler.markNotificationAsRead,
-		validateExternalToken(s.getExternalUserID, s.logger, httpClient))
-	notifications.GET("/mark-all-as-read", s.handler.markAllNotificationAsRead,
-		validateExternalToken(s.getExternalUserID, s.logger, httpClient))
-	notifications.GET("/ws", s.handler.wsNotification, validateExternalToken(s.getExternalUserID, s.logger, httpClient))
+	notifications.POST("/list", s.handler.findNotifications, identified)
+	notifications.GET("/:notificationID/mark-as-read", s.handler.markNotificationAsRead, identified)
+	notifications.GET("/mark-all-as-read", s.handler.markAllNotificationAsRead, identified)
+	notifications.GET("/unread-count", s.handler.getUnreadCount, identified)
+	notifications.POST("/:notificationID/archive", s.handler.archiveNotification, identified)
+	notifications.POST("/:notificationID/unarchive", s.handler.unarchiveNotification, identified)
+	notifications.DELETE("/:notificationID", s.handler.deleteNotification, identified)
+	notifications.GET("/ws", s.handler.wsNotification, identified)
+
+	// Tracking links are opened by the mail clients, they are verified by their signature.
+	tracking := v1.Group("/tracking")
+	tracking.GET("/:notificationID/open", s.handler.trackEmailOpen)
+	tracking.GET("/:notificationID/click", s.handler.trackEmailClick)
 
 	settings := v1.Group("/settings")
-	settings.GET("", s.handler.getUserSettingClient, validateExternalToken(s.getExternalUserID, s.logger, httpClient))
-	settings.POST("", s.handler.updateUserSettingClient, validateExternalToken(s.getExternalUserID, s.logger, httpClient))
+	settings.GET("", s.handler.getUserSettingClient, identified)
+	settings.POST("", s.handler.updateUserSettingClient, identified)
+	settings.GET("/preferences", s.handler.getUserPreferencesClient, identified)
+
+	unsubscribe := v1.Group("/unsubscribe")
+	unsubscribe.GET("", s.handler.unsubscribeConfirmation)
+	unsubscribe.POST("", s.handler.unsubscribe)
 }
 
 func (s ClientServer) registerSwagger() {
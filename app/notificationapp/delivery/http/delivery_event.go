@@ -0,0 +1,186 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	providerSendGrid = "sendgrid"
+	providerTwilio   = "twilio"
+)
+
+// providerWebhook docs
+// @Router /v1/webhooks/{provider} [POST]
+// @Summary provider webhook
+// @Description This API endpoint receives the delivery events of the email, SMS and push providers. sendgrid and twilio
+// @Description payloads are supported, other providers must send a list of service.DeliveryEventRequest. The token of
+// @Description the provider is sent by the token query parameter or the X-Webhook-Token header. The sms sender must set
+// @Description the twilio status callback to this endpoint with the token and the notification_id query parameters.
+// @Description The events are applied independently, when an event fails the webhook responds with 500, so the provider
+// @Description retries it, the events with a provider event id are applied once.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param provider path string true "name of the provider"
+// @Param token query string false "token of the provider"
+// @Success 200 {object} service.HandleWebhookResponse
+// @Failure 400 {string} string Bad Request
+// @Failure 401 {string} the webhook token is not valid
+// @Failure 500 {string} something went wrong.
+func (h Handler) providerWebhook(c echo.Context) error {
+	provider := c.Param("provider")
+
+	token := c.QueryParam("token")
+	if token == "" {
+		token = c.Request().Header.Get("X-Webhook-Token")
+	}
+
+	if vErr := h.svc.VerifyWebhookToken(provider, token); vErr != nil {
+		return servermsg.HTTPMsg(c, vErr, h.t)
+	}
+
+	var events []service.DeliveryEventRequest
+	var pErr error
+	switch provider {
+	case providerSendGrid:
+		events, pErr = parseSendGridEvents(c)
+	case providerTwilio:
+		events, pErr = parseTwilioEvents(c)
+	default:
+		pErr = json.NewDecoder(c.Request().Body).Decode(&events)
+	}
+
+	if pErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.HandleDeliveryEvents(c.Request().Context(), provider, events)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getNotificationTimeline docs
+// @Router /v1/notifications/{notificationID}/timeline [GET]
+// @Summary notification timeline
+// @Description This API endpoint returns the channel deliveries of a notification and its events ordered by time.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "ID of the notification"
+// @Success 200 {object} service.NotificationTimelineResponse
+// @Failure 404 {string} the notification with this notificationID does not exist
+// @Failure 500 {string} something went wrong.
+func (h Handler) getNotificationTimeline(c echo.Context) error {
+	resp, sErr := h.svc.GetNotificationTimeline(c.Request().Context(), types.ID(c.Param("notificationID")))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// sendGridEvent notification_id is a custom argument of the sent email.
+type sendGridEvent struct {
+	EventID        string `json:"sg_event_id"`
+	Event          string `json:"event"`
+	Timestamp      int64  `json:"timestamp"`
+	NotificationID string `json:"notification_id"`
+	Reason         string `json:"reason"`
+	URL            string `json:"url"`
+}
+
+var sendGridEventTypes = map[string]service.DeliveryEventType{
+	"processed": service.DeliveryEventSent,
+	"deferred":  service.DeliveryEventDeferred,
+	"delivered": service.DeliveryEventDelivered,
+	"bounce":    service.DeliveryEventBounced,
+	"dropped":   service.DeliveryEventFailed,
+	"open":      service.DeliveryEventOpened,
+	"click":     service.DeliveryEventClicked,
+}
+
+func parseSendGridEvents(c echo.Context) ([]service.DeliveryEventRequest, error) {
+	var payload []sendGridEvent
+	if dErr := json.NewDecoder(c.Request().Body).Decode(&payload); dErr != nil {
+		return nil, dErr
+	}
+
+	events := make([]service.DeliveryEventRequest, 0, len(payload))
+	for _, event := range payload {
+		eventType, ok := sendGridEventTypes[event.Event]
+		if !ok {
+			continue
+		}
+
+		var eventErr *string
+		if event.Reason != "" {
+			eventErr = &event.Reason
+		}
+
+		var occurredAt time.Time
+		if event.Timestamp > 0 {
+			occurredAt = time.Unix(event.Timestamp, 0)
+		}
+
+		events = append(events, service.DeliveryEventRequest{
+			NotificationID: types.ID(event.NotificationID),
+			Channel:        service.ChannelTypeEmail,
+			Type:           eventType,
+			URL:            event.URL,
+			Error:          eventErr,
+			OccurredAt:     occurredAt,
+
+			ProviderEventID: event.EventID,
+		})
+	}
+
+	return events, nil
+}
+
+var twilioEventTypes = map[string]service.DeliveryEventType{
+	"sent":        service.DeliveryEventSent,
+	"delivered":   service.DeliveryEventDelivered,
+	"undelivered": service.DeliveryEventFailed,
+	"failed":      service.DeliveryEventFailed,
+}
+
+// parseTwilioEvents twilio sends the status callback as a form, notification_id is a query parameter of
+// the status callback url of the sent message, so the sms sender must set the StatusCallback of the message to
+// /v1/webhooks/twilio?token={token}&notification_id={notification id}. A message has one callback of each status,
+// so the sid and the status of the message are the id of the event.
+func parseTwilioEvents(c echo.Context) ([]service.DeliveryEventRequest, error) {
+	params, fErr := c.FormParams()
+	if fErr != nil {
+		return nil, fErr
+	}
+
+	eventType, ok := twilioEventTypes[params.Get("MessageStatus")]
+	if !ok {
+		return []service.DeliveryEventRequest{}, nil
+	}
+
+	var eventErr *string
+	if code := params.Get("ErrorCode"); code != "" {
+		message := "twilio error code " + code
+		eventErr = &message
+	}
+
+	return []service.DeliveryEventRequest{{
+		NotificationID: types.ID(c.QueryParam("notification_id")),
+		Channel:        service.ChannelTypeSMS,
+		Type:           eventType,
+		Error:          eventErr,
+
+		ProviderEventID: params.Get("MessageSid") + ":" + params.Get("MessageStatus"),
+	}}, nil
+}
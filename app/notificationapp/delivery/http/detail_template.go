@@ -27,3 +27,22 @@ func (h Handler) getDetailTemplate(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+// getTemplateLanguageWarnings docs
+// @Router /v1/templates/{templateID}/language-warnings [GET]
+// @Summary template language warnings
+// @Description This API endpoint returns the languages of the users that the template contents don't have, these users receive the fallback language.
+// @Tags NotificationAdmin
+// @Produce json
+// @Param templateID path string true "ID of the template"
+// @Success 200 {object} service.TemplateLanguageWarningsResponse
+// @Failure 404 {string} the template with this templateID does not exist
+// @Failure 500 {string} something went wrong.
+func (h Handler) getTemplateLanguageWarnings(c echo.Context) error {
+	resp, sErr := h.svc.TemplateLanguageWarnings(c.Request().Context(), types.ID(c.Param("templateID")))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// ArchiveNotification docs
+// @Summary archive notification
+// @Description archived notifications are not listed and counted unless they are requested.
+// @Tags NotificationClient
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "notification id"
+// @Success 200 {string} string "archived"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} something went wrong
+// @Router /v1/notifications/{notificationID}/archive [POST].
+func (h Handler) archiveNotification(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
+	}
+
+	if sErr := h.svc.ArchiveNotification(c.Request().Context(), types.ID(c.Param("notificationID")), userID); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, "")
+}
+
+// UnarchiveNotification docs
+// @Summary unarchive notification
+// @Description move an archived notification back to the inbox.
+// @Tags NotificationClient
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "notification id"
+// @Success 200 {string} string "unarchived"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} something went wrong
+// @Router /v1/notifications/{notificationID}/unarchive [POST].
+func (h Handler) unarchiveNotification(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
+	}
+
+	if sErr := h.svc.UnarchiveNotification(c.Request().Context(), types.ID(c.Param("notificationID")), userID); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, "")
+}
+
+// DeleteNotification docs
+// @Summary delete notification
+// @Description delete a notification of the user.
+// @Tags NotificationClient
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "notification id"
+// @Success 200 {string} string "deleted"
+// @Failure 404 {string} string "notification not found"
+// @Failure 500 {string} something went wrong
+// @Router /v1/notifications/{notificationID} [DELETE].
+func (h Handler) deleteNotification(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
+	}
+
+	if sErr := h.svc.DeleteNotification(c.Request().Context(), types.ID(c.Param("notificationID")), userID); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, "")
+}
+
+// GetUnreadCount docs
+// @Summary get unread count
+// @Description get the unread notifications count of the user in total and per category,
+// @Description the count is also pushed on the notification websocket whenever it changes.
+// @Tags NotificationClient
+// @Accept json
+// @Produce json
+// @Success 200 {object} service.UnreadCountResponse
+// @Failure 500 {string} something went wrong
+// @Router /v1/notifications/unread-count [GET].
+func (h Handler) getUnreadCount(c echo.Context) error {
+	userID, ok := c.Get("user_id").(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
+	}
+
+	resp, sErr := h.svc.GetUnreadCount(c.Request().Context(), userID)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// getUserPreferencesAdmin docs
+// @Router /v1/settings/{externalUserID}/preferences [GET]
+// @Summary retrieve user effective preferences
+// @Description retrieve the effective preference of the user on every topic and channel
+// @Tags NotificationAdmin
+// @Produce json
+// @Param externalUserID path string true "external user id"
+// @Success 200 {object} service.UserPreferences
+// @Failure 500 {string} something went wrong.
+func (h Handler) getUserPreferencesAdmin(c echo.Context) error {
+	resp, sErr := h.svc.GetUserPreferences(c.Request().Context(), c.Param("externalUserID"))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getUserPreferencesClient docs
+// @Router /v1/settings/preferences [GET]
+// @Summary retrieve user effective preferences
+// @Description retrieve the effective preference of the user on every topic and channel, preferences are
+// @Description updated by the topic preferences of the user setting.
+// @Tags NotificationClient
+// @Produce json
+// @Success 200 {object} service.UserPreferences
+// @Success 401 {string} unauthorized
+// @Failure 500 {string} something went wrong.
+func (h Handler) getUserPreferencesClient(c echo.Context) error {
+	externalUserID, ok := c.Get("user_id").(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
+	}
+
+	resp, sErr := h.svc.GetUserPreferences(c.Request().Context(), externalUserID)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// unsubscribeConfirmation docs
+// @Router /v1/unsubscribe [GET]
+// @Summary unsubscribe confirmation
+// @Description This API endpoint is the signed unsubscribe link of the emails, it renders a page that confirms the
+// @Description unsubscribe by a POST to the same link, so the link scanners of the mail clients don't unsubscribe the user.
+// @Tags NotificationClient
+// @Produce html
+// @Param user_id query string true "ID of the user"
+// @Param topic query string true "name of the topic"
+// @Param channel query string true "channel of the topic"
+// @Param sig query string true "signature of the unsubscribe link"
+// @Success 200 {string} the unsubscribe confirmation page
+// @Failure 400 {string} the unsubscribe link is not valid
+func (h Handler) unsubscribeConfirmation(c echo.Context) error {
+	if vErr := h.svc.VerifyUnsubscribeLink(unsubscribeRequest(c)); vErr != nil {
+		return servermsg.HTTPMsg(c, vErr, h.t)
+	}
+
+	page := fmt.Sprintf(`<!DOCTYPE html><html><body style="font-family:sans-serif;text-align:center">`+
+		`<form method="post" action="%s"><p>%s</p><button type="submit">%s</button></form></body></html>`,
+		html.EscapeString(c.Request().URL.RequestURI()),
+		html.EscapeString(h.t.TranslateMessage(servermsg.MsgUnsubscribeConfirmation)),
+		html.EscapeString(h.t.TranslateMessage(servermsg.MsgUnsubscribe)))
+
+	return c.HTML(http.StatusOK, page)
+}
+
+// unsubscribe docs
+// @Router /v1/unsubscribe [POST]
+// @Summary one-click unsubscribe
+// @Description This API endpoint opts the user out of the topic on the channel of a signed unsubscribe link, it is the
+// @Description one-click unsubscribe of the List-Unsubscribe-Post header and the submit of the confirmation page.
+// @Tags NotificationClient
+// @Produce json
+// @Param user_id query string true "ID of the user"
+// @Param topic query string true "name of the topic"
+// @Param channel query string true "channel of the topic"
+// @Param sig query string true "signature of the unsubscribe link"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} the unsubscribe link is not valid
+// @Failure 500 {string} something went wrong.
+func (h Handler) unsubscribe(c echo.Context) error {
+	if sErr := h.svc.Unsubscribe(c.Request().Context(), unsubscribeRequest(c)); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": h.t.TranslateMessage(servermsg.MsgUnsubscribed)})
+}
+
+func unsubscribeRequest(c echo.Context) service.UnsubscribeRequest {
+	return service.UnsubscribeRequest{
+		UserID:    types.ID(c.QueryParam("user_id")),
+		Topic:     c.QueryParam("topic"),
+		Channel:   service.ChannelType(c.QueryParam("channel")),
+		Signature: c.QueryParam("sig"),
+	}
+}
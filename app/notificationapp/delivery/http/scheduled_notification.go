@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// cancelScheduledNotification docs
+// @Router /v1/notifications/{notificationID}/cancel [POST]
+// @Summary cancel scheduled notification
+// @Description This API endpoint cancels a scheduled notification before it is dispatched.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "ID of the scheduled notification"
+// @Success 200
+// @Failure 404 {string} the notification with this notificationID does not exist
+// @Failure 409 {string} the notification is not scheduled
+// @Failure 500 {string} something went wrong.
+func (h Handler) cancelScheduledNotification(c echo.Context) error {
+	if sErr := h.svc.CancelScheduledNotification(c.Request().Context(), types.ID(c.Param("notificationID"))); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// rescheduleNotification docs
+// @Router /v1/notifications/{notificationID}/reschedule [POST]
+// @Summary reschedule notification
+// @Description This API endpoint changes the send time of a scheduled or canceled notification.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param notificationID path string true "ID of the scheduled notification"
+// @Param Request body service.RescheduleNotificationRequest true "new send time"
+// @Success 200 {object} service.Notification
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} the notification with this notificationID does not exist
+// @Failure 409 {string} the notification is not scheduled
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) rescheduleNotification(c echo.Context) error {
+	var req service.RescheduleNotificationRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.RescheduleNotification(c.Request().Context(), types.ID(c.Param("notificationID")), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// listScheduledNotifications docs
+// @Router /v1/notifications/scheduled/list [POST]
+// @Summary list scheduled notifications
+// @Description This API endpoint lists scheduled notifications, optionally filtered by status.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param Request body service.ListScheduledNotificationRequest true "scheduled notification list"
+// @Success 200 {object} service.ListScheduledNotificationResponse
+// @Failure 400 {string} string Bad Request
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) listScheduledNotifications(c echo.Context) error {
+	var req service.ListScheduledNotificationRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.ListScheduledNotifications(c.Request().Context(), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -14,9 +14,11 @@ import (
 // @Tags NotificationAdmin
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "used when the idempotency_key field of the body is empty"
 // @Param Request body service.SendNotificationRequest true "notification body"
 // @Success 201 {object} service.Notification
 // @Failure 400 {string} string Bad Request
+// @Failure 409 {string} string notification with this idempotency key is in progress
 // @Failure 422 {object} servermsg.ErrorResponse
 // @Failure 500 {string} something went wrong
 // @Router /v1/notifications [POST].
@@ -27,6 +29,10 @@ func (h Handler) sendNotification(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = c.Request().Header.Get("Idempotency-Key")
+	}
+
 	resp, sErr := h.svc.SendNotification(c.Request().Context(), req)
 	if sErr != nil {
 		return servermsg.HTTPMsg(c, sErr, h.t)
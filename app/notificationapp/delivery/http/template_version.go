@@ -0,0 +1,113 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// listTemplateVersions docs
+// @Router /v1/templates/{templateID}/versions [GET]
+// @Summary list template versions
+// @Description This API endpoint returns all versions of a template, the newest version is first.
+// @Tags NotificationAdmin
+// @Produce json
+// @Param templateID path string true "ID of the template"
+// @Success 200 {object} service.ListTemplateVersionResponse
+// @Failure 404 {string} the template with this templateID does not exist
+// @Failure 500 {string} something went wrong.
+func (h Handler) listTemplateVersions(c echo.Context) error {
+	resp, sErr := h.svc.ListTemplateVersions(c.Request().Context(), types.ID(c.Param("templateID")))
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// rollbackTemplate docs
+// @Router /v1/templates/{templateID}/versions/{version}/rollback [POST]
+// @Summary rollback template
+// @Description This API endpoint creates a new version of the template with the contents of an old version.
+// @Tags NotificationAdmin
+// @Produce json
+// @Param templateID path string true "ID of the template"
+// @Param version path int true "version to rollback to"
+// @Success 200 {object} service.Template
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} the template or version does not exist
+// @Failure 409 {string} the template is already in this version
+// @Failure 500 {string} something went wrong.
+func (h Handler) rollbackTemplate(c echo.Context) error {
+	version, pErr := strconv.Atoi(c.Param("version"))
+	if pErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.RollbackTemplate(c.Request().Context(), types.ID(c.Param("templateID")), version)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// previewTemplate docs
+// @Router /v1/templates/{templateID}/preview [POST]
+// @Summary preview template
+// @Description This API endpoint renders a template version for a channel and language with sample data and reports missing variables.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param templateID path string true "ID of the template"
+// @Param Request body service.PreviewTemplateRequest true "preview"
+// @Success 200 {object} service.PreviewTemplateResponse
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} the template or version does not exist
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) previewTemplate(c echo.Context) error {
+	var req service.PreviewTemplateRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.PreviewTemplate(c.Request().Context(), types.ID(c.Param("templateID")), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// sendTestNotification docs
+// @Router /v1/templates/{templateID}/test [POST]
+// @Summary send test notification
+// @Description This API endpoint sends the current version of a template to an external user as a direct notification.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param templateID path string true "ID of the template"
+// @Param Request body service.SendTestNotificationRequest true "test notification"
+// @Success 201 {object} service.Notification
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} the template with this templateID does not exist
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) sendTestNotification(c echo.Context) error {
+	var req service.SendTestNotificationRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.SendTestNotification(c.Request().Context(), types.ID(c.Param("templateID")), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
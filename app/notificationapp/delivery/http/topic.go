@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// createTopic docs
+// @Router /v1/topics [POST]
+// @Summary create topic
+// @Description This API endpoint creates a topic, templates are mapped to a topic and users can opt in or out of it per channel.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param Request body service.AddTopicRequest true "topic"
+// @Success 201 {object} service.Topic
+// @Failure 400 {string} string Bad Request
+// @Failure 409 {string} the name of topic has conflict
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) createTopic(c echo.Context) error {
+	var req service.AddTopicRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.AddTopic(c.Request().Context(), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// listTopics docs
+// @Router /v1/topics [GET]
+// @Summary list topics
+// @Description This API endpoint lists all topics.
+// @Tags NotificationAdmin
+// @Produce json
+// @Success 200 {object} service.ListTopicResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) listTopics(c echo.Context) error {
+	resp, sErr := h.svc.ListTopics(c.Request().Context())
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// updateTopic docs
+// @Router /v1/topics/{topicID} [PUT]
+// @Summary update topic
+// @Description This API endpoint updates the title and description of a topic, the name of a topic can't be changed.
+// @Tags NotificationAdmin
+// @Accept json
+// @Produce json
+// @Param topicID path string true "ID of the topic"
+// @Param Request body service.AddTopicRequest true "topic"
+// @Success 200 {object} service.Topic
+// @Failure 400 {string} string Bad Request
+// @Failure 404 {string} the topic with this topicID does not exist
+// @Failure 422 {object} servermsg.ErrorResponse
+// @Failure 500 {string} something went wrong.
+func (h Handler) updateTopic(c echo.Context) error {
+	var req service.AddTopicRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	resp, sErr := h.svc.UpdateTopic(c.Request().Context(), types.ID(c.Param("topicID")), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// deleteTopic docs
+// @Router /v1/topics/{topicID} [DELETE]
+// @Summary delete topic
+// @Description This API endpoint deletes a topic and removes it from its templates.
+// @Tags NotificationAdmin
+// @Produce json
+// @Param topicID path string true "ID of the topic"
+// @Success 204
+// @Failure 404 {string} the topic with this topicID does not exist
+// @Failure 500 {string} something went wrong.
+func (h Handler) deleteTopic(c echo.Context) error {
+	if sErr := h.svc.DeleteTopic(c.Request().Context(), types.ID(c.Param("topicID"))); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
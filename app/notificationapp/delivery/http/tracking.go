@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// trackingPixel is a transparent 1x1 GIF image.
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// trackEmailOpen docs
+// @Router /v1/tracking/{notificationID}/open [GET]
+// @Summary email open tracking
+// @Description This API endpoint is the tracking pixel of the emails, the pixel is returned even if the signature is not valid.
+// @Tags NotificationClient
+// @Produce image/gif
+// @Param notificationID path string true "ID of the notification"
+// @Param sig query string true "signature of the tracking link"
+// @Success 200
+func (h Handler) trackEmailOpen(c echo.Context) error {
+	_ = h.svc.TrackEmailOpen(c.Request().Context(), types.ID(c.Param("notificationID")), c.QueryParam("sig"))
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+
+	return c.Blob(http.StatusOK, "image/gif", trackingPixel)
+}
+
+// trackEmailClick docs
+// @Router /v1/tracking/{notificationID}/click [GET]
+// @Summary email click tracking
+// @Description This API endpoint records the click of an email link and redirects to it.
+// @Tags NotificationClient
+// @Param notificationID path string true "ID of the notification"
+// @Param url query string true "link of the email"
+// @Param sig query string true "signature of the tracking link"
+// @Success 302
+// @Failure 400 {string} the tracking link is not valid
+func (h Handler) trackEmailClick(c echo.Context) error {
+	link := c.QueryParam("url")
+
+	if tErr := h.svc.TrackEmailClick(c.Request().Context(), types.ID(c.Param("notificationID")), link,
+		c.QueryParam("sig")); tErr != nil {
+		return servermsg.HTTPMsg(c, tErr, h.t)
+	}
+
+	return c.Redirect(http.StatusFound, link)
+}
@@ -11,7 +11,8 @@ import (
 // updateUserSettingAdmin docs
 // @Router /v1/settings/{externalUserID} [POST]
 // @Summary update user setting
-// @Description This API endpoint updates user notification settings
+// @Description This API endpoint updates user notification settings, including the timezone and quiet hours per channel.
+// @Description Non-critical notifications that fall in quiet hours are deferred until the quiet window ends.
 // @Tags NotificationAdmin
 // @Accept json
 // @Produce json
@@ -38,7 +39,8 @@ func (h Handler) updateUserSettingAdmin(c echo.Context) error {
 // updateUserSettingClient docs
 // @Router /v1/settings [POST]
 // @Summary update user setting
-// @Description This API endpoint updates user notification settings
+// @Description This API endpoint updates user notification settings, including the timezone and quiet hours per channel.
+// @Description Non-critical notifications that fall in quiet hours are deferred until the quiet window ends.
 // @Tags NotificationClient
 // @Accept json
 // @Produce json
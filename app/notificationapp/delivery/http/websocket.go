@@ -2,14 +2,22 @@ package http
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
+// wsNotification a reconnected client sends the seq of its last received notification as last_seq query param
+// to receive the missed notifications.
 func (h Handler) wsNotification(c echo.Context) error {
-	conn, uErr := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
-	if uErr != nil {
-		return echo.NewHTTPError(http.StatusNotAcceptable, "could not upgrade connection")
+	var lastSeq *int64
+	if value := c.QueryParam("last_seq"); value != "" {
+		seq, pErr := strconv.ParseInt(value, 10, 64)
+		if pErr != nil || seq < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "last seq is not valid")
+		}
+
+		lastSeq = &seq
 	}
 
 	userID, ok := c.Get("user_id").(string)
@@ -17,7 +25,12 @@ func (h Handler) wsNotification(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "user id is not valid")
 	}
 
-	h.svc.JoinClient(c.Request().Context(), conn, userID)
+	conn, uErr := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if uErr != nil {
+		return echo.NewHTTPError(http.StatusNotAcceptable, "could not upgrade connection")
+	}
+
+	h.svc.JoinClient(c.Request().Context(), conn, userID, lastSeq)
 
 	return nil
 }
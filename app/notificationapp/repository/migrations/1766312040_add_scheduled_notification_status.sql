@@ -0,0 +1,6 @@
+-- +migrate Up notransaction
+ALTER TYPE notification_status ADD VALUE IF NOT EXISTS 'scheduled';
+ALTER TYPE notification_status ADD VALUE IF NOT EXISTS 'canceled';
+
+-- +migrate Down
+-- PostgreSQL does not support removing values from an enum type.
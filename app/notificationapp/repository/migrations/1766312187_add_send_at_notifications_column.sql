@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "send_at" TIMESTAMPTZ NULL;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "is_scheduled" BOOL DEFAULT false;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "dispatched_at" TIMESTAMPTZ NULL;
+CREATE INDEX idx_send_at_scheduled_notifications ON notifications(send_at) WHERE overall_status = 'scheduled';
+CREATE INDEX idx_is_scheduled_notifications ON notifications(is_scheduled) WHERE is_scheduled IS true;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_is_scheduled_notifications;
+DROP INDEX IF EXISTS idx_send_at_scheduled_notifications;
+ALTER TABLE notifications DROP COLUMN IF EXISTS "dispatched_at";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "is_scheduled";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "send_at";
@@ -0,0 +1,7 @@
+-- +migrate Up
+CREATE TYPE campaign_status AS ENUM ('queued', 'running', 'completed', 'canceled');
+CREATE TYPE campaign_recipient_status AS ENUM ('pending', 'processing', 'sent', 'failed', 'skipped');
+
+-- +migrate Down
+DROP TYPE campaign_recipient_status;
+DROP TYPE campaign_status;
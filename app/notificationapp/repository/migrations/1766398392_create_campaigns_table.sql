@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS campaigns (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "type" notification_type NOT NULL,
+    "data" JSONB NULL,
+    "template_name" VARCHAR(255) NOT NULL,
+    "dynamic_body_data" JSONB NULL,
+    "dynamic_title_data" JSONB NULL,
+    "channel_deliveries" JSONB NOT NULL DEFAULT '[]'::jsonb,
+    "file_id" VARCHAR(26) NULL,
+    "status" campaign_status NOT NULL DEFAULT 'queued',
+    "total_count" INT NOT NULL DEFAULT 0,
+    "sent_count" INT NOT NULL DEFAULT 0,
+    "failed_count" INT NOT NULL DEFAULT 0,
+    "skipped_count" INT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "completed_at" TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_status_campaigns ON campaigns(status);
+CREATE INDEX idx_created_at_campaigns ON campaigns(created_at);
+
+CREATE TABLE IF NOT EXISTS campaign_recipients (
+    "id" BIGSERIAL PRIMARY KEY,
+    "campaign_id" VARCHAR(26) NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    "external_user_id" VARCHAR(255) NOT NULL,
+    "status" campaign_recipient_status NOT NULL DEFAULT 'pending',
+    "notification_id" VARCHAR(26) NULL,
+    "error" TEXT NULL,
+    "locked_at" TIMESTAMPTZ NULL,
+    UNIQUE ("campaign_id", "external_user_id")
+);
+CREATE INDEX idx_status_campaign_recipients ON campaign_recipients(status) WHERE status IN ('pending', 'processing');
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_status_campaign_recipients;
+DROP TABLE IF EXISTS campaign_recipients;
+DROP INDEX IF EXISTS idx_created_at_campaigns;
+DROP INDEX IF EXISTS idx_status_campaigns;
+DROP TABLE IF EXISTS campaigns;
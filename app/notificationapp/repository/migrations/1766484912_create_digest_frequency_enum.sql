@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE TYPE digest_frequency AS ENUM ('immediate', 'hourly', 'daily');
+
+-- +migrate Down
+DROP TYPE digest_frequency;
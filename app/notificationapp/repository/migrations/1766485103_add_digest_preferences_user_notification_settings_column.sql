@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE user_notification_settings ADD COLUMN IF NOT EXISTS "digest_preferences" JSONB NULL;
+
+-- +migrate Down
+ALTER TABLE user_notification_settings DROP COLUMN IF EXISTS "digest_preferences";
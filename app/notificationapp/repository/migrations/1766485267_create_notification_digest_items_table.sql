@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS notification_digest_items (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" VARCHAR(26) NOT NULL,
+    "notification_id" VARCHAR(26) NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    "channel" VARCHAR(26) NOT NULL,
+    "frequency" digest_frequency NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "digested_at" TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_pending_notification_digest_items ON notification_digest_items(user_id, channel, frequency)
+    WHERE digested_at IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_pending_notification_digest_items;
+DROP TABLE IF EXISTS notification_digest_items;
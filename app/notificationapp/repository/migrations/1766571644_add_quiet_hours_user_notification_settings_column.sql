@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE user_notification_settings ADD COLUMN IF NOT EXISTS "timezone" VARCHAR(64) NULL;
+ALTER TABLE user_notification_settings ADD COLUMN IF NOT EXISTS "quiet_hours" JSONB NULL;
+
+-- +migrate Down
+ALTER TABLE user_notification_settings DROP COLUMN IF EXISTS "quiet_hours";
+ALTER TABLE user_notification_settings DROP COLUMN IF EXISTS "timezone";
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS notification_deferred_deliveries (
+    "id" BIGSERIAL PRIMARY KEY,
+    "notification_id" VARCHAR(26) NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    "user_id" VARCHAR(26) NOT NULL,
+    "channel" VARCHAR(26) NOT NULL,
+    "deliver_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_deliver_at_notification_deferred_deliveries ON notification_deferred_deliveries(deliver_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_deliver_at_notification_deferred_deliveries;
+DROP TABLE IF EXISTS notification_deferred_deliveries;
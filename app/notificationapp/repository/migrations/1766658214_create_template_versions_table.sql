@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS "version" INT NOT NULL DEFAULT 1;
+CREATE TABLE IF NOT EXISTS template_versions (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "template_id" VARCHAR(26) NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    "version" INT NOT NULL,
+    "contents" JSONB NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE ("template_id", "version")
+);
+INSERT INTO template_versions (id, template_id, version, contents, created_at)
+SELECT id, id, version, contents, COALESCE(updated_at, NOW()) FROM templates
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DROP TABLE IF EXISTS template_versions;
+ALTER TABLE templates DROP COLUMN IF EXISTS "version";
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "template_version" INT NULL;
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS "template_version";
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS "kind" VARCHAR(16) NOT NULL DEFAULT 'notification';
+CREATE INDEX IF NOT EXISTS idx_kind_templates ON templates(kind);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_kind_templates;
+ALTER TABLE templates DROP COLUMN IF EXISTS "kind";
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS notification_delivery_events (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "notification_id" VARCHAR(26) NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    "channel" VARCHAR(26) NOT NULL,
+    "type" VARCHAR(26) NOT NULL,
+    "provider" VARCHAR(64) NOT NULL,
+    "url" TEXT NULL,
+    "error" TEXT NULL,
+    "occurred_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_notification_id_notification_delivery_events ON notification_delivery_events(notification_id, occurred_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_notification_id_notification_delivery_events;
+DROP TABLE IF EXISTS notification_delivery_events;
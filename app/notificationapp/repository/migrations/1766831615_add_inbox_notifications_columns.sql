@@ -0,0 +1,23 @@
+-- +migrate Up
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "category" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "is_archived" BOOL NOT NULL DEFAULT false;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "archived_at" TIMESTAMPTZ NULL;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "is_deleted" BOOL NOT NULL DEFAULT false;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMPTZ NULL;
+UPDATE notifications SET category = type::text WHERE category = '';
+CREATE INDEX IF NOT EXISTS idx_category_notifications ON notifications(category);
+CREATE INDEX IF NOT EXISTS idx_user_id_unread_notifications ON notifications(user_id)
+    WHERE is_read = false AND is_archived = false AND is_deleted = false;
+
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS "category" VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE templates DROP COLUMN IF EXISTS "category";
+
+DROP INDEX IF EXISTS idx_user_id_unread_notifications;
+DROP INDEX IF EXISTS idx_category_notifications;
+ALTER TABLE notifications DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "is_deleted";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "archived_at";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "is_archived";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "category";
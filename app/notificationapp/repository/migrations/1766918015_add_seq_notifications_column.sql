@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "seq" BIGSERIAL;
+CREATE INDEX IF NOT EXISTS idx_user_id_seq_notifications ON notifications(user_id, seq);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_user_id_seq_notifications;
+ALTER TABLE notifications DROP COLUMN IF EXISTS "seq";
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS notification_topics (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "name" VARCHAR(64) NOT NULL UNIQUE,
+    "title" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +migrate StatementBegin
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE ON notification_topics
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS set_updated_at ON notification_topics;
+DROP TABLE IF EXISTS notification_topics;
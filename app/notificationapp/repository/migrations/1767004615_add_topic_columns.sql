@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS "topic" VARCHAR(64) NULL
+    REFERENCES notification_topics(name) ON UPDATE CASCADE ON DELETE SET NULL;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "topic" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE user_notification_settings ADD COLUMN IF NOT EXISTS "topic_preferences" JSONB NULL;
+
+-- +migrate Down
+ALTER TABLE user_notification_settings DROP COLUMN IF EXISTS "topic_preferences";
+ALTER TABLE notifications DROP COLUMN IF EXISTS "topic";
+ALTER TABLE templates DROP COLUMN IF EXISTS "topic";
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS notification_idempotency_keys (
+    "user_id" VARCHAR(26) NOT NULL,
+    "idempotency_key" VARCHAR(255) NOT NULL,
+    "notification_id" VARCHAR(26) NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_id_idempotency_key_notification_idempotency_keys UNIQUE (user_id, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idx_expires_at_notification_idempotency_keys ON notification_idempotency_keys(expires_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_expires_at_notification_idempotency_keys;
+DROP TABLE IF EXISTS notification_idempotency_keys;
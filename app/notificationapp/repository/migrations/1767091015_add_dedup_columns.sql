@@ -0,0 +1,12 @@
+-- +migrate Up
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS "content_hash" VARCHAR(64) NULL;
+CREATE INDEX IF NOT EXISTS idx_user_id_content_hash_notifications ON notifications(user_id, content_hash, created_at)
+    WHERE content_hash IS NOT NULL;
+
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS "dedup_window_seconds" INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE templates DROP COLUMN IF EXISTS "dedup_window_seconds";
+
+DROP INDEX IF EXISTS idx_user_id_content_hash_notifications;
+ALTER TABLE notifications DROP COLUMN IF EXISTS "content_hash";
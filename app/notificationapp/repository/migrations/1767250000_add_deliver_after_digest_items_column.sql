@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notification_digest_items ADD COLUMN IF NOT EXISTS "deliver_after" TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE notification_digest_items DROP COLUMN IF EXISTS "deliver_after";
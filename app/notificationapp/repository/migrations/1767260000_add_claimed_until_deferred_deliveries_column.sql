@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notification_deferred_deliveries ADD COLUMN IF NOT EXISTS "claimed_until" TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE notification_deferred_deliveries DROP COLUMN IF EXISTS "claimed_until";
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE notification_delivery_events ADD COLUMN IF NOT EXISTS "provider_event_id" VARCHAR(128) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_provider_event_id_notification_delivery_events
+    ON notification_delivery_events(provider, provider_event_id) WHERE provider_event_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS uq_provider_event_id_notification_delivery_events;
+ALTER TABLE notification_delivery_events DROP COLUMN IF EXISTS "provider_event_id";
@@ -3,16 +3,19 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/oklog/ulid/v2"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 	"github.com/syntaxfa/quick-connect/types"
 )
 
-const queryCreateNotification = `INSERT INTO notifications (id, user_id, type, data, template_name, dynamic_body_data, dynamic_title_data, is_in_app, overall_status, channel_deliveries)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-RETURNING id, user_id, type, data, template_name, dynamic_body_data, dynamic_title_data, is_read, is_in_app, created_at, overall_status, channel_deliveries;`
+const queryCreateNotification = `INSERT INTO notifications (id, user_id, type, data, template_name, dynamic_body_data, dynamic_title_data, is_in_app, overall_status, channel_deliveries, send_at, is_scheduled, template_version, category, topic, content_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''))
+RETURNING id, user_id, type, data, template_name, dynamic_body_data, dynamic_title_data, is_read, is_in_app, created_at, overall_status, channel_deliveries, send_at, template_version, category, topic, seq;`
 
 func (d *DB) Save(ctx context.Context, req service.SendNotificationRequest) (service.Notification, error) {
 	const op = "repository.postgres.create.Save"
@@ -38,10 +41,12 @@ func (d *DB) Save(ctx context.Context, req service.SendNotificationRequest) (ser
 	var notification service.Notification
 	var jsonChannelDeliveries json.RawMessage
 	if qErr := d.conn.Conn().QueryRow(ctx, queryCreateNotification, req.ID, req.UserID, req.Type, jsonData, req.TemplateName,
-		jsonBodyData, jsonTitleData, req.IsInApp, req.Status, req.ChannelDeliveries).Scan(
+		jsonBodyData, jsonTitleData, req.IsInApp, req.Status, req.ChannelDeliveries, req.SendAt, req.SendAt != nil,
+		req.TemplateVersion, req.Category, req.Topic, req.ContentHash).Scan(
 		&notification.ID, &notification.UserID, &notification.Type, &jsonData, &notification.TemplateName, &jsonBodyData,
 		&jsonTitleData, &notification.IsRead, &notification.IsInApp, &notification.CreatedAt, &notification.OverallStatus,
-		&jsonChannelDeliveries); qErr != nil {
+		&jsonChannelDeliveries, &notification.SendAt, &notification.TemplateVersion, &notification.Category,
+		&notification.Topic, &notification.Seq); qErr != nil {
 		return service.Notification{}, richerror.New(op).WithMessage("can't insert into notifications table").
 			WithWrapError(qErr).WithKind(richerror.KindUnexpected)
 	}
@@ -83,9 +88,12 @@ func (d *DB) CreateUserIDFromExternalUserID(ctx context.Context, externalUserID
 	return nil
 }
 
-const queryCreateTemplate = `INSERT INTO templates (id, name, contents)
-VALUES ($1, $2, $3)
-RETURNING id, created_at, updated_at;`
+const queryCreateTemplate = `INSERT INTO templates (id, name, kind, category, topic, dedup_window_seconds, contents, version)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, 1)
+RETURNING id, version, created_at, updated_at;`
+
+const queryCreateTemplateVersion = `INSERT INTO template_versions (id, template_id, version, contents)
+VALUES ($1, $2, $3, $4);`
 
 func (d *DB) CreateTemplate(ctx context.Context, req service.AddTemplateRequest) (service.Template, error) {
 	const op = "repository.postgres.create.CreateTemplate"
@@ -95,20 +103,51 @@ func (d *DB) CreateTemplate(ctx context.Context, req service.AddTemplateRequest)
 		return service.Template{}, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected)
 	}
 
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return service.Template{}, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
 	var template service.Template
-	if qErr := d.conn.Conn().QueryRow(ctx, queryCreateTemplate, req.ID, req.Name, jsonContents).
-		Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
+	if qErr := tx.QueryRow(ctx, queryCreateTemplate, req.ID, req.Name, req.Kind, req.Category, req.Topic,
+		req.DedupWindowSeconds, jsonContents).
+		Scan(&template.ID, &template.Version, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return service.Template{}, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
 		return service.Template{}, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
 	}
 
+	if _, eErr := tx.Exec(ctx, queryCreateTemplateVersion, ulid.Make().String(), template.ID, template.Version,
+		jsonContents); eErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return service.Template{}, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return service.Template{}, richerror.New(op).WithMessage("can't insert into template_versions table").
+			WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return service.Template{}, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
 	template.Name = req.Name
+	template.Kind = req.Kind
+	template.Category = req.Category
+	template.Topic = req.Topic
+	template.DedupWindowSeconds = req.DedupWindowSeconds
 	template.Contents = req.Contents
 
 	return template, nil
 }
 
-const queryCreateUserSetting = `INSERT INTO user_notification_settings (id, user_id, lang, ignore_channels)
-VALUES ($1, $2, $3, $4);`
+const queryCreateUserSetting = `INSERT INTO user_notification_settings (id, user_id, lang, ignore_channels, digest_preferences,
+timezone, quiet_hours, topic_preferences)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
 
 func (d *DB) CreateUserSetting(ctx context.Context, userID types.ID, req service.UpdateUserSettingRequest) (service.UserSetting, error) {
 	const op = "repository.postgres.create.CreateUserSetting"
@@ -121,14 +160,232 @@ func (d *DB) CreateUserSetting(ctx context.Context, userID types.ID, req service
 			WithWrapError(mErr).WithKind(richerror.KindUnexpected)
 	}
 
-	if _, eErr := d.conn.Conn().Exec(ctx, queryCreateUserSetting, id, userID, req.Lang, jsonChannel); eErr != nil {
+	jsonDigest, mdErr := json.Marshal(req.DigestPreferences)
+	if mdErr != nil {
+		return service.UserSetting{}, richerror.New(op).WithMessage("can't marshal digest preferences").
+			WithWrapError(mdErr).WithKind(richerror.KindUnexpected)
+	}
+
+	jsonQuietHours, mqErr := json.Marshal(req.QuietHours)
+	if mqErr != nil {
+		return service.UserSetting{}, richerror.New(op).WithMessage("can't marshal quiet hours").
+			WithWrapError(mqErr).WithKind(richerror.KindUnexpected)
+	}
+
+	jsonTopics, mtErr := json.Marshal(req.TopicPreferences)
+	if mtErr != nil {
+		return service.UserSetting{}, richerror.New(op).WithMessage("can't marshal topic preferences").
+			WithWrapError(mtErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryCreateUserSetting, id, userID, req.Lang, jsonChannel, jsonDigest, req.Timezone,
+		jsonQuietHours, jsonTopics); eErr != nil {
 		return service.UserSetting{}, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
 	}
 
 	return service.UserSetting{
-		ID:             types.ID(id),
-		UserID:         userID,
-		Lang:           req.Lang,
-		IgnoreChannels: req.IgnoreChannels,
+		ID:                types.ID(id),
+		UserID:            userID,
+		Lang:              req.Lang,
+		IgnoreChannels:    req.IgnoreChannels,
+		DigestPreferences: req.DigestPreferences,
+		Timezone:          req.Timezone,
+		QuietHours:        req.QuietHours,
+		TopicPreferences:  req.TopicPreferences,
 	}, nil
 }
+
+// querySaveTopicPreference creates the setting of the user with the topic preference or replaces the preference
+// of the same topic and channel in the stored topic preferences, the conflicting row is locked by the upsert,
+// so the concurrent preferences of the user are applied one after another.
+const querySaveTopicPreference = `INSERT INTO user_notification_settings (id, user_id, lang, ignore_channels, topic_preferences)
+VALUES ($1, $2, $3, '[]'::jsonb, jsonb_build_array($4::jsonb))
+ON CONFLICT (user_id) DO UPDATE
+SET topic_preferences = COALESCE((
+        SELECT jsonb_agg(p) FROM jsonb_array_elements(CASE
+            WHEN jsonb_typeof(user_notification_settings.topic_preferences) = 'array'
+            THEN user_notification_settings.topic_preferences
+            ELSE '[]'::jsonb
+        END) p
+        WHERE p->>'topic' <> $4::jsonb->>'topic' OR p->>'channel' <> $4::jsonb->>'channel'
+    ), '[]'::jsonb) || jsonb_build_array($4::jsonb);`
+
+func (d *DB) SaveTopicPreference(ctx context.Context, userID types.ID, lang string, preference service.TopicPreference) error {
+	const op = "repository.postgres.create.SaveTopicPreference"
+
+	jsonPreference, mErr := json.Marshal(preference)
+	if mErr != nil {
+		return richerror.New(op).WithMessage("can't marshal topic preference").WithWrapError(mErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	if _, eErr := d.conn.Conn().Exec(ctx, querySaveTopicPreference, ulid.Make().String(), userID, lang,
+		jsonPreference); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryCreateCampaign = `INSERT INTO campaigns (id, name, type, data, template_name, dynamic_body_data, dynamic_title_data, channel_deliveries, file_id, total_count)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING ` + campaignFields + `;`
+
+// CreateCampaign stores the campaign and all of its recipients in a single transaction.
+// Recipients are inserted with the COPY protocol, a campaign can have a large number of recipients.
+func (d *DB) CreateCampaign(ctx context.Context, req service.BroadcastRequest, externalUserIDs []string) (service.Campaign, error) {
+	const op = "repository.postgres.create.CreateCampaign"
+
+	jsonData, mdErr := json.Marshal(req.Data)
+	if mdErr != nil {
+		return service.Campaign{}, richerror.New(op).WithMessage("can't marshal campaign data").
+			WithWrapError(mdErr).WithKind(richerror.KindUnexpected)
+	}
+
+	jsonBodyData, mbErr := json.Marshal(req.DynamicBodyData)
+	if mbErr != nil {
+		return service.Campaign{}, richerror.New(op).WithMessage("can't marshal campaign dynamic body data").
+			WithWrapError(mbErr).WithKind(richerror.KindUnexpected)
+	}
+
+	jsonTitleData, mtErr := json.Marshal(req.DynamicTitleData)
+	if mtErr != nil {
+		return service.Campaign{}, richerror.New(op).WithMessage("can't marshal campaign dynamic title data").
+			WithWrapError(mtErr).WithKind(richerror.KindUnexpected)
+	}
+
+	jsonChannelDeliveries, mcErr := json.Marshal(req.ChannelDeliveries)
+	if mcErr != nil {
+		return service.Campaign{}, richerror.New(op).WithMessage("can't marshal campaign channel deliveries").
+			WithWrapError(mcErr).WithKind(richerror.KindUnexpected)
+	}
+
+	var fileID *types.ID
+	if req.CSVFileID != "" {
+		fileID = &req.CSVFileID
+	}
+
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return service.Campaign{}, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	campaign, sErr := scanCampaign(tx.QueryRow(ctx, queryCreateCampaign, req.ID, req.Name, req.Type, jsonData, req.TemplateName,
+		jsonBodyData, jsonTitleData, jsonChannelDeliveries, fileID, len(externalUserIDs)))
+	if sErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return service.Campaign{}, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return service.Campaign{}, richerror.New(op).WithMessage("can't insert into campaigns table").
+			WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	rows := make([][]interface{}, 0, len(externalUserIDs))
+	for _, externalUserID := range externalUserIDs {
+		rows = append(rows, []interface{}{req.ID, externalUserID})
+	}
+
+	if _, cErr := tx.CopyFrom(ctx, pgx.Identifier{"campaign_recipients"}, []string{"campaign_id", "external_user_id"},
+		pgx.CopyFromRows(rows)); cErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return service.Campaign{}, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return service.Campaign{}, richerror.New(op).WithMessage("can't copy into campaign_recipients table").
+			WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return service.Campaign{}, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return campaign, nil
+}
+
+// SaveDigestItems inserts the digest items with the COPY protocol.
+func (d *DB) SaveDigestItems(ctx context.Context, items []service.DigestItem) error {
+	const op = "repository.postgres.create.SaveDigestItems"
+
+	rows := make([][]interface{}, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []interface{}{item.UserID, item.NotificationID, item.Channel, item.Frequency})
+	}
+
+	if _, cErr := d.conn.Conn().CopyFrom(ctx, pgx.Identifier{"notification_digest_items"},
+		[]string{"user_id", "notification_id", "channel", "frequency"}, pgx.CopyFromRows(rows)); cErr != nil {
+		return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryCreateDeferredDelivery = `INSERT INTO notification_deferred_deliveries (notification_id, user_id, channel, deliver_at)
+VALUES ($1, $2, $3, $4);`
+
+func (d *DB) SaveDeferredDelivery(ctx context.Context, delivery service.DeferredDelivery) error {
+	const op = "repository.postgres.create.SaveDeferredDelivery"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryCreateDeferredDelivery, delivery.NotificationID, delivery.UserID,
+		delivery.Channel, delivery.DeliverAt); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryCreateTopic = `INSERT INTO notification_topics (id, name, title, description)
+VALUES ($1, $2, $3, $4)
+RETURNING ` + topicFields + `;`
+
+func (d *DB) CreateTopic(ctx context.Context, req service.AddTopicRequest) (service.Topic, error) {
+	const op = "repository.postgres.create.CreateTopic"
+
+	topic, sErr := scanTopic(d.conn.Conn().QueryRow(ctx, queryCreateTopic, req.ID, req.Name, req.Title, req.Description))
+	if sErr != nil {
+		return service.Topic{}, richerror.New(op).WithMessage("can't insert into notification_topics table").
+			WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return topic, nil
+}
+
+// queryClaimIdempotencyKey inserts the idempotency key or takes over an expired one and a stale claim, a claim is stale
+// when it is claimed before $5 and its notification is not saved, e.g. the instance is stopped before it. When the key
+// is claimed by another notification, the id of that notification is returned.
+const queryClaimIdempotencyKey = `WITH claimed AS (
+    INSERT INTO notification_idempotency_keys (user_id, idempotency_key, notification_id, expires_at)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (user_id, idempotency_key) DO UPDATE
+    SET notification_id = EXCLUDED.notification_id, expires_at = EXCLUDED.expires_at, created_at = NOW()
+    WHERE notification_idempotency_keys.expires_at <= NOW() OR (notification_idempotency_keys.created_at <= $5
+        AND NOT EXISTS (SELECT 1 FROM notifications n WHERE n.id = notification_idempotency_keys.notification_id))
+    RETURNING notification_id
+)
+SELECT notification_id FROM claimed
+UNION ALL
+SELECT notification_id FROM notification_idempotency_keys
+WHERE user_id = $1 AND idempotency_key = $2 AND NOT EXISTS (SELECT 1 FROM claimed)
+LIMIT 1;`
+
+// ClaimIdempotencyKey returns the id of the notification which owns the key, it is empty when the key is
+// claimed by a request which is not committed yet.
+func (d *DB) ClaimIdempotencyKey(ctx context.Context, userID types.ID, key string, notificationID types.ID,
+	expiresAt, staleBefore time.Time) (types.ID, error) {
+	const op = "repository.postgres.create.ClaimIdempotencyKey"
+
+	var ownerID types.ID
+	if qErr := d.conn.Conn().QueryRow(ctx, queryClaimIdempotencyKey, userID, key, notificationID, expiresAt,
+		staleBefore).
+		Scan(&ownerID); qErr != nil {
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return ownerID, nil
+}
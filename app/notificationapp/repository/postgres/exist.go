@@ -72,6 +72,27 @@ func (d *DB) IsExistTemplateByID(ctx context.Context, id types.ID) (bool, error)
 	return exists, nil
 }
 
+const queryIsExistTemplateVersion = `SELECT EXISTS (
+	SELECT 1
+	FROM template_versions
+	WHERE template_id = $1 AND version = $2
+);`
+
+func (d *DB) IsExistTemplateVersion(ctx context.Context, templateID types.ID, version int) (bool, error) {
+	const op = "repository.postgres.exist.IsExistTemplateVersion"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistTemplateVersion, templateID, version).Scan(&exists); qErr != nil {
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
 const queryIsExistUserSetting = `SELECT EXISTS (
 	SELECT 1
 	FROM user_notification_settings
@@ -92,3 +113,79 @@ func (d *DB) IsExistUserSetting(ctx context.Context, userID types.ID) (bool, err
 
 	return exists, nil
 }
+
+const queryIsExistNotificationByID = `SELECT EXISTS (
+	SELECT 1
+	FROM notifications
+	WHERE id = $1
+);`
+
+func (d *DB) IsExistNotificationByID(ctx context.Context, notificationID types.ID) (bool, error) {
+	const op = "repository.postgres.exist.IsExistNotificationByID"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistNotificationByID, notificationID).Scan(&exists); qErr != nil {
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistCampaignByID = `SELECT EXISTS (
+	SELECT 1
+	FROM campaigns
+	WHERE id = $1
+);`
+
+func (d *DB) IsExistCampaignByID(ctx context.Context, campaignID types.ID) (bool, error) {
+	const op = "repository.postgres.exist.IsExistCampaignByID"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistCampaignByID, campaignID).Scan(&exists); qErr != nil {
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistTopicByName = `SELECT EXISTS (
+	SELECT 1
+	FROM notification_topics
+	WHERE name = $1
+);`
+
+func (d *DB) IsExistTopicByName(ctx context.Context, name string) (bool, error) {
+	const op = "repository.postgres.exist.IsExistTopicByName"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistTopicByName, name).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistTopicByID = `SELECT EXISTS (
+	SELECT 1
+	FROM notification_topics
+	WHERE id = $1
+);`
+
+func (d *DB) IsExistTopicByID(ctx context.Context, id types.ID) (bool, error) {
+	const op = "repository.postgres.exist.IsExistTopicByID"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistTopicByID, id).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
@@ -3,7 +3,11 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"slices"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
 	paginate "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset"
 	pagesql "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset/sql"
@@ -11,33 +15,75 @@ import (
 	"github.com/syntaxfa/quick-connect/types"
 )
 
-func (d *DB) FindNotificationByUserID(ctx context.Context, userID types.ID, paginated paginate.RequestBase, isRead,
-	isInApp *bool) ([]service.Notification, paginate.ResponseBase, error) {
+func (d *DB) FindNotificationByUserID(ctx context.Context, userID types.ID, filter service.NotificationFilter,
+	paginated paginate.RequestBase) ([]service.Notification, paginate.ResponseBase, error) {
 	const op = "repository.get.FindNotificationByUserID"
 
 	filters := map[paginate.FilterParameter]paginate.Filter{
-		"user_id": {Operation: paginate.FilterOperationEqual, Values: []interface{}{userID}},
+		"user_id":    {Operation: paginate.FilterOperationEqual, Values: []interface{}{userID}},
+		"is_deleted": {Operation: paginate.FilterOperationEqual, Values: []interface{}{false}},
 	}
 
-	if isRead != nil {
-		filters["is_read"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{*isRead}}
+	if filter.IsRead != nil {
+		filters["is_read"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{*filter.IsRead}}
 	}
 
-	if isInApp != nil {
-		filters["is_in_app"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{*isInApp}}
+	if filter.IsInApp != nil {
+		filters["is_in_app"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{*filter.IsInApp}}
 	}
 
-	fields := []string{
-		"id", "user_id", "type", "data", "template_name", "dynamic_body_data", "dynamic_title_data",
-		"is_read", "created_at", "overall_status", "channel_deliveries",
+	if filter.IsArchived != nil {
+		filters["is_archived"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{*filter.IsArchived}}
 	}
+
+	switch {
+	case len(filter.Types) > 0:
+		values := make([]interface{}, 0, len(filter.Types))
+		for _, notificationType := range filter.Types {
+			if !slices.Contains(filter.ExcludeTypes, notificationType) {
+				values = append(values, notificationType)
+			}
+		}
+
+		// all the requested types are excluded.
+		if len(values) == 0 {
+			return nil, paginate.ResponseBase{CurrentPage: paginated.CurrentPage, PageSize: paginated.PageSize}, nil
+		}
+
+		filters["type"] = paginate.Filter{Operation: paginate.FilterOperationIn, Values: values}
+	case len(filter.ExcludeTypes) > 0:
+		values := make([]interface{}, 0, len(filter.ExcludeTypes))
+		for _, notificationType := range filter.ExcludeTypes {
+			values = append(values, notificationType)
+		}
+		filters["type"] = paginate.Filter{Operation: paginate.FilterOperationNotIn, Values: values}
+	}
+
+	if filter.Category != "" {
+		filters["category"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{filter.Category}}
+	}
+
+	switch {
+	case filter.From != nil && filter.To != nil:
+		filters["created_at"] = paginate.Filter{Operation: paginate.FilterOperationBetween,
+			Values: []interface{}{*filter.From, *filter.To}}
+	case filter.From != nil:
+		filters["created_at"] = paginate.Filter{Operation: paginate.FilterOperationGreaterEqual, Values: []interface{}{*filter.From}}
+	case filter.To != nil:
+		filters["created_at"] = paginate.Filter{Operation: paginate.FilterOperationLessEqual, Values: []interface{}{*filter.To}}
+	}
+
+	// Scheduled notifications are hidden from the user until the scheduler dispatches them.
+	filters["overall_status"] = paginate.Filter{Operation: paginate.FilterOperationNotIn,
+		Values: []interface{}{service.OverallStatusScheduled, service.OverallStatusCanceled}}
+
 	sortColumn := "created_at"
 	offset := (paginated.CurrentPage - 1) * paginated.PageSize
 	limit := paginated.PageSize
 
 	query, countQuery, args := pagesql.WriteQuery(pagesql.Parameters{
 		Table:      "notifications",
-		Fields:     fields,
+		Fields:     []string{notificationFields},
 		Filters:    filters,
 		SortColumn: sortColumn,
 		Descending: paginated.Descending,
@@ -55,37 +101,12 @@ func (d *DB) FindNotificationByUserID(ctx context.Context, userID types.ID, pagi
 	defer rows.Close()
 
 	var notifications []service.Notification
-	var jsonData json.RawMessage
-	var jsonBodyData json.RawMessage
-	var jsonTitleData json.RawMessage
-	var jsonChannelDelivery json.RawMessage
 	for rows.Next() {
-		var notification service.Notification
-		if sErr := rows.Scan(&notification.ID, &notification.UserID, &notification.Type, &jsonData, &notification.TemplateName,
-			&jsonBodyData, &jsonTitleData, &notification.IsRead, &notification.CreatedAt, &notification.OverallStatus, &jsonChannelDelivery); sErr != nil {
+		notification, sErr := scanNotification(rows)
+		if sErr != nil {
 			return nil, paginate.ResponseBase{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
 		}
 
-		if uErr := json.Unmarshal(jsonData, &notification.Data); uErr != nil {
-			return nil, paginate.ResponseBase{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected).
-				WithMessage("can't unmarshal notification data")
-		}
-
-		if uErr := json.Unmarshal(jsonBodyData, &notification.DynamicBodyData); uErr != nil {
-			return nil, paginate.ResponseBase{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected).
-				WithMessage("can't unmarshal notification dynamic body data")
-		}
-
-		if uErr := json.Unmarshal(jsonTitleData, &notification.DynamicTitleData); uErr != nil {
-			return nil, paginate.ResponseBase{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected).
-				WithMessage("can't unmarshal notification dynamic title data")
-		}
-
-		if uErr := json.Unmarshal(jsonChannelDelivery, &notification.ChannelDeliveries); uErr != nil {
-			return nil, paginate.ResponseBase{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected).
-				WithMessage("can't unmarshal notification channel deliveries")
-		}
-
 		notifications = append(notifications, notification)
 	}
 
@@ -99,6 +120,82 @@ func (d *DB) FindNotificationByUserID(ctx context.Context, userID types.ID, pagi
 	}, nil
 }
 
+const queryGetInAppNotificationsAfterSeq = `SELECT ` + notificationFields + `
+FROM notifications
+WHERE user_id = $1 AND seq > $2 AND is_in_app = true AND is_deleted = false
+AND overall_status NOT IN ('scheduled', 'canceled')
+ORDER BY seq
+LIMIT $3;`
+
+// GetInAppNotificationsAfterSeq returns the in-app notifications of the user which are published after the seq.
+func (d *DB) GetInAppNotificationsAfterSeq(ctx context.Context, userID types.ID, seq int64,
+	limit int) ([]service.Notification, error) {
+	const op = "repository.postgres.get.GetInAppNotificationsAfterSeq"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetInAppNotificationsAfterSeq, userID, seq, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var notifications []service.Notification
+	for rows.Next() {
+		notification, sErr := scanNotification(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return notifications, nil
+}
+
+const queryGetUnreadCountByCategory = `SELECT category, COUNT(*)
+FROM notifications
+WHERE user_id = $1 AND is_in_app = true AND is_read = false AND is_archived = false AND is_deleted = false
+AND overall_status NOT IN ('scheduled', 'canceled') AND NOT (type::text = ANY($2))
+GROUP BY category;`
+
+// GetUnreadCountByCategory counts the unread in-app notifications of the user in every category,
+// archived notifications and excluded types are not counted.
+func (d *DB) GetUnreadCountByCategory(ctx context.Context, userID types.ID,
+	excludeTypes []service.NotificationType) (map[string]int, error) {
+	const op = "repository.postgres.get.GetUnreadCountByCategory"
+
+	excludes := make([]string, 0, len(excludeTypes))
+	for _, notificationType := range excludeTypes {
+		excludes = append(excludes, string(notificationType))
+	}
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetUnreadCountByCategory, userID, excludes)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if sErr := rows.Scan(&category, &count); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		counts[category] = count
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return counts, nil
+}
+
 const queryGetUserIDFromExternalUserID = `SELECT user_id FROM external_users
 WHERE external_user_id = $1
 LIMIT 1;`
@@ -114,7 +211,7 @@ func (d *DB) GetUserIDFromExternalUserID(ctx context.Context, externalUserID str
 	return types.ID(userID), nil
 }
 
-const queryGetTemplateByName = `SELECT id, name, bodies, created_at, updated_at
+const queryGetTemplateByName = `SELECT id, name, kind, category, COALESCE(topic, ''), dedup_window_seconds, version, contents, created_at, updated_at
 FROM templates WHERE name = $1
 LIMIT 1;`
 
@@ -125,7 +222,8 @@ func (d *DB) GetTemplateByName(ctx context.Context, name string) (service.Templa
 	var jsonContents json.RawMessage
 
 	if qErr := d.conn.Conn().QueryRow(ctx, queryGetTemplateByName, name).
-		Scan(&template.ID, &template.Name, &jsonContents, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
+		Scan(&template.ID, &template.Name, &template.Kind, &template.Category, &template.Topic, &template.DedupWindowSeconds,
+			&template.Version, &jsonContents, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
 		return service.Template{}, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
 	}
 
@@ -137,7 +235,7 @@ func (d *DB) GetTemplateByName(ctx context.Context, name string) (service.Templa
 	return template, nil
 }
 
-const queryTemplateByID = `SELECT id, name, contents, created_at, updated_at
+const queryTemplateByID = `SELECT id, name, kind, category, COALESCE(topic, ''), dedup_window_seconds, version, contents, created_at, updated_at
 FROM templates WHERE id = $1
 LIMIT 1;`
 
@@ -148,7 +246,8 @@ func (d *DB) GetTemplateByID(ctx context.Context, id types.ID) (service.Template
 	var jsonContents json.RawMessage
 
 	if qErr := d.conn.Conn().QueryRow(ctx, queryTemplateByID, id).
-		Scan(&template.ID, &template.Name, &jsonContents, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
+		Scan(&template.ID, &template.Name, &template.Kind, &template.Category, &template.Topic, &template.DedupWindowSeconds,
+			&template.Version, &jsonContents, &template.CreatedAt, &template.UpdatedAt); qErr != nil {
 		return service.Template{}, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
 	}
 
@@ -160,7 +259,7 @@ func (d *DB) GetTemplateByID(ctx context.Context, id types.ID) (service.Template
 	return template, nil
 }
 
-const queryGetTemplatesByNames = `SELECT id, name, contents, created_at
+const queryGetTemplatesByNames = `SELECT id, name, kind, category, COALESCE(topic, ''), dedup_window_seconds, version, contents, created_at
 FROM templates WHERE name = ANY($1)`
 
 func (d *DB) GetTemplatesByNames(ctx context.Context, names ...string) ([]service.Template, error) {
@@ -177,7 +276,8 @@ func (d *DB) GetTemplatesByNames(ctx context.Context, names ...string) ([]servic
 	for rows.Next() {
 		var template service.Template
 		var jsonContents json.RawMessage
-		if sErr := rows.Scan(&template.ID, &template.Name, &jsonContents, &template.CreatedAt); sErr != nil {
+		if sErr := rows.Scan(&template.ID, &template.Name, &template.Kind, &template.Category, &template.Topic,
+			&template.DedupWindowSeconds, &template.Version, &jsonContents, &template.CreatedAt); sErr != nil {
 			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
 		}
 
@@ -202,8 +302,11 @@ func (d *DB) GetTemplates(ctx context.Context, req service.ListTemplateRequest)
 	if req.Name != "" {
 		filters["name"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{req.Name}}
 	}
+	if req.Kind != "" {
+		filters["kind"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{req.Kind}}
+	}
 
-	fields := []string{"id", "name", "created_at", "updated_at"}
+	fields := []string{"id", "name", "kind", "version", "created_at", "updated_at"}
 	sortColumn := "created_at"
 	offset := (req.Paginated.CurrentPage - 1) * req.Paginated.PageSize
 	limit := req.Paginated.PageSize
@@ -230,7 +333,8 @@ func (d *DB) GetTemplates(ctx context.Context, req service.ListTemplateRequest)
 	var templates []service.ListTemplateResult
 	for rows.Next() {
 		var template service.ListTemplateResult
-		if sErr := rows.Scan(&template.ID, &template.Name, &template.CreatedAt, &template.UpdatedAt); sErr != nil {
+		if sErr := rows.Scan(&template.ID, &template.Name, &template.Kind, &template.Version, &template.CreatedAt,
+			&template.UpdatedAt); sErr != nil {
 			return service.ListTemplateResponse{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
 		}
 		templates = append(templates, template)
@@ -249,7 +353,70 @@ func (d *DB) GetTemplates(ctx context.Context, req service.ListTemplateRequest)
 	}, nil
 }
 
-const queryGetUserSetting = `SELECT id, user_id, lang, ignore_channels
+const queryGetTemplateVersions = `SELECT id, template_id, version, contents, created_at
+FROM template_versions
+WHERE template_id = $1
+ORDER BY version DESC;`
+
+func (d *DB) GetTemplateVersions(ctx context.Context, templateID types.ID) ([]service.TemplateVersion, error) {
+	const op = "repository.postgres.get.GetTemplateVersions"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetTemplateVersions, templateID)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	versions := make([]service.TemplateVersion, 0)
+	for rows.Next() {
+		version, sErr := scanTemplateVersion(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		versions = append(versions, version)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return versions, nil
+}
+
+const queryGetTemplateVersion = `SELECT id, template_id, version, contents, created_at
+FROM template_versions
+WHERE template_id = $1 AND version = $2
+LIMIT 1;`
+
+func (d *DB) GetTemplateVersion(ctx context.Context, templateID types.ID, version int) (service.TemplateVersion, error) {
+	const op = "repository.postgres.get.GetTemplateVersion"
+
+	templateVersion, sErr := scanTemplateVersion(d.conn.Conn().QueryRow(ctx, queryGetTemplateVersion, templateID, version))
+	if sErr != nil {
+		return service.TemplateVersion{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return templateVersion, nil
+}
+
+func scanTemplateVersion(row pgx.Row) (service.TemplateVersion, error) {
+	var version service.TemplateVersion
+	var jsonContents json.RawMessage
+
+	if sErr := row.Scan(&version.ID, &version.TemplateID, &version.Version, &jsonContents, &version.CreatedAt); sErr != nil {
+		return service.TemplateVersion{}, sErr
+	}
+
+	if uErr := json.Unmarshal(jsonContents, &version.Contents); uErr != nil {
+		return service.TemplateVersion{}, uErr
+	}
+
+	return version, nil
+}
+
+const queryGetUserSetting = `SELECT id, user_id, lang, ignore_channels, digest_preferences, COALESCE(timezone, ''), quiet_hours,
+topic_preferences
 FROM user_notification_settings
 WHERE user_id = $1`
 
@@ -257,9 +424,9 @@ func (d *DB) GetUserSetting(ctx context.Context, userID types.ID) (service.UserS
 	const op = "repository.postgres.get.GetUserSetting"
 
 	var setting service.UserSetting
-	var jsonChannel json.RawMessage
-	if qErr := d.conn.Conn().QueryRow(ctx, queryGetUserSetting, userID).
-		Scan(&setting.ID, &setting.UserID, &setting.Lang, &jsonChannel); qErr != nil {
+	var jsonChannel, jsonDigest, jsonQuietHours, jsonTopics json.RawMessage
+	if qErr := d.conn.Conn().QueryRow(ctx, queryGetUserSetting, userID).Scan(&setting.ID, &setting.UserID, &setting.Lang,
+		&jsonChannel, &jsonDigest, &setting.Timezone, &jsonQuietHours, &jsonTopics); qErr != nil {
 		return service.UserSetting{}, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
 	}
 
@@ -267,5 +434,405 @@ func (d *DB) GetUserSetting(ctx context.Context, userID types.ID) (service.UserS
 		return service.UserSetting{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected)
 	}
 
+	if jsonDigest != nil {
+		if uErr := json.Unmarshal(jsonDigest, &setting.DigestPreferences); uErr != nil {
+			return service.UserSetting{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	if jsonQuietHours != nil {
+		if uErr := json.Unmarshal(jsonQuietHours, &setting.QuietHours); uErr != nil {
+			return service.UserSetting{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	if jsonTopics != nil {
+		if uErr := json.Unmarshal(jsonTopics, &setting.TopicPreferences); uErr != nil {
+			return service.UserSetting{}, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected)
+		}
+	}
+
 	return setting, nil
 }
+
+const queryGetUserLanguages = `SELECT lang, COUNT(*)
+FROM user_notification_settings
+WHERE lang != ''
+GROUP BY lang
+ORDER BY COUNT(*) DESC;`
+
+func (d *DB) GetUserLanguages(ctx context.Context) ([]service.UserLanguage, error) {
+	const op = "repository.postgres.get.GetUserLanguages"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetUserLanguages)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	languages := make([]service.UserLanguage, 0)
+	for rows.Next() {
+		var language service.UserLanguage
+		if sErr := rows.Scan(&language.Lang, &language.Users); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		languages = append(languages, language)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return languages, nil
+}
+
+const notificationFields = `id, user_id, type, data, template_name, dynamic_body_data, dynamic_title_data, is_read, is_in_app,
+created_at, overall_status, channel_deliveries, send_at, dispatched_at, template_version, category, topic, is_archived, seq`
+
+const queryGetNotificationByID = `SELECT ` + notificationFields + `
+FROM notifications
+WHERE id = $1
+LIMIT 1;`
+
+func (d *DB) GetNotificationByID(ctx context.Context, notificationID types.ID) (service.Notification, error) {
+	const op = "repository.postgres.get.GetNotificationByID"
+
+	notification, sErr := scanNotification(d.conn.Conn().QueryRow(ctx, queryGetNotificationByID, notificationID))
+	if sErr != nil {
+		return service.Notification{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return notification, nil
+}
+
+func (d *DB) GetScheduledNotifications(ctx context.Context,
+	req service.ListScheduledNotificationRequest) (service.ListScheduledNotificationResponse, error) {
+	const op = "repository.postgres.get.GetScheduledNotifications"
+
+	filters := map[paginate.FilterParameter]paginate.Filter{
+		"is_scheduled": {Operation: paginate.FilterOperationEqual, Values: []interface{}{true}},
+	}
+
+	if req.Status != "" {
+		filters["overall_status"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{req.Status}}
+	}
+
+	query, _, args := pagesql.WriteQuery(pagesql.Parameters{
+		Table:      "notifications",
+		Fields:     []string{notificationFields},
+		Filters:    filters,
+		SortColumn: "send_at",
+		Descending: req.Paginated.Descending,
+		Limit:      req.Paginated.PageSize,
+		Offset:     (req.Paginated.CurrentPage - 1) * req.Paginated.PageSize,
+	})
+
+	rows, qErr := d.conn.Conn().Query(ctx, query, args...)
+	if qErr != nil {
+		return service.ListScheduledNotificationResponse{}, richerror.New(op).WithWrapError(qErr).
+			WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	notifications := make([]service.Notification, 0)
+	for rows.Next() {
+		notification, sErr := scanNotification(rows)
+		if sErr != nil {
+			return service.ListScheduledNotificationResponse{}, richerror.New(op).WithWrapError(sErr).
+				WithKind(richerror.KindUnexpected)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return service.ListScheduledNotificationResponse{}, richerror.New(op).WithWrapError(rErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	return service.ListScheduledNotificationResponse{
+		Results: notifications,
+		Paginate: paginate.ResponseBase{
+			CurrentPage: req.Paginated.CurrentPage,
+			PageSize:    req.Paginated.PageSize,
+		},
+	}, nil
+}
+
+// scanNotification scans a row selected with notificationFields.
+func scanNotification(row pgx.Row) (service.Notification, error) {
+	var notification service.Notification
+	var jsonData, jsonBodyData, jsonTitleData, jsonChannelDeliveries json.RawMessage
+
+	if sErr := row.Scan(&notification.ID, &notification.UserID, &notification.Type, &jsonData, &notification.TemplateName,
+		&jsonBodyData, &jsonTitleData, &notification.IsRead, &notification.IsInApp, &notification.CreatedAt,
+		&notification.OverallStatus, &jsonChannelDeliveries, &notification.SendAt, &notification.DispatchedAt,
+		&notification.TemplateVersion, &notification.Category, &notification.Topic, &notification.IsArchived,
+		&notification.Seq); sErr != nil {
+		return service.Notification{}, sErr
+	}
+
+	if uErr := json.Unmarshal(jsonData, &notification.Data); uErr != nil {
+		return service.Notification{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonBodyData, &notification.DynamicBodyData); uErr != nil {
+		return service.Notification{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonTitleData, &notification.DynamicTitleData); uErr != nil {
+		return service.Notification{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonChannelDeliveries, &notification.ChannelDeliveries); uErr != nil {
+		return service.Notification{}, uErr
+	}
+
+	return notification, nil
+}
+
+const campaignFields = `id, name, type, data, template_name, dynamic_body_data, dynamic_title_data, channel_deliveries, file_id, status,
+total_count, sent_count, failed_count, skipped_count, created_at, updated_at, completed_at`
+
+const queryGetCampaignByID = `SELECT ` + campaignFields + `
+FROM campaigns
+WHERE id = $1
+LIMIT 1;`
+
+func (d *DB) GetCampaignByID(ctx context.Context, campaignID types.ID) (service.Campaign, error) {
+	const op = "repository.postgres.get.GetCampaignByID"
+
+	campaign, sErr := scanCampaign(d.conn.Conn().QueryRow(ctx, queryGetCampaignByID, campaignID))
+	if sErr != nil {
+		return service.Campaign{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return campaign, nil
+}
+
+func (d *DB) GetCampaigns(ctx context.Context, req service.ListCampaignRequest) (service.ListCampaignResponse, error) {
+	const op = "repository.postgres.get.GetCampaigns"
+
+	filters := make(map[paginate.FilterParameter]paginate.Filter)
+	if req.Status != "" {
+		filters["status"] = paginate.Filter{Operation: paginate.FilterOperationEqual, Values: []interface{}{req.Status}}
+	}
+
+	query, _, args := pagesql.WriteQuery(pagesql.Parameters{
+		Table:      "campaigns",
+		Fields:     []string{campaignFields},
+		Filters:    filters,
+		SortColumn: "created_at",
+		Descending: req.Paginated.Descending,
+		Limit:      req.Paginated.PageSize,
+		Offset:     (req.Paginated.CurrentPage - 1) * req.Paginated.PageSize,
+	})
+
+	rows, qErr := d.conn.Conn().Query(ctx, query, args...)
+	if qErr != nil {
+		return service.ListCampaignResponse{}, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	campaigns := make([]service.Campaign, 0)
+	for rows.Next() {
+		campaign, sErr := scanCampaign(rows)
+		if sErr != nil {
+			return service.ListCampaignResponse{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		campaigns = append(campaigns, campaign)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return service.ListCampaignResponse{}, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return service.ListCampaignResponse{
+		Results: campaigns,
+		Paginate: paginate.ResponseBase{
+			CurrentPage: req.Paginated.CurrentPage,
+			PageSize:    req.Paginated.PageSize,
+		},
+	}, nil
+}
+
+// scanCampaign scans a row selected with campaignFields.
+func scanCampaign(row pgx.Row) (service.Campaign, error) {
+	var campaign service.Campaign
+	var jsonData, jsonBodyData, jsonTitleData, jsonChannelDeliveries json.RawMessage
+
+	if sErr := row.Scan(&campaign.ID, &campaign.Name, &campaign.Type, &jsonData, &campaign.TemplateName, &jsonBodyData,
+		&jsonTitleData, &jsonChannelDeliveries, &campaign.FileID, &campaign.Status, &campaign.TotalCount, &campaign.SentCount,
+		&campaign.FailedCount, &campaign.SkippedCount, &campaign.CreatedAt, &campaign.UpdatedAt, &campaign.CompletedAt); sErr != nil {
+		return service.Campaign{}, sErr
+	}
+
+	if uErr := json.Unmarshal(jsonData, &campaign.Data); uErr != nil {
+		return service.Campaign{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonBodyData, &campaign.DynamicBodyData); uErr != nil {
+		return service.Campaign{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonTitleData, &campaign.DynamicTitleData); uErr != nil {
+		return service.Campaign{}, uErr
+	}
+
+	if uErr := json.Unmarshal(jsonChannelDeliveries, &campaign.ChannelDeliveries); uErr != nil {
+		return service.Campaign{}, uErr
+	}
+
+	campaign.QueuedCount = campaign.TotalCount - campaign.SentCount - campaign.FailedCount - campaign.SkippedCount
+
+	return campaign, nil
+}
+
+const queryGetNotificationsByIDs = `SELECT ` + notificationFields + `
+FROM notifications
+WHERE id = ANY($1)
+ORDER BY created_at;`
+
+func (d *DB) GetNotificationsByIDs(ctx context.Context, notificationIDs []types.ID) ([]service.Notification, error) {
+	const op = "repository.postgres.get.GetNotificationsByIDs"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetNotificationsByIDs, notificationIDs)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var notifications []service.Notification
+	for rows.Next() {
+		notification, sErr := scanNotification(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return notifications, nil
+}
+
+const queryGetDeliveryEvents = `SELECT id, notification_id, channel, type, provider, COALESCE(url, ''), error, occurred_at, created_at
+FROM notification_delivery_events
+WHERE notification_id = $1
+ORDER BY occurred_at;`
+
+func (d *DB) GetDeliveryEvents(ctx context.Context, notificationID types.ID) ([]service.DeliveryEvent, error) {
+	const op = "repository.postgres.get.GetDeliveryEvents"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetDeliveryEvents, notificationID)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	events := make([]service.DeliveryEvent, 0)
+	for rows.Next() {
+		var event service.DeliveryEvent
+		if sErr := rows.Scan(&event.ID, &event.NotificationID, &event.Channel, &event.Type, &event.Provider, &event.URL,
+			&event.Error, &event.OccurredAt, &event.CreatedAt); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		events = append(events, event)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return events, nil
+}
+
+const topicFields = `id, name, title, description, created_at, updated_at`
+
+const queryGetTopicByID = `SELECT ` + topicFields + `
+FROM notification_topics
+WHERE id = $1
+LIMIT 1;`
+
+func (d *DB) GetTopicByID(ctx context.Context, id types.ID) (service.Topic, error) {
+	const op = "repository.postgres.get.GetTopicByID"
+
+	topic, sErr := scanTopic(d.conn.Conn().QueryRow(ctx, queryGetTopicByID, id))
+	if sErr != nil {
+		return service.Topic{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return topic, nil
+}
+
+const queryGetTopics = `SELECT ` + topicFields + `
+FROM notification_topics
+ORDER BY name;`
+
+func (d *DB) GetTopics(ctx context.Context) ([]service.Topic, error) {
+	const op = "repository.postgres.get.GetTopics"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetTopics)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	topics := make([]service.Topic, 0)
+	for rows.Next() {
+		topic, sErr := scanTopic(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		topics = append(topics, topic)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return topics, nil
+}
+
+func scanTopic(row pgx.Row) (service.Topic, error) {
+	var topic service.Topic
+
+	if sErr := row.Scan(&topic.ID, &topic.Name, &topic.Title, &topic.Description, &topic.CreatedAt,
+		&topic.UpdatedAt); sErr != nil {
+		return service.Topic{}, sErr
+	}
+
+	return topic, nil
+}
+
+const queryGetLatestNotificationByContentHash = `SELECT ` + notificationFields + `
+FROM notifications
+WHERE user_id = $1 AND content_hash = $2 AND created_at >= $3 AND is_deleted = false
+ORDER BY created_at DESC
+LIMIT 1;`
+
+// GetLatestNotificationByContentHash returns the last notification of the user with the content hash which is
+// created since the given time, it returns false when there is no such notification.
+func (d *DB) GetLatestNotificationByContentHash(ctx context.Context, userID types.ID, contentHash string,
+	since time.Time) (service.Notification, bool, error) {
+	const op = "repository.postgres.get.GetLatestNotificationByContentHash"
+
+	notification, sErr := scanNotification(d.conn.Conn().QueryRow(ctx, queryGetLatestNotificationByContentHash, userID,
+		contentHash, since))
+	if sErr != nil {
+		if errors.Is(sErr, pgx.ErrNoRows) {
+			return service.Notification{}, false, nil
+		}
+
+		return service.Notification{}, false, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return notification, true, nil
+}
@@ -9,7 +9,7 @@ import (
 
 const queryMarkAllAsRead = `UPDATE notifications
 SET is_read = true
-WHERE user_id = $1;`
+WHERE user_id = $1 AND is_deleted = false AND overall_status NOT IN ('scheduled', 'canceled');`
 
 func (d *DB) MarkAllAsReadByUserID(ctx context.Context, userID types.ID) error {
 	const op = "repository.mark.MarkAllAsReadByUserID"
@@ -24,7 +24,7 @@ func (d *DB) MarkAllAsReadByUserID(ctx context.Context, userID types.ID) error {
 
 const queryMarkAsRead = `UPDATE notifications
 SET is_read = true
-WHERE id = $1 AND user_id = $2;`
+WHERE id = $1 AND user_id = $2 AND is_deleted = false;`
 
 func (d *DB) MarkAsRead(ctx context.Context, notificationID, userID types.ID) error {
 	const op = "repository.mark.MarkAsRead"
@@ -36,3 +36,35 @@ func (d *DB) MarkAsRead(ctx context.Context, notificationID, userID types.ID) er
 
 	return nil
 }
+
+const queryArchive = `UPDATE notifications
+SET is_archived = $3, archived_at = CASE WHEN $3 THEN NOW() ELSE NULL END
+WHERE id = $1 AND user_id = $2 AND is_deleted = false;`
+
+// Archive archives or unarchives a notification of the user, it returns false if the notification does not exist.
+func (d *DB) Archive(ctx context.Context, notificationID, userID types.ID, archive bool) (bool, error) {
+	const op = "repository.mark.Archive"
+
+	tag, eErr := d.conn.Conn().Exec(ctx, queryArchive, notificationID, userID, archive)
+	if eErr != nil {
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+const querySoftDelete = `UPDATE notifications
+SET is_deleted = true, deleted_at = NOW()
+WHERE id = $1 AND user_id = $2 AND is_deleted = false;`
+
+// SoftDelete hides a notification of the user, it returns false if the notification does not exist.
+func (d *DB) SoftDelete(ctx context.Context, notificationID, userID types.ID) (bool, error) {
+	const op = "repository.mark.SoftDelete"
+
+	tag, eErr := d.conn.Conn().Exec(ctx, querySoftDelete, notificationID, userID)
+	if eErr != nil {
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const queryDeleteDeferredDelivery = `DELETE FROM notification_deferred_deliveries
+WHERE id = $1;`
+
+// DeleteDeferredDelivery removes the deferred delivery after it is delivered.
+func (d *DB) DeleteDeferredDelivery(ctx context.Context, deliveryID int64) error {
+	const op = "repository.postgres.remove.DeleteDeferredDelivery"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryDeleteDeferredDelivery, deliveryID); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryUnsetTemplatesTopic = `UPDATE templates
+SET topic = NULL
+WHERE topic = (SELECT name FROM notification_topics WHERE id = $1)
+RETURNING name;`
+
+const queryDeleteTopic = `DELETE FROM notification_topics
+WHERE id = $1;`
+
+// DeleteTopic removes the topic from its templates and deletes it in a single transaction,
+// it returns the name of the templates which were mapped to the topic.
+func (d *DB) DeleteTopic(ctx context.Context, id types.ID) ([]string, error) {
+	const op = "repository.postgres.remove.DeleteTopic"
+
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return nil, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	rows, qErr := tx.Query(ctx, queryUnsetTemplatesTopic, id)
+	if qErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	templateNames := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if sErr := rows.Scan(&name); sErr != nil {
+			rows.Close()
+
+			if rErr := tx.Rollback(ctx); rErr != nil {
+				return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+			}
+
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		templateNames = append(templateNames, name)
+	}
+	rows.Close()
+
+	if rErr := rows.Err(); rErr != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return nil, richerror.New(op).WithWrapError(rbErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if _, eErr := tx.Exec(ctx, queryDeleteTopic, id); eErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return nil, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return nil, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return templateNames, nil
+}
+
+const queryReleaseIdempotencyKey = `DELETE FROM notification_idempotency_keys
+WHERE user_id = $1 AND idempotency_key = $2 AND notification_id = $3;`
+
+// ReleaseIdempotencyKey removes the idempotency key when it is still claimed by claimID, so the claim of a request
+// that has taken over a stale claim is kept.
+func (d *DB) ReleaseIdempotencyKey(ctx context.Context, userID types.ID, key string, claimID types.ID) error {
+	const op = "repository.postgres.remove.ReleaseIdempotencyKey"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryReleaseIdempotencyKey, userID, key, claimID); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteExpiredIdempotencyKeys = `DELETE FROM notification_idempotency_keys
+WHERE expires_at <= $1;`
+
+func (d *DB) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) error {
+	const op = "repository.postgres.remove.DeleteExpiredIdempotencyKeys"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryDeleteExpiredIdempotencyKeys, now); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
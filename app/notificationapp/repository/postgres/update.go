@@ -3,35 +3,69 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
 	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 	"github.com/syntaxfa/quick-connect/types"
 )
 
 const queryUpdateTemplate = `UPDATE templates
-SET name = $1, contents = $2
-WHERE id = $3;`
+SET name = $1, category = $2, topic = NULLIF($3, ''), dedup_window_seconds = $4, contents = $5, version = version + 1
+WHERE id = $6
+RETURNING version;`
 
-func (d *DB) UpdateTemplate(ctx context.Context, id types.ID, req service.AddTemplateRequest) error {
+// UpdateTemplate overwrites the current template contents and stores them as a new version, it returns the new version.
+func (d *DB) UpdateTemplate(ctx context.Context, id types.ID, req service.AddTemplateRequest) (int, error) {
 	const op = "repository.postgres.update.UpdateTemplate"
 
 	jsonContents, mErr := json.Marshal(req.Contents)
 	if mErr != nil {
-		return richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected)
+		return 0, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected)
 	}
 
-	if _, eErr := d.conn.Conn().Exec(ctx, queryUpdateTemplate, req.Name, jsonContents, id); eErr != nil {
-		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return 0, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
 	}
 
-	return nil
+	var version int
+	if qErr := tx.QueryRow(ctx, queryUpdateTemplate, req.Name, req.Category, req.Topic, req.DedupWindowSeconds,
+		jsonContents, id).Scan(&version); qErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return 0, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return 0, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if _, eErr := tx.Exec(ctx, queryCreateTemplateVersion, ulid.Make().String(), id, version, jsonContents); eErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return 0, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return 0, richerror.New(op).WithMessage("can't insert into template_versions table").
+			WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return 0, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return version, nil
 }
 
 const queryUpdateUserSetting = `UPDATE user_notification_settings
-SET lang = $1, ignore_channels = $2
-WHERE user_id = $3;`
+SET lang = $1, ignore_channels = $2, digest_preferences = $3, timezone = $4, quiet_hours = $5,
+    topic_preferences = COALESCE($6, topic_preferences)
+WHERE user_id = $7;`
 
+// UpdateUserSetting updates the setting of the user, the stored topic preferences are kept when the request doesn't have them.
 func (d *DB) UpdateUserSetting(ctx context.Context, userID types.ID, req service.UpdateUserSettingRequest) error {
 	const op = "repository.postgres.update.UpdateUserSetting"
 
@@ -41,9 +75,479 @@ func (d *DB) UpdateUserSetting(ctx context.Context, userID types.ID, req service
 			WithKind(richerror.KindUnexpected)
 	}
 
-	if _, eErr := d.conn.Conn().Exec(ctx, queryUpdateUserSetting, req.Lang, jsonChannels, userID); eErr != nil {
+	jsonDigest, mdErr := json.Marshal(req.DigestPreferences)
+	if mdErr != nil {
+		return richerror.New(op).WithMessage("can't marshal digest preferences").WithWrapError(mdErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	jsonQuietHours, mqErr := json.Marshal(req.QuietHours)
+	if mqErr != nil {
+		return richerror.New(op).WithMessage("can't marshal quiet hours").WithWrapError(mqErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	var jsonTopics []byte
+	if req.TopicPreferences != nil {
+		var mtErr error
+		if jsonTopics, mtErr = json.Marshal(req.TopicPreferences); mtErr != nil {
+			return richerror.New(op).WithMessage("can't marshal topic preferences").WithWrapError(mtErr).
+				WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryUpdateUserSetting, req.Lang, jsonChannels, jsonDigest, req.Timezone,
+		jsonQuietHours, jsonTopics, userID); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+// queryClaimDueScheduledNotifications moves due scheduled notifications out of the scheduled state.
+// FOR UPDATE SKIP LOCKED guarantees that each notification is claimed by only one notification instance.
+// The seq is renewed, so the websocket clients which reconnect after the dispatch receive it in their replay.
+const queryClaimDueScheduledNotifications = `UPDATE notifications
+SET overall_status = CASE
+        WHEN jsonb_array_length(channel_deliveries) = 1 AND channel_deliveries->0->>'channel' = 'in_app'
+        THEN 'sent'::notification_status
+        ELSE 'pending'::notification_status
+    END,
+    dispatched_at = NOW(),
+    seq = nextval(pg_get_serial_sequence('notifications', 'seq'))
+WHERE id IN (
+    SELECT id FROM notifications
+    WHERE overall_status = 'scheduled' AND send_at <= $1
+    ORDER BY send_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + notificationFields + `;`
+
+func (d *DB) ClaimDueScheduledNotifications(ctx context.Context, now time.Time, limit int) ([]service.Notification, error) {
+	const op = "repository.postgres.update.ClaimDueScheduledNotifications"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryClaimDueScheduledNotifications, now, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var notifications []service.Notification
+	for rows.Next() {
+		notification, sErr := scanNotification(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return notifications, nil
+}
+
+const queryCancelScheduledNotification = `UPDATE notifications
+SET overall_status = 'canceled'
+WHERE id = $1 AND overall_status = 'scheduled';`
+
+func (d *DB) CancelScheduledNotification(ctx context.Context, notificationID types.ID) (bool, error) {
+	const op = "repository.postgres.update.CancelScheduledNotification"
+
+	tag, eErr := d.conn.Conn().Exec(ctx, queryCancelScheduledNotification, notificationID)
+	if eErr != nil {
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+const queryRescheduleNotification = `UPDATE notifications
+SET send_at = $1, overall_status = 'scheduled'
+WHERE id = $2 AND overall_status IN ('scheduled', 'canceled');`
+
+func (d *DB) RescheduleNotification(ctx context.Context, notificationID types.ID, sendAt time.Time) (bool, error) {
+	const op = "repository.postgres.update.RescheduleNotification"
+
+	tag, eErr := d.conn.Conn().Exec(ctx, queryRescheduleNotification, sendAt, notificationID)
+	if eErr != nil {
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+const queryCancelCampaign = `UPDATE campaigns
+SET status = 'canceled', completed_at = NOW()
+WHERE id = $1 AND status IN ('queued', 'running');`
+
+func (d *DB) CancelCampaign(ctx context.Context, campaignID types.ID) (bool, error) {
+	const op = "repository.postgres.update.CancelCampaign"
+
+	tag, eErr := d.conn.Conn().Exec(ctx, queryCancelCampaign, campaignID)
+	if eErr != nil {
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// queryClaimCampaignRecipients locks pending recipients of active campaigns for the current campaign worker.
+// FOR UPDATE SKIP LOCKED guarantees that each recipient is claimed by only one notification instance.
+const queryClaimCampaignRecipients = `UPDATE campaign_recipients
+SET status = 'processing', locked_at = NOW()
+WHERE id IN (
+    SELECT r.id FROM campaign_recipients r
+    JOIN campaigns c ON c.id = r.campaign_id
+    WHERE r.status = 'pending' AND c.status IN ('queued', 'running')
+    ORDER BY r.id
+    LIMIT $1
+    FOR UPDATE OF r SKIP LOCKED
+)
+RETURNING id, campaign_id, external_user_id, status, notification_id, error;`
+
+func (d *DB) ClaimCampaignRecipients(ctx context.Context, limit int) ([]service.CampaignRecipient, error) {
+	const op = "repository.postgres.update.ClaimCampaignRecipients"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryClaimCampaignRecipients, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var recipients []service.CampaignRecipient
+	for rows.Next() {
+		var recipient service.CampaignRecipient
+		if sErr := rows.Scan(&recipient.ID, &recipient.CampaignID, &recipient.ExternalUserID, &recipient.Status,
+			&recipient.NotificationID, &recipient.Error); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return recipients, nil
+}
+
+// queryReleaseStaleCampaignRecipients gives back recipients of a crashed campaign worker to the queue.
+const queryReleaseStaleCampaignRecipients = `UPDATE campaign_recipients
+SET status = 'pending', locked_at = NULL
+WHERE status = 'processing' AND locked_at < $1;`
+
+func (d *DB) ReleaseStaleCampaignRecipients(ctx context.Context, lockedBefore time.Time) error {
+	const op = "repository.postgres.update.ReleaseStaleCampaignRecipients"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryReleaseStaleCampaignRecipients, lockedBefore); eErr != nil {
 		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
 	}
 
 	return nil
 }
+
+const queryUpdateCampaignRecipient = `UPDATE campaign_recipients
+SET status = $1, notification_id = $2, error = $3, locked_at = NULL
+WHERE id = $4;`
+
+const queryIncrementCampaignCounts = `UPDATE campaigns
+SET sent_count = sent_count + $1, failed_count = failed_count + $2, skipped_count = skipped_count + $3,
+    status = CASE WHEN status = 'queued' THEN 'running'::campaign_status ELSE status END
+WHERE id = $4;`
+
+// SaveCampaignRecipientResults stores the recipient results and increments the campaign counters in a single transaction.
+func (d *DB) SaveCampaignRecipientResults(ctx context.Context, campaignID types.ID, results []service.CampaignRecipientResult) error {
+	const op = "repository.postgres.update.SaveCampaignRecipientResults"
+
+	var sentCount, failedCount, skippedCount int
+	batch := &pgx.Batch{}
+	for _, result := range results {
+		batch.Queue(queryUpdateCampaignRecipient, result.Status, result.NotificationID, result.Error, result.RecipientID)
+
+		switch result.Status {
+		case service.CampaignRecipientStatusSent:
+			sentCount++
+		case service.CampaignRecipientStatusSkipped:
+			skippedCount++
+		default:
+			failedCount++
+		}
+	}
+	batch.Queue(queryIncrementCampaignCounts, sentCount, failedCount, skippedCount, campaignID)
+
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	if bErr := tx.SendBatch(ctx, batch).Close(); bErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return nil
+}
+
+const queryCompleteFinishedCampaigns = `UPDATE campaigns c
+SET status = 'completed', completed_at = NOW()
+WHERE c.status IN ('queued', 'running') AND NOT EXISTS (
+    SELECT 1 FROM campaign_recipients r
+    WHERE r.campaign_id = c.id AND r.status IN ('pending', 'processing')
+);`
+
+func (d *DB) CompleteFinishedCampaigns(ctx context.Context) error {
+	const op = "repository.postgres.update.CompleteFinishedCampaigns"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryCompleteFinishedCampaigns); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+// queryClaimDueDigestItems claims pending items of the digests whose oldest item waited at least one digest window,
+// the requeued items are held back until their deliver_after. FOR UPDATE SKIP LOCKED guarantees that each digest item
+// is claimed by only one notification instance.
+const queryClaimDueDigestItems = `UPDATE notification_digest_items
+SET digested_at = NOW()
+WHERE id IN (
+    SELECT i.id FROM notification_digest_items i
+    JOIN (
+        SELECT user_id, channel, frequency FROM notification_digest_items
+        WHERE digested_at IS NULL AND (deliver_after IS NULL OR deliver_after <= NOW())
+        GROUP BY user_id, channel, frequency
+        HAVING MIN(created_at) <= CASE WHEN frequency = 'hourly' THEN $1::timestamptz ELSE $2::timestamptz END
+        LIMIT $3
+    ) d ON d.user_id = i.user_id AND d.channel = i.channel AND d.frequency = i.frequency
+    WHERE i.digested_at IS NULL AND (i.deliver_after IS NULL OR i.deliver_after <= NOW())
+    FOR UPDATE OF i SKIP LOCKED
+)
+RETURNING id, user_id, notification_id, channel, frequency, created_at;`
+
+func (d *DB) ClaimDueDigestItems(ctx context.Context, hourlyBefore, dailyBefore time.Time, limit int) ([]service.DigestItem, error) {
+	const op = "repository.postgres.update.ClaimDueDigestItems"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryClaimDueDigestItems, hourlyBefore, dailyBefore, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var items []service.DigestItem
+	for rows.Next() {
+		var item service.DigestItem
+		if sErr := rows.Scan(&item.ID, &item.UserID, &item.NotificationID, &item.Channel, &item.Frequency,
+			&item.CreatedAt); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		items = append(items, item)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return items, nil
+}
+
+const queryRequeueDigestItems = `UPDATE notification_digest_items
+SET digested_at = NULL, deliver_after = $2
+WHERE id = ANY($1);`
+
+// RequeueDigestItems gives back the digest items that could not be sent, they are claimed again after deliverAfter.
+func (d *DB) RequeueDigestItems(ctx context.Context, itemIDs []int64, deliverAfter time.Time) error {
+	const op = "repository.postgres.update.RequeueDigestItems"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryRequeueDigestItems, itemIDs, deliverAfter); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryGetNotificationDeliveriesForUpdate = `SELECT channel_deliveries, overall_status
+FROM notifications
+WHERE id = $1
+FOR UPDATE;`
+
+const queryUpdateNotificationDeliveries = `UPDATE notifications
+SET channel_deliveries = $1, overall_status = $2
+WHERE id = $3;`
+
+// queryCreateDeliveryEvent an event whose provider event id is saved before is not inserted.
+const queryCreateDeliveryEvent = `INSERT INTO notification_delivery_events (id, notification_id, channel, type, provider, url, error,
+occurred_at, provider_event_id)
+VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, NULLIF($9, ''))
+ON CONFLICT (provider, provider_event_id) WHERE provider_event_id IS NOT NULL DO NOTHING;`
+
+// ApplyDeliveryEvent applies the event on the channel deliveries of the notification and saves the event,
+// it returns false when the notification or its channel does not exist or the provider event is applied before.
+// The notification row is locked, so the concurrent events of a notification are applied one by one.
+func (d *DB) ApplyDeliveryEvent(ctx context.Context, event service.DeliveryEvent) (bool, error) {
+	const op = "repository.postgres.update.ApplyDeliveryEvent"
+
+	tx, tErr := d.conn.Conn().Begin(ctx)
+	if tErr != nil {
+		return false, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	var jsonDeliveries json.RawMessage
+	var overallStatus service.OverallStatus
+	if qErr := tx.QueryRow(ctx, queryGetNotificationDeliveriesForUpdate, event.NotificationID).
+		Scan(&jsonDeliveries, &overallStatus); qErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	var deliveries []service.ChannelDelivery
+	if uErr := json.Unmarshal(jsonDeliveries, &deliveries); uErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if !service.ApplyDeliveryEvent(deliveries, event) {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, nil
+	}
+
+	cmdTag, ceErr := tx.Exec(ctx, queryCreateDeliveryEvent, event.ID, event.NotificationID, event.Channel, event.Type,
+		event.Provider, event.URL, event.Error, event.OccurredAt, event.ProviderEventID)
+	if ceErr != nil || cmdTag.RowsAffected() == 0 {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if ceErr != nil {
+			return false, richerror.New(op).WithMessage("can't insert into notification_delivery_events table").
+				WithWrapError(ceErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, nil
+	}
+
+	jsonDeliveries, mErr := json.Marshal(deliveries)
+	if mErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if _, eErr := tx.Exec(ctx, queryUpdateNotificationDeliveries, jsonDeliveries,
+		service.OverallStatusOf(deliveries, overallStatus), event.NotificationID); eErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return false, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return true, nil
+}
+
+const queryUpdateTopic = `UPDATE notification_topics
+SET title = $1, description = $2
+WHERE id = $3
+RETURNING ` + topicFields + `;`
+
+func (d *DB) UpdateTopic(ctx context.Context, id types.ID, req service.AddTopicRequest) (service.Topic, error) {
+	const op = "repository.postgres.update.UpdateTopic"
+
+	topic, sErr := scanTopic(d.conn.Conn().QueryRow(ctx, queryUpdateTopic, req.Title, req.Description, id))
+	if sErr != nil {
+		return service.Topic{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return topic, nil
+}
+
+const queryBindIdempotencyKey = `UPDATE notification_idempotency_keys
+SET notification_id = $4
+WHERE user_id = $1 AND idempotency_key = $2 AND notification_id = $3;`
+
+// BindIdempotencyKey sets the notification of the idempotency key when it is still claimed by claimID.
+func (d *DB) BindIdempotencyKey(ctx context.Context, userID types.ID, key string, claimID, notificationID types.ID) error {
+	const op = "repository.postgres.update.BindIdempotencyKey"
+
+	if _, eErr := d.conn.Conn().Exec(ctx, queryBindIdempotencyKey, userID, key, claimID, notificationID); eErr != nil {
+		return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+// queryClaimDueDeferredDeliveries claims the deferred deliveries whose quiet window has ended until $2, a delivery
+// that is not removed until then is claimed again. FOR UPDATE SKIP LOCKED guarantees that each delivery is claimed
+// by only one notification instance.
+const queryClaimDueDeferredDeliveries = `UPDATE notification_deferred_deliveries
+SET claimed_until = $2
+WHERE id IN (
+    SELECT id FROM notification_deferred_deliveries
+    WHERE deliver_at <= $1 AND (claimed_until IS NULL OR claimed_until <= $1)
+    ORDER BY deliver_at
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, notification_id, user_id, channel, deliver_at;`
+
+func (d *DB) ClaimDueDeferredDeliveries(ctx context.Context, now, claimedUntil time.Time, limit int) ([]service.DeferredDelivery, error) {
+	const op = "repository.postgres.update.ClaimDueDeferredDeliveries"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryClaimDueDeferredDeliveries, now, claimedUntil, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	var deliveries []service.DeferredDelivery
+	for rows.Next() {
+		var delivery service.DeferredDelivery
+		if sErr := rows.Scan(&delivery.ID, &delivery.NotificationID, &delivery.UserID, &delivery.Channel,
+			&delivery.DeliverAt); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const csvExternalUserIDHeader = "external_user_id"
+
+const defaultCampaignWorkerInterval = 5 * time.Second
+
+// Broadcast creates a campaign for many users, the campaign worker fans out the notifications in batches.
+func (s Service) Broadcast(ctx context.Context, req BroadcastRequest) (Campaign, error) {
+	const op = "service.campaign.Broadcast"
+
+	if vErr := s.vld.ValidateBroadcastRequest(req); vErr != nil {
+		return Campaign{}, vErr
+	}
+
+	if cErr := s.checkSendableTemplate(ctx, req.TemplateName); cErr != nil {
+		return Campaign{}, cErr
+	}
+
+	externalUserIDs := req.ExternalUserIDs
+	if req.CSVFileID != "" {
+		var cErr error
+		externalUserIDs, cErr = s.getExternalUserIDsFromCSV(ctx, req.CSVFileID)
+		if cErr != nil {
+			return Campaign{}, cErr
+		}
+	}
+
+	externalUserIDs = uniqueExternalUserIDs(externalUserIDs)
+	if len(externalUserIDs) == 0 {
+		return Campaign{}, richerror.New(op).WithMessage(servermsg.MsgCampaignHasNoRecipient).WithKind(richerror.KindBadRequest)
+	}
+
+	req.ID = types.ID(ulid.Make().String())
+
+	campaign, cErr := s.repo.CreateCampaign(ctx, req, externalUserIDs)
+	if cErr != nil {
+		return Campaign{}, errlog.ErrLog(richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return campaign, nil
+}
+
+func (s Service) GetCampaign(ctx context.Context, campaignID types.ID) (Campaign, error) {
+	const op = "service.campaign.GetCampaign"
+
+	if eErr := s.checkCampaignExists(ctx, campaignID, op); eErr != nil {
+		return Campaign{}, eErr
+	}
+
+	campaign, gErr := s.repo.GetCampaignByID(ctx, campaignID)
+	if gErr != nil {
+		return Campaign{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return campaign, nil
+}
+
+func (s Service) CampaignList(ctx context.Context, req ListCampaignRequest) (ListCampaignResponse, error) {
+	const op = "service.campaign.CampaignList"
+
+	if req.Status != "" && !IsValidCampaignStatus(req.Status) {
+		return ListCampaignResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidCampaignStatus).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if bErr := req.Paginated.BasicValidation(); bErr != nil {
+		return ListCampaignResponse{}, richerror.New(op).WithKind(richerror.KindBadRequest)
+	}
+
+	campaigns, gErr := s.repo.GetCampaigns(ctx, req)
+	if gErr != nil {
+		return ListCampaignResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return campaigns, nil
+}
+
+// CancelCampaign stops a queued or running campaign, recipients that are already processed are not affected.
+func (s Service) CancelCampaign(ctx context.Context, campaignID types.ID) (Campaign, error) {
+	const op = "service.campaign.CancelCampaign"
+
+	if eErr := s.checkCampaignExists(ctx, campaignID, op); eErr != nil {
+		return Campaign{}, eErr
+	}
+
+	canceled, cErr := s.repo.CancelCampaign(ctx, campaignID)
+	if cErr != nil {
+		return Campaign{}, errlog.ErrLog(richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !canceled {
+		return Campaign{}, richerror.New(op).WithMessage(servermsg.MsgCampaignIsNotCancelable).WithKind(richerror.KindConflict)
+	}
+
+	campaign, gErr := s.repo.GetCampaignByID(ctx, campaignID)
+	if gErr != nil {
+		return Campaign{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return campaign, nil
+}
+
+// campaignWorkerInterval a non-positive interval panics the ticker, so it is defaulted.
+func (s Service) campaignWorkerInterval() time.Duration {
+	if s.cfg.CampaignWorkerInterval > 0 {
+		return s.cfg.CampaignWorkerInterval
+	}
+
+	return defaultCampaignWorkerInterval
+}
+
+// RunCampaignWorker fans out campaign notifications every CampaignWorkerInterval until ctx is canceled.
+// Recipients are claimed with row level locks, so running several notification instances is safe.
+func (s Service) RunCampaignWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.campaignWorkerInterval())
+	defer ticker.Stop()
+
+	for {
+		if pErr := s.processCampaigns(ctx); pErr != nil {
+			errlog.WithoutErrContext(ctx, pErr, s.logger)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			s.logger.Info("stopping campaign worker")
+
+			return
+		}
+	}
+}
+
+func (s Service) processCampaigns(ctx context.Context) error {
+	const op = "service.campaign.processCampaigns"
+
+	if rErr := s.repo.ReleaseStaleCampaignRecipients(ctx, time.Now().Add(-s.cfg.CampaignLockTimeout)); rErr != nil {
+		return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	for {
+		recipients, cErr := s.repo.ClaimCampaignRecipients(ctx, s.cfg.CampaignBatchSize)
+		if cErr != nil {
+			return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if len(recipients) == 0 {
+			break
+		}
+
+		if pErr := s.processCampaignRecipients(ctx, recipients); pErr != nil {
+			return richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if len(recipients) < s.cfg.CampaignBatchSize {
+			break
+		}
+	}
+
+	if cErr := s.repo.CompleteFinishedCampaigns(ctx); cErr != nil {
+		return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+// processCampaignRecipients stores the result of each recipient as soon as its notification is sent, so a failure of
+// the batch doesn't give back the recipients that are already sent to the queue and they are not notified again.
+func (s Service) processCampaignRecipients(ctx context.Context, recipients []CampaignRecipient) error {
+	campaigns := make(map[types.ID]Campaign)
+
+	for _, recipient := range recipients {
+		campaign, ok := campaigns[recipient.CampaignID]
+		if !ok {
+			var gErr error
+			campaign, gErr = s.repo.GetCampaignByID(ctx, recipient.CampaignID)
+			if gErr != nil {
+				return gErr
+			}
+
+			campaigns[recipient.CampaignID] = campaign
+		}
+
+		result := s.sendCampaignNotification(ctx, campaign, recipient)
+		if sErr := s.repo.SaveCampaignRecipientResults(ctx, campaign.ID, []CampaignRecipientResult{result}); sErr != nil {
+			return sErr
+		}
+	}
+
+	s.logger.DebugContext(ctx, "campaign batch processed", slog.Int("count", len(recipients)))
+
+	return nil
+}
+
+// sendCampaignNotification sends the campaign notification only through the channels the recipient has not opted out of.
+func (s Service) sendCampaignNotification(ctx context.Context, campaign Campaign, recipient CampaignRecipient) CampaignRecipientResult {
+	const op = "service.campaign.sendCampaignNotification"
+
+	result := CampaignRecipientResult{RecipientID: recipient.ID, Status: CampaignRecipientStatusFailed}
+
+	userID, gErr := s.getUserIDFromExternalUserID(ctx, recipient.ExternalUserID)
+	if gErr != nil {
+		errMsg := gErr.Error()
+		result.Error = &errMsg
+
+		return result
+	}
+
+	userSetting, usErr := s.GetUserSetting(ctx, recipient.ExternalUserID)
+	if usErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(usErr).WithMessage(fmt.Sprintf("can't get user setting for user id: %s",
+			userID)), s.logger)
+	}
+
+	campaignNotification := Notification{Type: campaign.Type}
+	templates, tErr := s.getTemplates(ctx, []string{campaign.TemplateName})
+	if tErr != nil {
+		errMsg := tErr.Error()
+		result.Error = &errMsg
+
+		return result
+	}
+	campaignNotification.Topic = templates[campaign.TemplateName].Topic
+
+	channels := make([]ChannelDeliveryRequest, 0, len(campaign.ChannelDeliveries))
+	for _, channel := range campaign.ChannelDeliveries {
+		if s.CheckNotificationAccessToSend(campaignNotification, userSetting, channel.Channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	if len(channels) == 0 {
+		result.Status = CampaignRecipientStatusSkipped
+
+		return result
+	}
+
+	notification, cErr := s.createNotification(ctx, SendNotificationRequest{
+		UserID:            userID,
+		ExternalUserID:    recipient.ExternalUserID,
+		Type:              campaign.Type,
+		Data:              campaign.Data,
+		TemplateName:      campaign.TemplateName,
+		DynamicBodyData:   campaign.DynamicBodyData,
+		DynamicTitleData:  campaign.DynamicTitleData,
+		ChannelDeliveries: channels,
+	})
+	if cErr != nil {
+		errMsg := cErr.Error()
+		result.Error = &errMsg
+
+		return result
+	}
+
+	result.Status = CampaignRecipientStatusSent
+	result.NotificationID = &notification.ID
+
+	return result
+}
+
+// getExternalUserIDsFromCSV downloads a CSV file from the storage service and confirms it, so the storage
+// service keeps the file.
+func (s Service) getExternalUserIDsFromCSV(ctx context.Context, fileID types.ID) ([]string, error) {
+	const op = "service.campaign.getExternalUserIDsFromCSV"
+
+	ctxWithToken, tErr := s.tokenManager.SetTokenInContext(ctx)
+	if tErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	fileInfo, fErr := s.storageSvc.GetFileInfo(ctxWithToken, &storagepb.GetFileInfoRequest{FileId: string(fileID)})
+	if fErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(fErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if fileInfo.GetSize() > s.cfg.CampaignMaxCSVSize {
+		return nil, richerror.New(op).WithMessage(servermsg.MsgInvalidCampaignCSVFile).WithKind(richerror.KindBadRequest)
+	}
+
+	link, lErr := s.storageSvc.GetLink(ctxWithToken, &storagepb.GetLinkRequest{FileId: string(fileID)})
+	if lErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(lErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	externalUserIDs, dErr := s.downloadExternalUserIDs(ctx, link.GetUrl())
+	if dErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindBadRequest).
+			WithMessage(servermsg.MsgInvalidCampaignCSVFile), s.logger)
+	}
+
+	if _, cErr := s.storageSvc.ConfirmFile(ctxWithToken, &storagepb.ConfirmFileRequest{FileId: string(fileID)}); cErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return externalUserIDs, nil
+}
+
+func (s Service) downloadExternalUserIDs(ctx context.Context, url string) ([]string, error) {
+	req, rErr := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	resp, dErr := s.httpClient.Do(req)
+	if dErr != nil {
+		return nil, dErr
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil {
+			s.logger.WarnContext(ctx, "can't close csv response body", slog.String("error", cErr.Error()))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while downloading csv file", resp.StatusCode)
+	}
+
+	reader := csv.NewReader(io.LimitReader(resp.Body, s.cfg.CampaignMaxCSVSize))
+	reader.FieldsPerRecord = -1
+
+	var externalUserIDs []string
+	for {
+		record, rcErr := reader.Read()
+		if errors.Is(rcErr, io.EOF) {
+			break
+		}
+
+		if rcErr != nil {
+			return nil, rcErr
+		}
+
+		if len(record) == 0 {
+			continue
+		}
+
+		externalUserID := strings.TrimSpace(record[0])
+		if externalUserID == "" || strings.EqualFold(externalUserID, csvExternalUserIDHeader) {
+			continue
+		}
+
+		externalUserIDs = append(externalUserIDs, externalUserID)
+	}
+
+	return externalUserIDs, nil
+}
+
+func (s Service) checkCampaignExists(ctx context.Context, campaignID types.ID, op string) error {
+	exists, eErr := s.repo.IsExistCampaignByID(ctx, campaignID)
+	if eErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return richerror.New(op).WithMessage(servermsg.MsgCampaignNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	return nil
+}
+
+func uniqueExternalUserIDs(externalUserIDs []string) []string {
+	seen := make(map[string]struct{}, len(externalUserIDs))
+	unique := make([]string, 0, len(externalUserIDs))
+
+	for _, externalUserID := range externalUserIDs {
+		externalUserID = strings.TrimSpace(externalUserID)
+		if externalUserID == "" || len(externalUserID) > maxExternalUserIDLength {
+			continue
+		}
+
+		if _, ok := seen[externalUserID]; ok {
+			continue
+		}
+
+		seen[externalUserID] = struct{}{}
+		unique = append(unique, externalUserID)
+	}
+
+	return unique
+}
@@ -13,4 +13,26 @@ type Config struct {
 	PingPeriod              time.Duration
 	DefaultUserLanguage     string        `koanf:"default_user_language"`
 	TemplateCacheExpiration time.Duration `koanf:"template_cache_expiration"`
+	SchedulerInterval       time.Duration `koanf:"scheduler_interval"`
+	SchedulerBatchSize      int           `koanf:"scheduler_batch_size"`
+	CampaignWorkerInterval  time.Duration `koanf:"campaign_worker_interval"`
+	CampaignBatchSize       int           `koanf:"campaign_batch_size"`
+	CampaignLockTimeout     time.Duration `koanf:"campaign_lock_timeout"`
+	CampaignMaxCSVSize      int64         `koanf:"campaign_max_csv_size"`
+	DigestWorkerInterval    time.Duration `koanf:"digest_worker_interval"`
+	DigestBatchSize         int           `koanf:"digest_batch_size"`
+	DigestTemplateName      string        `koanf:"digest_template_name"`
+	DigestChannelName       string        `koanf:"digest_channel_name"` // consumed by the external email and sms senders
+	TemplateChannelName     string        `koanf:"template_channel_name"`
+	// WebhookTokens are the tokens of the provider webhooks by provider name, providers without a token are disabled.
+	WebhookTokens map[string]string `koanf:"webhook_tokens"`
+	// TrackingBaseURL is the public url of the client server, email tracking and the unsubscribe links of the emails
+	// are disabled when it is empty. TrackingSecret signs both of them.
+	TrackingBaseURL string `koanf:"tracking_base_url"`
+	TrackingSecret  string `koanf:"tracking_secret"`
+	SendBulkMaxSize int    `koanf:"send_bulk_max_size"`
+	// ReplayLimit is the maximum number of missed notifications which are replayed to a reconnected websocket client.
+	ReplayLimit int `koanf:"replay_limit"`
+	// IdempotencyKeyTTL is how long the idempotency key of a sent notification is kept, it must be positive.
+	IdempotencyKeyTTL time.Duration `koanf:"idempotency_key_ttl"`
 }
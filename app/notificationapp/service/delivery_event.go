@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	timelineEventCreated    = "created"
+	timelineEventDispatched = "dispatched"
+)
+
+// VerifyWebhookToken checks the token of a provider webhook, providers without a configured token are disabled.
+func (s Service) VerifyWebhookToken(provider, token string) error {
+	const op = "service.delivery_event.VerifyWebhookToken"
+
+	expected, ok := s.cfg.WebhookTokens[provider]
+	if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(token)) != 1 {
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidWebhookToken).WithKind(richerror.KindUnAuthorized).
+			WithMeta(map[string]interface{}{"provider": provider})
+	}
+
+	return nil
+}
+
+// HandleDeliveryEvents applies the normalized events of a provider on the channel deliveries of the notifications.
+// The events are applied independently, when an event fails the other events are still applied and an error is
+// returned, so the provider retries the webhook, the events with a provider event id are not applied twice.
+func (s Service) HandleDeliveryEvents(ctx context.Context, provider string,
+	events []DeliveryEventRequest) (HandleWebhookResponse, error) {
+	const op = "service.delivery_event.HandleDeliveryEvents"
+
+	var resp HandleWebhookResponse
+	var failed int
+	for _, event := range events {
+		if event.NotificationID == "" || !IsValidChannelType(event.Channel) || !IsValidDeliveryEventType(event.Type) {
+			resp.Skipped++
+
+			continue
+		}
+
+		applied, aErr := s.applyDeliveryEvent(ctx, provider, event)
+		if aErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected).
+				WithMeta(map[string]interface{}{"provider": provider, "event": event}), s.logger)
+			failed++
+
+			continue
+		}
+
+		if applied {
+			resp.Accepted++
+		} else {
+			resp.Skipped++
+		}
+	}
+
+	if failed > 0 {
+		return resp, richerror.New(op).WithMessage(fmt.Sprintf("%d of %d events are not applied", failed, len(events))).
+			WithKind(richerror.KindUnexpected).WithMeta(map[string]interface{}{"provider": provider})
+	}
+
+	return resp, nil
+}
+
+// GetNotificationTimeline returns the events of a notification and its channel deliveries ordered by time.
+func (s Service) GetNotificationTimeline(ctx context.Context, notificationID types.ID) (NotificationTimelineResponse, error) {
+	const op = "service.delivery_event.GetNotificationTimeline"
+
+	if eErr := s.checkNotificationExists(ctx, notificationID, op); eErr != nil {
+		return NotificationTimelineResponse{}, eErr
+	}
+
+	notification, gErr := s.repo.GetNotificationByID(ctx, notificationID)
+	if gErr != nil {
+		return NotificationTimelineResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	events, geErr := s.repo.GetDeliveryEvents(ctx, notificationID)
+	if geErr != nil {
+		return NotificationTimelineResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(geErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	timeline := make([]TimelineEvent, 0, len(events)+2) //nolint:mnd // created and dispatched events
+	timeline = append(timeline, TimelineEvent{Type: timelineEventCreated, OccurredAt: notification.CreatedAt})
+	if notification.DispatchedAt != nil {
+		timeline = append(timeline, TimelineEvent{Type: timelineEventDispatched, OccurredAt: *notification.DispatchedAt})
+	}
+
+	for _, event := range events {
+		timeline = append(timeline, TimelineEvent{
+			Type:       string(event.Type),
+			Channel:    event.Channel,
+			Provider:   event.Provider,
+			URL:        event.URL,
+			Error:      event.Error,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].OccurredAt.Before(timeline[j].OccurredAt)
+	})
+
+	return NotificationTimelineResponse{
+		NotificationID:    notification.ID,
+		OverallStatus:     notification.OverallStatus,
+		ChannelDeliveries: notification.ChannelDeliveries,
+		Events:            timeline,
+	}, nil
+}
+
+func (s Service) applyDeliveryEvent(ctx context.Context, provider string, req DeliveryEventRequest) (bool, error) {
+	occurredAt := req.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	return s.repo.ApplyDeliveryEvent(ctx, DeliveryEvent{
+		ID:             types.ID(ulid.Make().String()),
+		NotificationID: req.NotificationID,
+		Channel:        req.Channel,
+		Type:           req.Type,
+		Provider:       provider,
+		URL:            req.URL,
+		Error:          req.Error,
+		OccurredAt:     occurredAt,
+
+		ProviderEventID: req.ProviderEventID,
+	})
+}
+
+// ApplyDeliveryEvent updates the delivery of the event channel, it returns false when the notification does not
+// have the channel. Statuses only move forward, e.g. a late sent event does not change a delivered channel,
+// but a bounce after the delivery is applied.
+func ApplyDeliveryEvent(deliveries []ChannelDelivery, event DeliveryEvent) bool {
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if delivery.Channel != event.Channel {
+			continue
+		}
+
+		occurredAt := event.OccurredAt
+		failed := delivery.Status == DeliveryStatusFailed || delivery.Status == DeliveryStatusBounced
+
+		switch event.Type {
+		case DeliveryEventSent:
+			delivery.LastAttemptAt = &occurredAt
+			if delivery.Status == DeliveryStatusPending || delivery.Status == DeliveryStatusRetrying {
+				delivery.Status = DeliveryStatusSent
+			}
+		case DeliveryEventDeferred:
+			delivery.LastAttemptAt = &occurredAt
+			delivery.AttemptCount++
+			delivery.Error = event.Error
+			if delivery.Status == DeliveryStatusPending || delivery.Status == DeliveryStatusSent {
+				delivery.Status = DeliveryStatusRetrying
+			}
+		case DeliveryEventBounced, DeliveryEventFailed:
+			delivery.LastAttemptAt = &occurredAt
+			delivery.Error = event.Error
+			delivery.Status = DeliveryStatusFailed
+			if event.Type == DeliveryEventBounced {
+				delivery.Status = DeliveryStatusBounced
+			}
+		case DeliveryEventDelivered, DeliveryEventOpened, DeliveryEventClicked:
+			if delivery.DeliveredAt == nil {
+				delivery.DeliveredAt = &occurredAt
+			}
+
+			if event.Type == DeliveryEventOpened && delivery.OpenedAt == nil {
+				delivery.OpenedAt = &occurredAt
+			}
+
+			if event.Type == DeliveryEventClicked && delivery.ClickedAt == nil {
+				delivery.ClickedAt = &occurredAt
+			}
+
+			if !failed {
+				delivery.Status = DeliveryStatusDelivered
+				delivery.Error = nil
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// OverallStatusOf computes the overall status from the channel deliveries, scheduled and canceled notifications
+// and notifications with pending channels keep their current status.
+func OverallStatusOf(deliveries []ChannelDelivery, current OverallStatus) OverallStatus {
+	if current == OverallStatusScheduled || current == OverallStatusCanceled {
+		return current
+	}
+
+	succeeded, failed := 0, 0
+	for _, delivery := range deliveries {
+		switch delivery.Status {
+		case DeliveryStatusRetrying:
+			return OverallStatusRetrying
+		case DeliveryStatusPending:
+			return current
+		case DeliveryStatusSent, DeliveryStatusDelivered:
+			succeeded++
+		case DeliveryStatusFailed, DeliveryStatusBounced:
+			failed++
+		case DeliveryStatusIgnored:
+		}
+	}
+
+	switch {
+	case succeeded > 0 && failed > 0:
+		return OverallStatusMixed
+	case succeeded > 0:
+		return OverallStatusSent
+	case failed > 0:
+		return OverallStatusFailed
+	default:
+		return current
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestHandleDeliveryEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []DeliveryEventRequest
+		failing  types.ID
+		accepted int
+		skipped  int
+		wantErr  bool
+	}{
+		{
+			name: "duplicate provider event",
+			events: []DeliveryEventRequest{
+				{NotificationID: "notification-1", Channel: ChannelTypeEmail, Type: DeliveryEventDelivered, ProviderEventID: "event-1"},
+				{NotificationID: "notification-1", Channel: ChannelTypeEmail, Type: DeliveryEventDelivered, ProviderEventID: "event-1"},
+			},
+			accepted: 1,
+			skipped:  1,
+		},
+		{
+			name: "failed event doesn't stop the others",
+			events: []DeliveryEventRequest{
+				{NotificationID: "notification-2", Channel: ChannelTypeEmail, Type: DeliveryEventDelivered, ProviderEventID: "event-1"},
+				{NotificationID: "notification-1", Channel: ChannelTypeEmail, Type: DeliveryEventDelivered, ProviderEventID: "event-2"},
+				{NotificationID: "notification-1", Channel: "fax", Type: DeliveryEventDelivered},
+			},
+			failing:  "notification-2",
+			accepted: 1,
+			skipped:  1,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			repo.notifications["notification-1"] = Notification{ID: "notification-1"}
+			repo.notifications["notification-2"] = Notification{ID: "notification-2"}
+			if test.failing != "" {
+				repo.eventErrs[test.failing] = errors.New("connection refused")
+			}
+			svc := Service{repo: repo, logger: discardLogger()}
+
+			resp, hErr := svc.HandleDeliveryEvents(context.Background(), "sendgrid", test.events)
+			if (hErr != nil) != test.wantErr {
+				t.Fatalf("expected error %t, got %v", test.wantErr, hErr)
+			}
+
+			if resp.Accepted != test.accepted || resp.Skipped != test.skipped {
+				t.Fatalf("expected %d accepted and %d skipped, got %+v", test.accepted, test.skipped, resp)
+			}
+		})
+	}
+}
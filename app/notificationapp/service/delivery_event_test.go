@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/app/notificationapp/service"
+)
+
+func TestApplyDeliveryEvent(t *testing.T) {
+	occurredAt := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	earlier := occurredAt.Add(-time.Hour)
+	providerErr := "mailbox does not exist"
+
+	tests := []struct {
+		name      string
+		delivery  service.ChannelDelivery
+		event     service.DeliveryEvent
+		applied   bool
+		status    service.DeliveryStatus
+		attempts  int
+		delivered *time.Time
+		opened    bool
+		clicked   bool
+		hasError  bool
+	}{
+		{
+			name:     "sent pending channel",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusPending},
+			event:    service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventSent},
+			applied:  true,
+			status:   service.DeliveryStatusSent,
+		},
+		{
+			name: "late sent keeps delivered channel",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusDelivered,
+				DeliveredAt: &earlier},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventSent},
+			applied:   true,
+			status:    service.DeliveryStatusDelivered,
+			delivered: &earlier,
+		},
+		{
+			name:     "deferred is retried",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusSent, AttemptCount: 1},
+			event:    service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventDeferred, Error: &providerErr},
+			applied:  true,
+			status:   service.DeliveryStatusRetrying,
+			attempts: 2,
+			hasError: true,
+		},
+		{
+			name: "bounce after delivery",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusDelivered,
+				DeliveredAt: &earlier},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventBounced, Error: &providerErr},
+			applied:   true,
+			status:    service.DeliveryStatusBounced,
+			delivered: &earlier,
+			hasError:  true,
+		},
+		{
+			name:     "failed channel",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeSMS, Status: service.DeliveryStatusRetrying},
+			event:    service.DeliveryEvent{Channel: service.ChannelTypeSMS, Type: service.DeliveryEventFailed, Error: &providerErr},
+			applied:  true,
+			status:   service.DeliveryStatusFailed,
+			hasError: true,
+		},
+		{
+			name:      "delivered",
+			delivery:  service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusSent},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventDelivered},
+			applied:   true,
+			status:    service.DeliveryStatusDelivered,
+			delivered: &occurredAt,
+		},
+		{
+			name:      "open implies delivery",
+			delivery:  service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusSent},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventOpened},
+			applied:   true,
+			status:    service.DeliveryStatusDelivered,
+			delivered: &occurredAt,
+			opened:    true,
+		},
+		{
+			name: "click keeps the first delivery",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusDelivered,
+				DeliveredAt: &earlier},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventClicked},
+			applied:   true,
+			status:    service.DeliveryStatusDelivered,
+			delivered: &earlier,
+			clicked:   true,
+		},
+		{
+			name: "open of a bounced channel keeps the bounce",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: service.DeliveryStatusBounced,
+				Error: &providerErr},
+			event:     service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventOpened},
+			applied:   true,
+			status:    service.DeliveryStatusBounced,
+			delivered: &occurredAt,
+			opened:    true,
+			hasError:  true,
+		},
+		{
+			name:     "channel of another notification",
+			delivery: service.ChannelDelivery{Channel: service.ChannelTypeSMS, Status: service.DeliveryStatusSent},
+			event:    service.DeliveryEvent{Channel: service.ChannelTypeEmail, Type: service.DeliveryEventDelivered},
+			status:   service.DeliveryStatusSent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deliveries := []service.ChannelDelivery{test.delivery}
+			test.event.OccurredAt = occurredAt
+
+			if applied := service.ApplyDeliveryEvent(deliveries, test.event); applied != test.applied {
+				t.Fatalf("expected applied %t, got %t", test.applied, applied)
+			}
+
+			delivery := deliveries[0]
+			if delivery.Status != test.status || delivery.AttemptCount != test.attempts {
+				t.Fatalf("expected status %s with %d attempts, got %s with %d", test.status, test.attempts,
+					delivery.Status, delivery.AttemptCount)
+			}
+
+			if (delivery.DeliveredAt == nil) != (test.delivered == nil) ||
+				(test.delivered != nil && !delivery.DeliveredAt.Equal(*test.delivered)) {
+				t.Fatalf("expected delivered at %v, got %v", test.delivered, delivery.DeliveredAt)
+			}
+
+			if (delivery.OpenedAt != nil) != test.opened || (delivery.ClickedAt != nil) != test.clicked {
+				t.Fatalf("expected opened %t and clicked %t, got %v and %v", test.opened, test.clicked,
+					delivery.OpenedAt, delivery.ClickedAt)
+			}
+
+			if (delivery.Error != nil) != test.hasError {
+				t.Fatalf("expected error %t, got %v", test.hasError, delivery.Error)
+			}
+		})
+	}
+}
+
+func TestOverallStatusOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []service.DeliveryStatus
+		current  service.OverallStatus
+		expected service.OverallStatus
+	}{
+		{name: "all sent", statuses: []service.DeliveryStatus{service.DeliveryStatusSent, service.DeliveryStatusDelivered},
+			current: service.OverallStatusPending, expected: service.OverallStatusSent},
+		{name: "all failed", statuses: []service.DeliveryStatus{service.DeliveryStatusFailed, service.DeliveryStatusBounced},
+			current: service.OverallStatusPending, expected: service.OverallStatusFailed},
+		{name: "sent and failed", statuses: []service.DeliveryStatus{service.DeliveryStatusDelivered,
+			service.DeliveryStatusBounced}, current: service.OverallStatusSent, expected: service.OverallStatusMixed},
+		{name: "retrying channel", statuses: []service.DeliveryStatus{service.DeliveryStatusSent,
+			service.DeliveryStatusRetrying}, current: service.OverallStatusPending, expected: service.OverallStatusRetrying},
+		{name: "pending channel keeps the status", statuses: []service.DeliveryStatus{service.DeliveryStatusSent,
+			service.DeliveryStatusPending}, current: service.OverallStatusPending, expected: service.OverallStatusPending},
+		{name: "ignored channels are skipped", statuses: []service.DeliveryStatus{service.DeliveryStatusIgnored,
+			service.DeliveryStatusSent}, current: service.OverallStatusPending, expected: service.OverallStatusSent},
+		{name: "only ignored channels", statuses: []service.DeliveryStatus{service.DeliveryStatusIgnored},
+			current: service.OverallStatusIgnored, expected: service.OverallStatusIgnored},
+		{name: "scheduled notification", statuses: []service.DeliveryStatus{service.DeliveryStatusFailed},
+			current: service.OverallStatusScheduled, expected: service.OverallStatusScheduled},
+		{name: "canceled notification", statuses: []service.DeliveryStatus{service.DeliveryStatusSent},
+			current: service.OverallStatusCanceled, expected: service.OverallStatusCanceled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deliveries := make([]service.ChannelDelivery, 0, len(test.statuses))
+			for _, status := range test.statuses {
+				deliveries = append(deliveries, service.ChannelDelivery{Channel: service.ChannelTypeEmail, Status: status})
+			}
+
+			if status := service.OverallStatusOf(deliveries, test.current); status != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	hourlyDigestWindow = time.Hour
+	dailyDigestWindow  = time.Hour * 24
+
+	defaultDigestWorkerInterval = time.Minute
+)
+
+// GetDigestFrequency returns how notifications of this type must be delivered to the user on the channel.
+// Critical and direct notifications and channels without digest support are always delivered immediately,
+// like critical and direct notifications bypass quiet hours.
+func (s Service) GetDigestFrequency(notificationType NotificationType, userSetting UserSetting,
+	channel ChannelType) DigestFrequency {
+	if notificationType == NotificationTypeCritical || notificationType == NotificationTypeDirect || !IsDigestChannel(channel) {
+		return DigestFrequencyImmediate
+	}
+
+	for _, preference := range userSetting.DigestPreferences {
+		if preference.Channel != channel {
+			continue
+		}
+
+		for _, nt := range preference.NotificationTypes {
+			if nt == notificationType {
+				return preference.Frequency
+			}
+		}
+	}
+
+	return DigestFrequencyImmediate
+}
+
+// queueDigestItems holds back email and sms deliveries of the notification that the user wants to receive in a digest.
+func (s Service) queueDigestItems(ctx context.Context, notification Notification) error {
+	const op = "service.digest.queueDigestItems"
+
+	if notification.Type == NotificationTypeCritical || notification.Type == NotificationTypeDirect ||
+		!hasDigestChannel(notification.ChannelDeliveries) {
+		return nil
+	}
+
+	userSetting, usErr := s.GetUserSetting(ctx, string(notification.UserID))
+	if usErr != nil {
+		return richerror.New(op).WithWrapError(usErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if len(userSetting.DigestPreferences) == 0 {
+		return nil
+	}
+
+	var items []DigestItem
+	for _, ch := range notification.ChannelDeliveries {
+		if !s.CheckNotificationAccessToSend(notification, userSetting, ch.Channel) {
+			continue
+		}
+
+		frequency := s.GetDigestFrequency(notification.Type, userSetting, ch.Channel)
+		if frequency == DigestFrequencyImmediate {
+			continue
+		}
+
+		items = append(items, DigestItem{
+			UserID:         notification.UserID,
+			NotificationID: notification.ID,
+			Channel:        ch.Channel,
+			Frequency:      frequency,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if sErr := s.repo.SaveDigestItems(ctx, items); sErr != nil {
+		return richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+func (s Service) digestWorkerInterval() time.Duration {
+	if s.cfg.DigestWorkerInterval > 0 {
+		return s.cfg.DigestWorkerInterval
+	}
+
+	return defaultDigestWorkerInterval
+}
+
+// RunDigestWorker sends due digests every DigestWorkerInterval until ctx is canceled.
+// Digest items are claimed with row level locks, so running several notification instances is safe.
+func (s Service) RunDigestWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.digestWorkerInterval())
+	defer ticker.Stop()
+
+	for {
+		if sErr := s.sendDueDigests(ctx, time.Now()); sErr != nil {
+			errlog.WithoutErrContext(ctx, sErr, s.logger)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			s.logger.Info("stopping digest worker")
+
+			return
+		}
+	}
+}
+
+type digestKey struct {
+	userID    types.ID
+	channel   ChannelType
+	frequency DigestFrequency
+}
+
+// sendDueDigests sends the digests that are due at now, the items that are requeued in the pass are held back
+// until after now, so they are not claimed again by the pass.
+func (s Service) sendDueDigests(ctx context.Context, now time.Time) error {
+	const op = "service.digest.sendDueDigests"
+
+	for {
+		items, cErr := s.repo.ClaimDueDigestItems(ctx, now.Add(-hourlyDigestWindow), now.Add(-dailyDigestWindow),
+			s.cfg.DigestBatchSize)
+		if cErr != nil {
+			return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		digests := make(map[digestKey][]DigestItem)
+		for _, item := range items {
+			key := digestKey{userID: item.UserID, channel: item.Channel, frequency: item.Frequency}
+			digests[key] = append(digests[key], item)
+		}
+
+		for key, digestItems := range digests {
+			userSetting, usErr := s.GetUserSetting(ctx, string(key.userID))
+			if usErr != nil {
+				errlog.WithoutErr(richerror.New(op).WithWrapError(usErr).WithMessage(fmt.Sprintf("can't get user setting for user id: %s",
+					key.userID)), s.logger)
+			}
+
+			// The digest waits in the queue until the quiet window of the channel ends, it is not claimed again before then.
+			if quietEnd, quiet := s.GetQuietHoursEnd(NotificationTypeInfo, userSetting, key.channel, now); quiet {
+				if rErr := s.repo.RequeueDigestItems(ctx, digestItemIDs(digestItems), quietEnd); rErr != nil {
+					return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+				}
+
+				continue
+			}
+
+			if sErr := s.sendDigest(ctx, key, digestItems, userSetting); sErr != nil {
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+
+				// the failed digest is retried by the next run of the worker, not by this pass.
+				if rErr := s.repo.RequeueDigestItems(ctx, digestItemIDs(digestItems), now.Add(s.digestWorkerInterval())); rErr != nil {
+					return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+				}
+			}
+		}
+
+		if len(digests) < s.cfg.DigestBatchSize {
+			return nil
+		}
+	}
+}
+
+// sendDigest renders the notifications of a digest through the digest template and publishes it on DigestChannelName
+// for the channel sender. The notification service doesn't send the emails and the sms itself, the external sender
+// must subscribe to DigestChannelName, a digest that is published while the sender is not subscribed is lost.
+func (s Service) sendDigest(ctx context.Context, key digestKey, items []DigestItem, userSetting UserSetting) error {
+	const op = "service.digest.sendDigest"
+
+	notificationIDs := make([]types.ID, 0, len(items))
+	for _, item := range items {
+		notificationIDs = append(notificationIDs, item.NotificationID)
+	}
+
+	notifications, gErr := s.repo.GetNotificationsByIDs(ctx, notificationIDs)
+	if gErr != nil {
+		return richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+	}
+
+	messages := make([]NotificationMessage, 0, len(notifications))
+	for _, notification := range notifications {
+		notificationMsgs, rErr := s.RenderNotificationTemplates(ctx, key.channel, userSetting.Lang, notification)
+		if rErr != nil {
+			continue
+		}
+
+		messages = append(messages, notificationMsgs...)
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	templates, tErr := s.getTemplates(ctx, []string{s.cfg.DigestTemplateName})
+	if tErr != nil {
+		return richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected)
+	}
+
+	var digestTemplate *Template
+	includes := make([]Template, 0)
+	if template, ok := templates[s.cfg.DigestTemplateName]; ok {
+		digestTemplate = &template
+
+		var iErr error
+		includes, iErr = s.getTemplateIncludes(ctx, template)
+		if iErr != nil {
+			return richerror.New(op).WithWrapError(iErr).WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	res, rErr := s.renderSvc.RenderDigestTemplate(digestTemplate, key.channel, userSetting.Lang, key.frequency, messages,
+		includes...)
+	if rErr != nil {
+		return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	digestNotificationIDs := make([]types.ID, 0, len(messages))
+	for _, message := range messages {
+		digestNotificationIDs = append(digestNotificationIDs, message.ID)
+	}
+
+	jsonData, mErr := json.Marshal(DigestMessage{
+		UserID:          key.userID,
+		Channel:         key.channel,
+		Frequency:       key.frequency,
+		Lang:            res.Lang,
+		Dir:             res.Dir,
+		Title:           res.Title,
+		Body:            res.Body,
+		NotificationIDs: digestNotificationIDs,
+		Timestamp:       time.Now().Unix(),
+	})
+	if mErr != nil {
+		return richerror.New(op).WithMessage("can't marshalling digest message").WithWrapError(mErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	if pErr := s.publisher.Publish(ctx, s.cfg.DigestChannelName, jsonData); pErr != nil {
+		return richerror.New(op).WithMessage("can't publish digest message").WithWrapError(pErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	s.logger.DebugContext(ctx, "digest sent", slog.String("user_id", string(key.userID)),
+		slog.String("channel", string(key.channel)), slog.Int("count", len(messages)))
+
+	return nil
+}
+
+func hasDigestChannel(channels []ChannelDelivery) bool {
+	for _, ch := range channels {
+		if IsDigestChannel(ch.Channel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func digestItemIDs(items []DigestItem) []int64 {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
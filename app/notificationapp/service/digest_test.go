@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestSendDueDigestsRequeueQuietHours(t *testing.T) {
+	userID := types.ID(ulid.Make().String())
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	quietHours := QuietHours{
+		Channel: ChannelTypeEmail,
+		Start:   now.Add(-time.Hour).Format(quietHoursLayout),
+		End:     now.Add(time.Hour).Format(quietHoursLayout),
+	}
+
+	repo := newMemRepository()
+	repo.now = now
+	repo.userSettings[userID] = UserSetting{QuietHours: []QuietHours{quietHours}}
+	repo.digestItems = []memDigestItem{
+		{item: DigestItem{ID: 1, UserID: userID, NotificationID: "n1", Channel: ChannelTypeEmail, Frequency: DigestFrequencyHourly}},
+		{item: DigestItem{ID: 2, UserID: userID, NotificationID: "n2", Channel: ChannelTypeEmail, Frequency: DigestFrequencyHourly}},
+	}
+
+	svc := Service{cfg: Config{DigestBatchSize: 1, DigestWorkerInterval: time.Minute}, repo: repo, logger: discardLogger()}
+
+	if sErr := svc.sendDueDigests(context.Background(), now); sErr != nil {
+		t.Fatalf("unexpected error: %v", sErr)
+	}
+
+	if repo.claims != 2 {
+		t.Fatalf("expected the requeued items not to be claimed again in the pass, claimed %d times", repo.claims)
+	}
+
+	if len(repo.requeues) != 1 {
+		t.Fatalf("expected the digest to be requeued once, requeued %d times", len(repo.requeues))
+	}
+
+	expectedEnd, _ := quietHoursEnd(quietHours, now)
+	if !repo.requeues[0].Equal(expectedEnd) || !repo.requeues[0].After(now) {
+		t.Fatalf("expected the digest to be held back until the quiet window ends, got %s", repo.requeues[0])
+	}
+
+	for _, item := range repo.digestItems {
+		if item.digested {
+			t.Fatalf("expected item %d to be pending", item.item.ID)
+		}
+	}
+}
+
+func TestWorkerIntervals(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantSchedule time.Duration
+		wantCampaign time.Duration
+		wantDigest   time.Duration
+	}{
+		{name: "configured", cfg: Config{SchedulerInterval: time.Second, CampaignWorkerInterval: 2 * time.Second,
+			DigestWorkerInterval: 3 * time.Second}, wantSchedule: time.Second, wantCampaign: 2 * time.Second,
+			wantDigest: 3 * time.Second},
+		{name: "zero", cfg: Config{}, wantSchedule: defaultSchedulerInterval, wantCampaign: defaultCampaignWorkerInterval,
+			wantDigest: defaultDigestWorkerInterval},
+		{name: "negative", cfg: Config{SchedulerInterval: -time.Second, CampaignWorkerInterval: -time.Second,
+			DigestWorkerInterval: -time.Second}, wantSchedule: defaultSchedulerInterval,
+			wantCampaign: defaultCampaignWorkerInterval, wantDigest: defaultDigestWorkerInterval},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := Service{cfg: test.cfg}
+
+			if interval := svc.schedulerInterval(); interval != test.wantSchedule {
+				t.Fatalf("expected scheduler interval %s, got %s", test.wantSchedule, interval)
+			}
+
+			if interval := svc.campaignWorkerInterval(); interval != test.wantCampaign {
+				t.Fatalf("expected campaign worker interval %s, got %s", test.wantCampaign, interval)
+			}
+
+			if interval := svc.digestWorkerInterval(); interval != test.wantDigest {
+				t.Fatalf("expected digest worker interval %s, got %s", test.wantDigest, interval)
+			}
+		})
+	}
+}
+
+func TestGetDigestFrequency(t *testing.T) {
+	userSetting := UserSetting{DigestPreferences: []DigestPreference{{Channel: ChannelTypeEmail,
+		NotificationTypes: []NotificationType{NotificationTypePromotion, NotificationTypeDirect}, Frequency: DigestFrequencyDaily}}}
+
+	tests := []struct {
+		name             string
+		notificationType NotificationType
+		channel          ChannelType
+		expected         DigestFrequency
+	}{
+		{name: "digested type", notificationType: NotificationTypePromotion, channel: ChannelTypeEmail, expected: DigestFrequencyDaily},
+		{name: "direct notification", notificationType: NotificationTypeDirect, channel: ChannelTypeEmail,
+			expected: DigestFrequencyImmediate},
+		{name: "critical notification", notificationType: NotificationTypeCritical, channel: ChannelTypeEmail,
+			expected: DigestFrequencyImmediate},
+		{name: "channel without digest", notificationType: NotificationTypePromotion, channel: ChannelTypeInApp,
+			expected: DigestFrequencyImmediate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if frequency := (Service{}).GetDigestFrequency(test.notificationType, userSetting, test.channel); frequency != test.expected {
+				t.Fatalf("expected frequency %q, got %q", test.expected, frequency)
+			}
+		})
+	}
+}
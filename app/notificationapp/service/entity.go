@@ -1,6 +1,8 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"time"
 
 	"github.com/syntaxfa/quick-connect/types"
@@ -14,13 +16,21 @@ type Notification struct {
 	Type              NotificationType  `json:"type"`
 	Data              map[string]string `json:"data,omitempty"`
 	TemplateName      string            `json:"template_name"`
-	DynamicBodyData   map[string]string `json:"dynamic_body_data,omitempty"`
-	DynamicTitleData  map[string]string `json:"dynamic_title_data,omitempty"`
+	TemplateVersion   *int              `json:"template_version,omitempty"`
+	DynamicBodyData   DynamicData       `json:"dynamic_body_data,omitempty"`
+	DynamicTitleData  DynamicData       `json:"dynamic_title_data,omitempty"`
 	IsRead            bool              `json:"is_read"`
 	IsInApp           bool              `json:"is_in_app"`
 	CreatedAt         time.Time         `json:"created_at"`
 	ChannelDeliveries []ChannelDelivery `json:"channel_deliveries"`
 	OverallStatus     OverallStatus     `json:"overall_status"`
+	SendAt            *time.Time        `json:"send_at,omitempty"`
+	DispatchedAt      *time.Time        `json:"dispatched_at,omitempty"`
+	Category          string            `json:"category"`
+	Topic             string            `json:"topic,omitempty"`
+	IsArchived        bool              `json:"is_archived"`
+	// Seq orders the in-app notifications of the websocket, it is renewed when a scheduled notification is dispatched.
+	Seq int64 `json:"seq"`
 }
 
 // OverallStatus defines the aggregate delivery status of a notification across all channels.
@@ -28,18 +38,21 @@ type Notification struct {
 type OverallStatus string
 
 const (
-	OverallStatusPending  OverallStatus = "pending"  // Notification is newly created and processing/delivery attempts are pending
-	OverallStatusSent     OverallStatus = "sent"     // All requested channels have successfully delivered the notification
-	OverallStatusFailed   OverallStatus = "failed"   // Delivery to all critical/requested channels failed after all retries
-	OverallStatusRetrying OverallStatus = "retrying" // At least one channel is still in a retrying state
-	OverallStatusIgnored  OverallStatus = "ignored"  // Some channels succeeded, while others failed or are still pending (partial success)
-	OverallStatusMixed    OverallStatus = "mixed"
+	OverallStatusPending   OverallStatus = "pending"  // Notification is newly created and processing/delivery attempts are pending
+	OverallStatusSent      OverallStatus = "sent"     // All requested channels have successfully delivered the notification
+	OverallStatusFailed    OverallStatus = "failed"   // Delivery to all critical/requested channels failed after all retries
+	OverallStatusRetrying  OverallStatus = "retrying" // At least one channel is still in a retrying state
+	OverallStatusIgnored   OverallStatus = "ignored"  // Some channels succeeded, while others failed or are still pending (partial success)
+	OverallStatusMixed     OverallStatus = "mixed"
+	OverallStatusScheduled OverallStatus = "scheduled" // Notification is waiting for its send_at time to be dispatched by the scheduler
+	OverallStatusCanceled  OverallStatus = "canceled"  // Scheduled notification was canceled before it was dispatched
 )
 
 func IsValidOverallStatus(overallStatus OverallStatus) bool {
 	if overallStatus == OverallStatusPending || overallStatus == OverallStatusSent ||
 		overallStatus == OverallStatusFailed || overallStatus == OverallStatusRetrying ||
-		overallStatus == OverallStatusIgnored || overallStatus == OverallStatusMixed {
+		overallStatus == OverallStatusIgnored || overallStatus == OverallStatusMixed ||
+		overallStatus == OverallStatusScheduled || overallStatus == OverallStatusCanceled {
 		return true
 	}
 
@@ -95,36 +108,133 @@ func IsValidChannelType(channelType ChannelType) bool {
 type DeliveryStatus string
 
 const (
-	DeliveryStatusPending  DeliveryStatus = "pending"  // Delivery for this channel has not been attempted yet, or is awaiting processing
-	DeliveryStatusSent     DeliveryStatus = "sent"     // The notification was successfully sent through this channel
-	DeliveryStatusFailed   DeliveryStatus = "failed"   // Delivery through this channel failed (after exhausting all retries)
-	DeliveryStatusRetrying DeliveryStatus = "retrying" // Delivery for this channel is currently being retried
-	DeliveryStatusIgnored  DeliveryStatus = "ignored"  // Delivery to this channel was ignored (e.g., user opted out, channel not configured, or a business rule prevented sending)
+	DeliveryStatusPending   DeliveryStatus = "pending"   // Delivery for this channel has not been attempted yet, or is awaiting processing
+	DeliveryStatusSent      DeliveryStatus = "sent"      // The notification was successfully sent through this channel
+	DeliveryStatusFailed    DeliveryStatus = "failed"    // Delivery through this channel failed (after exhausting all retries)
+	DeliveryStatusRetrying  DeliveryStatus = "retrying"  // Delivery for this channel is currently being retried
+	DeliveryStatusIgnored   DeliveryStatus = "ignored"   // Delivery to this channel was ignored (e.g., user opted out, channel not configured, or a business rule prevented sending)
+	DeliveryStatusDelivered DeliveryStatus = "delivered" // The provider reported that the notification reached the user
+	DeliveryStatusBounced   DeliveryStatus = "bounced"   // The provider reported that the recipient rejected the notification
 )
 
 // ChannelDelivery represents the detailed status of a notification's delivery attempt for a specific channel.
 type ChannelDelivery struct {
-	Channel       ChannelType    `json:"channel"`         // The type of communication channel
-	Status        DeliveryStatus `json:"status"`          // The current delivery status for this channel
-	LastAttemptAt *time.Time     `json:"last_attempt_at"` // Timestamp of the last delivery attempt
-	AttemptCount  int            `json:"attempt_count"`   // Number of times delivery has been attempted for this channel
-	Error         *string        `json:"error"`           // Optional: Error message if the last delivery attempt failed
+	Channel       ChannelType    `json:"channel"`                // The type of communication channel
+	Status        DeliveryStatus `json:"status"`                 // The current delivery status for this channel
+	LastAttemptAt *time.Time     `json:"last_attempt_at"`        // Timestamp of the last delivery attempt
+	AttemptCount  int            `json:"attempt_count"`          // Number of times delivery has been attempted for this channel
+	Error         *string        `json:"error"`                  // Optional: Error message if the last delivery attempt failed
+	DeliveredAt   *time.Time     `json:"delivered_at,omitempty"` // Reported by the provider webhooks
+	OpenedAt      *time.Time     `json:"opened_at,omitempty"`    // First open of the email, reported by the tracking pixel
+	ClickedAt     *time.Time     `json:"clicked_at,omitempty"`   // First click of a tracked email link
+}
+
+// DeliveryEventType is the type of an event that is reported by a provider webhook or by the email tracking.
+type DeliveryEventType string
+
+const (
+	DeliveryEventSent      DeliveryEventType = "sent"      // Provider accepted the notification
+	DeliveryEventDeferred  DeliveryEventType = "deferred"  // Provider failed temporary and retries the delivery
+	DeliveryEventDelivered DeliveryEventType = "delivered" // Notification reached the user
+	DeliveryEventBounced   DeliveryEventType = "bounced"   // Recipient rejected the notification
+	DeliveryEventFailed    DeliveryEventType = "failed"    // Provider could not deliver the notification
+	DeliveryEventOpened    DeliveryEventType = "opened"    // Email was opened
+	DeliveryEventClicked   DeliveryEventType = "clicked"   // A link of the email was clicked
+)
+
+var AllDeliveryEventType = []DeliveryEventType{DeliveryEventSent, DeliveryEventDeferred, DeliveryEventDelivered,
+	DeliveryEventBounced, DeliveryEventFailed, DeliveryEventOpened, DeliveryEventClicked}
+
+func IsValidDeliveryEventType(eventType DeliveryEventType) bool {
+	for _, t := range AllDeliveryEventType {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeliveryEvent is a single event of a channel delivery, events are kept to build the notification timeline.
+type DeliveryEvent struct {
+	ID             types.ID          `json:"id"`
+	NotificationID types.ID          `json:"notification_id"`
+	Channel        ChannelType       `json:"channel"`
+	Type           DeliveryEventType `json:"type"`
+	Provider       string            `json:"provider"`
+	URL            string            `json:"url,omitempty"` // clicked link
+	Error          *string           `json:"error,omitempty"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+
+	ProviderEventID string `json:"-"`
+}
+
+// DynamicData is the data of a template, values can be strings, numbers, booleans, lists or objects.
+type DynamicData map[string]any
+
+// UnmarshalJSON numbers are decoded as json.Number, so they are rendered as they are sent, e.g. 1000000
+// is not rendered as 1e+06.
+func (d *DynamicData) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return err
+	}
+
+	*d = values
+
+	return nil
+}
+
+// TemplateKind layouts and partials are not sent on their own, they are shared by the notification templates.
+type TemplateKind string
+
+const (
+	TemplateKindNotification TemplateKind = "notification"
+	TemplateKindLayout       TemplateKind = "layout"  // wraps the body of templates, e.g. an email header and footer
+	TemplateKindPartial      TemplateKind = "partial" // included in templates by {{template "partial_name" .}}
+)
+
+func IsValidTemplateKind(kind TemplateKind) bool {
+	return kind == TemplateKindNotification || kind == TemplateKindLayout || kind == TemplateKindPartial
 }
 
 // Template represents a notification template definition.
 // It groups different content variations (bodies) for various channels and languages
 // under a single logical template name.
+// A notification of the template is not sent again to a user with the same content in DedupWindowSeconds,
+// the deduplication is disabled when it is zero.
 type Template struct {
-	ID        types.ID          `json:"id"`
-	Name      string            `json:"name"`
-	Contents  []TemplateContent `json:"contents"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID                 types.ID          `json:"id"`
+	Name               string            `json:"name"`
+	Kind               TemplateKind      `json:"kind"`
+	Category           string            `json:"category"`
+	Topic              string            `json:"topic,omitempty"`
+	DedupWindowSeconds int               `json:"dedup_window_seconds"`
+	Version            int               `json:"version"`
+	Contents           []TemplateContent `json:"contents"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// TemplateVersion is an immutable snapshot of a template contents, every update or rollback of a template
+// creates a new version.
+type TemplateVersion struct {
+	ID         types.ID          `json:"id"`
+	TemplateID types.ID          `json:"template_id"`
+	Version    int               `json:"version"`
+	Contents   []TemplateContent `json:"contents"`
+	CreatedAt  time.Time         `json:"created_at"`
 }
 
 // TemplateContent defines the content of a specific template for a given channel.
+// Layout is the name of a layout template, the rendered body is placed where the layout calls {{template "content" .}}.
 type TemplateContent struct {
 	Channel ChannelType   `json:"channel"`
+	Layout  string        `json:"layout,omitempty"`
 	Bodies  []ContentBody `json:"bodies"`
 }
 
@@ -138,10 +248,20 @@ type ContentBody struct {
 // UserSetting A user can have their custom and personalized settings, such as language and channels
 // they do not want to receive notifications from.
 type UserSetting struct {
-	ID             types.ID        `json:"id"`
-	UserID         types.ID        `json:"user_id"`
-	Lang           string          `json:"lang"`
-	IgnoreChannels []IgnoreChannel `json:"ignore_channels"`
+	ID                types.ID           `json:"id"`
+	UserID            types.ID           `json:"user_id"`
+	Lang              string             `json:"lang"`
+	IgnoreChannels    []IgnoreChannel    `json:"ignore_channels"`
+	DigestPreferences []DigestPreference `json:"digest_preferences"`
+	Timezone          string             `json:"timezone"`
+	QuietHours        []QuietHours       `json:"quiet_hours"`
+	TopicPreferences  []TopicPreference  `json:"topic_preferences"`
+}
+
+// UserLanguage number of the users who have the language in their settings.
+type UserLanguage struct {
+	Lang  string `json:"lang"`
+	Users int    `json:"users"`
 }
 
 // IgnoreChannel A user can ignore channels with a high level of customization. A user can specify based on notification type,
@@ -151,3 +271,144 @@ type IgnoreChannel struct {
 	Channel           ChannelType        `json:"channel"`
 	NotificationTypes []NotificationType `json:"notification_type"`
 }
+
+// Topic is a subject of notifications which users can subscribe to per channel, for example "order updates"
+// or "newsletter". Templates are mapped to a topic and their notifications inherit it.
+type Topic struct {
+	ID          types.ID  `json:"id"`
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TopicPreference A user can opt in or opt out of a topic on a channel. A topic preference overrides the ignore
+// channels of the notification type, for example, a user who ignores promotion emails can still subscribe to the newsletter.
+// Note: Critical notifications are always sent.
+type TopicPreference struct {
+	Topic      string      `json:"topic"`
+	Channel    ChannelType `json:"channel"`
+	Subscribed bool        `json:"subscribed"`
+}
+
+// QuietHours A user can ask not to be disturbed on a channel in a daily window, Start and End are in the
+// 15:04 format of the user timezone. The window crosses midnight when End is before Start, for example 22:00 to 07:00.
+// Note: Critical and direct notifications are not deferred by quiet hours.
+type QuietHours struct {
+	Channel ChannelType `json:"channel"`
+	Start   string      `json:"start"`
+	End     string      `json:"end"`
+}
+
+// DeferredDelivery is a delivery that was held back by quiet hours until DeliverAt.
+type DeferredDelivery struct {
+	ID             int64       `json:"id"`
+	NotificationID types.ID    `json:"notification_id"`
+	UserID         types.ID    `json:"user_id"`
+	Channel        ChannelType `json:"channel"`
+	DeliverAt      time.Time   `json:"deliver_at"`
+}
+
+// DigestFrequency defines how often notifications of a channel are delivered to a user.
+type DigestFrequency string
+
+const (
+	DigestFrequencyImmediate DigestFrequency = "immediate" // Every notification is delivered on its own
+	DigestFrequencyHourly    DigestFrequency = "hourly"    // Notifications are aggregated into one message per hour
+	DigestFrequencyDaily     DigestFrequency = "daily"     // Notifications are aggregated into one message per day
+)
+
+func IsValidDigestFrequency(frequency DigestFrequency) bool {
+	return frequency == DigestFrequencyImmediate || frequency == DigestFrequencyHourly || frequency == DigestFrequencyDaily
+}
+
+// DigestChannels are the channels that support digest delivery, in-app delivery is always real-time.
+var DigestChannels = []ChannelType{ChannelTypeEmail, ChannelTypeSMS}
+
+func IsDigestChannel(channel ChannelType) bool {
+	for _, ch := range DigestChannels {
+		if ch == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DigestPreference A user can receive notifications of a channel and notification types aggregated into a digest,
+// for example, info notifications on email once a day.
+// Note: Critical notifications are always delivered immediately.
+type DigestPreference struct {
+	Channel           ChannelType        `json:"channel"`
+	NotificationTypes []NotificationType `json:"notification_types"`
+	Frequency         DigestFrequency    `json:"frequency"`
+}
+
+// DigestItem is a notification that waits to be delivered in the next digest of a user.
+type DigestItem struct {
+	ID             int64           `json:"id"`
+	UserID         types.ID        `json:"user_id"`
+	NotificationID types.ID        `json:"notification_id"`
+	Channel        ChannelType     `json:"channel"`
+	Frequency      DigestFrequency `json:"frequency"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// CampaignStatus defines the lifecycle of a broadcast campaign.
+type CampaignStatus string
+
+const (
+	CampaignStatusQueued    CampaignStatus = "queued"    // Campaign is created and waiting for the campaign worker
+	CampaignStatusRunning   CampaignStatus = "running"   // Campaign worker is fanning out notifications to recipients
+	CampaignStatusCompleted CampaignStatus = "completed" // All recipients have been processed
+	CampaignStatusCanceled  CampaignStatus = "canceled"  // Campaign was canceled by the admin, remaining recipients are not processed
+)
+
+func IsValidCampaignStatus(status CampaignStatus) bool {
+	return status == CampaignStatusQueued || status == CampaignStatusRunning ||
+		status == CampaignStatusCompleted || status == CampaignStatusCanceled
+}
+
+// Campaign represents a broadcast of a single notification template to many users.
+// QueuedCount is the number of recipients that are not processed yet.
+type Campaign struct {
+	ID                types.ID                 `json:"id"`
+	Name              string                   `json:"name"`
+	Type              NotificationType         `json:"type"`
+	Data              map[string]string        `json:"data,omitempty"`
+	TemplateName      string                   `json:"template_name"`
+	DynamicBodyData   DynamicData              `json:"dynamic_body_data,omitempty"`
+	DynamicTitleData  DynamicData              `json:"dynamic_title_data,omitempty"`
+	ChannelDeliveries []ChannelDeliveryRequest `json:"channel_deliveries"`
+	FileID            *types.ID                `json:"file_id,omitempty"`
+	Status            CampaignStatus           `json:"status"`
+	TotalCount        int                      `json:"total_count"`
+	QueuedCount       int                      `json:"queued_count"`
+	SentCount         int                      `json:"sent_count"`
+	FailedCount       int                      `json:"failed_count"`
+	SkippedCount      int                      `json:"skipped_count"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+	CompletedAt       *time.Time               `json:"completed_at,omitempty"`
+}
+
+// CampaignRecipientStatus defines the processing status of a single campaign recipient.
+type CampaignRecipientStatus string
+
+const (
+	CampaignRecipientStatusPending    CampaignRecipientStatus = "pending"
+	CampaignRecipientStatusProcessing CampaignRecipientStatus = "processing"
+	CampaignRecipientStatusSent       CampaignRecipientStatus = "sent"
+	CampaignRecipientStatusFailed     CampaignRecipientStatus = "failed"
+	CampaignRecipientStatusSkipped    CampaignRecipientStatus = "skipped" // User opted out from all campaign channels
+)
+
+type CampaignRecipient struct {
+	ID             int64                   `json:"id"`
+	CampaignID     types.ID                `json:"campaign_id"`
+	ExternalUserID string                  `json:"external_user_id"`
+	Status         CampaignRecipientStatus `json:"status"`
+	NotificationID *types.ID               `json:"notification_id,omitempty"`
+	Error          *string                 `json:"error,omitempty"`
+}
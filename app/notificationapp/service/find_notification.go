@@ -23,18 +23,29 @@ func (s Service) FindNotificationByUserID(ctx context.Context, req ListNotificat
 		return ListNotificationResponse{}, errlog.ErrLog(gErr, s.logger)
 	}
 
-	isInApp := true
-	notifications, paginateResp, fErr := s.repo.FindNotificationByUserID(ctx, userID, req.Paginated, req.IsRead, &isInApp)
-	if fErr != nil {
-		return ListNotificationResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(fErr).WithKind(richerror.KindUnexpected), s.logger)
-	}
-
 	userSetting, usErr := s.GetUserSetting(ctx, req.ExternalUserID)
 	if usErr != nil {
 		return ListNotificationResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(usErr).
 			WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	// the ignored types are excluded by the query like the unread count, so the pages are not shortened by them.
+	isInApp := true
+	notifications, paginateResp, fErr := s.repo.FindNotificationByUserID(ctx, userID, NotificationFilter{
+		IsRead:       req.IsRead,
+		IsInApp:      &isInApp,
+		IsArchived:   &req.IsArchived,
+		Types:        req.Types,
+		ExcludeTypes: ignoredInAppTypes(userSetting),
+		Category:     req.Category,
+		From:         req.From,
+		To:           req.To,
+	}, req.Paginated)
+	if fErr != nil {
+		return ListNotificationResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(fErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	// the topic preferences of the user are still checked on the found notifications.
 	var accessNotifications []Notification
 	for _, notification := range notifications {
 		if s.CheckNotificationAccessToSend(notification, userSetting, ChannelTypeInApp) {
@@ -14,18 +14,30 @@ import (
 	"github.com/syntaxfa/quick-connect/types"
 )
 
+const (
+	sendChanSize = 256
+	// maxPendingAcks limits the sent notifications of a client which are waiting for the acknowledgement.
+	maxPendingAcks = 1000
+)
+
 type Connection interface {
+	ReadMessage() (messageType int, p []byte, err error)
 	WriteMessage(messageType int, data []byte) error
 	Close() error
 	RemoteAddr() string
+	SetReadLimit(limit int64)
+	SetReadDeadline(t time.Time) error
+	SetPongHandler(h func(appData string) error)
 }
 
+// ClientMessageHandler is a function callback executed by ReadPump for processing the client messages.
+type ClientMessageHandler func(ctx context.Context, client *Client, messageType int, payload []byte)
+
 type Hub struct {
 	cfg          Config
 	clients      map[types.ID][]*Client
 	register     chan *Client
 	unregistered chan *Client
-	notification chan *NotificationMessage
 	logger       *slog.Logger
 	mu           sync.RWMutex
 	subscriber   pubsub.Subscriber
@@ -34,8 +46,22 @@ type Hub struct {
 type Client struct {
 	hub    *Hub
 	conn   Connection
-	send   chan *NotificationMessage
+	send   chan []byte
 	userID types.ID
+	// closed is set by the hub under its lock when send is closed.
+	closed bool
+	// pending are the notification ids of the sent notifications by their seq until the client acknowledges them.
+	pending   map[int64]types.ID
+	pendingMu sync.Mutex
+}
+
+// hubMessage is used to find the receiver of the messages published on the notification channel,
+// ID and Seq are set for the notification messages.
+type hubMessage struct {
+	Event  WSEvent  `json:"event"`
+	UserID types.ID `json:"user_id"`
+	ID     types.ID `json:"id"`
+	Seq    int64    `json:"seq"`
 }
 
 func NewHub(cfg Config, logger *slog.Logger, subscriber pubsub.Subscriber) *Hub {
@@ -44,7 +70,6 @@ func NewHub(cfg Config, logger *slog.Logger, subscriber pubsub.Subscriber) *Hub
 		clients:      make(map[types.ID][]*Client),
 		register:     make(chan *Client),
 		unregistered: make(chan *Client),
-		notification: make(chan *NotificationMessage),
 		logger:       logger,
 		subscriber:   subscriber,
 	}
@@ -75,15 +100,15 @@ func (h *Hub) Run(ctx context.Context) {
 			continue
 		}
 
-		var notification NotificationMessage
-		if uErr := json.Unmarshal(message, &notification); uErr != nil {
+		var msg hubMessage
+		if uErr := json.Unmarshal(message, &msg); uErr != nil {
 			errlog.WithoutErrContext(ctx, richerror.New(op).WithMessage("can't unmarshalling message").
 				WithWrapError(uErr).WithKind(richerror.KindUnexpected), h.logger)
 
 			continue
 		}
 
-		h.sendNotificationToClients(ctx, &notification)
+		h.sendMessageToClients(ctx, msg, message)
 	}
 }
 
@@ -114,6 +139,7 @@ func (h *Hub) handleClientRegister(ctx context.Context, client *Client, op strin
 
 func (h *Hub) closeAllUserConnections(ctx context.Context, connections []*Client, op string) {
 	for _, connection := range connections {
+		connection.closed = true
 		close(connection.send)
 		if cErr := connection.conn.Close(); cErr != nil {
 			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), h.logger)
@@ -132,6 +158,7 @@ func (h *Hub) handleClientUnregister(ctx context.Context, client *Client, op str
 
 	for i, connection := range connections {
 		if connection == client {
+			client.closed = true
 			close(client.send)
 			if cErr := client.conn.Close(); cErr != nil {
 				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), h.logger)
@@ -146,24 +173,102 @@ func (h *Hub) handleClientUnregister(ctx context.Context, client *Client, op str
 	}
 }
 
-func (h *Hub) sendNotificationToClients(ctx context.Context, notification *NotificationMessage) {
+// sendMessageToClients sends a published message as it is to the connections of the user,
+// it is a notification message or an event message like UnreadCountMessage.
+func (h *Hub) sendMessageToClients(ctx context.Context, msg hubMessage, message []byte) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	connections, ok := h.clients[notification.UserID]
+	connections, ok := h.clients[msg.UserID]
 	if !ok {
 		return
 	}
 
 	for _, client := range connections {
-		select {
-		case client.send <- notification:
-			h.logger.DebugContext(ctx, "notification sent to client",
-				slog.String("notification_id", string(notification.ID)))
-		default:
-			h.logger.WarnContext(ctx, "failed to send notification to client, client send buffer full",
-				slog.String("user_id", string(notification.UserID)))
+		h.sendToClient(ctx, client, msg, message)
+	}
+}
+
+// sendReplay sends a message to a single client, it is used to replay the missed notifications of the client.
+func (h *Hub) sendReplay(ctx context.Context, client *Client, msg hubMessage, message []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	h.sendToClient(ctx, client, msg, message)
+}
+
+// sendToClient must be called under the hub lock, so the send channel of the client is not closed meanwhile.
+func (h *Hub) sendToClient(ctx context.Context, client *Client, msg hubMessage, message []byte) {
+	if client.closed {
+		return
+	}
+
+	select {
+	case client.send <- message:
+		if msg.Event == "" && msg.Seq > 0 {
+			client.addPendingAck(msg.Seq, msg.ID)
 		}
+
+		h.logger.DebugContext(ctx, "message sent to client", slog.String("user_id", string(msg.UserID)))
+	default:
+		h.logger.WarnContext(ctx, "failed to send message to client, client send buffer full",
+			slog.String("user_id", string(msg.UserID)))
+	}
+}
+
+func (c *Client) addPendingAck(seq int64, notificationID types.ID) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	if len(c.pending) >= maxPendingAcks {
+		return
+	}
+
+	c.pending[seq] = notificationID
+}
+
+// Ack removes the notification of the seq from the pending acknowledgements, it returns false if the notification
+// was not sent to this client.
+func (c *Client) Ack(seq int64) (types.ID, bool) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	notificationID, ok := c.pending[seq]
+	delete(c.pending, seq)
+
+	return notificationID, ok
+}
+
+// ReadPump reads the client messages until the connection is closed, then it unregisters the client.
+func (c *Client) ReadPump(ctx context.Context, handler ClientMessageHandler) {
+	const op = "service.hub.ReadPump"
+
+	defer func() {
+		c.hub.unregistered <- c
+	}()
+
+	c.conn.SetReadLimit(int64(c.hub.cfg.MaxMessageSize))
+	if dErr := c.conn.SetReadDeadline(time.Now().Add(c.hub.cfg.PongWait)); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), c.hub.logger)
+
+		return
+	}
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(c.hub.cfg.PongWait))
+	})
+
+	for {
+		messageType, payload, rErr := c.conn.ReadMessage()
+		if rErr != nil {
+			if websocket.IsUnexpectedCloseError(rErr, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected),
+					c.hub.logger)
+			}
+
+			return
+		}
+
+		handler(ctx, c, messageType, payload)
 	}
 }
 
@@ -175,12 +280,12 @@ func (c *Client) WritePump() {
 
 	for {
 		select {
-		case notification, ok := <-c.send:
+		case message, ok := <-c.send:
 			if !ok {
 				c.sendCloseMessage(op)
 				return
 			}
-			c.sendNotification(notification, op)
+			c.sendMessage(message, op)
 
 		case <-ticker.C:
 			if !c.sendPing(op) {
@@ -215,19 +320,8 @@ func (c *Client) sendCloseMessage(op string) {
 	}
 }
 
-func (c *Client) sendNotification(notification *NotificationMessage, op string) {
-	msgBytes, mErr := json.Marshal(notification)
-	if mErr != nil {
-		errlog.WithoutErr(
-			richerror.New(op).
-				WithWrapError(mErr).
-				WithMessage("error marshalling notification"),
-			c.hub.logger,
-		)
-		return
-	}
-
-	if wErr := c.conn.WriteMessage(websocket.TextMessage, msgBytes); wErr != nil {
+func (c *Client) sendMessage(message []byte, op string) {
+	if wErr := c.conn.WriteMessage(websocket.TextMessage, message); wErr != nil {
 		errlog.WithoutErr(
 			richerror.New(op).
 				WithWrapError(wErr).
@@ -259,9 +353,10 @@ func (s Service) NewClient(ctx context.Context, conn Connection, externalUserID
 	}
 
 	return &Client{
-		hub:    s.hub,
-		conn:   conn,
-		send:   make(chan *NotificationMessage),
-		userID: userID,
+		hub:     s.hub,
+		conn:    conn,
+		send:    make(chan []byte, sendChanSize),
+		userID:  userID,
+		pending: make(map[int64]types.ID),
 	}
 }
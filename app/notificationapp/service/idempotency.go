@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// idempotencyClaimTimeout is how long a claimed idempotency key waits for its notification, a claim whose notification
+// is not saved after it is taken over by a retry of the request.
+const idempotencyClaimTimeout = time.Minute
+
+// claimIdempotencyKey claims the idempotency key of the request for the notification id of the request,
+// if the key is already claimed by a sent notification, that notification is returned.
+func (s Service) claimIdempotencyKey(ctx context.Context, req SendNotificationRequest) (Notification, bool, error) {
+	const op = "service.idempotency.claimIdempotencyKey"
+
+	now := time.Now()
+	ownerID, cErr := s.repo.ClaimIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, req.ID,
+		now.Add(s.cfg.IdempotencyKeyTTL), now.Add(-idempotencyClaimTimeout))
+	if cErr != nil {
+		return Notification{}, false, errlog.ErrLog(richerror.New(op).WithWrapError(cErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if ownerID == req.ID {
+		return Notification{}, false, nil
+	}
+
+	// the first request has claimed the key, but it has not saved its notification yet.
+	inProgressErr := richerror.New(op).WithMessage(servermsg.MsgIdempotencyKeyInProgress).WithKind(richerror.KindConflict).
+		WithMeta(map[string]interface{}{"idempotency_key": req.IdempotencyKey})
+
+	if ownerID == "" {
+		return Notification{}, false, inProgressErr
+	}
+
+	exists, eErr := s.repo.IsExistNotificationByID(ctx, ownerID)
+	if eErr != nil {
+		return Notification{}, false, errlog.ErrLog(richerror.New(op).WithWrapError(eErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return Notification{}, false, inProgressErr
+	}
+
+	notification, gErr := s.repo.GetNotificationByID(ctx, ownerID)
+	if gErr != nil {
+		return Notification{}, false, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return notification, true, nil
+}
+
+// releaseIdempotencyKey gives back the key of a request whose notification is not saved, so it can be retried.
+func (s Service) releaseIdempotencyKey(ctx context.Context, req SendNotificationRequest) {
+	const op = "service.idempotency.releaseIdempotencyKey"
+
+	if req.IdempotencyKey == "" {
+		return
+	}
+
+	if rErr := s.repo.ReleaseIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, req.ID); rErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+}
+
+// bindIdempotencyKey binds the key of a request whose notification is a duplicate to the sent notification,
+// so the retries of the request return it. The key is released when it can't be bound.
+func (s Service) bindIdempotencyKey(ctx context.Context, req SendNotificationRequest, notificationID types.ID) {
+	const op = "service.idempotency.bindIdempotencyKey"
+
+	if req.IdempotencyKey == "" {
+		return
+	}
+
+	if bErr := s.repo.BindIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, req.ID, notificationID); bErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		s.releaseIdempotencyKey(ctx, req)
+	}
+}
+
+// findDuplicateNotification returns the notification of the user with the same content which is sent
+// in the dedup window of the template. The deduplication is best effort, concurrent duplicate requests may both be sent.
+func (s Service) findDuplicateNotification(ctx context.Context, req SendNotificationRequest) (Notification, bool, error) {
+	const op = "service.idempotency.findDuplicateNotification"
+
+	templates, tErr := s.getTemplates(ctx, []string{req.TemplateName})
+	if tErr != nil {
+		return Notification{}, false, errlog.ErrLog(richerror.New(op).WithWrapError(tErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	template, ok := templates[req.TemplateName]
+	if !ok || template.DedupWindowSeconds <= 0 {
+		return Notification{}, false, nil
+	}
+
+	since := time.Now().Add(-time.Duration(template.DedupWindowSeconds) * time.Second)
+
+	notification, found, gErr := s.repo.GetLatestNotificationByContentHash(ctx, req.UserID, req.ContentHash, since)
+	if gErr != nil {
+		return Notification{}, false, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return notification, found, nil
+}
+
+// notificationContentHash is the hash of the template and data of a notification, the keys of the maps are
+// sorted by json.Marshal, so the same content always has the same hash.
+func notificationContentHash(req SendNotificationRequest) (string, error) {
+	content, mErr := json.Marshal(struct {
+		TemplateName     string            `json:"template_name"`
+		Type             NotificationType  `json:"type"`
+		Data             map[string]string `json:"data"`
+		DynamicTitleData DynamicData       `json:"dynamic_title_data"`
+		DynamicBodyData  DynamicData       `json:"dynamic_body_data"`
+	}{
+		TemplateName:     req.TemplateName,
+		Type:             req.Type,
+		Data:             req.Data,
+		DynamicTitleData: req.DynamicTitleData,
+		DynamicBodyData:  req.DynamicBodyData,
+	})
+	if mErr != nil {
+		return "", mErr
+	}
+
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// deleteExpiredIdempotencyKeys removes the idempotency keys whose ttl has ended.
+func (s Service) deleteExpiredIdempotencyKeys(ctx context.Context) error {
+	const op = "service.idempotency.deleteExpiredIdempotencyKeys"
+
+	if dErr := s.repo.DeleteExpiredIdempotencyKeys(ctx, time.Now()); dErr != nil {
+		return richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
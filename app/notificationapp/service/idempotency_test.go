@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestBindIdempotencyKey(t *testing.T) {
+	req := SendNotificationRequest{ID: "request-notification", UserID: "user-1", IdempotencyKey: "key-1"}
+
+	tests := []struct {
+		name     string
+		bindErr  error
+		expected types.ID
+		bound    bool
+	}{
+		{name: "bound to the duplicate", expected: "duplicate-notification", bound: true},
+		{name: "released when the key can't be bound", bindErr: errors.New("connection refused")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			repo.idempotency["user-1/key-1"] = req.ID
+			repo.bindErr = test.bindErr
+			svc := Service{repo: repo, logger: discardLogger()}
+
+			svc.bindIdempotencyKey(context.Background(), req, "duplicate-notification")
+
+			notificationID, bound := repo.idempotency["user-1/key-1"]
+			if bound != test.bound || notificationID != test.expected {
+				t.Fatalf("expected key bound %t to %q, got %t to %q", test.bound, test.expected, bound, notificationID)
+			}
+		})
+	}
+}
+
+func TestReleaseIdempotencyKey(t *testing.T) {
+	req := SendNotificationRequest{ID: "request-notification", UserID: "user-1", IdempotencyKey: "key-1"}
+
+	tests := []struct {
+		name     string
+		claimID  types.ID
+		released bool
+	}{
+		{name: "claimed by the request", claimID: req.ID, released: true},
+		{name: "taken over by a retry", claimID: "retry-notification", released: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			repo.idempotency["user-1/key-1"] = test.claimID
+			svc := Service{repo: repo, logger: discardLogger()}
+
+			svc.releaseIdempotencyKey(context.Background(), req)
+
+			if _, claimed := repo.idempotency["user-1/key-1"]; claimed == test.released {
+				t.Fatalf("expected key released %t", test.released)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func (s Service) ArchiveNotification(ctx context.Context, notificationID types.ID, externalUserID string) error {
+	const op = "service.inbox.ArchiveNotification"
+
+	return s.archiveNotification(ctx, notificationID, externalUserID, true, op)
+}
+
+func (s Service) UnarchiveNotification(ctx context.Context, notificationID types.ID, externalUserID string) error {
+	const op = "service.inbox.UnarchiveNotification"
+
+	return s.archiveNotification(ctx, notificationID, externalUserID, false, op)
+}
+
+func (s Service) archiveNotification(ctx context.Context, notificationID types.ID, externalUserID string, archive bool,
+	op string) error {
+	userID, gErr := s.getUserIDFromExternalUserID(ctx, externalUserID)
+	if gErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	archived, aErr := s.repo.Archive(ctx, notificationID, userID, archive)
+	if aErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !archived {
+		return richerror.New(op).WithMessage(servermsg.MsgNotificationNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	go s.notifyUnreadCount(s.cfg.PublishTimeout, userID) //nolint:contextcheck // This function run asynchronously
+
+	return nil
+}
+
+// DeleteNotification is a soft delete, the notification is hidden from the user but it is kept for the delivery history.
+func (s Service) DeleteNotification(ctx context.Context, notificationID types.ID, externalUserID string) error {
+	const op = "service.inbox.DeleteNotification"
+
+	userID, gErr := s.getUserIDFromExternalUserID(ctx, externalUserID)
+	if gErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	deleted, dErr := s.repo.SoftDelete(ctx, notificationID, userID)
+	if dErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !deleted {
+		return richerror.New(op).WithMessage(servermsg.MsgNotificationNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	go s.notifyUnreadCount(s.cfg.PublishTimeout, userID) //nolint:contextcheck // This function run asynchronously
+
+	return nil
+}
+
+// GetUnreadCount counts the unread in-app notifications of the user, the notification types which the user
+// ignored for the in-app channel are not counted.
+func (s Service) GetUnreadCount(ctx context.Context, externalUserID string) (UnreadCountResponse, error) {
+	const op = "service.inbox.GetUnreadCount"
+
+	userID, gErr := s.getUserIDFromExternalUserID(ctx, externalUserID)
+	if gErr != nil {
+		return UnreadCountResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	userSetting, usErr := s.GetUserSetting(ctx, string(userID))
+	if usErr != nil {
+		return UnreadCountResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(usErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	resp, cErr := s.unreadCount(ctx, userID, userSetting)
+	if cErr != nil {
+		return UnreadCountResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(cErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return resp, nil
+}
+
+func (s Service) unreadCount(ctx context.Context, userID types.ID, userSetting UserSetting) (UnreadCountResponse, error) {
+	categories, gErr := s.repo.GetUnreadCountByCategory(ctx, userID, ignoredInAppTypes(userSetting))
+	if gErr != nil {
+		return UnreadCountResponse{}, gErr
+	}
+
+	resp := UnreadCountResponse{Categories: categories}
+	for _, count := range categories {
+		resp.Total += count
+	}
+
+	return resp, nil
+}
+
+// ignoredInAppTypes critical notifications can't be ignored, so they are never excluded.
+func ignoredInAppTypes(userSetting UserSetting) []NotificationType {
+	var notificationTypes []NotificationType
+	for _, ignore := range userSetting.IgnoreChannels {
+		if ignore.Channel != ChannelTypeInApp {
+			continue
+		}
+
+		for _, notificationType := range ignore.NotificationTypes {
+			if notificationType != NotificationTypeCritical {
+				notificationTypes = append(notificationTypes, notificationType)
+			}
+		}
+	}
+
+	return notificationTypes
+}
+
+func (s Service) notifyUnreadCount(ctxTimeout time.Duration, userID types.ID) {
+	const op = "service.inbox.notifyUnreadCount"
+
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	defer cancel()
+
+	userSetting, usErr := s.GetUserSetting(ctx, string(userID))
+	if usErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(usErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	s.publishUnreadCount(ctx, userID, userSetting)
+}
+
+// publishUnreadCount publishes the unread count of the user for the websocket clients on every notification instance.
+func (s Service) publishUnreadCount(ctx context.Context, userID types.ID, userSetting UserSetting) {
+	const op = "service.inbox.publishUnreadCount"
+
+	resp, cErr := s.unreadCount(ctx, userID, userSetting)
+	if cErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	jsonData, mErr := json.Marshal(UnreadCountMessage{
+		Event:      WSEventUnreadCount,
+		UserID:     userID,
+		Total:      resp.Total,
+		Categories: resp.Categories,
+	})
+	if mErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithMessage("can't marshalling unread count message").WithWrapError(mErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	if pErr := s.publisher.Publish(ctx, s.cfg.ChannelName, jsonData); pErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithMessage("can't publish unread count message").
+			WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+}
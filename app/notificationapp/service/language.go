@@ -0,0 +1,86 @@
+package service
+
+import (
+	"strings"
+)
+
+type TextDirection string
+
+const (
+	TextDirectionLTR TextDirection = "ltr"
+	TextDirectionRTL TextDirection = "rtl"
+)
+
+// rtlLanguages are the primary language subtags that are written from right to left.
+var rtlLanguages = map[string]bool{
+	"ar": true, "fa": true, "he": true, "iw": true, "ur": true, "ps": true, "yi": true, "dv": true, "sd": true,
+	"ug": true, "ckb": true, "syr": true,
+}
+
+// rtlScripts are the script subtags that are written from right to left, e.g. az-Arab.
+var rtlScripts = map[string]bool{
+	"arab": true, "hebr": true, "thaa": true, "syrc": true, "nkoo": true, "adlm": true, "rohg": true,
+}
+
+// normalizeLanguageTag returns the tag with - as the subtag separator, e.g. fa_IR is fa-IR.
+func normalizeLanguageTag(lang string) string {
+	return strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")
+}
+
+// languageFallbacks returns the BCP-47 fallback chain of the language, subtags are removed from the end one by one,
+// e.g. zh-Hant-TW is zh-Hant-TW, zh-Hant and zh.
+func languageFallbacks(lang string) []string {
+	lang = normalizeLanguageTag(lang)
+	if lang == "" {
+		return nil
+	}
+
+	fallbacks := []string{lang}
+	for {
+		i := strings.LastIndex(lang, "-")
+		if i <= 0 {
+			return fallbacks
+		}
+
+		lang = lang[:i]
+		// a single letter subtag is an extension or private use singleton, it is removed with the subtag before it.
+		if len(lang) > 1 && lang[len(lang)-2] == '-' {
+			continue
+		}
+
+		fallbacks = append(fallbacks, lang)
+	}
+}
+
+// isLanguageInFallbacks reports whether lang is the language of the chain, the tags are compared case-insensitively.
+func isLanguageInFallbacks(fallbacks []string, lang string) bool {
+	lang = normalizeLanguageTag(lang)
+	for _, fallback := range fallbacks {
+		if strings.EqualFold(fallback, lang) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LanguageDirection returns rtl for the languages written from right to left, such as Persian and Arabic,
+// the script subtag takes precedence over the language, e.g. az-Arab is rtl.
+func LanguageDirection(lang string) TextDirection {
+	subtags := strings.Split(strings.ToLower(normalizeLanguageTag(lang)), "-")
+	for _, subtag := range subtags[1:] {
+		if len(subtag) == 4 && subtag[0] >= 'a' && subtag[0] <= 'z' {
+			if rtlScripts[subtag] {
+				return TextDirectionRTL
+			}
+
+			return TextDirectionLTR
+		}
+	}
+
+	if rtlLanguages[subtags[0]] {
+		return TextDirectionRTL
+	}
+
+	return TextDirectionLTR
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectLanguage(t *testing.T) {
+	renderSvc := &RenderService{defaultLang: "en"}
+	bodies := func(langs ...string) []ContentBody {
+		result := make([]ContentBody, 0, len(langs))
+		for _, lang := range langs {
+			result = append(result, ContentBody{Lang: lang})
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		bodies    []ContentBody
+		requested string
+		expected  string
+	}{
+		{name: "exact language", bodies: bodies("en", "fa-IR"), requested: "fa-IR", expected: "fa-IR"},
+		{name: "region falls back to language", bodies: bodies("en", "fa"), requested: "fa-IR", expected: "fa"},
+		{name: "underscore separator", bodies: bodies("en", "fa"), requested: "fa_IR", expected: "fa"},
+		{name: "case insensitive", bodies: bodies("en", "pt-BR"), requested: "pt-br", expected: "pt-BR"},
+		{name: "script chain", bodies: bodies("zh", "zh-Hant"), requested: "zh-Hant-TW", expected: "zh-Hant"},
+		{name: "private use subtag", bodies: bodies("en", "de"), requested: "de-x-formal", expected: "de"},
+		{name: "default language", bodies: bodies("ar", "en"), requested: "fr-CA", expected: "en"},
+		{name: "empty requested language", bodies: bodies("ar", "en"), requested: "", expected: "en"},
+		{name: "first body", bodies: bodies("ar", "fa"), requested: "fr", expected: "ar"},
+		{name: "no bodies", requested: "fa", expected: "en"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if lang := renderSvc.selectLanguage(test.bodies, test.requested); lang != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, lang)
+			}
+		})
+	}
+}
+
+func TestLanguageFallbacks(t *testing.T) {
+	tests := []struct {
+		lang     string
+		expected []string
+	}{
+		{lang: "fa", expected: []string{"fa"}},
+		{lang: "fa_IR", expected: []string{"fa-IR", "fa"}},
+		{lang: "zh-Hant-TW", expected: []string{"zh-Hant-TW", "zh-Hant", "zh"}},
+		{lang: "en-US-x-twain", expected: []string{"en-US-x-twain", "en-US", "en"}},
+		{lang: " ", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			if fallbacks := languageFallbacks(test.lang); !slices.Equal(fallbacks, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, fallbacks)
+			}
+		})
+	}
+}
+
+func TestLanguageDirection(t *testing.T) {
+	tests := []struct {
+		lang     string
+		expected TextDirection
+	}{
+		{lang: "fa", expected: TextDirectionRTL},
+		{lang: "ar-EG", expected: TextDirectionRTL},
+		{lang: "he_IL", expected: TextDirectionRTL},
+		{lang: "en", expected: TextDirectionLTR},
+		{lang: "az-Arab", expected: TextDirectionRTL},
+		{lang: "uz-Latn-UZ", expected: TextDirectionLTR},
+		{lang: "", expected: TextDirectionLTR},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			if direction := LanguageDirection(test.lang); direction != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, direction)
+			}
+		})
+	}
+}
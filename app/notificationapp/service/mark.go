@@ -20,6 +20,8 @@ func (s Service) MarkNotificationAsRead(ctx context.Context, notificationID type
 		return errlog.ErrLog(richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	go s.notifyUnreadCount(s.cfg.PublishTimeout, userID) //nolint:contextcheck // This function run asynchronously
+
 	return nil
 }
 
@@ -35,5 +37,7 @@ func (s Service) MarkAllNotificationAsRead(ctx context.Context, externalUserID s
 		return errlog.ErrLog(richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	go s.notifyUnreadCount(s.cfg.PublishTimeout, userID) //nolint:contextcheck // This function run asynchronously
+
 	return nil
 }
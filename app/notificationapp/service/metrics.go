@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const meterName = "github.com/syntaxfa/quick-connect/app/notificationapp"
+
+// Template cache layers, the cachemanager layer keeps template definitions and the executor layer keeps
+// the compiled templates of RenderService.
+const (
+	templateCacheLayerCacheManager = "cachemanager"
+	templateCacheLayerExecutor     = "executor"
+)
+
+// templateCacheMetrics uses the global meter provider, so it is a no-op until a provider is registered.
+type templateCacheMetrics struct {
+	hits      metric.Int64Counter
+	misses    metric.Int64Counter
+	evictions metric.Int64Counter
+}
+
+func newTemplateCacheMetrics() templateCacheMetrics {
+	meter := otel.Meter(meterName)
+
+	hits, hErr := meter.Int64Counter("notification.template_cache.hits",
+		metric.WithDescription("Number of template cache hits"))
+	if hErr != nil {
+		hits = noop.Int64Counter{}
+	}
+
+	misses, mErr := meter.Int64Counter("notification.template_cache.misses",
+		metric.WithDescription("Number of template cache misses"))
+	if mErr != nil {
+		misses = noop.Int64Counter{}
+	}
+
+	evictions, eErr := meter.Int64Counter("notification.template_cache.evictions",
+		metric.WithDescription("Number of template cache evictions caused by template changes"))
+	if eErr != nil {
+		evictions = noop.Int64Counter{}
+	}
+
+	return templateCacheMetrics{
+		hits:      hits,
+		misses:    misses,
+		evictions: evictions,
+	}
+}
+
+func (m templateCacheMetrics) addHits(ctx context.Context, layer string, count int) {
+	if count > 0 {
+		m.hits.Add(ctx, int64(count), metric.WithAttributes(attribute.String("layer", layer)))
+	}
+}
+
+func (m templateCacheMetrics) addMisses(ctx context.Context, layer string, count int) {
+	if count > 0 {
+		m.misses.Add(ctx, int64(count), metric.WithAttributes(attribute.String("layer", layer)))
+	}
+}
+
+func (m templateCacheMetrics) addEvictions(ctx context.Context, layer string, count int) {
+	if count > 0 {
+		m.evictions.Add(ctx, int64(count), metric.WithAttributes(attribute.String("layer", layer)))
+	}
+}
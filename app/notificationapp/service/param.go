@@ -18,60 +18,353 @@ type SendNotificationRequest struct {
 	Type              NotificationType         `json:"type"`
 	Data              map[string]string        `json:"data"`
 	TemplateName      string                   `json:"template_name"`
-	DynamicBodyData   map[string]string        `json:"dynamic_body_data,omitempty"`
-	DynamicTitleData  map[string]string        `json:"dynamic_title_data,omitempty"`
+	TemplateVersion   *int                     `json:"-"`
+	DynamicBodyData   DynamicData              `json:"dynamic_body_data,omitempty"`
+	DynamicTitleData  DynamicData              `json:"dynamic_title_data,omitempty"`
 	ChannelDeliveries []ChannelDeliveryRequest `json:"channel_deliveries"`
-	IsInApp           bool                     `json:"-"`
-	Status            DeliveryStatus           `json:"-"`
+	// SendAt schedules the notification for a later time, if it is empty or not in the future,
+	// the notification is dispatched immediately.
+	SendAt   *time.Time    `json:"send_at,omitempty"`
+	IsInApp  bool          `json:"-"`
+	Status   OverallStatus `json:"-"`
+	Category string        `json:"-"`
+	Topic    string        `json:"-"`
+	// IdempotencyKey a retried request with the same key returns the notification of the first request
+	// instead of sending it again, keys are kept for IdempotencyKeyTTL.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	ContentHash    string `json:"-"`
+}
+
+// SendBulkNotificationRequest every notification is sent on its own, a failed notification does not stop the others.
+type SendBulkNotificationRequest struct {
+	Notifications []SendNotificationRequest `json:"notifications"`
+}
+
+// SendBulkNotificationResult Index is the index of the notification in the request, Err is set when it is not sent.
+type SendBulkNotificationResult struct {
+	Index        int           `json:"index"`
+	Notification *Notification `json:"notification,omitempty"`
+	Err          error         `json:"-"`
+}
+
+type SendBulkNotificationResponse struct {
+	SentCount   int                          `json:"sent_count"`
+	FailedCount int                          `json:"failed_count"`
+	Results     []SendBulkNotificationResult `json:"results"`
 }
 
 // NotificationMessage rendered notification.
 type NotificationMessage struct {
-	ID        types.ID          `json:"id"`
-	UserID    types.ID          `json:"user_id"`
-	Type      NotificationType  `json:"type"`
-	Data      map[string]string `json:"data"`
-	Title     string            `json:"title"`
-	Body      string            `json:"body"`
-	IsRead    bool              `json:"is_read"`
-	Timestamp int64             `json:"timestamp"`
+	ID         types.ID          `json:"id"`
+	UserID     types.ID          `json:"user_id"`
+	Type       NotificationType  `json:"type"`
+	Category   string            `json:"category"`
+	Data       map[string]string `json:"data"`
+	Dir        TextDirection     `json:"dir"`
+	Title      string            `json:"title"`
+	Body       string            `json:"body"`
+	IsRead     bool              `json:"is_read"`
+	IsArchived bool              `json:"is_archived"`
+	Timestamp  int64             `json:"timestamp"`
+	Seq        int64             `json:"seq"`
+	// UnsubscribeURL is the signed one-click unsubscribe link of the topic, it is set only for emails,
+	// so the email sender can add the List-Unsubscribe header.
+	UnsubscribeURL string `json:"unsubscribe_url,omitempty"`
+}
+
+// WSEvent is the event of the messages written on the notification websocket other than the notifications,
+// notification messages don't have an event for the backward compatibility of the clients.
+type WSEvent string
+
+const (
+	WSEventUnreadCount WSEvent = "unread_count"
+	WSEventReplayed    WSEvent = "replayed"
+	WSEventAck         WSEvent = "ack" // sent by the clients when they received notifications
+)
+
+// ReplayedMessage is sent after the missed notifications of a reconnected client are replayed,
+// HasMore is true when there are more missed notifications than the replay limit, the client should use the list API.
+type ReplayedMessage struct {
+	Event   WSEvent  `json:"event"`
+	UserID  types.ID `json:"user_id"`
+	Count   int      `json:"count"`
+	LastSeq int64    `json:"last_seq"`
+	HasMore bool     `json:"has_more"`
+}
+
+// ClientMessage is a message which is sent by the websocket clients, Seqs are the seq of the received notifications.
+type ClientMessage struct {
+	Event WSEvent `json:"event"`
+	Seqs  []int64 `json:"seqs"`
+}
+
+// UnreadCountMessage is pushed to the websocket clients of the user whenever the unread count changes.
+type UnreadCountMessage struct {
+	Event      WSEvent        `json:"event"`
+	UserID     types.ID       `json:"user_id"`
+	Total      int            `json:"total"`
+	Categories map[string]int `json:"categories"`
 }
 
+// DigestMessage rendered digest of several notifications, it is published on DigestChannelName for the external
+// email and sms senders, which send it to the user on Channel.
+type DigestMessage struct {
+	UserID          types.ID        `json:"user_id"`
+	Channel         ChannelType     `json:"channel"`
+	Frequency       DigestFrequency `json:"frequency"`
+	Lang            string          `json:"lang"`
+	Dir             TextDirection   `json:"dir"`
+	Title           string          `json:"title"`
+	Body            string          `json:"body"`
+	NotificationIDs []types.ID      `json:"notification_ids"`
+	Timestamp       int64           `json:"timestamp"`
+}
+
+// ListNotificationRequest archived notifications are listed only when IsArchived is true,
+// From and To filter the created at of the notifications.
 type ListNotificationRequest struct {
 	ExternalUserID string               `json:"-"`
 	IsRead         *bool                `json:"is_read"`
+	IsArchived     bool                 `json:"is_archived"`
+	Types          []NotificationType   `json:"types"`
+	Category       string               `json:"category"`
+	From           *time.Time           `json:"from,omitempty"`
+	To             *time.Time           `json:"to,omitempty"`
 	Paginated      paginate.RequestBase `json:"paginated"`
 }
 
+// NotificationFilter filters the notifications of a user, deleted notifications are never returned.
+type NotificationFilter struct {
+	IsRead     *bool
+	IsInApp    *bool
+	IsArchived *bool
+	Types      []NotificationType
+	// ExcludeTypes are the notification types the user ignored.
+	ExcludeTypes []NotificationType
+	Category     string
+	From         *time.Time
+	To           *time.Time
+}
+
+// UnreadCountResponse Categories is the unread count of every category which has an unread notification.
+type UnreadCountResponse struct {
+	Total      int            `json:"total"`
+	Categories map[string]int `json:"categories"`
+}
+
 type ListNotificationResponse struct {
 	Results  []NotificationMessage `json:"results"`
 	Paginate paginate.ResponseBase `json:"paginate"`
 }
 
 type AddTemplateRequest struct {
-	ID       types.ID          `json:"-"`
-	Name     string            `json:"name"` // maximum is 255 characters.
-	Contents []TemplateContent `json:"contents"`
+	ID                 types.ID          `json:"-"`
+	Name               string            `json:"name"`                 // maximum is 255 characters.
+	Kind               TemplateKind      `json:"kind,omitempty"`       // default is notification, an update with another kind is rejected.
+	Category           string            `json:"category"`             // notifications are grouped by their type when it is empty.
+	Topic              string            `json:"topic"`                // name of an existing topic, it can be empty.
+	DedupWindowSeconds int               `json:"dedup_window_seconds"` // maximum is one day, zero disables the deduplication.
+	Contents           []TemplateContent `json:"contents"`
 }
 
 type UpdateUserSettingRequest struct {
-	Lang           string          `json:"lang"`
-	IgnoreChannels []IgnoreChannel `json:"ignore_channels"`
+	Lang              string             `json:"lang"`
+	IgnoreChannels    []IgnoreChannel    `json:"ignore_channels"`
+	DigestPreferences []DigestPreference `json:"digest_preferences"`
+	Timezone          string             `json:"timezone"`
+	QuietHours        []QuietHours       `json:"quiet_hours"`
+	TopicPreferences  []TopicPreference  `json:"topic_preferences"` // the stored preferences are kept when it is null.
+}
+
+type AddTopicRequest struct {
+	ID          types.ID `json:"-"`
+	Name        string   `json:"name"` // maximum is 64 characters, it can't be changed by update.
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+}
+
+type ListTopicResponse struct {
+	Results []Topic `json:"results"`
+}
+
+// UserPreferences is the effective preference of a user on every topic and channel.
+type UserPreferences struct {
+	UserID types.ID           `json:"user_id"`
+	Topics []TopicPreferences `json:"topics"`
+}
+
+type TopicPreferences struct {
+	Topic    Topic               `json:"topic"`
+	Channels []ChannelPreference `json:"channels"`
+}
+
+// ChannelPreference IsDefault is true when the user has not opted in or out of the topic on the channel,
+// in this case the ignore channels of the notification type are applied.
+type ChannelPreference struct {
+	Channel    ChannelType `json:"channel"`
+	Subscribed bool        `json:"subscribed"`
+	IsDefault  bool        `json:"is_default"`
+}
+
+// UnsubscribeRequest is the query of a signed unsubscribe link.
+type UnsubscribeRequest struct {
+	UserID    types.ID
+	Topic     string
+	Channel   ChannelType
+	Signature string
 }
 
 type ListTemplateRequest struct {
 	Name      string               `json:"template_name"`
+	Kind      TemplateKind         `json:"kind"`
 	Paginated paginate.RequestBase `json:"paginated"`
 }
 
 type ListTemplateResult struct {
-	ID        types.ID  `json:"id"`
-	Name      string    `json:"template_name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        types.ID     `json:"id"`
+	Name      string       `json:"template_name"`
+	Kind      TemplateKind `json:"kind"`
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
 }
 
 type ListTemplateResponse struct {
 	Results  []ListTemplateResult  `json:"results"`
 	Paginate paginate.ResponseBase `json:"paginate"`
 }
+
+type ListTemplateVersionResponse struct {
+	TemplateID types.ID          `json:"template_id"`
+	Version    int               `json:"version"` // current version of the template
+	Results    []TemplateVersion `json:"results"`
+}
+
+// PreviewTemplateRequest renders a template with sample data, if Version is empty the current version is used.
+type PreviewTemplateRequest struct {
+	Version          *int        `json:"version,omitempty"`
+	Channel          ChannelType `json:"channel"`
+	Lang             string      `json:"lang"`
+	DynamicTitleData DynamicData `json:"dynamic_title_data,omitempty"`
+	DynamicBodyData  DynamicData `json:"dynamic_body_data,omitempty"`
+}
+
+type PreviewTemplateResponse struct {
+	TemplateID       types.ID      `json:"template_id"`
+	Name             string        `json:"name"`
+	Version          int           `json:"version"`
+	Channel          ChannelType   `json:"channel"`
+	Lang             string        `json:"lang"`
+	Dir              TextDirection `json:"dir"`
+	Title            string        `json:"title"`
+	Body             string        `json:"body"`
+	MissingVariables []string      `json:"missing_variables"`
+}
+
+// TemplateLanguageWarning a content of the template doesn't have the language of Users users,
+// they receive the content in FallbackLang.
+type TemplateLanguageWarning struct {
+	Channel      ChannelType `json:"channel"`
+	Lang         string      `json:"lang"`
+	FallbackLang string      `json:"fallback_lang"`
+	Users        int         `json:"users"`
+}
+
+type TemplateLanguageWarningsResponse struct {
+	TemplateID types.ID                  `json:"template_id"`
+	Name       string                    `json:"name"`
+	Warnings   []TemplateLanguageWarning `json:"warnings"`
+}
+
+// SendTestNotificationRequest sends the current version of a template to the given external user, it is used by
+// admins to send a test notification to themselves.
+type SendTestNotificationRequest struct {
+	ExternalUserID    string                   `json:"external_user_id"`
+	ChannelDeliveries []ChannelDeliveryRequest `json:"channel_deliveries"`
+	DynamicTitleData  DynamicData              `json:"dynamic_title_data,omitempty"`
+	DynamicBodyData   DynamicData              `json:"dynamic_body_data,omitempty"`
+}
+
+type RescheduleNotificationRequest struct {
+	SendAt time.Time `json:"send_at"`
+}
+
+type ListScheduledNotificationRequest struct {
+	Status    OverallStatus        `json:"status"`
+	Paginated paginate.RequestBase `json:"paginated"`
+}
+
+type ListScheduledNotificationResponse struct {
+	Results  []Notification        `json:"results"`
+	Paginate paginate.ResponseBase `json:"paginate"`
+}
+
+// BroadcastRequest creates a campaign, recipients are given by ExternalUserIDs or by a CSV file
+// uploaded to the storage service whose first column is the external user id.
+type BroadcastRequest struct {
+	ID                types.ID                 `json:"-"`
+	Name              string                   `json:"name"`
+	Type              NotificationType         `json:"type"`
+	Data              map[string]string        `json:"data"`
+	TemplateName      string                   `json:"template_name"`
+	DynamicBodyData   DynamicData              `json:"dynamic_body_data,omitempty"`
+	DynamicTitleData  DynamicData              `json:"dynamic_title_data,omitempty"`
+	ChannelDeliveries []ChannelDeliveryRequest `json:"channel_deliveries"`
+	ExternalUserIDs   []string                 `json:"external_user_ids"`
+	CSVFileID         types.ID                 `json:"csv_file_id"`
+}
+
+type ListCampaignRequest struct {
+	Status    CampaignStatus       `json:"status"`
+	Paginated paginate.RequestBase `json:"paginated"`
+}
+
+type ListCampaignResponse struct {
+	Results  []Campaign            `json:"results"`
+	Paginate paginate.ResponseBase `json:"paginate"`
+}
+
+// CampaignRecipientResult is the outcome of processing a campaign recipient.
+type CampaignRecipientResult struct {
+	RecipientID    int64
+	Status         CampaignRecipientStatus
+	NotificationID *types.ID
+	Error          *string
+}
+
+// DeliveryEventRequest is a provider event that is normalized by the webhook handler of the provider.
+// DeliveryEventRequest ProviderEventID is the id of the event at the provider, an event with an id is applied once,
+// so the events of a retried webhook are not applied again.
+type DeliveryEventRequest struct {
+	NotificationID  types.ID          `json:"notification_id"`
+	Channel         ChannelType       `json:"channel"`
+	Type            DeliveryEventType `json:"type"`
+	URL             string            `json:"url,omitempty"`
+	Error           *string           `json:"error,omitempty"`
+	OccurredAt      time.Time         `json:"occurred_at"` // default is the receive time
+	ProviderEventID string            `json:"provider_event_id,omitempty"`
+}
+
+// HandleWebhookResponse events of unknown notifications or channels are skipped, so providers do not retry them.
+type HandleWebhookResponse struct {
+	Accepted int `json:"accepted"`
+	Skipped  int `json:"skipped"`
+}
+
+type NotificationTimelineResponse struct {
+	NotificationID    types.ID          `json:"notification_id"`
+	OverallStatus     OverallStatus     `json:"overall_status"`
+	ChannelDeliveries []ChannelDelivery `json:"channel_deliveries"`
+	Events            []TimelineEvent   `json:"events"`
+}
+
+// TimelineEvent Type is created, scheduled or dispatched for the notification events, otherwise it is
+// a DeliveryEventType of the channel.
+type TimelineEvent struct {
+	Type       string      `json:"type"`
+	Channel    ChannelType `json:"channel,omitempty"`
+	Provider   string      `json:"provider,omitempty"`
+	URL        string      `json:"url,omitempty"`
+	Error      *string     `json:"error,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// GetUserPreferences returns the effective preference of the user on every topic and channel, channels
+// the user has not opted in or out of are subscribed by default.
+func (s Service) GetUserPreferences(ctx context.Context, externalUserID string) (UserPreferences, error) {
+	const op = "service.preference.GetUserPreferences"
+
+	userID, gErr := s.getUserIDFromExternalUserID(ctx, externalUserID)
+	if gErr != nil {
+		return UserPreferences{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	userSetting, usErr := s.GetUserSetting(ctx, string(userID))
+	if usErr != nil {
+		return UserPreferences{}, usErr
+	}
+
+	topics, tErr := s.repo.GetTopics(ctx)
+	if tErr != nil {
+		return UserPreferences{}, errlog.ErrLog(richerror.New(op).WithWrapError(tErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	preferences := UserPreferences{UserID: userID, Topics: make([]TopicPreferences, 0, len(topics))}
+	for _, topic := range topics {
+		topicPreferences := TopicPreferences{Topic: topic, Channels: make([]ChannelPreference, 0, len(AllChannelType))}
+
+		for _, channel := range AllChannelType {
+			channelPreference := ChannelPreference{Channel: channel, Subscribed: true, IsDefault: true}
+			if preference, ok := findTopicPreference(userSetting.TopicPreferences, topic.Name, channel); ok {
+				channelPreference.Subscribed = preference.Subscribed
+				channelPreference.IsDefault = false
+			}
+
+			topicPreferences.Channels = append(topicPreferences.Channels, channelPreference)
+		}
+
+		preferences.Topics = append(preferences.Topics, topicPreferences)
+	}
+
+	return preferences, nil
+}
+
+// setTopicPreference opts the user in or out of the topic on the channel, the other settings of the user are kept.
+// The preference is saved by a single upsert, so the concurrent preferences of the user are not lost.
+func (s Service) setTopicPreference(ctx context.Context, userID types.ID, preference TopicPreference) error {
+	return s.repo.SaveTopicPreference(ctx, userID, s.cfg.DefaultUserLanguage, preference)
+}
+
+func findTopicPreference(preferences []TopicPreference, topic string, channel ChannelType) (TopicPreference, bool) {
+	for _, preference := range preferences {
+		if preference.Topic == topic && preference.Channel == channel {
+			return preference, true
+		}
+	}
+
+	return TopicPreference{}, false
+}
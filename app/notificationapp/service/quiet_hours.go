@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const quietHoursLayout = "15:04"
+
+// GetQuietHoursEnd returns the end of the quiet window of the channel that now falls in.
+// Critical and direct notifications bypass quiet hours, like they bypass the ignore rules.
+func (s Service) GetQuietHoursEnd(notificationType NotificationType, userSetting UserSetting, channel ChannelType,
+	now time.Time) (time.Time, bool) {
+	if notificationType == NotificationTypeCritical || notificationType == NotificationTypeDirect {
+		return time.Time{}, false
+	}
+
+	location := time.UTC
+	if userSetting.Timezone != "" {
+		if loc, lErr := time.LoadLocation(userSetting.Timezone); lErr == nil {
+			location = loc
+		}
+	}
+
+	for _, quietHours := range userSetting.QuietHours {
+		if quietHours.Channel != channel {
+			continue
+		}
+
+		if end, ok := quietHoursEnd(quietHours, now.In(location)); ok {
+			return end, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// quietHoursEnd now must be in the user location.
+func quietHoursEnd(quietHours QuietHours, now time.Time) (time.Time, bool) {
+	start, sErr := time.Parse(quietHoursLayout, quietHours.Start)
+	if sErr != nil {
+		return time.Time{}, false
+	}
+
+	end, eErr := time.Parse(quietHoursLayout, quietHours.End)
+	if eErr != nil {
+		return time.Time{}, false
+	}
+
+	year, month, day := now.Date()
+	todayStart := time.Date(year, month, day, start.Hour(), start.Minute(), 0, 0, now.Location())
+	todayEnd := time.Date(year, month, day, end.Hour(), end.Minute(), 0, 0, now.Location())
+
+	if todayStart.Before(todayEnd) {
+		if !now.Before(todayStart) && now.Before(todayEnd) {
+			return todayEnd, true
+		}
+
+		return time.Time{}, false
+	}
+
+	if now.Before(todayEnd) {
+		return todayEnd, true
+	}
+
+	if !now.Before(todayStart) {
+		return todayEnd.AddDate(0, 0, 1), true
+	}
+
+	return time.Time{}, false
+}
+
+// deferredDeliveryClaimTTL is the time that a claimed delivery is held by its instance, a delivery that is not
+// published until then is claimed again. It must be longer than PublishTimeout.
+const deferredDeliveryClaimTTL = 5 * time.Minute
+
+// deferDelivery holds back the delivery of the notification on the channel until the quiet window ends,
+// only the in-app deliveries are deferred, because the other channels are delivered by their senders.
+func (s Service) deferDelivery(ctx context.Context, notification Notification, channel ChannelType, deliverAt time.Time) error {
+	const op = "service.quiet_hours.deferDelivery"
+
+	if channel != ChannelTypeInApp {
+		return richerror.New(op).WithMessage(fmt.Sprintf("deferred delivery is not supported on channel %s", channel)).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	if sErr := s.repo.SaveDeferredDelivery(ctx, DeferredDelivery{
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Channel:        channel,
+		DeliverAt:      deliverAt,
+	}); sErr != nil {
+		return richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	s.logger.DebugContext(ctx, "notification deferred by quiet hours", slog.String("notification_id", string(notification.ID)),
+		slog.String("channel", string(channel)), slog.Time("deliver_at", deliverAt))
+
+	return nil
+}
+
+// dispatchDeferredDeliveries delivers the deferred in-app notifications whose quiet window has ended. A delivery is
+// removed after it is published, so a delivery that is failed is claimed again after deferredDeliveryClaimTTL.
+func (s Service) dispatchDeferredDeliveries(ctx context.Context) error {
+	const op = "service.quiet_hours.dispatchDeferredDeliveries"
+
+	for {
+		now := time.Now()
+
+		deliveries, cErr := s.repo.ClaimDueDeferredDeliveries(ctx, now, now.Add(deferredDeliveryClaimTTL), s.cfg.SchedulerBatchSize)
+		if cErr != nil {
+			return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		notificationIDs := make([]types.ID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			notificationIDs = append(notificationIDs, delivery.NotificationID)
+		}
+
+		notifications, gErr := s.repo.GetNotificationsByIDs(ctx, notificationIDs)
+		if gErr != nil {
+			return richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		notificationsByID := make(map[types.ID]Notification, len(notifications))
+		for _, notification := range notifications {
+			notificationsByID[notification.ID] = notification
+		}
+
+		for _, delivery := range deliveries {
+			notification, ok := notificationsByID[delivery.NotificationID]
+			if !ok || delivery.Channel != ChannelTypeInApp {
+				// the deliveries of the other channels are rejected by deferDelivery, they are not delivered.
+				s.deleteDeferredDelivery(ctx, delivery)
+
+				continue
+			}
+
+			go s.deliverDeferredDelivery(s.cfg.PublishTimeout, delivery, notification) //nolint:contextcheck // This function run asynchronously
+		}
+
+		if len(deliveries) < s.cfg.SchedulerBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverDeferredDelivery publishes the notification of the claimed delivery and removes the delivery.
+func (s Service) deliverDeferredDelivery(ctxTimeout time.Duration, delivery DeferredDelivery, notification Notification) {
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	defer cancel()
+
+	if dErr := s.deliverInApp(ctx, notification); dErr != nil {
+		errlog.WithoutErr(dErr, s.logger)
+
+		return
+	}
+
+	s.deleteDeferredDelivery(ctx, delivery)
+}
+
+func (s Service) deleteDeferredDelivery(ctx context.Context, delivery DeferredDelivery) {
+	const op = "service.quiet_hours.deleteDeferredDelivery"
+
+	if dErr := s.repo.DeleteDeferredDelivery(ctx, delivery.ID); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"deferred_delivery_id": delivery.ID}), s.logger)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*60*60+30*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, tehran)
+	}
+
+	tests := []struct {
+		name       string
+		quietHours QuietHours
+		now        time.Time
+		end        time.Time
+		quiet      bool
+	}{
+		{name: "inside a daytime window", quietHours: QuietHours{Start: "09:00", End: "17:00"}, now: at(19, 12, 0),
+			end: at(19, 17, 0), quiet: true},
+		{name: "at the start of a daytime window", quietHours: QuietHours{Start: "09:00", End: "17:00"}, now: at(19, 9, 0),
+			end: at(19, 17, 0), quiet: true},
+		{name: "at the end of a daytime window", quietHours: QuietHours{Start: "09:00", End: "17:00"}, now: at(19, 17, 0)},
+		{name: "before a daytime window", quietHours: QuietHours{Start: "09:00", End: "17:00"}, now: at(19, 8, 59)},
+		{name: "overnight window before midnight", quietHours: QuietHours{Start: "22:00", End: "07:30"}, now: at(19, 23, 15),
+			end: at(20, 7, 30), quiet: true},
+		{name: "overnight window after midnight", quietHours: QuietHours{Start: "22:00", End: "07:30"}, now: at(20, 2, 0),
+			end: at(20, 7, 30), quiet: true},
+		{name: "overnight window at its start", quietHours: QuietHours{Start: "22:00", End: "07:30"}, now: at(19, 22, 0),
+			end: at(20, 7, 30), quiet: true},
+		{name: "outside an overnight window", quietHours: QuietHours{Start: "22:00", End: "07:30"}, now: at(19, 12, 0)},
+		{name: "overnight window at the end of the month", quietHours: QuietHours{Start: "23:00", End: "06:00"},
+			now: at(31, 23, 30), end: time.Date(2026, time.November, 1, 6, 0, 0, 0, tehran), quiet: true},
+		{name: "invalid start", quietHours: QuietHours{Start: "9am", End: "17:00"}, now: at(19, 12, 0)},
+		{name: "invalid end", quietHours: QuietHours{Start: "09:00", End: "25:00"}, now: at(19, 12, 0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			end, quiet := quietHoursEnd(test.quietHours, test.now)
+			if quiet != test.quiet || !end.Equal(test.end) {
+				t.Fatalf("expected %s %t, got %s %t", test.end, test.quiet, end, quiet)
+			}
+		})
+	}
+}
+
+func TestGetQuietHoursEnd(t *testing.T) {
+	if _, lErr := time.LoadLocation("Asia/Tehran"); lErr != nil {
+		t.Skipf("time zone database is not available: %v", lErr)
+	}
+
+	svc := Service{}
+	userSetting := UserSetting{
+		Timezone:   "Asia/Tehran",
+		QuietHours: []QuietHours{{Channel: ChannelTypeEmail, Start: "22:00", End: "07:00"}},
+	}
+	// 20:00 UTC is 23:30 in Tehran.
+	now := time.Date(2026, time.October, 19, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		notificationType NotificationType
+		channel          ChannelType
+		quiet            bool
+	}{
+		{name: "quiet channel", notificationType: NotificationTypeInfo, channel: ChannelTypeEmail, quiet: true},
+		{name: "another channel", notificationType: NotificationTypeInfo, channel: ChannelTypeSMS},
+		{name: "critical notification", notificationType: NotificationTypeCritical, channel: ChannelTypeEmail},
+		{name: "direct notification", notificationType: NotificationTypeDirect, channel: ChannelTypeEmail},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			end, quiet := svc.GetQuietHoursEnd(test.notificationType, userSetting, test.channel, now)
+			if quiet != test.quiet {
+				t.Fatalf("expected quiet %t, got %t", test.quiet, quiet)
+			}
+
+			// 07:00 in Tehran is 03:30 UTC.
+			if quiet && !end.Equal(time.Date(2026, time.October, 20, 3, 30, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected end of the quiet window %s", end)
+			}
+		})
+	}
+}
+
+func TestDeferDeliveryChannels(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel ChannelType
+		saved   bool
+	}{
+		{name: "in-app", channel: ChannelTypeInApp, saved: true},
+		{name: "email", channel: ChannelTypeEmail},
+		{name: "sms", channel: ChannelTypeSMS},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			svc := Service{repo: repo, logger: discardLogger()}
+
+			dErr := svc.deferDelivery(context.Background(), Notification{ID: "n1", UserID: "u1"}, test.channel, time.Now())
+			if (dErr == nil) != test.saved || (len(repo.deferred) == 1) != test.saved {
+				t.Fatalf("expected saved %t, got error %v with %d deliveries", test.saved, dErr, len(repo.deferred))
+			}
+		})
+	}
+}
+
+func TestDeliverDeferredDelivery(t *testing.T) {
+	userID := types.ID(ulid.Make().String())
+	now := time.Now().UTC()
+	quietHours := QuietHours{
+		Channel: ChannelTypeInApp,
+		Start:   now.Add(-time.Hour).Format(quietHoursLayout),
+		End:     now.Add(time.Hour).Format(quietHoursLayout),
+	}
+
+	tests := []struct {
+		name     string
+		deferErr error
+		kept     bool
+	}{
+		{name: "deferred again", kept: false},
+		{name: "failed delivery is kept", deferErr: errors.New("connection refused"), kept: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			repo.userSettings[userID] = UserSetting{QuietHours: []QuietHours{quietHours}}
+			delivery := DeferredDelivery{ID: 100, NotificationID: "n1", UserID: userID, Channel: ChannelTypeInApp,
+				DeliverAt: now.Add(-time.Minute)}
+			repo.deferred[delivery.ID] = delivery
+			repo.deferErr = test.deferErr
+			svc := Service{repo: repo, logger: discardLogger()}
+
+			svc.deliverDeferredDelivery(time.Second, delivery, Notification{ID: "n1", UserID: userID, Type: NotificationTypeInfo})
+
+			if _, ok := repo.deferred[delivery.ID]; ok != test.kept {
+				t.Fatalf("expected the claimed delivery kept %t", test.kept)
+			}
+		})
+	}
+}
+
+func TestDispatchDeferredDeliveriesRemovesUndeliverable(t *testing.T) {
+	repo := newMemRepository()
+	due := time.Now().Add(-time.Minute)
+	repo.deferred[1] = DeferredDelivery{ID: 1, NotificationID: "n1", UserID: "u1", Channel: ChannelTypeSMS, DeliverAt: due}
+	repo.deferred[2] = DeferredDelivery{ID: 2, NotificationID: "deleted", UserID: "u1", Channel: ChannelTypeInApp, DeliverAt: due}
+	repo.notifications["n1"] = Notification{ID: "n1", UserID: "u1"}
+	svc := Service{cfg: Config{SchedulerBatchSize: 10}, repo: repo, logger: discardLogger()}
+
+	if dErr := svc.dispatchDeferredDeliveries(context.Background()); dErr != nil {
+		t.Fatalf("unexpected error: %v", dErr)
+	}
+
+	if len(repo.deferred) != 0 {
+		t.Fatalf("expected the undeliverable deliveries to be removed, got %v", repo.deferred)
+	}
+}
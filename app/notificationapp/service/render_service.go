@@ -2,11 +2,15 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	htmlTemp "html/template"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 	textTemp "text/template"
+	"text/template/parse"
 
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 )
@@ -22,6 +26,7 @@ type RenderService struct {
 	textTemps   map[string]*textTemp.Template
 	htmlTemps   map[string]*htmlTemp.Template
 	defaultLang string
+	metrics     templateCacheMetrics
 }
 
 func NewRenderService(defaultLang string) *RenderService {
@@ -29,23 +34,58 @@ func NewRenderService(defaultLang string) *RenderService {
 		textTemps:   make(map[string]*textTemp.Template),
 		htmlTemps:   make(map[string]*htmlTemp.Template),
 		defaultLang: defaultLang,
+		metrics:     newTemplateCacheMetrics(),
 	}
 }
 
+// Evict removes the compiled templates of all versions of a template and the compiled templates that include it
+// as a layout or partial, it returns the number of removed executors.
+func (r *RenderService) Evict(templateName string) int {
+	prefix := templateName + ":v"
+	include := includeKeySeparator + templateName + "@"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	evicted := 0
+	for key := range r.textTemps {
+		if strings.HasPrefix(key, prefix) || strings.Contains(key, include) {
+			delete(r.textTemps, key)
+			evicted++
+		}
+	}
+
+	for key := range r.htmlTemps {
+		if strings.HasPrefix(key, prefix) || strings.Contains(key, include) {
+			delete(r.htmlTemps, key)
+			evicted++
+		}
+	}
+
+	r.metrics.addEvictions(context.Background(), templateCacheLayerExecutor, evicted)
+
+	return evicted
+}
+
 const (
 	TemplateTypeText TemplateType = "text"
 	TemplateTypeHTML TemplateType = "html"
 )
 
+// RenderTemplate Dir is the text direction of Lang, so the clients can set dir="rtl" for Persian and Arabic.
 type RenderTemplate struct {
-	Name  string `json:"name"`
-	Lang  string `json:"lang"`
-	Title string `json:"title"`
-	Body  string `json:"body"`
+	Name  string        `json:"name"`
+	Lang  string        `json:"lang"`
+	Dir   TextDirection `json:"dir"`
+	Title string        `json:"title"`
+	Body  string        `json:"body"`
 }
 
+// RenderTemplate renders the content of the channel in the language, or in the fallback language. includes are
+// the layouts and partials of the template, they are always rendered in their current version.
+// The templates can use {{dir}} for the direction of the rendered language, e.g. <div dir="{{dir}}">.
 func (r *RenderService) RenderTemplate(template Template, channel ChannelType, lang string, titleData,
-	bodyData map[string]string) (RenderTemplate, error) {
+	bodyData DynamicData, includes ...Template) (RenderTemplate, error) {
 	const op = "service.template_render.RenderTemplate"
 
 	if !IsValidChannelType(channel) {
@@ -61,29 +101,389 @@ func (r *RenderService) RenderTemplate(template Template, channel ChannelType, l
 
 	tempLang := r.selectLanguage(content.Bodies, lang)
 	contentBody := r.findContentBody(content.Bodies, tempLang)
-	templateName := fmt.Sprintf("%s:%s:%s", template.Name, channel, tempLang)
+	if contentBody == nil {
+		return RenderTemplate{}, richerror.New(op).WithKind(richerror.KindUnexpected).
+			WithMessage(fmt.Sprintf("%s channel of %s template does not have any body", channel, template.Name))
+	}
+
+	layout, partials, includeKey := r.findIncludes(*content, *contentBody, tempLang, includes)
+	// Versions are immutable, so the parsed templates are cached per version of the template and its includes.
+	templateName := fmt.Sprintf("%s:v%d:%s:%s%s", template.Name, template.Version, channel, tempLang, includeKey)
 	templateType := r.getTemplateType(channel)
 
-	title, rtErr := r.renderTemplate(fmt.Sprintf("%s:%s", templateName, "title"), contentBody.Title, templateType, titleData)
+	title, rtErr := r.renderTemplate(templateSource{
+		name:     fmt.Sprintf("%s:%s", templateName, "title"),
+		lang:     tempLang,
+		text:     contentBody.Title,
+		partials: partials,
+	}, templateType, titleData)
 	if rtErr != nil {
-		return RenderTemplate{}, richerror.New(op).WithMessage("can't render template content title").
+		return RenderTemplate{}, richerror.New(op).WithMessage("can't render template content title").WithWrapError(rtErr).
 			WithKind(richerror.KindUnexpected).WithMeta(map[string]interface{}{"contentTitle": contentBody.Title, "titleData": titleData})
 	}
 
-	body, rbErr := r.renderTemplate(fmt.Sprintf("%s:%s", templateName, "body"), contentBody.Body, templateType, bodyData)
+	body, rbErr := r.renderTemplate(templateSource{
+		name:     fmt.Sprintf("%s:%s", templateName, "body"),
+		lang:     tempLang,
+		text:     contentBody.Body,
+		layout:   layout,
+		partials: partials,
+	}, templateType, bodyData)
 	if rbErr != nil {
-		return RenderTemplate{}, richerror.New(op).WithMessage("can't render template content body").
+		return RenderTemplate{}, richerror.New(op).WithMessage("can't render template content body").WithWrapError(rbErr).
 			WithKind(richerror.KindUnexpected).WithMeta(map[string]interface{}{"contentBody": contentBody.Body, "bodyData": bodyData})
 	}
 
 	return RenderTemplate{
 		Name:  template.Name,
 		Lang:  tempLang,
+		Dir:   LanguageDirection(tempLang),
 		Title: title,
 		Body:  body,
 	}, nil
 }
 
+// MissingVariables returns the variables used by the template title and body of the channel and language,
+// and by their layout and partials, that are not in titleData and bodyData.
+func (r *RenderService) MissingVariables(template Template, channel ChannelType, lang string, titleData,
+	bodyData DynamicData, includes ...Template) ([]string, error) {
+	const op = "service.template_render.MissingVariables"
+
+	content := r.findContentByChannel(template, channel)
+	if content == nil {
+		return nil, richerror.New(op).WithKind(richerror.KindUnexpected).
+			WithMessage(fmt.Sprintf("%s channel is not in %s template", channel, template.Name))
+	}
+
+	tempLang := r.selectLanguage(content.Bodies, lang)
+	contentBody := r.findContentBody(content.Bodies, tempLang)
+	if contentBody == nil {
+		return []string{}, nil
+	}
+
+	layout, partials, _ := r.findIncludes(*content, *contentBody, tempLang, includes)
+
+	titleTexts := []string{contentBody.Title}
+	bodyTexts := []string{contentBody.Body, layout}
+	for _, partial := range partials {
+		titleTexts = append(titleTexts, partial)
+		bodyTexts = append(bodyTexts, partial)
+	}
+
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	appendMissing := func(texts []string, data DynamicData) error {
+		for _, text := range texts {
+			variables, _, iErr := inspectTemplate(text)
+			if iErr != nil {
+				return iErr
+			}
+
+			for _, variable := range variables {
+				if _, ok := data[variable]; ok || seen[variable] {
+					continue
+				}
+
+				seen[variable] = true
+				missing = append(missing, variable)
+			}
+		}
+
+		return nil
+	}
+
+	if tErr := appendMissing(titleTexts, titleData); tErr != nil {
+		return nil, richerror.New(op).WithMessage("can't parse template content title").WithWrapError(tErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	if bErr := appendMissing(bodyTexts, bodyData); bErr != nil {
+		return nil, richerror.New(op).WithMessage("can't parse template content body").WithWrapError(bErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	return missing, nil
+}
+
+// Includes returns the names of the layouts and partials that are used by the template contents.
+func (r *RenderService) Includes(template Template) ([]string, error) {
+	const op = "service.template_render.Includes"
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	appendName := func(name string) {
+		if name != "" && name != template.Name && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, content := range template.Contents {
+		appendName(content.Layout)
+
+		for _, body := range content.Bodies {
+			for _, text := range []string{body.Title, body.Body} {
+				_, references, iErr := inspectTemplate(text)
+				if iErr != nil {
+					return nil, richerror.New(op).WithMessage(fmt.Sprintf("can't parse %s template", template.Name)).
+						WithWrapError(iErr).WithKind(richerror.KindUnexpected)
+				}
+
+				for _, reference := range references {
+					appendName(reference)
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// layoutContentTemplateName is the name of the rendered body in the layouts, e.g. {{template "content" .}}.
+const layoutContentTemplateName = "content"
+
+// includeKeySeparator separates the includes and their versions in the executor cache keys.
+const includeKeySeparator = "|"
+
+// findIncludes returns the layout body and the bodies of the partials that are used by the content body,
+// directly or by other partials, and a key of their versions.
+func (r *RenderService) findIncludes(content TemplateContent, contentBody ContentBody, lang string,
+	includes []Template) (string, map[string]string, string) {
+	partialTemplates := make(map[string]Template)
+	var layoutTemplate *Template
+	for i := range includes {
+		switch {
+		case includes[i].Kind == TemplateKindPartial:
+			partialTemplates[includes[i].Name] = includes[i]
+		case includes[i].Kind == TemplateKindLayout && includes[i].Name == content.Layout:
+			layoutTemplate = &includes[i]
+		}
+	}
+
+	includeBody := func(template Template) *ContentBody {
+		includeContent := r.findContentByChannel(template, content.Channel)
+		if includeContent == nil {
+			return nil
+		}
+
+		return r.findContentBody(includeContent.Bodies, r.selectLanguage(includeContent.Bodies, lang))
+	}
+
+	used := make([]Template, 0)
+	texts := []string{contentBody.Title, contentBody.Body}
+
+	layout := ""
+	if layoutTemplate != nil {
+		if body := includeBody(*layoutTemplate); body != nil {
+			layout = body.Body
+			texts = append(texts, layout)
+			used = append(used, *layoutTemplate)
+		}
+	}
+
+	partials := make(map[string]string)
+	for len(texts) > 0 {
+		text := texts[0]
+		texts = texts[1:]
+
+		// Parse errors are returned when the template is rendered.
+		_, references, _ := inspectTemplate(text)
+		for _, reference := range references {
+			partial, ok := partialTemplates[reference]
+			if _, included := partials[reference]; !ok || included {
+				continue
+			}
+
+			if body := includeBody(partial); body != nil {
+				partials[reference] = body.Body
+				texts = append(texts, body.Body)
+				used = append(used, partial)
+			}
+		}
+	}
+
+	sort.Slice(used, func(i, j int) bool {
+		return used[i].Name < used[j].Name
+	})
+
+	var key strings.Builder
+	for _, template := range used {
+		fmt.Fprintf(&key, "%s%s@v%d", includeKeySeparator, template.Name, template.Version)
+	}
+
+	return layout, partials, key.String()
+}
+
+// inspectTemplate returns the top level fields of the template data, such as name in {{.name}}, and the names
+// of the templates that are included and are not defined in the text, such as footer in {{template "footer" .}}.
+func inspectTemplate(text string) ([]string, []string, error) {
+	tmpl, pErr := textTemp.New("inspect").Funcs(templateFuncs("")).Parse(text)
+	if pErr != nil {
+		return nil, nil, pErr
+	}
+
+	variables := make([]string, 0)
+	references := make([]string, 0)
+	seen := make(map[string]bool)
+
+	// dot is false inside range and with, where the fields are not the top level fields.
+	var walk func(node parse.Node, dot bool)
+	walk = func(node parse.Node, dot bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+
+			for _, child := range n.Nodes {
+				walk(child, dot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, dot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+
+			for _, cmd := range n.Cmds {
+				walk(cmd, dot)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, dot)
+			}
+		case *parse.FieldNode:
+			if dot && len(n.Ident) > 0 && !seen[n.Ident[0]] {
+				seen[n.Ident[0]] = true
+				variables = append(variables, n.Ident[0])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, dot)
+			walk(n.List, dot)
+			walk(n.ElseList, dot)
+		case *parse.RangeNode:
+			walk(n.Pipe, dot)
+			walk(n.List, false)
+			walk(n.ElseList, dot)
+		case *parse.WithNode:
+			walk(n.Pipe, dot)
+			walk(n.List, false)
+			walk(n.ElseList, dot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, dot)
+
+			if tmpl.Lookup(n.Name) == nil && n.Name != layoutContentTemplateName && !seen["template:"+n.Name] {
+				seen["template:"+n.Name] = true
+				references = append(references, n.Name)
+			}
+		}
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walk(t.Tree.Root, true)
+		}
+	}
+
+	return variables, references, nil
+}
+
+// Default digest contents are used when the digest template is not defined.
+const (
+	defaultDigestTemplateName = "digest:default"
+	defaultDigestTitle        = "{{.Count}} new notifications"
+	defaultDigestTextBody     = "{{range .Items}}{{.Title}}\n{{.Body}}\n\n{{end}}"
+	defaultDigestHTMLBody     = "{{range .Items}}<h3>{{.Title}}</h3><div>{{.Body}}</div>{{end}}"
+)
+
+// DigestTemplateData is the data of a digest template, each item is an already rendered notification.
+type DigestTemplateData struct {
+	Count     int
+	Frequency DigestFrequency
+	Items     []DigestTemplateItem
+}
+
+// DigestTemplateItem Body is htmlTemp.HTML for the email channel, because it is rendered by an html template before.
+type DigestTemplateItem struct {
+	ID        string
+	Type      NotificationType
+	Title     string
+	Body      any
+	Timestamp int64
+}
+
+// RenderDigestTemplate renders several rendered notifications into a single message. If template is nil or
+// does not have content for the channel, the default digest contents are used. includes are the layouts and
+// partials of the template.
+func (r *RenderService) RenderDigestTemplate(template *Template, channel ChannelType, lang string,
+	frequency DigestFrequency, messages []NotificationMessage, includes ...Template) (RenderTemplate, error) {
+	const op = "service.template_render.RenderDigestTemplate"
+
+	templateType := r.getTemplateType(channel)
+
+	data := DigestTemplateData{
+		Count:     len(messages),
+		Frequency: frequency,
+		Items:     make([]DigestTemplateItem, 0, len(messages)),
+	}
+	for _, message := range messages {
+		var body any = message.Body
+		if templateType == TemplateTypeHTML {
+			body = htmlTemp.HTML(message.Body) //nolint:gosec // body is rendered by an html template before
+		}
+
+		data.Items = append(data.Items, DigestTemplateItem{
+			ID:        string(message.ID),
+			Type:      message.Type,
+			Title:     message.Title,
+			Body:      body,
+			Timestamp: message.Timestamp,
+		})
+	}
+
+	templateName := fmt.Sprintf("%s:%s:%s", defaultDigestTemplateName, channel, lang)
+	title := templateSource{lang: lang, text: defaultDigestTitle}
+	body := templateSource{lang: lang, text: defaultDigestTextBody}
+	if templateType == TemplateTypeHTML {
+		body.text = defaultDigestHTMLBody
+	}
+
+	if template != nil {
+		if content := r.findContentByChannel(*template, channel); content != nil && len(content.Bodies) > 0 {
+			lang = r.selectLanguage(content.Bodies, lang)
+			if contentBody := r.findContentBody(content.Bodies, lang); contentBody != nil {
+				layout, partials, includeKey := r.findIncludes(*content, *contentBody, lang, includes)
+				templateName = fmt.Sprintf("%s:v%d:%s:%s%s", template.Name, template.Version, channel, lang, includeKey)
+				title = templateSource{lang: lang, text: contentBody.Title, partials: partials}
+				body = templateSource{lang: lang, text: contentBody.Body, layout: layout, partials: partials}
+			}
+		}
+	}
+
+	title.name = fmt.Sprintf("%s:%s", templateName, "title")
+	body.name = fmt.Sprintf("%s:%s", templateName, "body")
+
+	renderedTitle, rtErr := r.renderTemplate(title, templateType, data)
+	if rtErr != nil {
+		return RenderTemplate{}, richerror.New(op).WithMessage("can't render digest template title").WithWrapError(rtErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	renderedBody, rbErr := r.renderTemplate(body, templateType, data)
+	if rbErr != nil {
+		return RenderTemplate{}, richerror.New(op).WithMessage("can't render digest template body").WithWrapError(rbErr).
+			WithKind(richerror.KindUnexpected)
+	}
+
+	return RenderTemplate{
+		Name:  templateName,
+		Lang:  lang,
+		Dir:   LanguageDirection(lang),
+		Title: renderedTitle,
+		Body:  renderedBody,
+	}, nil
+}
+
 func (r *RenderService) findContentByChannel(template Template, channel ChannelType) *TemplateContent {
 	for _, c := range template.Contents {
 		if c.Channel == channel {
@@ -93,27 +493,36 @@ func (r *RenderService) findContentByChannel(template Template, channel ChannelT
 	return nil
 }
 
+// selectLanguage returns the language of the body that is rendered for the requested language, it follows the
+// fallback chain of the requested language, e.g. fa-IR then fa, then the fallback chain of the default language,
+// and at last the first body.
 func (r *RenderService) selectLanguage(bodies []ContentBody, requestedLang string) string {
 	if len(bodies) == 0 {
 		return r.defaultLang
 	}
 
-	tempLang := bodies[0].Lang
+	if lang, ok := findFallbackLanguage(bodies, requestedLang); ok {
+		return lang
+	}
 
-	for _, b := range bodies {
-		if b.Lang == r.defaultLang {
-			tempLang = r.defaultLang
-			break
-		}
+	if lang, ok := findFallbackLanguage(bodies, r.defaultLang); ok {
+		return lang
 	}
 
-	for _, b := range bodies {
-		if b.Lang == requestedLang {
-			return requestedLang
+	return bodies[0].Lang
+}
+
+// findFallbackLanguage returns the language of the first body in the fallback chain of lang.
+func findFallbackLanguage(bodies []ContentBody, lang string) (string, bool) {
+	for _, fallback := range languageFallbacks(lang) {
+		for _, b := range bodies {
+			if strings.EqualFold(normalizeLanguageTag(b.Lang), fallback) {
+				return b.Lang, true
+			}
 		}
 	}
 
-	return tempLang
+	return "", false
 }
 
 func (r *RenderService) findContentBody(bodies []ContentBody, lang string) *ContentBody {
@@ -132,16 +541,25 @@ func (r *RenderService) getTemplateType(channel ChannelType) TemplateType {
 	return TemplateTypeText
 }
 
-func (r *RenderService) renderTemplate(templateName, template string, templateType TemplateType, data map[string]string) (string, error) {
+// templateSource is the text of a template with the layout and partials it can use, name is the executor cache key.
+type templateSource struct {
+	name     string
+	lang     string
+	text     string
+	layout   string
+	partials map[string]string
+}
+
+func (r *RenderService) renderTemplate(source templateSource, templateType TemplateType, data any) (string, error) {
 	const op = "service.template_render.RenderTemplate"
 
 	var executor Executor
 	var err error
 
 	if templateType == TemplateTypeText {
-		executor, err = r.getOrCreateTextTemplate(templateName, template)
+		executor, err = r.getOrCreateTextTemplate(source)
 	} else {
-		executor, err = r.getOrCreateHTMLTemplate(templateName, template)
+		executor, err = r.getOrCreateHTMLTemplate(source)
 	}
 
 	if err != nil {
@@ -157,49 +575,95 @@ func (r *RenderService) renderTemplate(templateName, template string, templateTy
 	return buf.String(), nil
 }
 
-func (r *RenderService) getOrCreateTextTemplate(templateName, template string) (Executor, error) {
+// getOrCreateTextTemplate when the source has a layout, the layout is the executed template and the text
+// is defined as its content.
+func (r *RenderService) getOrCreateTextTemplate(source templateSource) (Executor, error) {
 	const op = "service.template_render.getOrCreateTextTemplate"
 
 	r.mu.RLock()
-	temp, exists := r.textTemps[templateName]
+	temp, exists := r.textTemps[source.name]
 	r.mu.RUnlock()
 
 	if exists {
+		r.metrics.addHits(context.Background(), templateCacheLayerExecutor, 1)
+
 		return temp, nil
 	}
 
-	newTemp, pErr := textTemp.New(templateName).Parse(template)
-	if pErr != nil {
+	r.metrics.addMisses(context.Background(), templateCacheLayerExecutor, 1)
+
+	newTemp := textTemp.New(source.name).Funcs(templateFuncs(source.lang))
+	for name, partial := range source.partials {
+		if _, pErr := newTemp.New(name).Parse(partial); pErr != nil {
+			return nil, richerror.New(op).WithMessage(fmt.Sprintf("can't parse %s partial", name)).WithWrapError(pErr).
+				WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	text := source.text
+	if source.layout != "" {
+		if _, pErr := newTemp.New(layoutContentTemplateName).Parse(source.text); pErr != nil {
+			return nil, richerror.New(op).WithMessage("can't parse text template").WithWrapError(pErr).
+				WithKind(richerror.KindUnexpected)
+		}
+
+		text = source.layout
+	}
+
+	if _, pErr := newTemp.Parse(text); pErr != nil {
 		return nil, richerror.New(op).WithMessage("can't parse text template").WithWrapError(pErr).
 			WithKind(richerror.KindUnexpected)
 	}
 
 	r.mu.Lock()
-	r.textTemps[templateName] = newTemp
+	r.textTemps[source.name] = newTemp
 	r.mu.Unlock()
 
 	return newTemp, nil
 }
 
-func (r *RenderService) getOrCreateHTMLTemplate(templateName, template string) (Executor, error) {
+// getOrCreateHTMLTemplate when the source has a layout, the layout is the executed template and the text
+// is defined as its content.
+func (r *RenderService) getOrCreateHTMLTemplate(source templateSource) (Executor, error) {
 	const op = "service.template_render.getOrCreateHTMLTemplate"
 
 	r.mu.RLock()
-	temp, exists := r.htmlTemps[templateName]
+	temp, exists := r.htmlTemps[source.name]
 	r.mu.RUnlock()
 
 	if exists {
+		r.metrics.addHits(context.Background(), templateCacheLayerExecutor, 1)
+
 		return temp, nil
 	}
 
-	newTemp, pErr := htmlTemp.New(templateName).Parse(template)
-	if pErr != nil {
+	r.metrics.addMisses(context.Background(), templateCacheLayerExecutor, 1)
+
+	newTemp := htmlTemp.New(source.name).Funcs(templateFuncs(source.lang))
+	for name, partial := range source.partials {
+		if _, pErr := newTemp.New(name).Parse(partial); pErr != nil {
+			return nil, richerror.New(op).WithMessage(fmt.Sprintf("can't parse %s partial, %s", name, pErr)).
+				WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+		}
+	}
+
+	text := source.text
+	if source.layout != "" {
+		if _, pErr := newTemp.New(layoutContentTemplateName).Parse(source.text); pErr != nil {
+			return nil, richerror.New(op).WithMessage(fmt.Sprintf("can't parse html template, %s", pErr)).
+				WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+		}
+
+		text = source.layout
+	}
+
+	if _, pErr := newTemp.Parse(text); pErr != nil {
 		return nil, richerror.New(op).WithMessage(fmt.Sprintf("can't parse html template, %s", pErr)).WithWrapError(pErr).
 			WithKind(richerror.KindUnexpected)
 	}
 
 	r.mu.Lock()
-	r.htmlTemps[templateName] = newTemp
+	r.htmlTemps[source.name] = newTemp
 	r.mu.Unlock()
 
 	return newTemp, nil
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRenderTemplateIncludes(t *testing.T) {
+	layout := Template{Name: "base", Kind: TemplateKindLayout, Version: 1, Contents: []TemplateContent{
+		{Channel: ChannelTypeEmail, Bodies: []ContentBody{{Lang: "en", Body: `<div dir="{{dir}}">{{template "content" .}}</div>`}}},
+		{Channel: ChannelTypeSMS, Bodies: []ContentBody{{Lang: "en", Body: `[{{template "content" .}}]`}}},
+	}}
+	footer := Template{Name: "footer", Kind: TemplateKindPartial, Version: 1, Contents: []TemplateContent{
+		{Channel: ChannelTypeEmail, Bodies: []ContentBody{{Lang: "en", Body: `<p>{{.company}} {{template "signature" .}}</p>`}}},
+		{Channel: ChannelTypeSMS, Bodies: []ContentBody{{Lang: "en", Body: `- {{.company}}`}}},
+	}}
+	signature := Template{Name: "signature", Kind: TemplateKindPartial, Version: 1, Contents: []TemplateContent{
+		{Channel: ChannelTypeEmail, Bodies: []ContentBody{{Lang: "en", Body: `team`}}},
+	}}
+
+	template := Template{Name: "order", Kind: TemplateKindNotification, Version: 1, Contents: []TemplateContent{
+		{Channel: ChannelTypeEmail, Layout: "base", Bodies: []ContentBody{
+			{Lang: "en", Title: "Order {{.id}}", Body: `Hi {{.name}}{{template "footer" .}}`},
+		}},
+		{Channel: ChannelTypeSMS, Layout: "base", Bodies: []ContentBody{
+			{Lang: "en", Body: `Hi {{.name}} {{template "footer" .}}`},
+		}},
+		{Channel: ChannelTypeInApp, Bodies: []ContentBody{{Lang: "en", Body: `Hi {{.name}}`}}},
+	}}
+
+	tests := []struct {
+		name     string
+		channel  ChannelType
+		includes []Template
+		expected string
+		wantErr  bool
+	}{
+		{name: "html layout and nested partials", channel: ChannelTypeEmail, includes: []Template{layout, footer, signature},
+			expected: `<div dir="ltr">Hi &lt;Ali&gt;<p>Acme team</p></div>`},
+		{name: "text layout and partial", channel: ChannelTypeSMS, includes: []Template{layout, footer},
+			expected: `[Hi <Ali> - Acme]`},
+		{name: "content without layout", channel: ChannelTypeInApp, expected: `Hi <Ali>`},
+		{name: "missing partial", channel: ChannelTypeEmail, includes: []Template{layout}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, rErr := NewRenderService("en").RenderTemplate(template, test.channel, "en", DynamicData{"id": 7},
+				DynamicData{"name": "<Ali>", "company": "Acme"}, test.includes...)
+			if test.wantErr {
+				if rErr == nil {
+					t.Fatalf("expected error, got %q", res.Body)
+				}
+
+				return
+			}
+
+			if rErr != nil {
+				t.Fatalf("unexpected error: %v", rErr)
+			}
+
+			if res.Body != test.expected {
+				t.Fatalf("expected body %q, got %q", test.expected, res.Body)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateLayoutVersions(t *testing.T) {
+	layout := func(version int, text string) Template {
+		return Template{Name: "base", Kind: TemplateKindLayout, Version: version, Contents: []TemplateContent{
+			{Channel: ChannelTypeSMS, Bodies: []ContentBody{{Lang: "en", Body: text}}},
+		}}
+	}
+	template := Template{Name: "order", Version: 1, Contents: []TemplateContent{
+		{Channel: ChannelTypeSMS, Layout: "base", Bodies: []ContentBody{{Lang: "en", Body: "shipped"}}},
+	}}
+
+	renderSvc := NewRenderService("en")
+	for _, test := range []struct {
+		layout   Template
+		expected string
+	}{
+		{layout: layout(1, `v1 {{template "content" .}}`), expected: "v1 shipped"},
+		{layout: layout(2, `v2 {{template "content" .}}`), expected: "v2 shipped"},
+	} {
+		// the executors are cached by the versions of the includes, so a new layout version is rendered.
+		res, rErr := renderSvc.RenderTemplate(template, ChannelTypeSMS, "en", nil, nil, test.layout)
+		if rErr != nil {
+			t.Fatalf("unexpected error: %v", rErr)
+		}
+
+		if res.Body != test.expected {
+			t.Fatalf("expected body %q, got %q", test.expected, res.Body)
+		}
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	template := Template{Name: "order", Contents: []TemplateContent{
+		{Channel: ChannelTypeEmail, Layout: "base", Bodies: []ContentBody{
+			{Lang: "en", Title: `{{template "subject" .}}`, Body: `{{template "footer" .}}{{template "footer" .}}`},
+			{Lang: "fa", Body: `{{template "footer" .}}{{template "order" .}}`},
+		}},
+		{Channel: ChannelTypeSMS, Layout: "base", Bodies: []ContentBody{{Lang: "en", Body: "shipped"}}},
+	}}
+
+	names, iErr := NewRenderService("en").Includes(template)
+	if iErr != nil {
+		t.Fatalf("unexpected error: %v", iErr)
+	}
+
+	if expected := []string{"base", "subject", "footer"}; !slices.Equal(names, expected) {
+		t.Fatalf("expected includes %v, got %v", expected, names)
+	}
+
+	template.Contents[1].Bodies[0].Body = "{{template"
+	if _, iErr = NewRenderService("en").Includes(template); iErr == nil {
+		t.Fatal("expected error for invalid template syntax")
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/gorilla/websocket"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+// websocketProvider is the provider of the in-app delivery events which are acknowledged by the websocket clients.
+const websocketProvider = "websocket"
+
+// replayNotifications sends the in-app notifications which are published after lastSeq to a reconnected client.
+// A notification can be sent twice if it is published while the replay is running, clients deduplicate them by seq.
+func (s Service) replayNotifications(ctx context.Context, client *Client, lastSeq int64) {
+	const op = "service.replay.replayNotifications"
+
+	notifications, gErr := s.repo.GetInAppNotificationsAfterSeq(ctx, client.userID, lastSeq, s.cfg.ReplayLimit+1)
+	if gErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	replayed := ReplayedMessage{Event: WSEventReplayed, UserID: client.userID, LastSeq: lastSeq}
+	if len(notifications) > s.cfg.ReplayLimit {
+		notifications = notifications[:s.cfg.ReplayLimit]
+		replayed.HasMore = true
+	}
+
+	if len(notifications) > 0 {
+		userSetting, usErr := s.GetUserSetting(ctx, string(client.userID))
+		if usErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(usErr).WithKind(richerror.KindUnexpected), s.logger)
+
+			return
+		}
+
+		var accessNotifications []Notification
+		for _, notification := range notifications {
+			if s.CheckNotificationAccessToSend(notification, userSetting, ChannelTypeInApp) {
+				accessNotifications = append(accessNotifications, notification)
+			}
+		}
+
+		notificationMsgs, rErr := s.RenderNotificationTemplates(ctx, ChannelTypeInApp, userSetting.Lang, accessNotifications...)
+		if rErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected).
+				WithMessage("can't render notifications"), s.logger)
+
+			return
+		}
+
+		for _, msg := range notificationMsgs {
+			jsonData, mErr := json.Marshal(msg)
+			if mErr != nil {
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithMessage("can't marshalling notification message").
+					WithWrapError(mErr).WithKind(richerror.KindUnexpected), s.logger)
+
+				return
+			}
+
+			s.hub.sendReplay(ctx, client, hubMessage{UserID: msg.UserID, ID: msg.ID, Seq: msg.Seq}, jsonData)
+		}
+
+		replayed.Count = len(notificationMsgs)
+		replayed.LastSeq = notifications[len(notifications)-1].Seq
+	}
+
+	jsonData, mErr := json.Marshal(replayed)
+	if mErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithMessage("can't marshalling replayed message").
+			WithWrapError(mErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	s.hub.sendReplay(ctx, client, hubMessage{Event: WSEventReplayed, UserID: client.userID}, jsonData)
+}
+
+// HandleClientMessage is the callback for Client.ReadPump, acknowledged notifications are delivered on the
+// in-app channel.
+func (s Service) HandleClientMessage(ctx context.Context, client *Client, messageType int, payload []byte) {
+	const op = "service.replay.HandleClientMessage"
+
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg ClientMessage
+	if uErr := json.Unmarshal(payload, &msg); uErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindInvalid), s.logger)
+
+		return
+	}
+
+	if msg.Event != WSEventAck {
+		s.logger.WarnContext(ctx, "unknown client message event", slog.String("event", string(msg.Event)),
+			slog.String("user_id", string(client.userID)))
+
+		return
+	}
+
+	for _, seq := range msg.Seqs {
+		notificationID, ok := client.Ack(seq)
+		if !ok {
+			continue
+		}
+
+		if _, aErr := s.applyDeliveryEvent(ctx, websocketProvider, DeliveryEventRequest{
+			NotificationID: notificationID,
+			Channel:        ChannelTypeInApp,
+			Type:           DeliveryEventDelivered,
+		}); aErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	paginate "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const maxDigestClaims = 10
+
+var errTooManyClaims = errors.New("digest items are claimed too many times")
+
+var _ Repository = (*memRepository)(nil)
+
+// errNotImplemented is returned by the methods of memRepository that are not used by the tests.
+var errNotImplemented = errors.New("not implemented by memRepository")
+
+// memRepository is an in-memory Repository of the tests, the methods that are not implemented return errNotImplemented.
+type memRepository struct {
+	mu            sync.Mutex
+	userSettings  map[types.ID]UserSetting
+	digestItems   []memDigestItem
+	claims        int
+	now           time.Time
+	requeues      []time.Time
+	idempotency   map[string]types.ID
+	bindErr       error
+	deferred      map[int64]DeferredDelivery
+	deferErr      error
+	deferredSeq   int64
+	notifications map[types.ID]Notification
+	events        map[string]bool
+	eventErrs     map[types.ID]error
+}
+
+type memDigestItem struct {
+	item         DigestItem
+	digested     bool
+	deliverAfter time.Time
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{userSettings: make(map[types.ID]UserSetting), idempotency: make(map[string]types.ID),
+		deferred: make(map[int64]DeferredDelivery), notifications: make(map[types.ID]Notification),
+		events: make(map[string]bool), eventErrs: make(map[types.ID]error)}
+}
+
+func (m *memRepository) IsExistUserSetting(_ context.Context, userID types.ID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.userSettings[userID]
+
+	return ok, nil
+}
+
+func (m *memRepository) GetUserSetting(_ context.Context, userID types.ID) (UserSetting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.userSettings[userID], nil
+}
+
+// ClaimDueDigestItems claims all the pending items that are not held back at m.now, the digest windows are ignored.
+func (m *memRepository) ClaimDueDigestItems(_ context.Context, _, _ time.Time, _ int) ([]DigestItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.claims++
+	if m.claims > maxDigestClaims {
+		return nil, errTooManyClaims
+	}
+
+	var items []DigestItem
+	for i := range m.digestItems {
+		if m.digestItems[i].digested || m.digestItems[i].deliverAfter.After(m.now) {
+			continue
+		}
+
+		m.digestItems[i].digested = true
+		items = append(items, m.digestItems[i].item)
+	}
+
+	return items, nil
+}
+
+func (m *memRepository) RequeueDigestItems(_ context.Context, itemIDs []int64, deliverAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requeues = append(m.requeues, deliverAfter)
+	for _, id := range itemIDs {
+		for i := range m.digestItems {
+			if m.digestItems[i].item.ID == id {
+				m.digestItems[i].digested = false
+				m.digestItems[i].deliverAfter = deliverAfter
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *memRepository) ReleaseIdempotencyKey(_ context.Context, userID types.ID, key string, claimID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.idempotency[string(userID)+"/"+key] == claimID {
+		delete(m.idempotency, string(userID)+"/"+key)
+	}
+
+	return nil
+}
+
+func (m *memRepository) BindIdempotencyKey(_ context.Context, userID types.ID, key string, claimID, notificationID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.bindErr != nil {
+		return m.bindErr
+	}
+
+	if m.idempotency[string(userID)+"/"+key] == claimID {
+		m.idempotency[string(userID)+"/"+key] = notificationID
+	}
+
+	return nil
+}
+
+func (m *memRepository) SaveDeferredDelivery(_ context.Context, delivery DeferredDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.deferErr != nil {
+		return m.deferErr
+	}
+
+	m.deferredSeq++
+	delivery.ID = m.deferredSeq
+	m.deferred[delivery.ID] = delivery
+
+	return nil
+}
+
+// ClaimDueDeferredDeliveries claims all the due deliveries, the claims are not held by the fake.
+func (m *memRepository) ClaimDueDeferredDeliveries(_ context.Context, now, _ time.Time, _ int) ([]DeferredDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deliveries []DeferredDelivery
+	for _, delivery := range m.deferred {
+		if !delivery.DeliverAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (m *memRepository) DeleteDeferredDelivery(_ context.Context, deliveryID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.deferred, deliveryID)
+
+	return nil
+}
+
+func (m *memRepository) GetNotificationsByIDs(_ context.Context, notificationIDs []types.ID) ([]Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var notifications []Notification
+	for _, id := range notificationIDs {
+		if notification, ok := m.notifications[id]; ok {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return notifications, nil
+}
+
+// the methods below are not used by the tests.
+
+func (m *memRepository) Save(_ context.Context, _ SendNotificationRequest) (Notification, error) {
+	return Notification{}, errNotImplemented
+}
+
+func (m *memRepository) FindNotificationByUserID(_ context.Context, _ types.ID, _ NotificationFilter,
+	_ paginate.RequestBase) ([]Notification, paginate.ResponseBase, error) {
+	return nil, paginate.ResponseBase{}, errNotImplemented
+}
+
+func (m *memRepository) GetInAppNotificationsAfterSeq(_ context.Context, _ types.ID, _ int64, _ int) ([]Notification, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) GetUnreadCountByCategory(_ context.Context, _ types.ID, _ []NotificationType) (map[string]int, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) MarkAsRead(_ context.Context, _ types.ID, _ types.ID) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) MarkAllAsReadByUserID(_ context.Context, _ types.ID) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) Archive(_ context.Context, _ types.ID, _ types.ID, _ bool) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) SoftDelete(_ context.Context, _ types.ID, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) IsExistUserIDFromExternalUserID(_ context.Context, _ string) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) GetUserIDFromExternalUserID(_ context.Context, _ string) (types.ID, error) {
+	return "", errNotImplemented
+}
+
+func (m *memRepository) CreateUserIDFromExternalUserID(_ context.Context, _ string, _ types.ID) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) IsExistTemplateByName(_ context.Context, _ string) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) IsExistTemplateByID(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) CreateTemplate(_ context.Context, _ AddTemplateRequest) (Template, error) {
+	return Template{}, errNotImplemented
+}
+
+func (m *memRepository) UpdateTemplate(_ context.Context, _ types.ID, _ AddTemplateRequest) (int, error) {
+	return 0, errNotImplemented
+}
+
+func (m *memRepository) GetTemplateByName(_ context.Context, _ string) (Template, error) {
+	return Template{}, errNotImplemented
+}
+
+func (m *memRepository) GetTemplateByID(_ context.Context, _ types.ID) (Template, error) {
+	return Template{}, errNotImplemented
+}
+
+func (m *memRepository) GetTemplates(_ context.Context, _ ListTemplateRequest) (ListTemplateResponse, error) {
+	return ListTemplateResponse{}, errNotImplemented
+}
+
+func (m *memRepository) GetTemplatesByNames(_ context.Context, _ ...string) ([]Template, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) IsExistTemplateVersion(_ context.Context, _ types.ID, _ int) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) GetTemplateVersion(_ context.Context, _ types.ID, _ int) (TemplateVersion, error) {
+	return TemplateVersion{}, errNotImplemented
+}
+
+func (m *memRepository) GetTemplateVersions(_ context.Context, _ types.ID) ([]TemplateVersion, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) GetUserLanguages(_ context.Context) ([]UserLanguage, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) CreateUserSetting(_ context.Context, _ types.ID, _ UpdateUserSettingRequest) (UserSetting, error) {
+	return UserSetting{}, errNotImplemented
+}
+
+func (m *memRepository) UpdateUserSetting(_ context.Context, _ types.ID, _ UpdateUserSettingRequest) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) SaveTopicPreference(_ context.Context, _ types.ID, _ string, _ TopicPreference) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) IsExistNotificationByID(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) GetNotificationByID(_ context.Context, _ types.ID) (Notification, error) {
+	return Notification{}, errNotImplemented
+}
+
+func (m *memRepository) ClaimDueScheduledNotifications(_ context.Context, _ time.Time, _ int) ([]Notification, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) CancelScheduledNotification(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) RescheduleNotification(_ context.Context, _ types.ID, _ time.Time) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) GetScheduledNotifications(_ context.Context,
+	_ ListScheduledNotificationRequest) (ListScheduledNotificationResponse, error) {
+	return ListScheduledNotificationResponse{}, errNotImplemented
+}
+
+func (m *memRepository) CreateCampaign(_ context.Context, _ BroadcastRequest, _ []string) (Campaign, error) {
+	return Campaign{}, errNotImplemented
+}
+
+func (m *memRepository) IsExistCampaignByID(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) GetCampaignByID(_ context.Context, _ types.ID) (Campaign, error) {
+	return Campaign{}, errNotImplemented
+}
+
+func (m *memRepository) GetCampaigns(_ context.Context, _ ListCampaignRequest) (ListCampaignResponse, error) {
+	return ListCampaignResponse{}, errNotImplemented
+}
+
+func (m *memRepository) CancelCampaign(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) ClaimCampaignRecipients(_ context.Context, _ int) ([]CampaignRecipient, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) ReleaseStaleCampaignRecipients(_ context.Context, _ time.Time) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) SaveCampaignRecipientResults(_ context.Context, _ types.ID, _ []CampaignRecipientResult) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) CompleteFinishedCampaigns(_ context.Context) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) SaveDigestItems(_ context.Context, _ []DigestItem) error {
+	return errNotImplemented
+}
+
+// ApplyDeliveryEvent applies the events of the existing notifications, an event with a provider event id is applied once.
+func (m *memRepository) ApplyDeliveryEvent(_ context.Context, event DeliveryEvent) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if eErr := m.eventErrs[event.NotificationID]; eErr != nil {
+		return false, eErr
+	}
+
+	if _, ok := m.notifications[event.NotificationID]; !ok {
+		return false, nil
+	}
+
+	if event.ProviderEventID != "" {
+		key := event.Provider + "/" + event.ProviderEventID
+		if m.events[key] {
+			return false, nil
+		}
+		m.events[key] = true
+	}
+
+	return true, nil
+}
+
+func (m *memRepository) GetDeliveryEvents(_ context.Context, _ types.ID) ([]DeliveryEvent, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) IsExistTopicByName(_ context.Context, _ string) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) IsExistTopicByID(_ context.Context, _ types.ID) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (m *memRepository) CreateTopic(_ context.Context, _ AddTopicRequest) (Topic, error) {
+	return Topic{}, errNotImplemented
+}
+
+func (m *memRepository) UpdateTopic(_ context.Context, _ types.ID, _ AddTopicRequest) (Topic, error) {
+	return Topic{}, errNotImplemented
+}
+
+func (m *memRepository) GetTopicByID(_ context.Context, _ types.ID) (Topic, error) {
+	return Topic{}, errNotImplemented
+}
+
+func (m *memRepository) GetTopics(_ context.Context) ([]Topic, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) DeleteTopic(_ context.Context, _ types.ID) ([]string, error) {
+	return nil, errNotImplemented
+}
+
+func (m *memRepository) ClaimIdempotencyKey(_ context.Context, _ types.ID, _ string, _ types.ID,
+	_, _ time.Time) (types.ID, error) {
+	return "", errNotImplemented
+}
+
+func (m *memRepository) DeleteExpiredIdempotencyKeys(_ context.Context, _ time.Time) error {
+	return errNotImplemented
+}
+
+func (m *memRepository) GetLatestNotificationByContentHash(_ context.Context, _ types.ID, _ string,
+	_ time.Time) (Notification, bool, error) {
+	return Notification{}, false, errNotImplemented
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
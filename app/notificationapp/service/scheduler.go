@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const defaultSchedulerInterval = 10 * time.Second
+
+func (s Service) schedulerInterval() time.Duration {
+	if s.cfg.SchedulerInterval > 0 {
+		return s.cfg.SchedulerInterval
+	}
+
+	return defaultSchedulerInterval
+}
+
+// RunScheduler dispatches due scheduled notifications and deliveries deferred by quiet hours and removes the expired
+// idempotency keys every SchedulerInterval until ctx is canceled.
+// Due notifications are claimed with row level locks, so running several notification instances is safe.
+func (s Service) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.schedulerInterval())
+	defer ticker.Stop()
+
+	for {
+		if dErr := s.dispatchDueNotifications(ctx); dErr != nil {
+			errlog.WithoutErrContext(ctx, dErr, s.logger)
+		}
+
+		if dErr := s.dispatchDeferredDeliveries(ctx); dErr != nil {
+			errlog.WithoutErrContext(ctx, dErr, s.logger)
+		}
+
+		if dErr := s.deleteExpiredIdempotencyKeys(ctx); dErr != nil {
+			errlog.WithoutErrContext(ctx, dErr, s.logger)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			s.logger.Info("stopping notification scheduler")
+
+			return
+		}
+	}
+}
+
+func (s Service) dispatchDueNotifications(ctx context.Context) error {
+	const op = "service.scheduler.dispatchDueNotifications"
+
+	for {
+		notifications, cErr := s.repo.ClaimDueScheduledNotifications(ctx, time.Now(), s.cfg.SchedulerBatchSize)
+		if cErr != nil {
+			return richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+		}
+
+		for _, notification := range notifications {
+			s.logger.DebugContext(ctx, "scheduled notification dispatched",
+				slog.String("notification_id", string(notification.ID)))
+
+			if qErr := s.queueDigestItems(ctx, notification); qErr != nil {
+				errlog.WithoutErrContext(ctx, qErr, s.logger)
+			}
+
+			if notification.IsInApp {
+				go s.publishNotification(s.cfg.PublishTimeout, notification) //nolint:contextcheck // This function run asynchronously
+			}
+		}
+
+		if len(notifications) == 0 || len(notifications) < s.cfg.SchedulerBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s Service) CancelScheduledNotification(ctx context.Context, notificationID types.ID) error {
+	const op = "service.scheduler.CancelScheduledNotification"
+
+	if eErr := s.checkNotificationExists(ctx, notificationID, op); eErr != nil {
+		return eErr
+	}
+
+	canceled, cErr := s.repo.CancelScheduledNotification(ctx, notificationID)
+	if cErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !canceled {
+		return richerror.New(op).WithMessage(servermsg.MsgNotificationIsNotScheduled).WithKind(richerror.KindConflict)
+	}
+
+	return nil
+}
+
+// RescheduleNotification changes the send time of a scheduled notification, a canceled notification
+// is scheduled again.
+func (s Service) RescheduleNotification(ctx context.Context, notificationID types.ID,
+	req RescheduleNotificationRequest) (Notification, error) {
+	const op = "service.scheduler.RescheduleNotification"
+
+	if vErr := s.vld.ValidateRescheduleNotificationRequest(req); vErr != nil {
+		return Notification{}, vErr
+	}
+
+	if eErr := s.checkNotificationExists(ctx, notificationID, op); eErr != nil {
+		return Notification{}, eErr
+	}
+
+	rescheduled, rErr := s.repo.RescheduleNotification(ctx, notificationID, req.SendAt)
+	if rErr != nil {
+		return Notification{}, errlog.ErrLog(richerror.New(op).WithWrapError(rErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !rescheduled {
+		return Notification{}, richerror.New(op).WithMessage(servermsg.MsgNotificationIsNotScheduled).
+			WithKind(richerror.KindConflict)
+	}
+
+	notification, gErr := s.repo.GetNotificationByID(ctx, notificationID)
+	if gErr != nil {
+		return Notification{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return notification, nil
+}
+
+func (s Service) ListScheduledNotifications(ctx context.Context,
+	req ListScheduledNotificationRequest) (ListScheduledNotificationResponse, error) {
+	const op = "service.scheduler.ListScheduledNotifications"
+
+	if vErr := s.vld.ValidateListScheduledNotificationRequest(req); vErr != nil {
+		return ListScheduledNotificationResponse{}, vErr
+	}
+
+	if bErr := req.Paginated.BasicValidation(); bErr != nil {
+		return ListScheduledNotificationResponse{}, richerror.New(op).WithKind(richerror.KindBadRequest)
+	}
+
+	resp, gErr := s.repo.GetScheduledNotifications(ctx, req)
+	if gErr != nil {
+		return ListScheduledNotificationResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return resp, nil
+}
+
+func (s Service) checkNotificationExists(ctx context.Context, notificationID types.ID, op string) error {
+	exists, eErr := s.repo.IsExistNotificationByID(ctx, notificationID)
+	if eErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return richerror.New(op).WithMessage(servermsg.MsgNotificationNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	return nil
+}
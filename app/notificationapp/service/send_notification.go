@@ -20,13 +20,8 @@ func (s Service) SendNotification(ctx context.Context, req SendNotificationReque
 		return Notification{}, vErr
 	}
 
-	exists, eErr := s.repo.IsExistTemplateByName(ctx, req.TemplateName)
-	if eErr != nil {
-		return Notification{}, errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
-	}
-
-	if !exists {
-		return Notification{}, richerror.New(op).WithMessage(servermsg.MsgTemplateNotFound).WithKind(richerror.KindNotFound)
+	if cErr := s.checkSendableTemplate(ctx, req.TemplateName); cErr != nil {
+		return Notification{}, cErr
 	}
 
 	userID, gErr := s.getUserIDFromExternalUserID(ctx, req.ExternalUserID)
@@ -35,27 +30,143 @@ func (s Service) SendNotification(ctx context.Context, req SendNotificationReque
 			WithKind(richerror.KindUnexpected), s.logger)
 	}
 	req.UserID = userID
-
 	req.ID = types.ID(ulid.Make().String())
 
+	contentHash, hErr := notificationContentHash(req)
+	if hErr != nil {
+		return Notification{}, errlog.ErrLog(richerror.New(op).WithWrapError(hErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+	req.ContentHash = contentHash
+
+	if req.IdempotencyKey != "" {
+		original, found, cErr := s.claimIdempotencyKey(ctx, req)
+		if cErr != nil {
+			return Notification{}, cErr
+		}
+
+		if found {
+			return original, nil
+		}
+	}
+
+	duplicate, found, dErr := s.findDuplicateNotification(ctx, req)
+	if dErr != nil {
+		s.releaseIdempotencyKey(ctx, req)
+
+		return Notification{}, dErr
+	}
+
+	if found {
+		s.bindIdempotencyKey(ctx, req, duplicate.ID)
+
+		return duplicate, nil
+	}
+
+	notification, cErr := s.createNotification(ctx, req)
+	if cErr != nil {
+		s.releaseIdempotencyKey(ctx, req)
+
+		return Notification{}, errlog.ErrLog(richerror.New(op).
+			WithMessage("can't save notification").WithWrapError(cErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return notification, nil
+}
+
+// SendBulkNotification sends at most SendBulkMaxSize notifications, errors of the notifications are returned
+// in their results.
+func (s Service) SendBulkNotification(ctx context.Context, req SendBulkNotificationRequest) (SendBulkNotificationResponse, error) {
+	const op = "service.send_notification.SendBulkNotification"
+
+	if len(req.Notifications) == 0 || len(req.Notifications) > s.cfg.SendBulkMaxSize {
+		return SendBulkNotificationResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidSendBulkSize).
+			WithKind(richerror.KindInvalid).WithMeta(map[string]interface{}{"count": len(req.Notifications)})
+	}
+
+	resp := SendBulkNotificationResponse{Results: make([]SendBulkNotificationResult, 0, len(req.Notifications))}
+	for index, notificationReq := range req.Notifications {
+		result := SendBulkNotificationResult{Index: index}
+
+		notification, sErr := s.SendNotification(ctx, notificationReq)
+		if sErr != nil {
+			result.Err = sErr
+			resp.FailedCount++
+		} else {
+			result.Notification = &notification
+			resp.SentCount++
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// createNotification saves a validated notification for an already resolved user and publishes it
+// to in-app clients unless it is scheduled for later.
+func (s Service) createNotification(ctx context.Context, req SendNotificationRequest) (Notification, error) {
+	if req.ID == "" {
+		req.ID = types.ID(ulid.Make().String())
+	}
+
+	if req.ContentHash == "" {
+		contentHash, hErr := notificationContentHash(req)
+		if hErr != nil {
+			return Notification{}, hErr
+		}
+		req.ContentHash = contentHash
+	}
+
 	for _, channel := range req.ChannelDeliveries {
 		if channel.Channel == ChannelTypeInApp {
 			req.IsInApp = true
 		}
 	}
 
-	req.Status = DeliveryStatusPending
+	req.Status = OverallStatusPending
 	if len(req.ChannelDeliveries) == 1 {
 		if req.ChannelDeliveries[0].Channel == ChannelTypeInApp {
-			req.Status = DeliveryStatusSent
+			req.Status = OverallStatusSent
+		}
+	}
+
+	templates, tErr := s.getTemplates(ctx, []string{req.TemplateName})
+	if tErr != nil {
+		return Notification{}, tErr
+	}
+
+	req.Category = string(req.Type)
+	if template, ok := templates[req.TemplateName]; ok {
+		if template.Version > 0 {
+			req.TemplateVersion = &template.Version
 		}
+
+		if template.Category != "" {
+			req.Category = template.Category
+		}
+
+		req.Topic = template.Topic
+	}
+
+	if req.SendAt != nil && req.SendAt.After(time.Now()) {
+		req.Status = OverallStatusScheduled
+	} else {
+		req.SendAt = nil
 	}
 
 	notification, sErr := s.repo.Save(ctx, req)
 	if sErr != nil {
-		return Notification{}, errlog.ErrLog(richerror.New(op).
-			WithMessage("can't save notification").WithWrapError(sErr).
-			WithKind(richerror.KindUnexpected), s.logger)
+		return Notification{}, sErr
+	}
+
+	if notification.OverallStatus == OverallStatusScheduled {
+		return notification, nil
+	}
+
+	if qErr := s.queueDigestItems(ctx, notification); qErr != nil {
+		errlog.WithoutErrContext(ctx, qErr, s.logger)
 	}
 
 	for _, ch := range notification.ChannelDeliveries {
@@ -68,11 +179,19 @@ func (s Service) SendNotification(ctx context.Context, req SendNotificationReque
 }
 
 func (s Service) publishNotification(ctxTimeout time.Duration, notification Notification) {
-	const op = "service.send_notification.publishNotification"
-
 	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
 	defer cancel()
 
+	if dErr := s.deliverInApp(ctx, notification); dErr != nil {
+		errlog.WithoutErr(dErr, s.logger)
+	}
+}
+
+// deliverInApp publishes the in-app notification, or defers it until the quiet hours of the user end.
+// A notification that the user doesn't accept on the channel is not an error.
+func (s Service) deliverInApp(ctx context.Context, notification Notification) error {
+	const op = "service.send_notification.deliverInApp"
+
 	userSetting, usErr := s.GetUserSetting(ctx, string(notification.UserID))
 	if usErr != nil {
 		errlog.WithoutErr(richerror.New(op).WithWrapError(usErr).WithMessage(fmt.Sprintf("can't get user setting for user id: %s",
@@ -80,38 +199,51 @@ func (s Service) publishNotification(ctxTimeout time.Duration, notification Noti
 	}
 
 	if !s.CheckNotificationAccessToSend(notification, userSetting, ChannelTypeInApp) {
-		return
+		return nil
 	}
+
+	if deliverAt, quiet := s.GetQuietHoursEnd(notification.Type, userSetting, ChannelTypeInApp, time.Now()); quiet {
+		if dErr := s.deferDelivery(ctx, notification, ChannelTypeInApp, deliverAt); dErr != nil {
+			return richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return nil
+	}
+
 	notificationMsgs, rErr := s.RenderNotificationTemplates(ctx, ChannelTypeInApp, userSetting.Lang, notification)
 	if rErr != nil {
-		errlog.WithoutErr(richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected).
-			WithMessage("can't render notification"), s.logger)
-
-		return
+		return richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected).WithMessage("can't render notification")
 	}
 
 	jsonData, mErr := json.Marshal(notificationMsgs[0])
 	if mErr != nil {
-		errlog.WithoutErr(richerror.New(op).WithMessage("can't marshalling notification message").WithWrapError(mErr).
-			WithKind(richerror.KindUnexpected), s.logger)
-
-		return
+		return richerror.New(op).WithMessage("can't marshalling notification message").WithWrapError(mErr).
+			WithKind(richerror.KindUnexpected)
 	}
 
 	if pErr := s.publisher.Publish(ctx, s.cfg.ChannelName, jsonData); pErr != nil {
-		errlog.WithoutErr(richerror.New(op).WithMessage("can't publish notification message").
-			WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
-
-		return
+		return richerror.New(op).WithMessage("can't publish notification message").WithWrapError(pErr).
+			WithKind(richerror.KindUnexpected)
 	}
+
+	s.publishUnreadCount(ctx, notification.UserID, userSetting)
+
+	return nil
 }
 
-// CheckNotificationAccessToSend if notification type is critical, notification send and doesn't check user ignore channels.
+// CheckNotificationAccessToSend if notification type is critical, notification send and doesn't check user preferences.
+// The topic preference of the user on the channel is checked before the ignore channels of the notification type.
 func (s Service) CheckNotificationAccessToSend(notification Notification, userSetting UserSetting, channel ChannelType) bool {
 	if notification.Type == NotificationTypeCritical {
 		return true
 	}
 
+	if notification.Topic != "" {
+		if preference, ok := findTopicPreference(userSetting.TopicPreferences, notification.Topic, channel); ok {
+			return preference.Subscribed
+		}
+	}
+
 	for _, ignore := range userSetting.IgnoreChannels {
 		if ignore.Channel == channel {
 			for _, notificationType := range ignore.NotificationTypes {
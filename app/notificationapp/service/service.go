@@ -3,66 +3,143 @@ package service
 import (
 	"context"
 	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
 	paginate "github.com/syntaxfa/quick-connect/pkg/paginate/limitoffset"
 	"github.com/syntaxfa/quick-connect/pkg/pubsub"
+	"github.com/syntaxfa/quick-connect/pkg/tokenmanager"
+	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
 	"github.com/syntaxfa/quick-connect/types"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
 )
 
+const httpClientTimeout = time.Second * 30
+
 type Repository interface {
 	Save(ctx context.Context, req SendNotificationRequest) (Notification, error)
-	FindNotificationByUserID(ctx context.Context, userID types.ID, paginated paginate.RequestBase, isRead *bool,
-		isInApp *bool) ([]Notification, paginate.ResponseBase, error)
+	FindNotificationByUserID(ctx context.Context, userID types.ID, filter NotificationFilter,
+		paginated paginate.RequestBase) ([]Notification, paginate.ResponseBase, error)
+	GetInAppNotificationsAfterSeq(ctx context.Context, userID types.ID, seq int64, limit int) ([]Notification, error)
+	GetUnreadCountByCategory(ctx context.Context, userID types.ID, excludeTypes []NotificationType) (map[string]int, error)
 	MarkAsRead(ctx context.Context, notificationID, userID types.ID) error
 	MarkAllAsReadByUserID(ctx context.Context, userID types.ID) error
+	Archive(ctx context.Context, notificationID, userID types.ID, archive bool) (bool, error)
+	SoftDelete(ctx context.Context, notificationID, userID types.ID) (bool, error)
 	IsExistUserIDFromExternalUserID(ctx context.Context, externalUserID string) (bool, error)
 	GetUserIDFromExternalUserID(ctx context.Context, externalUserID string) (types.ID, error)
 	CreateUserIDFromExternalUserID(ctx context.Context, externalUserID string, userID types.ID) error
 	IsExistTemplateByName(ctx context.Context, name string) (bool, error)
 	IsExistTemplateByID(ctx context.Context, id types.ID) (bool, error)
 	CreateTemplate(ctx context.Context, req AddTemplateRequest) (Template, error)
-	UpdateTemplate(ctx context.Context, id types.ID, req AddTemplateRequest) error
+	UpdateTemplate(ctx context.Context, id types.ID, req AddTemplateRequest) (int, error)
 	GetTemplateByName(ctx context.Context, name string) (Template, error)
 	GetTemplateByID(ctx context.Context, id types.ID) (Template, error)
 	GetTemplates(ctx context.Context, req ListTemplateRequest) (ListTemplateResponse, error)
 	GetTemplatesByNames(ctx context.Context, names ...string) ([]Template, error)
+	IsExistTemplateVersion(ctx context.Context, templateID types.ID, version int) (bool, error)
+	GetTemplateVersion(ctx context.Context, templateID types.ID, version int) (TemplateVersion, error)
+	GetTemplateVersions(ctx context.Context, templateID types.ID) ([]TemplateVersion, error)
 	IsExistUserSetting(ctx context.Context, userID types.ID) (bool, error)
 	GetUserSetting(ctx context.Context, userID types.ID) (UserSetting, error)
+	GetUserLanguages(ctx context.Context) ([]UserLanguage, error)
 	CreateUserSetting(ctx context.Context, userID types.ID, req UpdateUserSettingRequest) (UserSetting, error)
 	UpdateUserSetting(ctx context.Context, userID types.ID, req UpdateUserSettingRequest) error
+	SaveTopicPreference(ctx context.Context, userID types.ID, lang string, preference TopicPreference) error
+	IsExistNotificationByID(ctx context.Context, notificationID types.ID) (bool, error)
+	GetNotificationByID(ctx context.Context, notificationID types.ID) (Notification, error)
+	ClaimDueScheduledNotifications(ctx context.Context, now time.Time, limit int) ([]Notification, error)
+	CancelScheduledNotification(ctx context.Context, notificationID types.ID) (bool, error)
+	RescheduleNotification(ctx context.Context, notificationID types.ID, sendAt time.Time) (bool, error)
+	GetScheduledNotifications(ctx context.Context, req ListScheduledNotificationRequest) (ListScheduledNotificationResponse, error)
+	CreateCampaign(ctx context.Context, req BroadcastRequest, externalUserIDs []string) (Campaign, error)
+	IsExistCampaignByID(ctx context.Context, campaignID types.ID) (bool, error)
+	GetCampaignByID(ctx context.Context, campaignID types.ID) (Campaign, error)
+	GetCampaigns(ctx context.Context, req ListCampaignRequest) (ListCampaignResponse, error)
+	CancelCampaign(ctx context.Context, campaignID types.ID) (bool, error)
+	ClaimCampaignRecipients(ctx context.Context, limit int) ([]CampaignRecipient, error)
+	ReleaseStaleCampaignRecipients(ctx context.Context, lockedBefore time.Time) error
+	SaveCampaignRecipientResults(ctx context.Context, campaignID types.ID, results []CampaignRecipientResult) error
+	CompleteFinishedCampaigns(ctx context.Context) error
+	GetNotificationsByIDs(ctx context.Context, notificationIDs []types.ID) ([]Notification, error)
+	SaveDigestItems(ctx context.Context, items []DigestItem) error
+	ClaimDueDigestItems(ctx context.Context, hourlyBefore, dailyBefore time.Time, limit int) ([]DigestItem, error)
+	RequeueDigestItems(ctx context.Context, itemIDs []int64, deliverAfter time.Time) error
+	SaveDeferredDelivery(ctx context.Context, delivery DeferredDelivery) error
+	ClaimDueDeferredDeliveries(ctx context.Context, now, claimedUntil time.Time, limit int) ([]DeferredDelivery, error)
+	DeleteDeferredDelivery(ctx context.Context, deliveryID int64) error
+	ApplyDeliveryEvent(ctx context.Context, event DeliveryEvent) (bool, error)
+	GetDeliveryEvents(ctx context.Context, notificationID types.ID) ([]DeliveryEvent, error)
+	IsExistTopicByName(ctx context.Context, name string) (bool, error)
+	IsExistTopicByID(ctx context.Context, id types.ID) (bool, error)
+	CreateTopic(ctx context.Context, req AddTopicRequest) (Topic, error)
+	UpdateTopic(ctx context.Context, id types.ID, req AddTopicRequest) (Topic, error)
+	GetTopicByID(ctx context.Context, id types.ID) (Topic, error)
+	GetTopics(ctx context.Context) ([]Topic, error)
+	DeleteTopic(ctx context.Context, id types.ID) ([]string, error)
+	ClaimIdempotencyKey(ctx context.Context, userID types.ID, key string, notificationID types.ID,
+		expiresAt, staleBefore time.Time) (types.ID, error)
+	ReleaseIdempotencyKey(ctx context.Context, userID types.ID, key string, claimID types.ID) error
+	BindIdempotencyKey(ctx context.Context, userID types.ID, key string, claimID, notificationID types.ID) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) error
+	GetLatestNotificationByContentHash(ctx context.Context, userID types.ID, contentHash string,
+		since time.Time) (Notification, bool, error)
+}
+
+type StorageService interface {
+	GetLink(ctx context.Context, req *storagepb.GetLinkRequest, opts ...grpc.CallOption) (*storagepb.GetLinkResponse, error)
+	GetFileInfo(ctx context.Context, req *storagepb.GetFileInfoRequest, opts ...grpc.CallOption) (*storagepb.File, error)
+	ConfirmFile(ctx context.Context, req *storagepb.ConfirmFileRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type Service struct {
-	cfg       Config
-	vld       Validate
-	cache     *cachemanager.CacheManager
-	repo      Repository
-	logger    *slog.Logger
-	hub       *Hub
-	publisher pubsub.Publisher
-	renderSvc *RenderService
+	cfg          Config
+	vld          Validate
+	cache        *cachemanager.CacheManager
+	repo         Repository
+	logger       *slog.Logger
+	hub          *Hub
+	publisher    pubsub.Publisher
+	renderSvc    *RenderService
+	storageSvc   StorageService
+	tokenManager *tokenmanager.TokenManager
+	httpClient   *http.Client
+	cacheMetrics templateCacheMetrics
 }
 
 func New(cfg Config, vld Validate, cache *cachemanager.CacheManager, repo Repository, logger *slog.Logger, hub *Hub,
-	publisher pubsub.Publisher) Service {
+	publisher pubsub.Publisher, storageSvc StorageService, tokenManager *tokenmanager.TokenManager) Service {
 	go hub.Run(context.Background())
 
 	return Service{
-		cfg:       cfg,
-		vld:       vld,
-		cache:     cache,
-		repo:      repo,
-		logger:    logger,
-		hub:       hub,
-		publisher: publisher,
-		renderSvc: NewRenderService(cfg.DefaultUserLanguage),
+		cfg:          cfg,
+		vld:          vld,
+		cache:        cache,
+		repo:         repo,
+		logger:       logger,
+		hub:          hub,
+		publisher:    publisher,
+		renderSvc:    NewRenderService(cfg.DefaultUserLanguage),
+		storageSvc:   storageSvc,
+		tokenManager: tokenManager,
+		httpClient:   &http.Client{Timeout: httpClientTimeout},
+		cacheMetrics: newTemplateCacheMetrics(),
 	}
 }
 
-func (s Service) JoinClient(ctx context.Context, conn Connection, externalUserID string) {
+// JoinClient registers the websocket connection and reads the client messages until the connection is closed,
+// the missed notifications are replayed when lastSeq is set.
+func (s Service) JoinClient(ctx context.Context, conn Connection, externalUserID string, lastSeq *int64) {
 	client := s.NewClient(ctx, conn, externalUserID)
 
 	s.hub.register <- client
 	go client.WritePump()
+
+	if lastSeq != nil && client.userID != "" {
+		go s.replayNotifications(ctx, client, *lastSeq)
+	}
+
+	client.ReadPump(ctx, s.HandleClientMessage)
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/oklog/ulid/v2"
@@ -30,7 +31,18 @@ func (s Service) AddTemplate(ctx context.Context, req AddTemplateRequest) (Templ
 			WithKind(richerror.KindConflict)
 	}
 
+	if cErr := s.checkTemplateLayouts(ctx, req.Contents); cErr != nil {
+		return Template{}, cErr
+	}
+
+	if cErr := s.checkTemplateTopic(ctx, req.Topic); cErr != nil {
+		return Template{}, cErr
+	}
+
 	req.ID = types.ID(ulid.Make().String())
+	if req.Kind == "" {
+		req.Kind = TemplateKindNotification
+	}
 
 	template, cErr := s.repo.CreateTemplate(ctx, req)
 	if cErr != nil {
@@ -63,6 +75,12 @@ func (s Service) UpdateTemplate(ctx context.Context, templateID types.ID, req Ad
 			WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	// the notifications and the layouts of the template would be rendered by a template of another kind.
+	if req.Kind != "" && req.Kind != template.Kind {
+		return Template{}, richerror.New(op).WithMessage(servermsg.MsgTemplateKindCanNotChange).
+			WithKind(richerror.KindBadRequest)
+	}
+
 	if template.Name != req.Name {
 		exists, eErr = s.repo.IsExistTemplateByName(ctx, req.Name)
 		if eErr != nil {
@@ -76,13 +94,25 @@ func (s Service) UpdateTemplate(ctx context.Context, templateID types.ID, req Ad
 		}
 	}
 
-	uErr := s.repo.UpdateTemplate(ctx, template.ID, req)
+	if cErr := s.checkTemplateLayouts(ctx, req.Contents); cErr != nil {
+		return Template{}, cErr
+	}
+
+	if cErr := s.checkTemplateTopic(ctx, req.Topic); cErr != nil {
+		return Template{}, cErr
+	}
+
+	version, uErr := s.repo.UpdateTemplate(ctx, template.ID, req)
 	if uErr != nil {
 		return Template{}, errlog.ErrLog(richerror.New(op).WithWrapError(uErr).
 			WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	s.invalidateTemplateCache(ctx, template.Name, req.Name)
+
 	template.Name = req.Name
+	template.Topic = req.Topic
+	template.Version = version
 	template.Contents = req.Contents
 
 	return template, nil
@@ -110,6 +140,45 @@ func (s Service) GetTemplate(ctx context.Context, templateID types.ID) (Template
 	return template, nil
 }
 
+// TemplateLanguageWarnings returns the languages of the users that a content of the template doesn't have,
+// the users of these languages receive the content in the fallback language.
+func (s Service) TemplateLanguageWarnings(ctx context.Context, templateID types.ID) (TemplateLanguageWarningsResponse, error) {
+	const op = "service.template.TemplateLanguageWarnings"
+
+	template, gErr := s.GetTemplate(ctx, templateID)
+	if gErr != nil {
+		return TemplateLanguageWarningsResponse{}, richerror.New(op).WithWrapError(gErr)
+	}
+
+	userLanguages, lErr := s.repo.GetUserLanguages(ctx)
+	if lErr != nil {
+		return TemplateLanguageWarningsResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(lErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	warnings := make([]TemplateLanguageWarning, 0)
+	for _, content := range template.Contents {
+		for _, userLanguage := range userLanguages {
+			if _, ok := findFallbackLanguage(content.Bodies, userLanguage.Lang); ok {
+				continue
+			}
+
+			warnings = append(warnings, TemplateLanguageWarning{
+				Channel:      content.Channel,
+				Lang:         userLanguage.Lang,
+				FallbackLang: s.renderSvc.selectLanguage(content.Bodies, userLanguage.Lang),
+				Users:        userLanguage.Users,
+			})
+		}
+	}
+
+	return TemplateLanguageWarningsResponse{
+		TemplateID: template.ID,
+		Name:       template.Name,
+		Warnings:   warnings,
+	}, nil
+}
+
 func (s Service) TemplateList(ctx context.Context, req ListTemplateRequest) (ListTemplateResponse, error) {
 	const op = "service.template.TemplateList"
 
@@ -150,6 +219,9 @@ func (s Service) getTemplates(ctx context.Context, names []string) (map[string]T
 		return nil, richerror.New(op).WithWrapError(mgErr).WithKind(richerror.KindUnexpected)
 	}
 
+	s.cacheMetrics.addHits(ctx, templateCacheLayerCacheManager, len(cacheKeys)-len(missedKeys))
+	s.cacheMetrics.addMisses(ctx, templateCacheLayerCacheManager, len(missedKeys))
+
 	if len(missedKeys) > 0 {
 		missedNames := make([]string, len(missedKeys))
 		for i, key := range missedKeys {
@@ -197,25 +269,171 @@ func (s Service) RenderNotificationTemplates(ctx context.Context, channel Channe
 		return nil, errlog.ErrLog(richerror.New(op).WithWrapError(tErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	renderTemplates := make([]Template, len(notifications))
+	for i, n := range notifications {
+		template := templates[n.TemplateName]
+		if n.TemplateVersion != nil && template.ID != "" && *n.TemplateVersion != template.Version {
+			versioned, vErr := s.getTemplateInVersion(ctx, template, *n.TemplateVersion)
+			if vErr != nil {
+				return nil, errlog.ErrLog(richerror.New(op).WithWrapError(vErr).WithKind(richerror.KindUnexpected), s.logger)
+			}
+
+			template = versioned
+		}
+
+		renderTemplates[i] = template
+	}
+
+	includes, iErr := s.getTemplateIncludes(ctx, renderTemplates...)
+	if iErr != nil {
+		return nil, errlog.ErrLog(richerror.New(op).WithWrapError(iErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
 	notificationMessages := make([]NotificationMessage, 0)
-	for _, n := range notifications {
-		res, rErr := s.renderSvc.RenderTemplate(templates[n.TemplateName], channel, lang, n.DynamicTitleData, n.DynamicBodyData)
+	for i, n := range notifications {
+		timestamp := n.CreatedAt
+		if n.SendAt != nil {
+			timestamp = *n.SendAt
+		}
+
+		res, rErr := s.renderSvc.RenderTemplate(renderTemplates[i], channel, lang, n.DynamicTitleData, n.DynamicBodyData,
+			includes...)
 		if rErr != nil {
 			return nil, errlog.ErrLog(richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected).
 				WithMessage(fmt.Sprintf("can't render notification %s", n.ID)), s.logger)
 		}
 
+		// the missing language is logged on every render, so it is only a debug log, the fallback is still sent.
+		if lang != "" && !isLanguageInFallbacks(languageFallbacks(lang), res.Lang) {
+			s.logger.DebugContext(ctx, "template does not have the language of the user", slog.String("template", res.Name),
+				slog.String("channel", string(channel)), slog.String("lang", lang), slog.String("fallback_lang", res.Lang))
+		}
+
+		var unsubscribeURL string
+		if channel == ChannelTypeEmail {
+			res.Body = s.instrumentEmailBody(n.ID, res.Body)
+
+			// critical notifications are always sent, so they don't have an unsubscribe link.
+			if n.Type != NotificationTypeCritical {
+				unsubscribeURL = s.unsubscribeURL(n.UserID, n.Topic, channel)
+			}
+
+			if unsubscribeURL != "" {
+				res.Body = addUnsubscribeLink(res.Body, unsubscribeURL)
+			}
+		}
+
 		notificationMessages = append(notificationMessages, NotificationMessage{
-			ID:        n.ID,
-			UserID:    n.UserID,
-			Type:      n.Type,
-			Data:      n.Data,
-			Title:     res.Title,
-			Body:      res.Body,
-			IsRead:    n.IsRead,
-			Timestamp: n.CreatedAt.Unix(),
+			ID:             n.ID,
+			UserID:         n.UserID,
+			Type:           n.Type,
+			Category:       n.Category,
+			Data:           n.Data,
+			Dir:            res.Dir,
+			Title:          res.Title,
+			Body:           res.Body,
+			IsRead:         n.IsRead,
+			IsArchived:     n.IsArchived,
+			Timestamp:      timestamp.Unix(),
+			Seq:            n.Seq,
+			UnsubscribeURL: unsubscribeURL,
 		})
 	}
 
 	return notificationMessages, nil
 }
+
+// maxTemplateIncludeDepth limits the partials that include other partials.
+const maxTemplateIncludeDepth = 5
+
+// getTemplateIncludes returns the layouts and partials of the templates, and the partials used by them.
+// Includes are shared by the templates, so they are always used in their current version.
+func (s Service) getTemplateIncludes(ctx context.Context, templates ...Template) ([]Template, error) {
+	const op = "service.template.getTemplateIncludes"
+
+	includes := make([]Template, 0)
+	seen := make(map[string]bool)
+	for _, template := range templates {
+		seen[template.Name] = true
+	}
+
+	pending := templates
+	for depth := 0; depth < maxTemplateIncludeDepth && len(pending) > 0; depth++ {
+		names := make([]string, 0)
+		for _, template := range pending {
+			references, iErr := s.renderSvc.Includes(template)
+			if iErr != nil {
+				return nil, richerror.New(op).WithWrapError(iErr).WithKind(richerror.KindUnexpected)
+			}
+
+			for _, name := range references {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+
+		found, gErr := s.getTemplates(ctx, names)
+		if gErr != nil {
+			return nil, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		pending = make([]Template, 0, len(found))
+		for _, template := range found {
+			if template.Kind == TemplateKindLayout || template.Kind == TemplateKindPartial {
+				includes = append(includes, template)
+				pending = append(pending, template)
+			}
+		}
+	}
+
+	return includes, nil
+}
+
+// checkSendableTemplate layouts and partials are only used by the other templates, they can't be sent.
+func (s Service) checkSendableTemplate(ctx context.Context, name string) error {
+	const op = "service.template.checkSendableTemplate"
+
+	templates, gErr := s.getTemplates(ctx, []string{name})
+	if gErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	template, ok := templates[name]
+	if !ok {
+		return richerror.New(op).WithMessage(servermsg.MsgTemplateNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	if template.Kind == TemplateKindLayout || template.Kind == TemplateKindPartial {
+		return richerror.New(op).WithMessage(servermsg.MsgTemplateIsNotSendable).WithKind(richerror.KindBadRequest)
+	}
+
+	return nil
+}
+
+// checkTemplateLayouts the layouts of the contents must be layout templates.
+func (s Service) checkTemplateLayouts(ctx context.Context, contents []TemplateContent) error {
+	const op = "service.template.checkTemplateLayouts"
+
+	names := make([]string, 0)
+	for _, content := range contents {
+		if content.Layout != "" {
+			names = append(names, content.Layout)
+		}
+	}
+
+	layouts, gErr := s.getTemplates(ctx, names)
+	if gErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	for _, name := range names {
+		if layout, ok := layouts[name]; !ok || layout.Kind != TemplateKindLayout {
+			return richerror.New(op).WithMessage(servermsg.MsgTemplateLayoutNotFound).WithKind(richerror.KindBadRequest).
+				WithMeta(map[string]interface{}{"layout": name})
+		}
+	}
+
+	return nil
+}
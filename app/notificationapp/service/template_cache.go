@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/pubsub"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+// TemplateChangedEvent is broadcast to every notification instance when templates are updated or rolled back.
+type TemplateChangedEvent struct {
+	Names []string `json:"names"`
+}
+
+// invalidateTemplateCache evicts the templates on this instance and broadcasts a TemplateChangedEvent,
+// so the other instances evict them too.
+func (s Service) invalidateTemplateCache(ctx context.Context, names ...string) {
+	const op = "service.template_cache.invalidateTemplateCache"
+
+	s.evictTemplates(ctx, names...)
+
+	jsonData, mErr := json.Marshal(TemplateChangedEvent{Names: names})
+	if mErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithMessage("can't marshal template changed event").WithWrapError(mErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	if pErr := s.publisher.Publish(ctx, s.cfg.TemplateChannelName, jsonData); pErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithMessage("can't publish template changed event").WithWrapError(pErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+}
+
+// RunTemplateCacheInvalidator evicts the cached templates of this instance whenever a TemplateChangedEvent
+// is received, it stops when ctx is canceled.
+func (s Service) RunTemplateCacheInvalidator(ctx context.Context, subscriber pubsub.Subscriber) {
+	const op = "service.template_cache.RunTemplateCacheInvalidator"
+
+	receiver := subscriber.Subscribe(ctx, s.cfg.TemplateChannelName)
+
+	for {
+		message, rErr := receiver.ReceiveMessage(ctx)
+		if rErr != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithMessage("can't receive message").WithWrapError(rErr).
+				WithKind(richerror.KindUnexpected), s.logger)
+
+			continue
+		}
+
+		var event TemplateChangedEvent
+		if uErr := json.Unmarshal(message, &event); uErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithMessage("can't unmarshal template changed event").
+				WithWrapError(uErr).WithKind(richerror.KindUnexpected), s.logger)
+
+			continue
+		}
+
+		s.evictTemplates(ctx, event.Names...)
+	}
+}
+
+// evictTemplates removes the cachemanager entries and the compiled executors of the templates.
+func (s Service) evictTemplates(ctx context.Context, names ...string) {
+	const op = "service.template_cache.evictTemplates"
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, "template:"+name)
+
+		s.renderSvc.Evict(name)
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	if dErr := s.cache.Delete(ctx, keys...); dErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		return
+	}
+
+	s.cacheMetrics.addEvictions(ctx, templateCacheLayerCacheManager, len(keys))
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templateFuncs returns the helpers of the templates. They are bound to the language of the rendered content,
+// so dates, numbers and currencies are formatted for it. Helpers only format their arguments, they don't have
+// access to anything else.
+func templateFuncs(lang string) map[string]any {
+	l := newTemplateLocale(lang)
+
+	return map[string]any{
+		"formatDate":     l.formatDate,
+		"formatNumber":   l.formatNumber,
+		"formatCurrency": l.formatCurrency,
+		"digits":         l.digits,
+		"plural":         plural,
+		"default":        defaultValue,
+		"url":            buildURL,
+		"withQuery":      withQuery,
+		"upper":          strings.ToUpper,
+		"lower":          strings.ToLower,
+		"trim":           strings.TrimSpace,
+		"dir":            func() string { return string(LanguageDirection(lang)) },
+	}
+}
+
+const defaultDateLayout = "2006-01-02"
+
+type templateLocale struct {
+	persian bool
+}
+
+func newTemplateLocale(lang string) templateLocale {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+
+	return templateLocale{persian: base == "fa"}
+}
+
+// formatDate formats a time.Time, an RFC3339 or 2006-01-02 string or a unix timestamp by a Go layout,
+// the default layout is 2006-01-02. For fa the date is converted to the Persian (Jalali) calendar.
+func (l templateLocale) formatDate(value any, layout ...string) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	dateLayout := defaultDateLayout
+	if len(layout) > 0 && layout[0] != "" {
+		dateLayout = layout[0]
+	}
+
+	if l.persian {
+		return l.localizeDigits(formatJalali(t, dateLayout)), nil
+	}
+
+	return t.Format(dateLayout), nil
+}
+
+// formatNumber formats a number with thousands separators, decimals is the number of the decimal places,
+// by default the smallest number of places that represents the value is used.
+func (l templateLocale) formatNumber(value any, decimals ...int) (string, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	places := -1
+	if len(decimals) > 0 {
+		places = decimals[0]
+	}
+
+	return l.groupNumber(number, places), nil
+}
+
+type currency struct {
+	symbol      string
+	persianName string
+	prefix      bool
+	decimals    int
+}
+
+var currencies = map[string]currency{
+	"USD": {symbol: "$", persianName: "دلار", prefix: true, decimals: 2},
+	"EUR": {symbol: "€", persianName: "یورو", prefix: true, decimals: 2},
+	"GBP": {symbol: "£", persianName: "پوند", prefix: true, decimals: 2},
+	"IRR": {symbol: "IRR", persianName: "ریال", decimals: 0},
+	"IRT": {symbol: "Toman", persianName: "تومان", decimals: 0},
+}
+
+// formatCurrency formats an amount of an ISO 4217 currency code, IRT is used for Toman.
+func (l templateLocale) formatCurrency(value any, code string) (string, error) {
+	amount, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	code = strings.ToUpper(code)
+	c, ok := currencies[code]
+	if !ok {
+		c = currency{symbol: code, decimals: 2}
+	}
+
+	if l.persian && c.persianName != "" {
+		return l.groupNumber(amount, c.decimals) + " " + c.persianName, nil
+	}
+
+	if c.prefix {
+		formatted := c.symbol + l.groupNumber(math.Abs(amount), c.decimals)
+		if amount < 0 {
+			return "-" + formatted, nil
+		}
+
+		return formatted, nil
+	}
+
+	return l.groupNumber(amount, c.decimals) + " " + c.symbol, nil
+}
+
+// digits writes the digits of the value in the digits of the language.
+func (l templateLocale) digits(value any) string {
+	return l.localizeDigits(fmt.Sprint(value))
+}
+
+func (l templateLocale) groupNumber(number float64, decimals int) string {
+	formatted := strconv.FormatFloat(number, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+
+	thousandsSeparator, decimalSeparator := ",", "."
+	if l.persian {
+		thousandsSeparator, decimalSeparator = "٬", "٫"
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousandsSeparator)
+		}
+
+		b.WriteRune(digit)
+	}
+
+	if hasFraction {
+		b.WriteString(decimalSeparator)
+		b.WriteString(fraction)
+	}
+
+	return l.localizeDigits(b.String())
+}
+
+func (l templateLocale) localizeDigits(value string) string {
+	if !l.persian {
+		return value
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '۰' + (r - '0')
+		}
+
+		return r
+	}, value)
+}
+
+// plural returns singular when count is one, otherwise it returns pluralForm.
+func plural(count any, singular, pluralForm string) (string, error) {
+	number, err := toFloat(count)
+	if err != nil {
+		return "", err
+	}
+
+	if number == 1 {
+		return singular, nil
+	}
+
+	return pluralForm, nil
+}
+
+// defaultValue returns the value, or fallback when the value is missing or empty, e.g. {{default "friend" .name}}.
+// nil, empty strings, lists and maps, false and zero numbers are empty.
+func defaultValue(fallback any, value ...any) any {
+	if len(value) == 0 || isEmptyValue(value[0]) {
+		return fallback
+	}
+
+	return value[0]
+}
+
+func isEmptyValue(value any) bool {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+
+		return err == nil && f == 0
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// buildURL appends the escaped path segments to an http or https base url, e.g. {{url "https://a.com/orders" .id}}.
+func buildURL(base string, segments ...any) (string, error) {
+	u, err := parseTemplateURL(base)
+	if err != nil {
+		return "", err
+	}
+
+	elements := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		value := fmt.Sprint(segment)
+		escaped := url.PathEscape(value)
+		if value == "." || value == ".." {
+			escaped = strings.ReplaceAll(value, ".", "%2E")
+		}
+
+		elements = append(elements, escaped)
+	}
+
+	return u.JoinPath(elements...).String(), nil
+}
+
+// withQuery sets the query parameters of an http or https url, pairs are keys and values,
+// e.g. {{withQuery "https://a.com" "ref" "email"}}.
+func withQuery(rawURL string, pairs ...any) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", errors.New("withQuery needs pairs of key and value")
+	}
+
+	u, err := parseTemplateURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for i := 0; i < len(pairs); i += 2 {
+		query.Set(fmt.Sprint(pairs[i]), fmt.Sprint(pairs[i+1]))
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func parseTemplateURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("url scheme %q is not allowed", u.Scheme)
+	}
+
+	return u, nil
+}
+
+func toFloat(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	default:
+		return 0, fmt.Errorf("%v is not a number", value)
+	}
+}
+
+var templateDateLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v != nil {
+			return *v, nil
+		}
+	case string:
+		for _, layout := range templateDateLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, nil
+			}
+		}
+	default:
+		if seconds, err := toFloat(value); err == nil {
+			return time.Unix(int64(seconds), 0).UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%v is not a date", value)
+}
+
+var (
+	jalaliMonthNames = []string{
+		"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور", "مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+	}
+	persianWeekdayNames = []string{"یکشنبه", "دوشنبه", "سه‌شنبه", "چهارشنبه", "پنجشنبه", "جمعه", "شنبه"}
+	jalaliLayoutTokens  = []string{"January", "Monday", "2006", "01", "02", "15", "04", "05"}
+)
+
+// formatJalali supports the January, Monday, 2006, 01, 02, 15, 04 and 05 elements of the Go layouts,
+// other characters of the layout are written as is.
+func formatJalali(t time.Time, layout string) string {
+	year, month, day := gregorianToJalali(t.Year(), int(t.Month()), t.Day())
+
+	var b strings.Builder
+	for len(layout) > 0 {
+		token := ""
+		for _, layoutToken := range jalaliLayoutTokens {
+			if strings.HasPrefix(layout, layoutToken) {
+				token = layoutToken
+				break
+			}
+		}
+
+		switch token {
+		case "":
+			b.WriteByte(layout[0])
+			layout = layout[1:]
+
+			continue
+		case "January":
+			b.WriteString(jalaliMonthNames[month-1])
+		case "Monday":
+			b.WriteString(persianWeekdayNames[t.Weekday()])
+		case "2006":
+			b.WriteString(strconv.Itoa(year))
+		case "01":
+			fmt.Fprintf(&b, "%02d", month)
+		case "02":
+			fmt.Fprintf(&b, "%02d", day)
+		default:
+			b.WriteString(t.Format(token))
+		}
+
+		layout = layout[len(token):]
+	}
+
+	return b.String()
+}
+
+// gregorianToJalali converts a Gregorian date to the Persian (Jalali) calendar.
+//
+//nolint:mnd // the numbers are the days of the calendar cycles
+func gregorianToJalali(gYear, gMonth, gDay int) (int, int, int) {
+	daysBeforeMonth := []int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+
+	leapYear := gYear
+	if gMonth > 2 {
+		leapYear = gYear + 1
+	}
+
+	days := 355666 + (365 * gYear) + ((leapYear + 3) / 4) - ((leapYear + 99) / 100) + ((leapYear + 399) / 400) +
+		gDay + daysBeforeMonth[gMonth-1]
+
+	year := -1595 + (33 * (days / 12053))
+	days %= 12053
+	year += 4 * (days / 1461)
+	days %= 1461
+
+	if days > 365 {
+		year += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+
+	if days < 186 {
+		return year, 1 + days/31, 1 + days%31
+	}
+
+	return year, 7 + (days-186)/30, 1 + (days-186)%30
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFormatDate(t *testing.T) {
+	nowruz := time.Date(2024, time.March, 20, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		lang     string
+		value    any
+		layout   []string
+		expected string
+		wantErr  bool
+	}{
+		{name: "default layout", lang: "en", value: nowruz, expected: "2024-03-20"},
+		{name: "custom layout", lang: "en", value: nowruz, layout: []string{"02 January 2006 15:04"},
+			expected: "20 March 2024 10:30"},
+		{name: "rfc3339 string", lang: "en", value: "2024-03-20T10:30:00Z", expected: "2024-03-20"},
+		{name: "date string", lang: "en", value: "2024-03-20", expected: "2024-03-20"},
+		{name: "unix timestamp", lang: "en", value: json.Number("0"), expected: "1970-01-01"},
+		{name: "time pointer", lang: "en", value: &nowruz, expected: "2024-03-20"},
+		{name: "jalali first day of the year", lang: "fa", value: nowruz, layout: []string{"2006/01/02"},
+			expected: "۱۴۰۳/۰۱/۰۱"},
+		{name: "jalali month and weekday names", lang: "fa-IR", value: time.Date(1979, time.February, 11, 0, 0, 0, 0, time.UTC),
+			layout: []string{"Monday 02 January 2006"}, expected: "یکشنبه ۲۲ بهمن ۱۳۵۷"},
+		{name: "jalali time", lang: "fa", value: nowruz, layout: []string{"15:04"}, expected: "۱۰:۳۰"},
+		{name: "invalid date", lang: "en", value: "yesterday", wantErr: true},
+		{name: "nil time pointer", lang: "en", value: (*time.Time)(nil), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatted, fErr := newTemplateLocale(test.lang).formatDate(test.value, test.layout...)
+			if test.wantErr {
+				if fErr == nil {
+					t.Fatalf("expected error, got %q", formatted)
+				}
+
+				return
+			}
+
+			if fErr != nil {
+				t.Fatalf("unexpected error: %v", fErr)
+			}
+
+			if formatted != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestGregorianToJalali(t *testing.T) {
+	tests := []struct {
+		gregorian time.Time
+		year      int
+		month     int
+		day       int
+	}{
+		{gregorian: time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC), year: 1403, month: 1, day: 1},
+		{gregorian: time.Date(2024, time.March, 19, 0, 0, 0, 0, time.UTC), year: 1402, month: 12, day: 29},
+		{gregorian: time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC), year: 1403, month: 12, day: 30},
+		{gregorian: time.Date(1979, time.February, 11, 0, 0, 0, 0, time.UTC), year: 1357, month: 11, day: 22},
+		{gregorian: time.Date(2024, time.September, 22, 0, 0, 0, 0, time.UTC), year: 1403, month: 7, day: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.gregorian.Format(time.DateOnly), func(t *testing.T) {
+			year, month, day := gregorianToJalali(test.gregorian.Year(), int(test.gregorian.Month()), test.gregorian.Day())
+			if year != test.year || month != test.month || day != test.day {
+				t.Fatalf("expected %d/%d/%d, got %d/%d/%d", test.year, test.month, test.day, year, month, day)
+			}
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		value    any
+		decimals []int
+		expected string
+		wantErr  bool
+	}{
+		{name: "integer", lang: "en", value: 1234567, expected: "1,234,567"},
+		{name: "smallest places", lang: "en", value: 1234.5, expected: "1,234.5"},
+		{name: "fixed places", lang: "en", value: json.Number("1234.5"), decimals: []int{2}, expected: "1,234.50"},
+		{name: "negative", lang: "en", value: -1234567.25, expected: "-1,234,567.25"},
+		{name: "small number", lang: "en", value: "999", expected: "999"},
+		{name: "persian digits and separators", lang: "fa", value: 1234567.5, expected: "۱٬۲۳۴٬۵۶۷٫۵"},
+		{name: "not a number", lang: "en", value: "many", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatted, fErr := newTemplateLocale(test.lang).formatNumber(test.value, test.decimals...)
+			if test.wantErr {
+				if fErr == nil {
+					t.Fatalf("expected error, got %q", formatted)
+				}
+
+				return
+			}
+
+			if fErr != nil {
+				t.Fatalf("unexpected error: %v", fErr)
+			}
+
+			if formatted != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		value    any
+		code     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "prefixed symbol", lang: "en", value: 1234.5, code: "USD", expected: "$1,234.50"},
+		{name: "lower case code", lang: "en", value: 10, code: "eur", expected: "€10.00"},
+		{name: "negative prefixed symbol", lang: "en", value: -1234.5, code: "USD", expected: "-$1,234.50"},
+		{name: "suffixed symbol", lang: "en", value: 1500000, code: "IRR", expected: "1,500,000 IRR"},
+		{name: "unknown code", lang: "en", value: 1000, code: "JPY", expected: "1,000.00 JPY"},
+		{name: "persian name", lang: "fa", value: 25000, code: "IRT", expected: "۲۵٬۰۰۰ تومان"},
+		{name: "persian name of a foreign currency", lang: "fa", value: 12.5, code: "USD", expected: "۱۲٫۵۰ دلار"},
+		{name: "persian unknown code", lang: "fa", value: 3, code: "JPY", expected: "۳٫۰۰ JPY"},
+		{name: "not a number", lang: "en", value: "free", code: "USD", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formatted, fErr := newTemplateLocale(test.lang).formatCurrency(test.value, test.code)
+			if test.wantErr {
+				if fErr == nil {
+					t.Fatalf("expected error, got %q", formatted)
+				}
+
+				return
+			}
+
+			if fErr != nil {
+				t.Fatalf("unexpected error: %v", fErr)
+			}
+
+			if formatted != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "one", count: 1, expected: "item"},
+		{name: "one as json number", count: json.Number("1"), expected: "item"},
+		{name: "zero", count: 0, expected: "items"},
+		{name: "many", count: "3", expected: "items"},
+		{name: "fraction", count: 1.5, expected: "items"},
+		{name: "not a number", count: "some", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			word, pErr := plural(test.count, "item", "items")
+			if test.wantErr {
+				if pErr == nil {
+					t.Fatalf("expected error, got %q", word)
+				}
+
+				return
+			}
+
+			if pErr != nil {
+				t.Fatalf("unexpected error: %v", pErr)
+			}
+
+			if word != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, word)
+			}
+		})
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		segments []any
+		expected string
+		wantErr  bool
+	}{
+		{name: "segments", base: "https://example.com/orders", segments: []any{42, "items"},
+			expected: "https://example.com/orders/42/items"},
+		{name: "escaped segment", base: "https://example.com/search", segments: []any{"a b/c?d"},
+			expected: "https://example.com/search/a%20b%2Fc%3Fd"},
+		{name: "dot segments are not resolved", base: "https://example.com/orders", segments: []any{"..", "."},
+			expected: "https://example.com/orders/%2E%2E/%2E"},
+		{name: "query of the base", base: "http://example.com/orders?ref=email", segments: []any{"1"},
+			expected: "http://example.com/orders/1?ref=email"},
+		{name: "scheme not allowed", base: "javascript:alert(1)", segments: []any{"1"}, wantErr: true},
+		{name: "relative url", base: "/orders", segments: []any{"1"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			built, bErr := buildURL(test.base, test.segments...)
+			if test.wantErr {
+				if bErr == nil {
+					t.Fatalf("expected error, got %q", built)
+				}
+
+				return
+			}
+
+			if bErr != nil {
+				t.Fatalf("unexpected error: %v", bErr)
+			}
+
+			if built != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, built)
+			}
+		})
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		pairs    []any
+		expected string
+		wantErr  bool
+	}{
+		{name: "new parameters", rawURL: "https://example.com/orders", pairs: []any{"ref", "email", "id", 7},
+			expected: "https://example.com/orders?id=7&ref=email"},
+		{name: "replaced parameter", rawURL: "https://example.com?ref=sms&page=2", pairs: []any{"ref", "email"},
+			expected: "https://example.com?page=2&ref=email"},
+		{name: "escaped value", rawURL: "https://example.com", pairs: []any{"q", "a&b c"},
+			expected: "https://example.com?q=a%26b+c"},
+		{name: "odd pairs", rawURL: "https://example.com", pairs: []any{"ref"}, wantErr: true},
+		{name: "scheme not allowed", rawURL: "ftp://example.com", pairs: []any{"ref", "email"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			built, wErr := withQuery(test.rawURL, test.pairs...)
+			if test.wantErr {
+				if wErr == nil {
+					t.Fatalf("expected error, got %q", built)
+				}
+
+				return
+			}
+
+			if wErr != nil {
+				t.Fatalf("unexpected error: %v", wErr)
+			}
+
+			if built != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, built)
+			}
+		})
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    []any
+		expected any
+	}{
+		{name: "missing", value: nil, expected: "friend"},
+		{name: "nil", value: []any{nil}, expected: "friend"},
+		{name: "empty string", value: []any{""}, expected: "friend"},
+		{name: "zero json number", value: []any{json.Number("0")}, expected: "friend"},
+		{name: "value", value: []any{"Ali"}, expected: "Ali"},
+		{name: "true", value: []any{true}, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value := defaultValue("friend", test.value...); value != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func (s Service) ListTemplateVersions(ctx context.Context, templateID types.ID) (ListTemplateVersionResponse, error) {
+	const op = "service.template_version.ListTemplateVersions"
+
+	template, gErr := s.GetTemplate(ctx, templateID)
+	if gErr != nil {
+		return ListTemplateVersionResponse{}, gErr
+	}
+
+	versions, gvErr := s.repo.GetTemplateVersions(ctx, templateID)
+	if gvErr != nil {
+		return ListTemplateVersionResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gvErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return ListTemplateVersionResponse{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Results:    versions,
+	}, nil
+}
+
+// RollbackTemplate copies the contents of an old version into a new version, versions are never rewritten.
+func (s Service) RollbackTemplate(ctx context.Context, templateID types.ID, version int) (Template, error) {
+	const op = "service.template_version.RollbackTemplate"
+
+	template, gErr := s.GetTemplate(ctx, templateID)
+	if gErr != nil {
+		return Template{}, gErr
+	}
+
+	if template.Version == version {
+		return Template{}, richerror.New(op).WithMessage(servermsg.MsgTemplateIsAlreadyInVersion).
+			WithKind(richerror.KindConflict)
+	}
+
+	templateVersion, gvErr := s.getTemplateVersion(ctx, templateID, version)
+	if gvErr != nil {
+		return Template{}, gvErr
+	}
+
+	newVersion, uErr := s.repo.UpdateTemplate(ctx, templateID, AddTemplateRequest{
+		Name:     template.Name,
+		Contents: templateVersion.Contents,
+	})
+	if uErr != nil {
+		return Template{}, errlog.ErrLog(richerror.New(op).WithWrapError(uErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	s.invalidateTemplateCache(ctx, template.Name)
+
+	template.Version = newVersion
+	template.Contents = templateVersion.Contents
+
+	return template, nil
+}
+
+// PreviewTemplate renders a template version with sample data, variables that are used in the template
+// and are not in the sample data are reported in MissingVariables.
+func (s Service) PreviewTemplate(ctx context.Context, templateID types.ID, req PreviewTemplateRequest) (PreviewTemplateResponse, error) {
+	const op = "service.template_version.PreviewTemplate"
+
+	if vErr := s.vld.ValidatePreviewTemplateRequest(req); vErr != nil {
+		return PreviewTemplateResponse{}, vErr
+	}
+
+	template, gErr := s.GetTemplate(ctx, templateID)
+	if gErr != nil {
+		return PreviewTemplateResponse{}, gErr
+	}
+
+	if req.Version != nil && *req.Version != template.Version {
+		templateVersion, gvErr := s.getTemplateVersion(ctx, templateID, *req.Version)
+		if gvErr != nil {
+			return PreviewTemplateResponse{}, gvErr
+		}
+
+		template.Version = templateVersion.Version
+		template.Contents = templateVersion.Contents
+	}
+
+	if !hasTemplateChannel(template, req.Channel) {
+		return PreviewTemplateResponse{}, richerror.New(op).WithMessage(servermsg.MsgTemplateChannelNotFound).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	includes, iErr := s.getTemplateIncludes(ctx, template)
+	if iErr != nil {
+		return PreviewTemplateResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidTemplatePreview).
+			WithWrapError(iErr).WithKind(richerror.KindBadRequest)
+	}
+
+	missingVariables, mErr := s.renderSvc.MissingVariables(template, req.Channel, req.Lang, req.DynamicTitleData,
+		req.DynamicBodyData, includes...)
+	if mErr != nil {
+		return PreviewTemplateResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidTemplatePreview).
+			WithWrapError(mErr).WithKind(richerror.KindBadRequest)
+	}
+
+	res, rErr := s.renderSvc.RenderTemplate(template, req.Channel, req.Lang, req.DynamicTitleData, req.DynamicBodyData,
+		includes...)
+	if rErr != nil {
+		return PreviewTemplateResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidTemplatePreview).
+			WithWrapError(rErr).WithKind(richerror.KindBadRequest)
+	}
+
+	return PreviewTemplateResponse{
+		TemplateID:       template.ID,
+		Name:             template.Name,
+		Version:          template.Version,
+		Channel:          req.Channel,
+		Lang:             res.Lang,
+		Dir:              res.Dir,
+		Title:            res.Title,
+		Body:             res.Body,
+		MissingVariables: missingVariables,
+	}, nil
+}
+
+// SendTestNotification sends the current version of a template as a direct notification, so it is not
+// filtered by the user ignore channels.
+func (s Service) SendTestNotification(ctx context.Context, templateID types.ID, req SendTestNotificationRequest) (Notification, error) {
+	const op = "service.template_version.SendTestNotification"
+
+	if vErr := s.vld.ValidateSendTestNotificationRequest(req); vErr != nil {
+		return Notification{}, vErr
+	}
+
+	template, gErr := s.GetTemplate(ctx, templateID)
+	if gErr != nil {
+		return Notification{}, gErr
+	}
+
+	if template.Kind == TemplateKindLayout || template.Kind == TemplateKindPartial {
+		return Notification{}, richerror.New(op).WithMessage(servermsg.MsgTemplateIsNotSendable).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	for _, channel := range req.ChannelDeliveries {
+		if !hasTemplateChannel(template, channel.Channel) {
+			return Notification{}, richerror.New(op).WithMessage(servermsg.MsgTemplateChannelNotFound).
+				WithKind(richerror.KindBadRequest).WithMeta(map[string]interface{}{"channel": channel.Channel})
+		}
+	}
+
+	userID, guErr := s.getUserIDFromExternalUserID(ctx, req.ExternalUserID)
+	if guErr != nil {
+		return Notification{}, errlog.ErrLog(richerror.New(op).WithWrapError(guErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	notification, cErr := s.createNotification(ctx, SendNotificationRequest{
+		UserID:            userID,
+		ExternalUserID:    req.ExternalUserID,
+		Type:              NotificationTypeDirect,
+		Data:              map[string]string{"test": "true"},
+		TemplateName:      template.Name,
+		DynamicBodyData:   req.DynamicBodyData,
+		DynamicTitleData:  req.DynamicTitleData,
+		ChannelDeliveries: req.ChannelDeliveries,
+	})
+	if cErr != nil {
+		return Notification{}, errlog.ErrLog(richerror.New(op).WithMessage("can't save test notification").
+			WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return notification, nil
+}
+
+func (s Service) getTemplateVersion(ctx context.Context, templateID types.ID, version int) (TemplateVersion, error) {
+	const op = "service.template_version.getTemplateVersion"
+
+	exists, eErr := s.repo.IsExistTemplateVersion(ctx, templateID, version)
+	if eErr != nil {
+		return TemplateVersion{}, errlog.ErrLog(richerror.New(op).WithWrapError(eErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return TemplateVersion{}, richerror.New(op).WithMessage(servermsg.MsgTemplateVersionNotFound).
+			WithKind(richerror.KindNotFound)
+	}
+
+	templateVersion, gErr := s.repo.GetTemplateVersion(ctx, templateID, version)
+	if gErr != nil {
+		return TemplateVersion{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return templateVersion, nil
+}
+
+// getTemplateInVersion returns the template with the contents of the given version, versions are immutable,
+// so they are cached without invalidation.
+func (s Service) getTemplateInVersion(ctx context.Context, template Template, version int) (Template, error) {
+	const op = "service.template_version.getTemplateInVersion"
+
+	key := fmt.Sprintf("template:%s:v%d", template.ID, version)
+
+	var templateVersion TemplateVersion
+	gErr := s.cache.Get(ctx, key, &templateVersion)
+	if gErr != nil {
+		if !errors.Is(gErr, cachemanager.ErrKeyNotFound) {
+			return Template{}, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		var gvErr error
+		templateVersion, gvErr = s.repo.GetTemplateVersion(ctx, template.ID, version)
+		if gvErr != nil {
+			return Template{}, richerror.New(op).WithWrapError(gvErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if sErr := s.cache.Set(ctx, key, templateVersion, s.cfg.TemplateCacheExpiration); sErr != nil {
+			errlog.WithoutErr(richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}
+
+	template.Version = templateVersion.Version
+	template.Contents = templateVersion.Contents
+
+	return template, nil
+}
+
+func hasTemplateChannel(template Template, channel ChannelType) bool {
+	for _, content := range template.Contents {
+		if content.Channel == channel {
+			return true
+		}
+	}
+
+	return false
+}
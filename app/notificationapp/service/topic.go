@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func (s Service) AddTopic(ctx context.Context, req AddTopicRequest) (Topic, error) {
+	const op = "service.topic.AddTopic"
+
+	if vErr := s.vld.ValidateAddTopicRequest(req); vErr != nil {
+		return Topic{}, vErr
+	}
+
+	exists, eErr := s.repo.IsExistTopicByName(ctx, req.Name)
+	if eErr != nil {
+		return Topic{}, errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if exists {
+		return Topic{}, richerror.New(op).WithMessage(servermsg.MsgConflictTopic).WithKind(richerror.KindConflict)
+	}
+
+	req.ID = types.ID(ulid.Make().String())
+
+	topic, cErr := s.repo.CreateTopic(ctx, req)
+	if cErr != nil {
+		return Topic{}, errlog.ErrLog(richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return topic, nil
+}
+
+// UpdateTopic updates the title and description of the topic, the name of a topic can't be changed,
+// because it is kept in the notifications and the user preferences.
+func (s Service) UpdateTopic(ctx context.Context, topicID types.ID, req AddTopicRequest) (Topic, error) {
+	const op = "service.topic.UpdateTopic"
+
+	topic, gErr := s.GetTopic(ctx, topicID)
+	if gErr != nil {
+		return Topic{}, gErr
+	}
+
+	req.Name = topic.Name
+	if vErr := s.vld.ValidateAddTopicRequest(req); vErr != nil {
+		return Topic{}, vErr
+	}
+
+	updated, uErr := s.repo.UpdateTopic(ctx, topicID, req)
+	if uErr != nil {
+		return Topic{}, errlog.ErrLog(richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return updated, nil
+}
+
+func (s Service) GetTopic(ctx context.Context, topicID types.ID) (Topic, error) {
+	const op = "service.topic.GetTopic"
+
+	exists, eErr := s.repo.IsExistTopicByID(ctx, topicID)
+	if eErr != nil {
+		return Topic{}, errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return Topic{}, richerror.New(op).WithMessage(servermsg.MsgTopicNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	topic, gErr := s.repo.GetTopicByID(ctx, topicID)
+	if gErr != nil {
+		return Topic{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return topic, nil
+}
+
+func (s Service) ListTopics(ctx context.Context) (ListTopicResponse, error) {
+	const op = "service.topic.ListTopics"
+
+	topics, gErr := s.repo.GetTopics(ctx)
+	if gErr != nil {
+		return ListTopicResponse{}, errlog.ErrLog(richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return ListTopicResponse{Results: topics}, nil
+}
+
+// DeleteTopic removes the topic from its templates too, the preferences of the users on the topic are kept,
+// but they are not applied anymore.
+func (s Service) DeleteTopic(ctx context.Context, topicID types.ID) error {
+	const op = "service.topic.DeleteTopic"
+
+	if _, gErr := s.GetTopic(ctx, topicID); gErr != nil {
+		return gErr
+	}
+
+	templateNames, dErr := s.repo.DeleteTopic(ctx, topicID)
+	if dErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if len(templateNames) > 0 {
+		s.invalidateTemplateCache(ctx, templateNames...)
+	}
+
+	return nil
+}
+
+// checkTemplateTopic the topic of a template must exist.
+func (s Service) checkTemplateTopic(ctx context.Context, topic string) error {
+	const op = "service.topic.checkTemplateTopic"
+
+	if topic == "" {
+		return nil
+	}
+
+	exists, eErr := s.repo.IsExistTopicByName(ctx, topic)
+	if eErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return richerror.New(op).WithMessage(servermsg.MsgTopicNotFound).WithKind(richerror.KindBadRequest).
+			WithMeta(map[string]interface{}{"topic": topic})
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	trackingProvider    = "tracking"
+	trackingActionOpen  = "open"
+	trackingActionClick = "click"
+)
+
+var emailLinkRegex = regexp.MustCompile(`(?i)(<a\s[^>]*?href\s*=\s*)(["'])(https?://[^"']+)(["'])`)
+
+// TrackEmailOpen records the open of an email by the tracking pixel, a failed record is only logged, so the pixel
+// is always served to the mail client.
+func (s Service) TrackEmailOpen(ctx context.Context, notificationID types.ID, signature string) error {
+	const op = "service.tracking.TrackEmailOpen"
+
+	if !s.verifyTrackingSignature(trackingActionOpen, notificationID, "", signature) {
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidTrackingSignature).WithKind(richerror.KindBadRequest)
+	}
+
+	if _, aErr := s.applyDeliveryEvent(ctx, trackingProvider, DeliveryEventRequest{
+		NotificationID: notificationID,
+		Channel:        ChannelTypeEmail,
+		Type:           DeliveryEventOpened,
+	}); aErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return nil
+}
+
+// TrackEmailClick records the click of a tracked email link, the link is signed, so the tracking url
+// can't be used as an open redirect.
+func (s Service) TrackEmailClick(ctx context.Context, notificationID types.ID, link, signature string) error {
+	const op = "service.tracking.TrackEmailClick"
+
+	if !s.verifyTrackingSignature(trackingActionClick, notificationID, link, signature) {
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidTrackingSignature).WithKind(richerror.KindBadRequest)
+	}
+
+	if _, aErr := s.applyDeliveryEvent(ctx, trackingProvider, DeliveryEventRequest{
+		NotificationID: notificationID,
+		Channel:        ChannelTypeEmail,
+		Type:           DeliveryEventClicked,
+		URL:            link,
+	}); aErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return nil
+}
+
+func (s Service) isTrackingEnabled() bool {
+	return s.cfg.TrackingBaseURL != "" && s.cfg.TrackingSecret != ""
+}
+
+// instrumentEmailBody replaces the http links of an email body by click tracking links and adds the open
+// tracking pixel to the end of the body.
+func (s Service) instrumentEmailBody(notificationID types.ID, body string) string {
+	if !s.isTrackingEnabled() {
+		return body
+	}
+
+	body = emailLinkRegex.ReplaceAllStringFunc(body, func(match string) string {
+		groups := emailLinkRegex.FindStringSubmatch(match)
+		link := html.UnescapeString(groups[3])
+
+		return groups[1] + groups[2] + html.EscapeString(s.trackingURL(trackingActionClick, notificationID, link)) + groups[4]
+	})
+
+	pixel := fmt.Sprintf(`<img src="%s" width="1" height="1" alt="" style="display:none">`,
+		html.EscapeString(s.trackingURL(trackingActionOpen, notificationID, "")))
+
+	if index := strings.LastIndex(strings.ToLower(body), "</body>"); index >= 0 {
+		return body[:index] + pixel + body[index:]
+	}
+
+	return body + pixel
+}
+
+func (s Service) trackingURL(action string, notificationID types.ID, link string) string {
+	query := url.Values{"sig": {s.trackingSignature(action, notificationID, link)}}
+	if link != "" {
+		query.Set("url", link)
+	}
+
+	return strings.TrimSuffix(s.cfg.TrackingBaseURL, "/") + "/v1/tracking/" + url.PathEscape(string(notificationID)) +
+		"/" + action + "?" + query.Encode()
+}
+
+func (s Service) trackingSignature(action string, notificationID types.ID, link string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.TrackingSecret))
+	mac.Write([]byte(action + ":" + string(notificationID) + ":" + link))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s Service) verifyTrackingSignature(action string, notificationID types.ID, link, signature string) bool {
+	if !s.isTrackingEnabled() {
+		return false
+	}
+
+	return hmac.Equal([]byte(s.trackingSignature(action, notificationID, link)), []byte(signature))
+}
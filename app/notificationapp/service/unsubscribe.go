@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// VerifyUnsubscribeLink checks the signature of an unsubscribe link without changing the preferences of the user.
+func (s Service) VerifyUnsubscribeLink(req UnsubscribeRequest) error {
+	const op = "service.unsubscribe.VerifyUnsubscribeLink"
+
+	if req.UserID == "" || req.Topic == "" || !IsValidChannelType(req.Channel) ||
+		!s.verifyUnsubscribeSignature(req.UserID, req.Topic, req.Channel, req.Signature) {
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidUnsubscribeLink).WithKind(richerror.KindBadRequest)
+	}
+
+	return nil
+}
+
+// Unsubscribe opts the user out of the topic on the channel of a signed unsubscribe link,
+// the link doesn't need the user to be logged in.
+func (s Service) Unsubscribe(ctx context.Context, req UnsubscribeRequest) error {
+	const op = "service.unsubscribe.Unsubscribe"
+
+	if vErr := s.VerifyUnsubscribeLink(req); vErr != nil {
+		return vErr
+	}
+
+	if sErr := s.setTopicPreference(ctx, req.UserID, TopicPreference{
+		Topic:      req.Topic,
+		Channel:    req.Channel,
+		Subscribed: false,
+	}); sErr != nil {
+		return errlog.ErrLog(richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return nil
+}
+
+// unsubscribeURL returns the signed unsubscribe link of the topic, it is empty when tracking is disabled.
+func (s Service) unsubscribeURL(userID types.ID, topic string, channel ChannelType) string {
+	if !s.isTrackingEnabled() || topic == "" {
+		return ""
+	}
+
+	query := url.Values{
+		"user_id": {string(userID)},
+		"topic":   {topic},
+		"channel": {string(channel)},
+		"sig":     {s.unsubscribeSignature(userID, topic, channel)},
+	}
+
+	return strings.TrimSuffix(s.cfg.TrackingBaseURL, "/") + "/v1/unsubscribe?" + query.Encode()
+}
+
+// addUnsubscribeLink adds the unsubscribe link to the end of an email body.
+func addUnsubscribeLink(body, link string) string {
+	footer := fmt.Sprintf(`<p style="font-size:12px;text-align:center"><a href="%s">Unsubscribe</a></p>`,
+		html.EscapeString(link))
+
+	if index := strings.LastIndex(strings.ToLower(body), "</body>"); index >= 0 {
+		return body[:index] + footer + body[index:]
+	}
+
+	return body + footer
+}
+
+// unsubscribeSignature each field is prefixed by its length, so a delimiter in the topic can't move the boundaries
+// of the signed fields.
+func (s Service) unsubscribeSignature(userID types.ID, topic string, channel ChannelType) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.TrackingSecret))
+	for _, field := range []string{"unsubscribe", string(userID), topic, string(channel)} {
+		mac.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s Service) verifyUnsubscribeSignature(userID types.ID, topic string, channel ChannelType, signature string) bool {
+	if !s.isTrackingEnabled() {
+		return false
+	}
+
+	return hmac.Equal([]byte(s.unsubscribeSignature(userID, topic, channel)), []byte(signature))
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestUnsubscribeSignature(t *testing.T) {
+	svc := Service{cfg: Config{TrackingBaseURL: "https://example.com", TrackingSecret: "secret"}}
+	userID := types.ID("01JEXAMPLEUSER0000000000000")
+	signature := svc.unsubscribeSignature(userID, "news:weekly", ChannelTypeEmail)
+
+	tests := []struct {
+		name      string
+		userID    types.ID
+		topic     string
+		channel   ChannelType
+		signature string
+		valid     bool
+	}{
+		{name: "signed link", userID: userID, topic: "news:weekly", channel: ChannelTypeEmail, signature: signature,
+			valid: true},
+		{name: "another topic", userID: userID, topic: "news", channel: ChannelTypeEmail, signature: signature},
+		{name: "delimiter moved to the user id", userID: userID + ":news", topic: "weekly", channel: ChannelTypeEmail,
+			signature: signature},
+		{name: "another channel", userID: userID, topic: "news:weekly", channel: ChannelTypeSMS, signature: signature},
+		{name: "tampered signature", userID: userID, topic: "news:weekly", channel: ChannelTypeEmail,
+			signature: signature[:len(signature)-1] + "0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if valid := svc.verifyUnsubscribeSignature(test.userID, test.topic, test.channel, test.signature); valid != test.valid {
+				t.Fatalf("expected valid %t, got %t", test.valid, valid)
+			}
+		})
+	}
+
+	t.Run("tracking disabled", func(t *testing.T) {
+		disabled := Service{cfg: Config{TrackingSecret: "secret"}}
+		if disabled.verifyUnsubscribeSignature(userID, "news:weekly", ChannelTypeEmail, signature) {
+			t.Fatal("expected the links to be rejected when tracking is disabled")
+		}
+	})
+}
@@ -49,6 +49,13 @@ func (s Service) UpdateUserSetting(ctx context.Context, externalUserID string, r
 
 	userSetting.Lang = req.Lang
 	userSetting.IgnoreChannels = req.IgnoreChannels
+	userSetting.DigestPreferences = req.DigestPreferences
+	userSetting.Timezone = req.Timezone
+	userSetting.QuietHours = req.QuietHours
+	// the topic preferences are kept when the request doesn't have them, they are usually set by the unsubscribe links.
+	if req.TopicPreferences != nil {
+		userSetting.TopicPreferences = req.TopicPreferences
+	}
 
 	return userSetting, nil
 }
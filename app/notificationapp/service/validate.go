@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
@@ -14,6 +15,14 @@ const (
 	maxTemplateNameLength   = 255
 	minExternalUserIDLength = 1
 	maxExternalUserIDLength = 255
+	maxCategoryLength       = 64
+	minTopicNameLength      = 1
+	maxTopicNameLength      = 64
+	minTopicTitleLength     = 1
+	maxTopicTitleLength     = 255
+	maxTopicDescription     = 1024
+	maxIdempotencyKeyLength = 255
+	maxDedupWindowSeconds   = 86400
 )
 
 type Validate struct {
@@ -46,6 +55,9 @@ func (v Validate) ValidateSendNotificationRequest(req SendNotificationRequest) e
 			validation.Required.Error(servermsg.MsgFieldRequired),
 			validation.By(v.ValidateNotificationChannelDeliveries),
 		),
+		validation.Field(&req.IdempotencyKey,
+			validation.Length(0, maxIdempotencyKeyLength).Error(servermsg.MsgInvalidLengthOfIdempotencyKey),
+		),
 	); err != nil {
 		fieldErrors := make(map[string]string)
 
@@ -112,6 +124,21 @@ func (v Validate) ValidateListNotificationRequest(req ListNotificationRequest) e
 		validation.Field(&req.ExternalUserID,
 			validation.Required.Error(servermsg.MsgFieldRequired),
 		),
+		validation.Field(&req.Types,
+			validation.Each(validation.By(v.ValidateNotificationType)),
+		),
+		validation.Field(&req.Category,
+			validation.Length(0, maxCategoryLength).Error(servermsg.MsgInvalidLengthOfCategory),
+		),
+		validation.Field(&req.To,
+			validation.When(req.From != nil && req.To != nil, validation.By(func(value interface{}) error {
+				if req.To.Before(*req.From) {
+					return errors.New(servermsg.MsgInvalidDateRange)
+				}
+
+				return nil
+			})),
+		),
 	); err != nil {
 		fieldErrors := make(map[string]string)
 
@@ -139,6 +166,19 @@ func (v Validate) ValidateAddTemplateRequest(req AddTemplateRequest) error {
 			validation.Required.Error(servermsg.MsgFieldRequired),
 			validation.Length(minTemplateNameLength, maxTemplateNameLength).Error(servermsg.MsgInvalidLengthOfTemplateName),
 		),
+		validation.Field(&req.Kind,
+			validation.By(v.validateTemplateKind),
+		),
+		validation.Field(&req.Category,
+			validation.Length(0, maxCategoryLength).Error(servermsg.MsgInvalidLengthOfCategory),
+		),
+		validation.Field(&req.Topic,
+			validation.Length(0, maxTopicNameLength).Error(servermsg.MsgInvalidLengthOfTopicName),
+		),
+		validation.Field(&req.DedupWindowSeconds,
+			validation.Min(0).Error(servermsg.MsgInvalidDedupWindow),
+			validation.Max(maxDedupWindowSeconds).Error(servermsg.MsgInvalidDedupWindow),
+		),
 		validation.Field(&req.Contents,
 			validation.Required.Error(servermsg.MsgFieldRequired),
 			validation.By(v.ValidateTemplateContents),
@@ -184,12 +224,31 @@ func (v Validate) ValidateTemplateContents(value interface{}) error {
 					return errors.New(servermsg.MsgConflictTemplateChannelLang)
 				}
 			}
+
+			for _, text := range []string{body.Title, body.Body} {
+				if _, _, iErr := inspectTemplate(text); iErr != nil {
+					return errors.New(servermsg.MsgInvalidTemplateSyntax)
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
+func (v Validate) validateTemplateKind(value interface{}) error {
+	kind, ok := value.(TemplateKind)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidTemplateKind)
+	}
+
+	if kind != "" && !IsValidTemplateKind(kind) {
+		return errors.New(servermsg.MsgInvalidTemplateKind)
+	}
+
+	return nil
+}
+
 func (v Validate) ValidateUpdateUserSettingsRequest(req UpdateUserSettingRequest) error {
 	const op = "validate.ValidateUpdateUserNotificationSettingsRequest"
 
@@ -200,6 +259,18 @@ func (v Validate) ValidateUpdateUserSettingsRequest(req UpdateUserSettingRequest
 			validation.Required.Error(servermsg.MsgFieldRequired),
 			validation.By(v.validateIgnoreChannel),
 		),
+		validation.Field(&req.DigestPreferences,
+			validation.By(v.validateDigestPreferences),
+		),
+		validation.Field(&req.Timezone,
+			validation.By(v.validateTimezone),
+		),
+		validation.Field(&req.QuietHours,
+			validation.By(v.validateQuietHours),
+		),
+		validation.Field(&req.TopicPreferences,
+			validation.By(v.validateTopicPreferences),
+		),
 	); err != nil {
 		fieldErrors := make(map[string]string)
 
@@ -239,3 +310,322 @@ func (v Validate) validateIgnoreChannel(value interface{}) error {
 
 	return nil
 }
+
+func (v Validate) validateDigestPreferences(value interface{}) error {
+	preferences, ok := value.([]DigestPreference)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidDigestPreference)
+	}
+
+	seen := make(map[string]struct{})
+	for _, preference := range preferences {
+		if !IsDigestChannel(preference.Channel) {
+			return errors.New(servermsg.MsgInvalidDigestChannel)
+		}
+
+		if !IsValidDigestFrequency(preference.Frequency) {
+			return errors.New(servermsg.MsgInvalidDigestFrequency)
+		}
+
+		for _, nt := range preference.NotificationTypes {
+			if !IsValidNotificationType(nt) || nt == NotificationTypeCritical || nt == NotificationTypeDirect {
+				return errors.New(servermsg.MsgInvalidNotificationType)
+			}
+
+			key := string(preference.Channel) + ":" + string(nt)
+			if _, ok := seen[key]; ok {
+				return errors.New(servermsg.MsgConflictDigestPreference)
+			}
+			seen[key] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (v Validate) validateTimezone(value interface{}) error {
+	timezone, ok := value.(string)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidTimezone)
+	}
+
+	if timezone == "" {
+		return nil
+	}
+
+	if _, lErr := time.LoadLocation(timezone); lErr != nil {
+		return errors.New(servermsg.MsgInvalidTimezone)
+	}
+
+	return nil
+}
+
+func (v Validate) validateQuietHours(value interface{}) error {
+	quietHours, ok := value.([]QuietHours)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidQuietHours)
+	}
+
+	for _, quiet := range quietHours {
+		if !IsValidChannelType(quiet.Channel) {
+			return errors.New(servermsg.MsgInvalidNotificationChannelDelivery)
+		}
+
+		start, sErr := time.Parse(quietHoursLayout, quiet.Start)
+		if sErr != nil {
+			return errors.New(servermsg.MsgInvalidQuietHours)
+		}
+
+		end, eErr := time.Parse(quietHoursLayout, quiet.End)
+		if eErr != nil {
+			return errors.New(servermsg.MsgInvalidQuietHours)
+		}
+
+		if start.Equal(end) {
+			return errors.New(servermsg.MsgInvalidQuietHours)
+		}
+	}
+
+	return nil
+}
+
+func (v Validate) validateTopicPreferences(value interface{}) error {
+	preferences, ok := value.([]TopicPreference)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidTopicPreference)
+	}
+
+	for index, preference := range preferences {
+		if preference.Topic == "" || len(preference.Topic) > maxTopicNameLength {
+			return errors.New(servermsg.MsgInvalidLengthOfTopicName)
+		}
+
+		if !IsValidChannelType(preference.Channel) {
+			return errors.New(servermsg.MsgInvalidNotificationChannelDelivery)
+		}
+
+		for _, other := range preferences[index+1:] {
+			if other.Topic == preference.Topic && other.Channel == preference.Channel {
+				return errors.New(servermsg.MsgConflictTopicPreference)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v Validate) ValidateAddTopicRequest(req AddTopicRequest) error {
+	const op = "validate.ValidateAddTopicRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.Length(minTopicNameLength, maxTopicNameLength).Error(servermsg.MsgInvalidLengthOfTopicName),
+		),
+		validation.Field(&req.Title,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.Length(minTopicTitleLength, maxTopicTitleLength).Error(servermsg.MsgInvalidLengthOfTopicTitle),
+		),
+		validation.Field(&req.Description,
+			validation.Length(0, maxTopicDescription).Error(servermsg.MsgInvalidLengthOfTopicDescription),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
+
+func (v Validate) ValidateRescheduleNotificationRequest(req RescheduleNotificationRequest) error {
+	const op = "validate.ValidateRescheduleNotificationRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.SendAt,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.Min(time.Now()).Error(servermsg.MsgSendAtMustBeInFuture),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
+
+func (v Validate) ValidateListScheduledNotificationRequest(req ListScheduledNotificationRequest) error {
+	const op = "validate.ValidateListScheduledNotificationRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.Status,
+			validation.By(v.validateOverallStatus),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
+
+func (v Validate) validateOverallStatus(value interface{}) error {
+	status, ok := value.(OverallStatus)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidNotificationStatus)
+	}
+
+	if status != "" && !IsValidOverallStatus(status) {
+		return errors.New(servermsg.MsgInvalidNotificationStatus)
+	}
+
+	return nil
+}
+
+func (v Validate) ValidateBroadcastRequest(req BroadcastRequest) error {
+	const op = "validate.ValidateBroadcastRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.Name,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+		),
+		validation.Field(&req.Type,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.By(v.ValidateNotificationType),
+		),
+		validation.Field(&req.TemplateName,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.Length(minTemplateNameLength, maxTemplateNameLength).Error(servermsg.MsgInvalidLengthOfTemplateName),
+		),
+		validation.Field(&req.ChannelDeliveries,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.By(v.ValidateNotificationChannelDeliveries),
+		),
+		validation.Field(&req.ExternalUserIDs,
+			validation.When(req.CSVFileID == "", validation.Required.Error(servermsg.MsgFieldRequired)),
+			validation.When(req.CSVFileID != "", validation.Empty.Error(servermsg.MsgCampaignRecipientsConflict)),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
+
+func (v Validate) ValidatePreviewTemplateRequest(req PreviewTemplateRequest) error {
+	const op = "validate.ValidatePreviewTemplateRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.Version,
+			validation.Min(1).Error(servermsg.MsgTemplateVersionNotFound),
+		),
+		validation.Field(&req.Channel,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.By(v.validateChannelType),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
+
+func (v Validate) validateChannelType(value interface{}) error {
+	channel, ok := value.(ChannelType)
+	if !ok {
+		return errors.New(servermsg.MsgInvalidNotificationChannelDelivery)
+	}
+
+	if !IsValidChannelType(channel) {
+		return errors.New(servermsg.MsgInvalidNotificationChannelDelivery)
+	}
+
+	return nil
+}
+
+func (v Validate) ValidateSendTestNotificationRequest(req SendTestNotificationRequest) error {
+	const op = "validate.ValidateSendTestNotificationRequest"
+
+	if err := validation.ValidateStruct(&req,
+		validation.Field(&req.ExternalUserID,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.Length(minExternalUserIDLength, maxExternalUserIDLength).Error(servermsg.MsgInvalidLengthOfUserID),
+		),
+		validation.Field(&req.ChannelDeliveries,
+			validation.Required.Error(servermsg.MsgFieldRequired),
+			validation.By(v.ValidateNotificationChannelDeliveries),
+		),
+	); err != nil {
+		fieldErrors := make(map[string]string)
+
+		vErr := validation.Errors{}
+		if errors.As(err, &vErr) {
+			for key, value := range vErr {
+				if value != nil {
+					fieldErrors[key] = v.t.TranslateMessage(value.Error())
+				}
+			}
+		}
+
+		return richerror.New(op).WithMessage(servermsg.MsgInvalidInput).WithKind(richerror.KindInvalid).
+			WithErrorFields(fieldErrors).WithMeta(map[string]interface{}{"req": req})
+	}
+
+	return nil
+}
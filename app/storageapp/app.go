@@ -11,6 +11,7 @@ import (
 	"github.com/syntaxfa/quick-connect/adapter/postgres"
 	"github.com/syntaxfa/quick-connect/adapter/storage/aws"
 	"github.com/syntaxfa/quick-connect/adapter/storage/local"
+	"github.com/syntaxfa/quick-connect/adapter/storage/proxy"
 	grpcdelivery "github.com/syntaxfa/quick-connect/app/storageapp/delivery/grpc"
 	"github.com/syntaxfa/quick-connect/app/storageapp/delivery/http"
 	postgres2 "github.com/syntaxfa/quick-connect/app/storageapp/repository/postgres"
@@ -33,6 +34,7 @@ import (
 
 type Application struct {
 	cfg                Config
+	svc                service.Service
 	httpServer         http.Server
 	logger             *slog.Logger
 	trap               <-chan os.Signal
@@ -50,27 +52,16 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 	cfg.Service.Driver = cfg.Storage.Driver
 	cfg.Service.Bucket = cfg.Storage.AWS.BucketName
 
-	var storage service.Storage
-	var storageErr error
+	storage, storageErr := NewStorage(context.Background(), cfg.Storage.Driver, cfg.Storage, logger)
+	if storageErr != nil {
+		errlog.WithoutErr(richerror.New(op).WithWrapError(storageErr).WithKind(richerror.KindUnexpected), logger)
 
-	if cfg.Storage.Driver == service.DriverS3 {
-		ctx := context.Background()
-
-		storage, storageErr = aws.New(ctx, cfg.Storage.AWS)
-		if storageErr != nil {
-			logger.Error("can't connect to s3", slog.String("error", storageErr.Error()))
-
-			panic(storageErr)
-		}
-	} else {
-		storage, storageErr = local.New(cfg.Storage.Local, logger)
-		if storageErr != nil {
-			errlog.WithoutErr(richerror.New(op).WithWrapError(storageErr).WithKind(richerror.KindUnexpected), logger)
-
-			panic(storageErr)
-		}
+		panic(storageErr)
 	}
 
+	// the urls of the local driver and the storage proxy are signed urls of the storage app.
+	urlVerifier, _ := storage.(http.URLVerifier)
+
 	repo := postgres2.New(psqAdapter)
 	svc := service.New(cfg.Service, storage, repo, logger)
 
@@ -101,7 +92,7 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 	jwtValidator := jwtvalidator.New(resp.GetPublicKey(), logger)
 	authMid := auth.New(jwtValidator)
 
-	handler := http.NewHandler(svc, t, cfg.Storage.Local.RootPath, cfg.Service.MaxFileSize, logger)
+	handler := http.NewHandler(svc, t, cfg.Service.MaxFileSize, urlVerifier, logger)
 	httpServer := http.New(httpserver.New(cfg.HTTPServer, logger), handler, logger, authMid)
 
 	internalRoleManager := SetupInternalRoleManager()
@@ -112,6 +103,7 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 
 	return Application{
 		cfg:                cfg,
+		svc:                svc,
 		httpServer:         httpServer,
 		logger:             logger,
 		trap:               trap,
@@ -119,10 +111,59 @@ func Setup(cfg Config, logger *slog.Logger, trap <-chan os.Signal, t *translatio
 	}, svc
 }
 
+// NewStorage returns the storage of the driver, the s3 storage is connected by ctx and it is served by the storage
+// proxy when the proxy is enabled.
+func NewStorage(ctx context.Context, driver service.Driver, cfg Storage, logger *slog.Logger) (service.Storage, error) {
+	if driver == service.DriverS3 {
+		s3Storage, sErr := aws.New(ctx, cfg.AWS)
+		if sErr != nil {
+			return nil, fmt.Errorf("can't connect to s3: %w", sErr)
+		}
+
+		if !cfg.Proxy.Enabled {
+			return s3Storage, nil
+		}
+
+		return proxy.New(s3Storage, cfg.Proxy)
+	}
+
+	localStorage, lErr := local.New(cfg.Local, logger)
+	if lErr != nil {
+		return nil, lErr
+	}
+
+	return localStorage, nil
+}
+
 func (a Application) Start() {
 	httpServerChan := make(chan error, 1)
 	grpcServerChan := make(chan error, 1)
 
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+
+		a.svc.RunHeartbeat(workerCtx)
+	}()
+
+	imageWorkersDone := make(chan struct{})
+	go func() {
+		defer close(imageWorkersDone)
+
+		a.svc.RunImageWorkers(workerCtx)
+	}()
+
+	if a.cfg.Service.Janitor.Enabled {
+		go func() {
+			a.logger.Info("storage janitor started", slog.Bool("dry_run", a.cfg.Service.Janitor.DryRun))
+
+			a.svc.RunJanitor(workerCtx)
+		}()
+	}
+
 	go func() {
 		a.logger.Info(fmt.Sprintf("http server started on %d", a.cfg.HTTPServer.Port))
 
@@ -146,6 +187,11 @@ func (a Application) Start() {
 		a.logger.Info("received http server shutdown signal!!!")
 	}
 
+	cancelWorkers()
+	// the heartbeat is removed before the app stops, so the driver migration can run right after the instances stop.
+	<-heartbeatDone
+	<-imageWorkersDone
+
 	shutdownTimeoutCtx, cancel := context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
 	defer cancel()
 
@@ -196,9 +242,10 @@ func (a Application) stopInternalGRPCServer(wg *sync.WaitGroup) {
 
 func SetupInternalRoleManager() *rolemanager.RoleManager {
 	methodRoles := map[string][]types.Role{
-		"/storage.StorageInternalService/GetLink":     {types.RoleService},
-		"/storage.StorageInternalService/GetFileInfo": {types.RoleService},
-		"/storage.StorageInternalService/ConfirmFile": {types.RoleService},
+		"/storage.StorageInternalService/GetLink":          {types.RoleService},
+		"/storage.StorageInternalService/GetFileInfo":      {types.RoleService},
+		"/storage.StorageInternalService/ConfirmFile":      {types.RoleService},
+		"/storage.StorageInternalService/GetStorageUsages": {types.RoleService, types.RoleSuperUser, types.RoleFile},
 	}
 
 	return rolemanager.NewRoleManager(methodRoles)
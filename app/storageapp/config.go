@@ -6,6 +6,7 @@ import (
 	"github.com/syntaxfa/quick-connect/adapter/postgres"
 	"github.com/syntaxfa/quick-connect/adapter/storage/aws"
 	"github.com/syntaxfa/quick-connect/adapter/storage/local"
+	"github.com/syntaxfa/quick-connect/adapter/storage/proxy"
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/grpcclient"
 	"github.com/syntaxfa/quick-connect/pkg/grpcserver"
@@ -13,10 +14,12 @@ import (
 	"github.com/syntaxfa/quick-connect/pkg/logger"
 )
 
+// Storage Proxy serves the objects of the s3 driver by the storage app when it is enabled.
 type Storage struct {
 	Driver service.Driver `koanf:"driver"`
 	AWS    aws.Config     `koanf:"aws"`
 	Local  local.Config   `koanf:"local"`
+	Proxy  proxy.Config   `koanf:"proxy"`
 }
 
 type Config struct {
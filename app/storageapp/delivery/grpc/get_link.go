@@ -9,7 +9,7 @@ import (
 )
 
 func (h InternalHandler) GetLink(ctx context.Context, req *storagepb.GetLinkRequest) (*storagepb.GetLinkResponse, error) {
-	resp, sErr := h.svc.GetLink(ctx, types.ID(req.GetFileId()))
+	resp, sErr := h.svc.GetLink(ctx, types.ID(req.GetFileId()), types.ID(req.GetUserId()), req.GetVariant())
 	if sErr != nil {
 		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
 	}
@@ -0,0 +1,18 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
+)
+
+func (h InternalHandler) GetStorageUsages(ctx context.Context,
+	req *storagepb.GetStorageUsagesRequest) (*storagepb.GetStorageUsagesResponse, error) {
+	resp, sErr := h.svc.GetStorageUsages(ctx, convertStorageUsersFromPB(req.GetUsers()))
+	if sErr != nil {
+		return nil, servermsg.GRPCMsg(sErr, h.t, h.logger)
+	}
+
+	return convertStorageUsagesToPB(resp), nil
+}
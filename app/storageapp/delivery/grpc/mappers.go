@@ -3,6 +3,7 @@ package grpc
 import (
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/protobuf/storage/golang/storagepb"
+	"github.com/syntaxfa/quick-connect/types"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -22,9 +23,40 @@ func convertFileToPB(file service.File) *storagepb.File {
 		Driver:      string(file.Driver),
 		Bucket:      file.Bucket,
 		IsPublic:    file.IsPublic,
+		Sha256:      file.SHA256,
+		Purpose:     string(file.Purpose),
+		Status:      string(file.Status),
 		IsConfirmed: file.IsConfirmed,
 		CreatedAt:   timestamppb.New(file.CreatedAt),
 		UpdatedAt:   timestamppb.New(file.UpdatedAt),
 		DeletedAt:   deletedAt,
 	}
 }
+
+func convertStorageUsersFromPB(users []*storagepb.StorageUser) []service.UsageUser {
+	usageUsers := make([]service.UsageUser, 0, len(users))
+	for _, user := range users {
+		roles := make([]types.Role, 0, len(user.GetRoles()))
+		for _, role := range user.GetRoles() {
+			roles = append(roles, types.Role(role))
+		}
+
+		usageUsers = append(usageUsers, service.UsageUser{ID: types.ID(user.GetUserId()), Roles: roles})
+	}
+
+	return usageUsers
+}
+
+func convertStorageUsagesToPB(usages []service.StorageUsage) *storagepb.GetStorageUsagesResponse {
+	pbUsages := make([]*storagepb.StorageUsage, 0, len(usages))
+	for _, usage := range usages {
+		pbUsages = append(pbUsages, &storagepb.StorageUsage{
+			UserId:    string(usage.UploaderID),
+			UsedBytes: usage.UsedBytes,
+			FileCount: usage.FileCount,
+			Limit:     usage.Limit,
+		})
+	}
+
+	return &storagepb.GetStorageUsagesResponse{Usages: pbUsages}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/auth"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// createDirectUpload docs
+// @Summary Create a direct upload
+// @Description Creates a pending file and returns the presigned request that uploads the file directly to the storage.
+// @Description The file must be sent with the method, the url and the headers of the upload, then the upload is completed
+// @Description by /files/{fileID}/complete. It is only supported by the S3 driver.
+// @Tags Storage
+// @Accept json
+// @Produce json
+// @Param Request body service.CreateDirectUploadRequest true "filename, size and content type of the file, sha256 is optional"
+// @Success 201 {object} service.DirectUploadResponse
+// @Failure 400 {string} string "the request is not valid, the file type is not allowed or direct upload is not supported"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Storage quota exceeded"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/direct [POST].
+func (h Handler) createDirectUpload(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	var req service.CreateDirectUploadRequest
+	if bErr := c.Bind(&req); bErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+	req.UploaderID = claims.UserID
+	req.UploaderRoles = claims.Roles
+
+	resp, sErr := h.svc.CreateDirectUpload(c.Request().Context(), req)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// completeDirectUpload docs
+// @Summary Complete a direct upload
+// @Description Checks the file that is uploaded to the storage and marks it ready, a file that is not allowed is removed.
+// @Tags Storage
+// @Produce json
+// @Param fileID path string true "file ID"
+// @Success 200 {object} service.File
+// @Failure 400 {string} string "the file is not uploaded, it does not match the upload or the file type is not allowed"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "file not found"
+// @Failure 409 {string} string "the upload is being completed by another request"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/{fileID}/complete [POST].
+func (h Handler) completeDirectUpload(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	resp, sErr := h.svc.CompleteDirectUpload(c.Request().Context(), types.ID(c.Param("fileID")), claims.UserID)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
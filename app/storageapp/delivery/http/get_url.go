@@ -17,10 +17,11 @@ import (
 // @Accept json
 // @Produce json
 // @Param fileID path string true "file ID"
+// @Param variant query string false "name of the image variant, such as thumbnail, the original file is returned when the variant does not exist"
 // @Failure 404 {string} conversation does not exist
 // @Failure 500 {string} something went wrong.
 func (h Handler) getPublicLink(c echo.Context) error {
-	resp, sErr := h.svc.GetPublicLink(c.Request().Context(), types.ID(c.Param("fileID")))
+	resp, sErr := h.svc.GetPublicLink(c.Request().Context(), types.ID(c.Param("fileID")), c.QueryParam("variant"))
 	if sErr != nil {
 		return servermsg.HTTPMsg(c, sErr, h.t)
 	}
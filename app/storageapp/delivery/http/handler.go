@@ -2,25 +2,34 @@ package http
 
 import (
 	"log/slog"
+	"net/url"
 
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/translation"
+	"github.com/syntaxfa/quick-connect/types"
 )
 
+// URLVerifier verifies the signed urls of the local driver, userID is empty for the anonymous requests.
+type URLVerifier interface {
+	VerifySignedURL(key string, query url.Values, userID types.ID) error
+}
+
 type Handler struct {
-	svc           service.Service
-	t             *translation.Translate
-	localRootPath string
-	maxSize       int64
-	logger        *slog.Logger
+	svc         service.Service
+	t           *translation.Translate
+	maxSize     int64
+	urlVerifier URLVerifier
+	logger      *slog.Logger
 }
 
-func NewHandler(svc service.Service, t *translation.Translate, localRootPath string, maxSize int64, logger *slog.Logger) Handler {
+// NewHandler urlVerifier is nil when the urls of the storage are not signed by the storage app.
+func NewHandler(svc service.Service, t *translation.Translate, maxSize int64, urlVerifier URLVerifier,
+	logger *slog.Logger) Handler {
 	return Handler{
-		svc:           svc,
-		t:             t,
-		localRootPath: localRootPath,
-		maxSize:       maxSize,
-		logger:        logger,
+		svc:         svc,
+		t:           t,
+		maxSize:     maxSize,
+		urlVerifier: urlVerifier,
+		logger:      logger,
 	}
 }
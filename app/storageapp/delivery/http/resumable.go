@@ -0,0 +1,265 @@
+package http
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+	"github.com/syntaxfa/quick-connect/pkg/auth"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// Headers of the tus resumable upload protocol, https://tus.io/protocols/resumable-upload.
+const (
+	headerTusResumable   = "Tus-Resumable"
+	headerTusVersion     = "Tus-Version"
+	headerTusExtension   = "Tus-Extension"
+	headerTusMaxSize     = "Tus-Max-Size"
+	headerUploadOffset   = "Upload-Offset"
+	headerUploadLength   = "Upload-Length"
+	headerUploadMetadata = "Upload-Metadata"
+	headerUploadExpires  = "Upload-Expires"
+
+	tusVersion          = "1.0.0"
+	tusExtensions       = "creation,expiration,termination"
+	tusChunkContentType = "application/offset+octet-stream"
+)
+
+// tusExposedHeaders are the headers that the browser clients can read.
+var tusExposedHeaders = strings.Join([]string{headerTusResumable, headerTusVersion, headerTusExtension, headerTusMaxSize,
+	headerUploadOffset, headerUploadLength, headerUploadExpires, echo.HeaderLocation}, ", ")
+
+// tusResumable sets the tus headers of the responses and rejects the requests of the other versions of the protocol,
+// the OPTIONS requests don't need the Tus-Resumable header.
+func (h Handler) tusResumable(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Response().Header()
+		header.Set(headerTusResumable, tusVersion)
+		header.Set(echo.HeaderAccessControlExposeHeaders, tusExposedHeaders)
+
+		if c.Request().Method != http.MethodOptions && c.Request().Header.Get(headerTusResumable) != tusVersion {
+			header.Set(headerTusVersion, tusVersion)
+
+			return c.NoContent(http.StatusPreconditionFailed)
+		}
+
+		return next(c)
+	}
+}
+
+// resumableOptions docs
+// @Summary Resumable upload options
+// @Description Returns the tus protocol version, extensions and the maximum size of the resumable uploads
+// @Tags Storage
+// @Success 204
+// @Router /files/resumable [OPTIONS].
+func (h Handler) resumableOptions(c echo.Context) error {
+	header := c.Response().Header()
+	header.Set(headerTusVersion, tusVersion)
+	header.Set(headerTusExtension, tusExtensions)
+	header.Set(headerTusMaxSize, strconv.FormatInt(h.svc.ResumableMaxFileSize(), 10))
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// createResumableUpload docs
+// @Summary Create a resumable upload
+// @Description Creates a tus resumable upload, the chunks are sent to the Location of the response.
+// @Description The id of the upload is the id of the file when the upload is completed.
+// @Description Upload-Metadata has the base64 encoded filename, is_public and purpose of the file.
+// @Tags Storage
+// @Produce json
+// @Param Tus-Resumable header string true "tus protocol version" default(1.0.0)
+// @Param Upload-Length header integer true "Size of the file"
+// @Param Upload-Metadata header string true "filename, is_public and purpose, e.g. filename dmlkZW8ubXA0,purpose c3Rvcnk="
+// @Success 201 {object} service.ResumableUploadResponse
+// @Failure 400 {string} string "Upload-Length or Upload-Metadata is not valid"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Storage quota exceeded"
+// @Failure 413 {string} string "File size limit exceeded"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/resumable [POST].
+func (h Handler) createResumableUpload(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	size, pErr := strconv.ParseInt(c.Request().Header.Get(headerUploadLength), 10, 64)
+	if pErr != nil || size <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload-Length is not valid")
+	}
+
+	if size > h.svc.ResumableMaxFileSize() {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "file size limit exceeded")
+	}
+
+	metadata, mErr := parseUploadMetadata(c.Request().Header.Get(headerUploadMetadata))
+	if mErr != nil || metadata["filename"] == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload-Metadata is not valid, filename is required")
+	}
+
+	var isPublic bool
+	if value, ok := metadata["is_public"]; ok {
+		var bErr error
+		if isPublic, bErr = strconv.ParseBool(value); bErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "is_public is not valid")
+		}
+	}
+
+	resp, sErr := h.svc.CreateResumableUpload(c.Request().Context(), service.CreateResumableUploadRequest{
+		UploaderID:    claims.UserID,
+		UploaderRoles: claims.Roles,
+		Filename:      metadata["filename"],
+		Size:          size,
+		IsPublic:      isPublic,
+		Purpose:       service.UploadPurpose(metadata["purpose"]),
+	})
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderLocation, strings.TrimSuffix(c.Request().URL.Path, "/")+"/"+string(resp.ID))
+	header.Set(headerUploadExpires, resp.ExpiresAt.UTC().Format(http.TimeFormat))
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// getResumableUpload docs
+// @Summary Get the offset of a resumable upload
+// @Description Returns the uploaded size of the upload in the Upload-Offset header, the upload is resumed from it
+// @Tags Storage
+// @Param Tus-Resumable header string true "tus protocol version" default(1.0.0)
+// @Param uploadID path string true "upload ID"
+// @Success 200
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "upload does not exist or it is expired"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/resumable/{uploadID} [HEAD].
+func (h Handler) getResumableUpload(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	resp, sErr := h.svc.GetResumableUpload(c.Request().Context(), types.ID(c.Param("uploadID")), claims.UserID)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	setUploadHeaders(c, resp)
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	return c.NoContent(http.StatusOK)
+}
+
+// writeChunk docs
+// @Summary Upload a chunk of a resumable upload
+// @Description Appends the body to the upload at Upload-Offset, the file is created when the last chunk is uploaded
+// @Tags Storage
+// @Accept application/offset+octet-stream
+// @Param Tus-Resumable header string true "tus protocol version" default(1.0.0)
+// @Param Upload-Offset header integer true "Uploaded size of the file"
+// @Param uploadID path string true "upload ID"
+// @Success 204
+// @Failure 400 {string} string "Upload-Offset is not valid, or the file type is not allowed"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "upload does not exist or it is expired"
+// @Failure 409 {string} string "Upload-Offset does not match the uploaded size"
+// @Failure 415 {string} string "Content-Type is not application/offset+octet-stream"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/resumable/{uploadID} [PATCH].
+func (h Handler) writeChunk(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	if c.Request().Header.Get(echo.HeaderContentType) != tusChunkContentType {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be "+tusChunkContentType)
+	}
+
+	offset, pErr := strconv.ParseInt(c.Request().Header.Get(headerUploadOffset), 10, 64)
+	if pErr != nil || offset < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload-Offset is not valid")
+	}
+
+	resp, sErr := h.svc.WriteChunk(c.Request().Context(), service.WriteChunkRequest{
+		UploadID:   types.ID(c.Param("uploadID")),
+		UploaderID: claims.UserID,
+		Offset:     offset,
+		Chunk:      c.Request().Body,
+	})
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	setUploadHeaders(c, resp)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// abortResumableUpload docs
+// @Summary Abort a resumable upload
+// @Description Removes the upload and its uploaded chunks
+// @Tags Storage
+// @Param Tus-Resumable header string true "tus protocol version" default(1.0.0)
+// @Param uploadID path string true "upload ID"
+// @Success 204
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "upload does not exist or it is expired"
+// @Failure 409 {string} string "upload is being written by another request"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/resumable/{uploadID} [DELETE].
+func (h Handler) abortResumableUpload(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	if sErr := h.svc.AbortResumableUpload(c.Request().Context(), types.ID(c.Param("uploadID")), claims.UserID); sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func setUploadHeaders(c echo.Context, resp service.ResumableUploadResponse) {
+	header := c.Response().Header()
+	header.Set(headerUploadOffset, strconv.FormatInt(resp.Offset, 10))
+	header.Set(headerUploadLength, strconv.FormatInt(resp.Size, 10))
+	header.Set(headerUploadExpires, resp.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// parseUploadMetadata parses the Upload-Metadata header, it is a comma separated list of a key and
+// a base64 encoded value, the value of a key without a value is empty.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+
+		value, dErr := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if dErr != nil {
+			return nil, dErr
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
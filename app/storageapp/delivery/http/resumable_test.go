@@ -0,0 +1,192 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const testMaxFileSize = 1024
+
+func newTestHandler() Handler {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.New(service.Config{MaxFileSize: testMaxFileSize}, nil, nil, logger)
+
+	return NewHandler(svc, nil, testMaxFileSize, nil, logger)
+}
+
+// serveTus serves the request by the handler behind the tus middleware, the request is authenticated as user-1.
+func serveTus(handler echo.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, error) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(string(types.UserContextKey), &types.UserClaims{UserID: "user-1"})
+
+	hErr := newTestHandler().tusResumable(handler)(c)
+
+	return rec, hErr
+}
+
+func TestTusResumable(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		version    string
+		wantStatus int
+	}{
+		{name: "supported version", method: http.MethodHead, version: tusVersion, wantStatus: http.StatusOK},
+		{name: "missing version", method: http.MethodHead, wantStatus: http.StatusPreconditionFailed},
+		{name: "unsupported version", method: http.MethodPatch, version: "0.2.2", wantStatus: http.StatusPreconditionFailed},
+		{name: "options without version", method: http.MethodOptions, wantStatus: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/files/resumable", nil)
+			if test.version != "" {
+				req.Header.Set(headerTusResumable, test.version)
+			}
+
+			rec, hErr := serveTus(func(c echo.Context) error { return c.NoContent(http.StatusOK) }, req)
+			if hErr != nil {
+				t.Fatalf("unexpected error: %v", hErr)
+			}
+
+			if rec.Code != test.wantStatus {
+				t.Fatalf("expected status %d, got %d", test.wantStatus, rec.Code)
+			}
+
+			if rec.Header().Get(headerTusResumable) != tusVersion {
+				t.Fatalf("expected the %s header in every response", headerTusResumable)
+			}
+		})
+	}
+}
+
+func TestResumableOptions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/files/resumable", nil)
+
+	rec, hErr := serveTus(newTestHandler().resumableOptions, req)
+	if hErr != nil {
+		t.Fatalf("unexpected error: %v", hErr)
+	}
+
+	if rec.Code != http.StatusNoContent || rec.Header().Get(headerTusMaxSize) != "1024" ||
+		rec.Header().Get(headerTusExtension) != tusExtensions {
+		t.Fatalf("unexpected options response %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestCreateResumableUploadValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     string
+		metadata   string
+		wantStatus int
+	}{
+		{name: "missing length", metadata: "filename bm90ZXMudHh0", wantStatus: http.StatusBadRequest},
+		{name: "zero length", length: "0", metadata: "filename bm90ZXMudHh0", wantStatus: http.StatusBadRequest},
+		{name: "too large", length: "1025", metadata: "filename bm90ZXMudHh0", wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "missing filename", length: "10", metadata: "purpose Z2VuZXJhbA==", wantStatus: http.StatusBadRequest},
+		{name: "invalid metadata", length: "10", metadata: "filename !!!", wantStatus: http.StatusBadRequest},
+		{name: "invalid is_public", length: "10", metadata: "filename bm90ZXMudHh0,is_public eWVz",
+			wantStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/files/resumable", nil)
+			req.Header.Set(headerTusResumable, tusVersion)
+			req.Header.Set(headerUploadLength, test.length)
+			req.Header.Set(headerUploadMetadata, test.metadata)
+
+			_, hErr := serveTus(newTestHandler().createResumableUpload, req)
+			assertHTTPStatus(t, hErr, test.wantStatus)
+		})
+	}
+}
+
+func TestWriteChunkValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		offset      string
+		wantStatus  int
+	}{
+		{name: "wrong content type", contentType: "application/octet-stream", offset: "0",
+			wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing offset", contentType: tusChunkContentType, wantStatus: http.StatusBadRequest},
+		{name: "negative offset", contentType: tusChunkContentType, offset: "-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/files/resumable/upload-1", strings.NewReader("chunk"))
+			req.Header.Set(headerTusResumable, tusVersion)
+			req.Header.Set(echo.HeaderContentType, test.contentType)
+			req.Header.Set(headerUploadOffset, test.offset)
+
+			_, hErr := serveTus(newTestHandler().writeChunk, req)
+			assertHTTPStatus(t, hErr, test.wantStatus)
+		})
+	}
+}
+
+func TestParseUploadMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{name: "empty", header: "", expected: map[string]string{}},
+		{name: "pairs", header: "filename bm90ZXMudHh0, purpose Z2VuZXJhbA==",
+			expected: map[string]string{"filename": "notes.txt", "purpose": "general"}},
+		{name: "key without value", header: "filename bm90ZXMudHh0,is_confidential",
+			expected: map[string]string{"filename": "notes.txt", "is_confidential": ""}},
+		{name: "invalid base64", header: "filename %%%", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata, pErr := parseUploadMetadata(test.header)
+			if test.wantErr {
+				if pErr == nil {
+					t.Fatalf("expected error, got %v", metadata)
+				}
+
+				return
+			}
+
+			if pErr != nil {
+				t.Fatalf("unexpected error: %v", pErr)
+			}
+
+			if len(metadata) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, metadata)
+			}
+
+			for key, value := range test.expected {
+				if metadata[key] != value {
+					t.Fatalf("expected %s=%q, got %q", key, value, metadata[key])
+				}
+			}
+		})
+	}
+}
+
+func assertHTTPStatus(t *testing.T, hErr error, status int) {
+	t.Helper()
+
+	var httpErr *echo.HTTPError
+	if !errors.As(hErr, &httpErr) || httpErr.Code != status {
+		t.Fatalf("expected http error %d, got %v", status, hErr)
+	}
+}
@@ -1,13 +1,23 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"path/filepath"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/pkg/auth"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
 )
 
+// ServeFile serves the files of the local driver and of the storage proxy. Requests with a signature are verified by
+// the url verifier, the other requests are served only for the public files, the object is opened after the access
+// to its file is checked. The ranges and the conditional requests
+// are handled by http.ServeContent with the ETag and the Last-Modified of the file.
 func (h Handler) ServeFile(c echo.Context) error {
 	relativePath := c.Param("*")
 
@@ -15,7 +25,62 @@ func (h Handler) ServeFile(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid path"})
 	}
 
-	fullPath := filepath.Join(h.localRootPath, relativePath)
+	signed := c.QueryParam("sig") != ""
+	if signed {
+		if h.urlVerifier == nil {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "signed urls are not supported"})
+		}
 
-	return c.File(fullPath)
+		var userID types.ID
+		if claims, cErr := auth.GetUserClaimFormContext(c); cErr == nil {
+			userID = claims.UserID
+		}
+
+		if vErr := h.urlVerifier.VerifySignedURL(relativePath, c.QueryParams(), userID); vErr != nil {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": vErr.Error()})
+		}
+	}
+
+	download, gErr := h.svc.GetDownload(c.Request().Context(), relativePath)
+
+	// a missing file gets the response of a private file, so the unsigned requests can't probe the keys.
+	if !signed && ((gErr == nil && !download.File.IsPublic) || isNotFound(gErr)) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "signature is required"})
+	}
+
+	if gErr != nil {
+		return servermsg.HTTPMsg(c, gErr, h.t)
+	}
+
+	download, oErr := h.svc.OpenDownload(c.Request().Context(), download)
+	if oErr != nil {
+		return servermsg.HTTPMsg(c, oErr, h.t)
+	}
+	defer func() {
+		if cErr := download.Content.Close(); cErr != nil {
+			h.logger.Error("can't close download content", slog.String("error", cErr.Error()))
+		}
+	}()
+
+	// the objects are not changed after they are stored, so the public files are cached as immutable.
+	cacheControl := fmt.Sprintf("private, max-age=%d", int64(download.MaxAge.Seconds()))
+	if download.File.IsPublic {
+		cacheControl = fmt.Sprintf("public, max-age=%d, immutable", int64(download.MaxAge.Seconds()))
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, download.MimeType)
+	header.Set("Cache-Control", cacheControl)
+	header.Set("ETag", fmt.Sprintf("%q", download.ETag))
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+
+	http.ServeContent(c.Response(), c.Request(), download.Key, download.ModTime, download.Content)
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var richErr richerror.RichError
+
+	return errors.As(err, &richErr) && richErr.Kind() == richerror.KindNotFound
 }
@@ -42,11 +42,22 @@ func (s Server) registerRoutes() {
 	s.httpServer.Router.GET("health-check", s.handler.healthCheck)
 
 	downloadGR := s.httpServer.Router.Group("downloads")
-	downloadGR.GET("/*", s.handler.ServeFile)
+	downloadGR.GET("/*", s.handler.ServeFile, s.authMid.OptionalAuth)
+	downloadGR.HEAD("/*", s.handler.ServeFile, s.authMid.OptionalAuth)
 
 	fileGR := s.httpServer.Router.Group("files")
+	fileGR.GET("/usage", s.handler.getStorageUsage, s.authMid.RequireAuth)
 	fileGR.GET("/:fileID", s.handler.getPublicLink)
 	fileGR.POST("", s.handler.upload, s.authMid.RequireAuth)
+	fileGR.POST("/direct", s.handler.createDirectUpload, s.authMid.RequireAuth)
+	fileGR.POST("/:fileID/complete", s.handler.completeDirectUpload, s.authMid.RequireAuth)
+
+	resumableGR := fileGR.Group("/resumable", s.handler.tusResumable)
+	resumableGR.OPTIONS("", s.handler.resumableOptions)
+	resumableGR.POST("", s.handler.createResumableUpload, s.authMid.RequireAuth)
+	resumableGR.HEAD("/:uploadID", s.handler.getResumableUpload, s.authMid.RequireAuth)
+	resumableGR.PATCH("/:uploadID", s.handler.writeChunk, s.authMid.RequireAuth)
+	resumableGR.DELETE("/:uploadID", s.handler.abortResumableUpload, s.authMid.RequireAuth)
 }
 
 func (s Server) registerSwagger() {
@@ -19,9 +19,11 @@ import (
 // @Produce json
 // @Param file formData file true "The file to upload"
 // @Param is_public formData boolean false "Is the file public?"
+// @Param purpose formData string false "Purpose of the file, the allowed file types depend on it" Enums(general, story, chat)
 // @Success 201 {object} service.File
-// @Failure 400 {string} string "File is required"
+// @Failure 400 {string} string "File is required, or the file type is not allowed"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Storage quota exceeded"
 // @Failure 413 {string} string "File size limit exceeded"
 // @Failure 500 {string} string something went wrong
 // @Security JWT
@@ -56,18 +58,14 @@ func (h Handler) upload(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "is_public is not valid")
 	}
 
-	contentType := fileHeader.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
 	resp, sErr := h.svc.Upload(c.Request().Context(), service.UploadRequest{
-		UploaderID:  claims.UserID,
-		File:        src,
-		Filename:    fileHeader.Filename,
-		Size:        fileHeader.Size,
-		ContentType: contentType,
-		IsPublic:    isPublic,
+		UploaderID:    claims.UserID,
+		UploaderRoles: claims.Roles,
+		File:          src,
+		Filename:      fileHeader.Filename,
+		Size:          fileHeader.Size,
+		IsPublic:      isPublic,
+		Purpose:       service.UploadPurpose(c.FormValue("purpose")),
 	})
 
 	if sErr != nil {
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/syntaxfa/quick-connect/pkg/auth"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+// getStorageUsage docs
+// @Summary Get storage usage
+// @Description Returns the size and the number of the files of the user, limit is the storage quota of the user
+// @Description and it is zero when the user is not limited.
+// @Tags Storage
+// @Produce json
+// @Success 200 {object} service.StorageUsage
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string something went wrong
+// @Security JWT
+// @Router /files/usage [GET].
+func (h Handler) getStorageUsage(c echo.Context) error {
+	claims, cErr := auth.GetUserClaimFormContext(c)
+	if cErr != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, cErr.Error())
+	}
+
+	resp, sErr := h.svc.GetStorageUsage(c.Request().Context(), claims.UserID, claims.Roles)
+	if sErr != nil {
+		return servermsg.HTTPMsg(c, sErr, h.t)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -46,6 +46,17 @@ const docTemplatestorage = `{
                         "description": "Is the file public?",
                         "name": "is_public",
                         "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "general",
+                            "story",
+                            "chat"
+                        ],
+                        "type": "string",
+                        "description": "Purpose of the file, the allowed file types depend on it",
+                        "name": "purpose",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -56,7 +67,149 @@ const docTemplatestorage = `{
                         }
                     },
                     "400": {
-                        "description": "File is required",
+                        "description": "File is required, or the file type is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "File size limit exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/direct": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a pending file and returns the presigned request that uploads the file directly to the storage.\nThe file must be sent with the method, the url and the headers of the upload, then the upload is completed\nby /files/{fileID}/complete. It is only supported by the S3 driver.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Create a direct upload",
+                "parameters": [
+                    {
+                        "description": "filename, size and content type of the file, sha256 is optional",
+                        "name": "Request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateDirectUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service.DirectUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "the request is not valid, the file type is not allowed or direct upload is not supported",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/resumable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a tus resumable upload, the chunks are sent to the Location of the response.\nThe id of the upload is the id of the file when the upload is completed.\nUpload-Metadata has the base64 encoded filename, is_public and purpose of the file.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Create a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "tus protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size of the file",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filename, is_public and purpose, e.g. filename dmlkZW8ubXA0,purpose c3Rvcnk=",
+                        "name": "Upload-Metadata",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service.ResumableUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Upload-Length or Upload-Metadata is not valid",
                         "schema": {
                             "type": "string"
                         }
@@ -67,6 +220,12 @@ const docTemplatestorage = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Storage quota exceeded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "413": {
                         "description": "File size limit exceeded",
                         "schema": {
@@ -80,6 +239,247 @@ const docTemplatestorage = `{
                         }
                     }
                 }
+            },
+            "options": {
+                "description": "Returns the tus protocol version, extensions and the maximum size of the resumable uploads",
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Resumable upload options",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/files/resumable/{uploadID}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Removes the upload and its uploaded chunks",
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Abort a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "tus protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload ID",
+                        "name": "uploadID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "upload does not exist or it is expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "upload is being written by another request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Returns the uploaded size of the upload in the Upload-Offset header, the upload is resumed from it",
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get the offset of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "tus protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload ID",
+                        "name": "uploadID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "upload does not exist or it is expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Appends the body to the upload at Upload-Offset, the file is created when the last chunk is uploaded",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload a chunk of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "tus protocol version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Uploaded size of the file",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upload ID",
+                        "name": "uploadID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Upload-Offset is not valid, or the file type is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "upload does not exist or it is expired",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload-Offset does not match the uploaded size",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/offset+octet-stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/usage": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Returns the size and the number of the files of the user, limit is the storage quota of the user\nand it is zero when the user is not limited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get storage usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.StorageUsage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
             }
         },
         "/files/{fileID}": {
@@ -107,6 +507,12 @@ const docTemplatestorage = `{
                         "name": "fileID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of the image variant, such as thumbnail, the original file is returned when the variant does not exist",
+                        "name": "variant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -125,6 +531,70 @@ const docTemplatestorage = `{
                 }
             }
         },
+        "/files/{fileID}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Checks the file that is uploaded to the storage and marks it ready, a file that is not allowed is removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Complete a direct upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file ID",
+                        "name": "fileID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.File"
+                        }
+                    },
+                    "400": {
+                        "description": "the file is not uploaded, it does not match the upload or the file type is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "file not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the upload is being completed by another request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/health-check": {
             "get": {
                 "description": "health check manager service",
@@ -156,6 +626,40 @@ const docTemplatestorage = `{
         }
     },
     "definitions": {
+        "service.CreateDirectUploadRequest": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "purpose": {
+                    "$ref": "#/definitions/service.UploadPurpose"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.DirectUploadResponse": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "$ref": "#/definitions/service.File"
+                },
+                "upload": {
+                    "$ref": "#/definitions/service.PresignedUpload"
+                }
+            }
+        },
         "service.Driver": {
             "type": "string",
             "enum": [
@@ -200,17 +704,128 @@ const docTemplatestorage = `{
                 "name": {
                     "type": "string"
                 },
+                "purpose": {
+                    "$ref": "#/definitions/service.UploadPurpose"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/service.FileStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uploader_id": {
+                    "$ref": "#/definitions/types.ID"
+                }
+            }
+        },
+        "service.FileStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "ready"
+            ],
+            "x-enum-varnames": [
+                "FileStatusPending",
+                "FileStatusReady"
+            ]
+        },
+        "service.PresignedUpload": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ResumableUploadResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "file": {
+                    "$ref": "#/definitions/service.File"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "$ref": "#/definitions/types.ID"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "purpose": {
+                    "$ref": "#/definitions/service.UploadPurpose"
+                },
                 "size": {
                     "type": "integer"
                 },
+                "uploader_id": {
+                    "$ref": "#/definitions/types.ID"
+                }
+            }
+        },
+        "service.StorageUsage": {
+            "type": "object",
+            "properties": {
+                "file_count": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 },
                 "uploader_id": {
                     "$ref": "#/definitions/types.ID"
+                },
+                "used_bytes": {
+                    "type": "integer"
                 }
             }
         },
+        "service.UploadPurpose": {
+            "type": "string",
+            "enum": [
+                "general",
+                "story",
+                "chat"
+            ],
+            "x-enum-varnames": [
+                "UploadPurposeGeneral",
+                "UploadPurposeStory",
+                "UploadPurposeChat"
+            ]
+        },
         "types.ID": {
             "type": "string",
             "enum": [
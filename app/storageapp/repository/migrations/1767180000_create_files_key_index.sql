@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_files_key ON files(key);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_files_key;
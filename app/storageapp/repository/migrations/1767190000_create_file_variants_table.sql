@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS file_variants (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "file_id" VARCHAR(26) NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    "name" VARCHAR(50) NOT NULL,
+    "key" VARCHAR(512) NOT NULL,
+    "mime_type" VARCHAR(100) NOT NULL,
+    "size" BIGINT NOT NULL,
+    "width" INT NOT NULL,
+    "height" INT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (file_id, name)
+);
+CREATE INDEX idx_file_variants_key ON file_variants(key);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_file_variants_key;
+DROP TABLE IF EXISTS file_variants;
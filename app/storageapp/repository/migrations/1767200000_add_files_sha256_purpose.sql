@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE files ADD COLUMN IF NOT EXISTS "sha256" VARCHAR(64) NULL;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS "purpose" VARCHAR(20) NOT NULL DEFAULT 'general';
+
+-- +migrate Down
+ALTER TABLE files DROP COLUMN IF EXISTS "purpose";
+ALTER TABLE files DROP COLUMN IF EXISTS "sha256";
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "uploader_id" VARCHAR(26) NOT NULL,
+    "filename" VARCHAR(255) NOT NULL,
+    "key" VARCHAR(512) NOT NULL,
+    "storage_upload_id" VARCHAR(1024) NOT NULL,
+    "size" BIGINT NOT NULL,
+    "is_public" BOOLEAN NOT NULL DEFAULT false,
+    "purpose" VARCHAR(20) NOT NULL DEFAULT 'general',
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_upload_sessions_expires_at ON upload_sessions(expires_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_upload_sessions_expires_at;
+DROP TABLE IF EXISTS upload_sessions;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE files ADD COLUMN IF NOT EXISTS "status" VARCHAR(20) NOT NULL DEFAULT 'ready';
+
+-- +migrate Down
+ALTER TABLE files DROP COLUMN IF EXISTS "status";
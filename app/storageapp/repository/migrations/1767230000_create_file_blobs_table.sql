@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS file_blobs (
+    "key" VARCHAR(512) PRIMARY KEY,
+    "driver" files_driver NOT NULL,
+    "bucket" VARCHAR(100) NOT NULL DEFAULT '',
+    "sha256" VARCHAR(64) NOT NULL,
+    "size" BIGINT NOT NULL,
+    "is_public" BOOLEAN NOT NULL DEFAULT false,
+    "ref_count" INT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (driver, bucket, sha256, is_public)
+);
+CREATE INDEX idx_file_blobs_released ON file_blobs(key) WHERE ref_count = 0;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_file_blobs_released;
+DROP TABLE IF EXISTS file_blobs;
@@ -0,0 +1,47 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS storage_usages (
+    "uploader_id" VARCHAR(26) PRIMARY KEY,
+    "used_bytes" BIGINT NOT NULL DEFAULT 0,
+    "file_count" BIGINT NOT NULL DEFAULT 0,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- the usage of an uploader is the size of its files that are not deleted, including the pending files.
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION update_storage_usage()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        UPDATE storage_usages
+        SET used_bytes = used_bytes - OLD.size, file_count = file_count - 1, updated_at = NOW()
+        WHERE uploader_id = OLD.uploader_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO storage_usages (uploader_id, used_bytes, file_count)
+        VALUES (NEW.uploader_id, NEW.size, 1)
+        ON CONFLICT (uploader_id) DO UPDATE
+        SET used_bytes = storage_usages.used_bytes + EXCLUDED.used_bytes, file_count = storage_usages.file_count + 1,
+        updated_at = NOW();
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER files_storage_usage
+    AFTER INSERT OR DELETE OR UPDATE OF uploader_id, size, deleted_at ON files
+    FOR EACH ROW
+    EXECUTE FUNCTION update_storage_usage();
+
+INSERT INTO storage_usages (uploader_id, used_bytes, file_count)
+SELECT uploader_id, SUM(size), COUNT(*)
+FROM files
+WHERE deleted_at IS NULL
+GROUP BY uploader_id;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS files_storage_usage ON files;
+DROP FUNCTION IF EXISTS update_storage_usage();
+DROP TABLE IF EXISTS storage_usages;
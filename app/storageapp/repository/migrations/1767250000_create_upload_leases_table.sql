@@ -0,0 +1,13 @@
+-- +migrate Up
+-- the lease of an upload is taken while a chunk of a resumable upload is written or a direct upload is completed,
+-- a lease that is not released or renewed expires at its locked_until.
+CREATE TABLE IF NOT EXISTS upload_leases (
+    "upload_id" VARCHAR(26) PRIMARY KEY,
+    "token" VARCHAR(26) NOT NULL,
+    "locked_until" TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_upload_leases_locked_until ON upload_leases(locked_until);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_upload_leases_locked_until;
+DROP TABLE IF EXISTS upload_leases;
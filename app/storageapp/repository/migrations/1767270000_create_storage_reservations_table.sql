@@ -0,0 +1,16 @@
+-- +migrate Up
+-- a reservation holds the quota of an upload until its file is saved, so the concurrent uploads of an uploader can't
+-- exceed its quota. The reservations that are not removed, e.g. the instance is stopped, are ignored when they expire.
+CREATE TABLE IF NOT EXISTS storage_reservations (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "uploader_id" VARCHAR(26) NOT NULL,
+    "size" BIGINT NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_storage_reservations_uploader_id ON storage_reservations(uploader_id);
+CREATE INDEX idx_storage_reservations_expires_at ON storage_reservations(expires_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_storage_reservations_expires_at;
+DROP INDEX IF EXISTS idx_storage_reservations_uploader_id;
+DROP TABLE IF EXISTS storage_reservations;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- each running storage instance renews its row, so the driver migration can check that no instance is running.
+CREATE TABLE IF NOT EXISTS storage_instances (
+    "id" VARCHAR(26) PRIMARY KEY,
+    "seen_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_storage_instances_seen_at ON storage_instances(seen_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_storage_instances_seen_at;
+DROP TABLE IF EXISTS storage_instances;
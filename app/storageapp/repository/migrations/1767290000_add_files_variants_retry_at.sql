@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE files ADD COLUMN IF NOT EXISTS "variants_retry_at" TIMESTAMPTZ NULL;
+CREATE INDEX IF NOT EXISTS idx_files_variants_retry_at ON files(variants_retry_at) WHERE variants_retry_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_files_variants_retry_at;
+ALTER TABLE files DROP COLUMN IF EXISTS "variants_retry_at";
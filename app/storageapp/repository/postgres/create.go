@@ -2,26 +2,230 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
 )
 
-const querySave = `INSERT INTO files (id, uploader_id, name, key, mime_type, size, driver, bucket, is_public)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+const querySave = `INSERT INTO files (id, uploader_id, name, key, mime_type, size, driver, bucket, is_public, sha256, purpose, status,
+variants_retry_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);`
 
 func (d *DB) Save(ctx context.Context, file service.File) error {
 	const op = "repository.postgres.create.Save"
 
+	if _, exErr := d.conn.Conn().Exec(ctx, querySave, fileArgs(file)...); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithMessage("can't insert file").WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+func fileArgs(file service.File) []any {
 	var nullable nullableFields
 	if file.Bucket != "" {
 		nullable.Bucket.String = file.Bucket
 		nullable.Bucket.Valid = true
 	}
+	if file.SHA256 != "" {
+		nullable.SHA256.String = file.SHA256
+		nullable.SHA256.Valid = true
+	}
+	if file.VariantsRetryAt != nil {
+		nullable.VariantsRetryAt.Time = *file.VariantsRetryAt
+		nullable.VariantsRetryAt.Valid = true
+	}
 
-	if _, exErr := d.conn.Conn().Exec(ctx, querySave, file.ID, file.UploaderID, file.Name, file.Key, file.MimeType,
-		file.Size, file.Driver, nullable.Bucket, file.IsPublic); exErr != nil {
-		return richerror.New(op).WithWrapError(exErr).WithMessage("can't insert file").WithKind(richerror.KindUnexpected)
+	return []any{file.ID, file.UploaderID, file.Name, file.Key, file.MimeType, file.Size, file.Driver, nullable.Bucket,
+		file.IsPublic, nullable.SHA256, file.Purpose, file.Status, nullable.VariantsRetryAt}
+}
+
+// SaveWithBlob saves the file with a reference to the blob of its content and returns the key of the file, it is the
+// key of an identical blob when it exists, otherwise the object of the file is saved as a new blob.
+func (d *DB) SaveWithBlob(ctx context.Context, file service.File) (string, error) {
+	const op = "repository.postgres.create.SaveWithBlob"
+
+	tx, bErr := d.conn.Conn().Begin(ctx)
+	if bErr != nil {
+		return "", richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	key, aErr := acquireBlob(ctx, tx, file)
+	if aErr == nil {
+		file.Key = key
+		_, aErr = tx.Exec(ctx, querySave, fileArgs(file)...)
+	}
+	if aErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return "", richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return "", richerror.New(op).WithWrapError(aErr).WithMessage("can't insert file").WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return "", richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return key, nil
+}
+
+// queryAcquireBlob a released blob is not acquired, because its object is being removed.
+const queryAcquireBlob = `UPDATE file_blobs
+SET ref_count = ref_count + 1, updated_at = NOW()
+WHERE driver = $1 AND bucket = $2 AND sha256 = $3 AND is_public = $4 AND ref_count > 0
+RETURNING key;`
+
+const querySaveBlob = `INSERT INTO file_blobs (key, driver, bucket, sha256, size, is_public, ref_count)
+VALUES ($1, $2, $3, $4, $5, $6, 1)
+ON CONFLICT DO NOTHING;`
+
+// acquireBlob takes a reference of the blob of the content of the file and returns its key. The object of the file
+// is saved as a new blob when an identical blob doesn't exist, and the file owns its object when the blob of another
+// upload or a released blob has the same content. The files without a checksum own their object too.
+func acquireBlob(ctx context.Context, tx pgx.Tx, file service.File) (string, error) {
+	if file.SHA256 == "" {
+		return file.Key, nil
+	}
+
+	var key string
+	qErr := tx.QueryRow(ctx, queryAcquireBlob, file.Driver, file.Bucket, file.SHA256, file.IsPublic).Scan(&key)
+	if qErr == nil {
+		return key, nil
+	}
+
+	if !errors.Is(qErr, pgx.ErrNoRows) {
+		return "", qErr
+	}
+
+	if _, eErr := tx.Exec(ctx, querySaveBlob, file.Key, file.Driver, file.Bucket, file.SHA256, file.Size,
+		file.IsPublic); eErr != nil {
+		return "", eErr
+	}
+
+	return file.Key, nil
+}
+
+const querySaveVariant = `INSERT INTO file_variants (id, file_id, name, key, mime_type, size, width, height, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (file_id, name) DO UPDATE
+SET key = EXCLUDED.key, mime_type = EXCLUDED.mime_type, size = EXCLUDED.size, width = EXCLUDED.width,
+height = EXCLUDED.height, created_at = EXCLUDED.created_at;`
+
+// SaveVariant replaces the variant of the file with the same name.
+func (d *DB) SaveVariant(ctx context.Context, variant service.FileVariant) error {
+	const op = "repository.postgres.create.SaveVariant"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, querySaveVariant, variant.ID, variant.FileID, variant.Name, variant.Key,
+		variant.MimeType, variant.Size, variant.Width, variant.Height, variant.CreatedAt); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithMessage("can't insert file variant").WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const querySaveUploadSession = `INSERT INTO upload_sessions (id, uploader_id, filename, key, storage_upload_id, size, is_public,
+purpose, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+
+func (d *DB) SaveUploadSession(ctx context.Context, session service.UploadSession) error {
+	const op = "repository.postgres.create.SaveUploadSession"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, querySaveUploadSession, session.ID, session.UploaderID, session.Filename, session.Key,
+		session.StorageUploadID, session.Size, session.IsPublic, session.Purpose, session.ExpiresAt, session.CreatedAt); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithMessage("can't insert upload session").WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+// queryLockStorageUsage locks the usage of the uploader, so the reservations of an uploader are checked one by one.
+const queryLockStorageUsage = `INSERT INTO storage_usages (uploader_id)
+VALUES ($1)
+ON CONFLICT (uploader_id) DO UPDATE
+SET uploader_id = EXCLUDED.uploader_id
+RETURNING used_bytes;`
+
+const queryGetReservedBytes = `SELECT COALESCE(SUM(size), 0)
+FROM storage_reservations
+WHERE uploader_id = $1 AND expires_at > NOW();`
+
+const querySaveStorageReservation = `INSERT INTO storage_reservations (id, uploader_id, size, expires_at)
+VALUES ($1, $2, $3, $4);`
+
+// ReserveStorage saves the reservation when the usage of the uploader with its reservations and the reservation
+// doesn't exceed the limit, it returns false otherwise. The usage row is locked, so the concurrent reservations of
+// an uploader see each other.
+func (d *DB) ReserveStorage(ctx context.Context, reservation service.StorageReservation, limit int64) (bool, error) {
+	const op = "repository.postgres.create.ReserveStorage"
+
+	tx, bErr := d.conn.Conn().Begin(ctx)
+	if bErr != nil {
+		return false, richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	reserved, rErr := reserveStorage(ctx, tx, reservation, limit)
+	if rErr != nil || !reserved {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return false, richerror.New(op).WithWrapError(rbErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if rErr != nil {
+			return false, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return false, nil
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return false, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return true, nil
+}
+
+// reserveStorage the reserved bytes are read after the usage is locked, so they include the committed reservations
+// of the transactions that held the lock before.
+func reserveStorage(ctx context.Context, tx pgx.Tx, reservation service.StorageReservation, limit int64) (bool, error) {
+	var used, reserved int64
+	if qErr := tx.QueryRow(ctx, queryLockStorageUsage, reservation.UploaderID).Scan(&used); qErr != nil {
+		return false, qErr
+	}
+
+	if qErr := tx.QueryRow(ctx, queryGetReservedBytes, reservation.UploaderID).Scan(&reserved); qErr != nil {
+		return false, qErr
+	}
+
+	if used+reserved+reservation.Size > limit {
+		return false, nil
+	}
+
+	if _, eErr := tx.Exec(ctx, querySaveStorageReservation, reservation.ID, reservation.UploaderID, reservation.Size,
+		reservation.ExpiresAt); eErr != nil {
+		return false, eErr
+	}
+
+	return true, nil
+}
+
+const querySaveInstanceHeartbeat = `INSERT INTO storage_instances (id, seen_at)
+VALUES ($1, NOW())
+ON CONFLICT (id) DO UPDATE
+SET seen_at = EXCLUDED.seen_at;`
+
+// SaveInstanceHeartbeat records that the instance is running now.
+func (d *DB) SaveInstanceHeartbeat(ctx context.Context, instanceID types.ID) error {
+	const op = "repository.postgres.create.SaveInstanceHeartbeat"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, querySaveInstanceHeartbeat, instanceID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 	"github.com/syntaxfa/quick-connect/types"
@@ -23,3 +24,110 @@ func (d *DB) IsExistByID(ctx context.Context, fileID types.ID) (bool, error) {
 
 	return exists, nil
 }
+
+const queryIsExistByKey = `SELECT EXISTS (
+	SELECT 1
+	FROM files
+	WHERE key = $1
+) OR EXISTS (
+	SELECT 1
+	FROM file_variants
+	WHERE key = $1
+);`
+
+func (d *DB) IsExistByKey(ctx context.Context, key string) (bool, error) {
+	const op = "repository.postgres.exist.IsExistByKey"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistByKey, key).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistVariant = `SELECT EXISTS (
+	SELECT 1
+	FROM file_variants
+	WHERE file_id = $1 AND name = $2
+);`
+
+func (d *DB) IsExistVariant(ctx context.Context, fileID types.ID, name string) (bool, error) {
+	const op = "repository.postgres.exist.IsExistVariant"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistVariant, fileID, name).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistVariantByKey = `SELECT EXISTS (
+	SELECT 1
+	FROM file_variants
+	WHERE key = $1
+);`
+
+func (d *DB) IsExistVariantByKey(ctx context.Context, key string) (bool, error) {
+	const op = "repository.postgres.exist.IsExistVariantByKey"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistVariantByKey, key).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistUploadSession = `SELECT EXISTS (
+	SELECT 1
+	FROM upload_sessions
+	WHERE id = $1
+);`
+
+func (d *DB) IsExistUploadSession(ctx context.Context, uploadID types.ID) (bool, error) {
+	const op = "repository.postgres.exist.IsExistUploadSession"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistUploadSession, uploadID).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistBlob = `SELECT EXISTS (
+	SELECT 1
+	FROM file_blobs
+	WHERE key = $1
+);`
+
+func (d *DB) IsExistBlob(ctx context.Context, key string) (bool, error) {
+	const op = "repository.postgres.exist.IsExistBlob"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistBlob, key).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
+
+const queryIsExistLiveInstance = `SELECT EXISTS (
+	SELECT 1
+	FROM storage_instances
+	WHERE seen_at >= $1
+);`
+
+// IsExistLiveInstance reports whether an instance has recorded a heartbeat since seenAfter.
+func (d *DB) IsExistLiveInstance(ctx context.Context, seenAfter time.Time) (bool, error) {
+	const op = "repository.postgres.exist.IsExistLiveInstance"
+
+	var exists bool
+	if qErr := d.conn.Conn().QueryRow(ctx, queryIsExistLiveInstance, seenAfter).Scan(&exists); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return exists, nil
+}
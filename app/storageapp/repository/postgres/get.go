@@ -2,14 +2,18 @@ package postgres
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 	"github.com/syntaxfa/quick-connect/types"
 )
 
-const queryGetByID = `SELECT id, uploader_id, name, key, mime_type, size, driver,
-bucket, is_public, is_confirmed, created_at, updated_at, deleted_at
+const fileFields = `id, uploader_id, name, key, mime_type, size, driver,
+bucket, is_public, sha256, purpose, status, is_confirmed, created_at, updated_at, deleted_at`
+
+const queryGetByID = `SELECT ` + fileFields + `
 FROM files
 WHERE id = $1
 LIMIT 1;`
@@ -17,13 +21,42 @@ LIMIT 1;`
 func (d *DB) GetByID(ctx context.Context, fileID types.ID) (service.File, error) {
 	const op = "repository.postgres.get.GetByID"
 
+	file, sErr := scanFile(d.conn.Conn().QueryRow(ctx, queryGetByID, fileID))
+	if sErr != nil {
+		return service.File{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return file, nil
+}
+
+// queryGetByKey the file of a variant key is the parent file of the variant, a not deleted file is preferred
+// when the files share the key of a blob.
+const queryGetByKey = `SELECT ` + fileFields + `
+FROM files
+WHERE key = $1 OR id = (SELECT file_id FROM file_variants WHERE key = $1 LIMIT 1)
+ORDER BY deleted_at IS NOT NULL
+LIMIT 1;`
+
+func (d *DB) GetByKey(ctx context.Context, key string) (service.File, error) {
+	const op = "repository.postgres.get.GetByKey"
+
+	file, sErr := scanFile(d.conn.Conn().QueryRow(ctx, queryGetByKey, key))
+	if sErr != nil {
+		return service.File{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return file, nil
+}
+
+// scanFile scans a row selected with fileFields.
+func scanFile(row pgx.Row) (service.File, error) {
 	var file service.File
 	var nullable nullableFields
 
-	if sErr := d.conn.Conn().QueryRow(ctx, queryGetByID, fileID).Scan(&file.ID, &file.UploaderID, &file.Name,
-		&file.Key, &file.MimeType, &file.Size, &file.Driver, &nullable.Bucket, &file.IsPublic, &file.IsConfirmed,
-		&file.CreatedAt, &file.UpdatedAt, &nullable.DeletedAt); sErr != nil {
-		return service.File{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	if sErr := row.Scan(&file.ID, &file.UploaderID, &file.Name, &file.Key, &file.MimeType, &file.Size, &file.Driver,
+		&nullable.Bucket, &file.IsPublic, &nullable.SHA256, &file.Purpose, &file.Status, &file.IsConfirmed, &file.CreatedAt,
+		&file.UpdatedAt, &nullable.DeletedAt); sErr != nil {
+		return service.File{}, sErr
 	}
 
 	if nullable.Bucket.Valid {
@@ -32,6 +65,342 @@ func (d *DB) GetByID(ctx context.Context, fileID types.ID) (service.File, error)
 	if nullable.DeletedAt.Valid {
 		file.DeletedAt = &nullable.DeletedAt.Time
 	}
+	if nullable.SHA256.Valid {
+		file.SHA256 = nullable.SHA256.String
+	}
 
 	return file, nil
 }
+
+const queryGetUnconfirmedFiles = `SELECT ` + fileFields + `
+FROM files
+WHERE is_confirmed IS false AND deleted_at IS NULL AND created_at < $1 AND id > $2
+ORDER BY id
+LIMIT $3;`
+
+// GetUnconfirmedFiles returns the files that are not confirmed and are created before the time, the files are
+// ordered by id and start after afterID.
+func (d *DB) GetUnconfirmedFiles(ctx context.Context, createdBefore time.Time, afterID types.ID, limit int) ([]service.File, error) {
+	const op = "repository.postgres.get.GetUnconfirmedFiles"
+
+	files, qErr := d.queryFiles(ctx, queryGetUnconfirmedFiles, createdBefore, afterID, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return files, nil
+}
+
+const queryGetDeletedFiles = `SELECT ` + fileFields + `
+FROM files
+WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND id > $2
+ORDER BY id
+LIMIT $3;`
+
+// GetDeletedFiles returns the files that are deleted before the time, the files are ordered by id
+// and start after afterID.
+func (d *DB) GetDeletedFiles(ctx context.Context, deletedBefore time.Time, afterID types.ID, limit int) ([]service.File, error) {
+	const op = "repository.postgres.get.GetDeletedFiles"
+
+	files, qErr := d.queryFiles(ctx, queryGetDeletedFiles, deletedBefore, afterID, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return files, nil
+}
+
+// queryGetFilesByDriver selects a file of each object, the files of a blob share its object.
+const queryGetFilesByDriver = `SELECT DISTINCT ON (key) ` + fileFields + `
+FROM files
+WHERE driver = $1 AND ($2 = '' OR bucket = $2) AND status = 'ready' AND key > $3
+ORDER BY key, deleted_at IS NOT NULL, id
+LIMIT $4;`
+
+// GetFilesByDriver returns a ready file of each object that is stored with the driver, the files are ordered by key
+// and start after afterKey. The files of all the buckets are returned when bucket is empty.
+func (d *DB) GetFilesByDriver(ctx context.Context, driver service.Driver, bucket, afterKey string, limit int) ([]service.File, error) {
+	const op = "repository.postgres.get.GetFilesByDriver"
+
+	files, qErr := d.queryFiles(ctx, queryGetFilesByDriver, driver, bucket, afterKey, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return files, nil
+}
+
+func (d *DB) queryFiles(ctx context.Context, query string, args ...any) ([]service.File, error) {
+	rows, qErr := d.conn.Conn().Query(ctx, query, args...)
+	if qErr != nil {
+		return nil, qErr
+	}
+	defer rows.Close()
+
+	files := make([]service.File, 0)
+	for rows.Next() {
+		file, sErr := scanFile(rows)
+		if sErr != nil {
+			return nil, sErr
+		}
+
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+const variantFields = `id, file_id, name, key, mime_type, size, width, height, created_at`
+
+const queryGetVariant = `SELECT ` + variantFields + `
+FROM file_variants
+WHERE file_id = $1 AND name = $2
+LIMIT 1;`
+
+func (d *DB) GetVariant(ctx context.Context, fileID types.ID, name string) (service.FileVariant, error) {
+	const op = "repository.postgres.get.GetVariant"
+
+	var variant service.FileVariant
+	if sErr := d.conn.Conn().QueryRow(ctx, queryGetVariant, fileID, name).Scan(&variant.ID, &variant.FileID, &variant.Name,
+		&variant.Key, &variant.MimeType, &variant.Size, &variant.Width, &variant.Height, &variant.CreatedAt); sErr != nil {
+		return service.FileVariant{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return variant, nil
+}
+
+const queryGetVariantByKey = `SELECT ` + variantFields + `
+FROM file_variants
+WHERE key = $1
+LIMIT 1;`
+
+func (d *DB) GetVariantByKey(ctx context.Context, key string) (service.FileVariant, error) {
+	const op = "repository.postgres.get.GetVariantByKey"
+
+	var variant service.FileVariant
+	if sErr := d.conn.Conn().QueryRow(ctx, queryGetVariantByKey, key).Scan(&variant.ID, &variant.FileID, &variant.Name,
+		&variant.Key, &variant.MimeType, &variant.Size, &variant.Width, &variant.Height, &variant.CreatedAt); sErr != nil {
+		return service.FileVariant{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return variant, nil
+}
+
+const queryGetVariantsByFileID = `SELECT ` + variantFields + `
+FROM file_variants
+WHERE file_id = $1
+ORDER BY name;`
+
+func (d *DB) GetVariantsByFileID(ctx context.Context, fileID types.ID) ([]service.FileVariant, error) {
+	const op = "repository.postgres.get.GetVariantsByFileID"
+
+	variants, qErr := d.queryVariants(ctx, queryGetVariantsByFileID, fileID)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return variants, nil
+}
+
+const queryGetVariantsByFileKey = `SELECT ` + variantFields + `
+FROM file_variants
+WHERE file_id IN (SELECT id FROM files WHERE driver = $1 AND key = $2)
+ORDER BY key;`
+
+// GetVariantsByFileKey returns the variants of the files of the driver that share the object of the key.
+func (d *DB) GetVariantsByFileKey(ctx context.Context, driver service.Driver, key string) ([]service.FileVariant, error) {
+	const op = "repository.postgres.get.GetVariantsByFileKey"
+
+	variants, qErr := d.queryVariants(ctx, queryGetVariantsByFileKey, driver, key)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return variants, nil
+}
+
+func (d *DB) queryVariants(ctx context.Context, query string, args ...any) ([]service.FileVariant, error) {
+	rows, qErr := d.conn.Conn().Query(ctx, query, args...)
+	if qErr != nil {
+		return nil, qErr
+	}
+	defer rows.Close()
+
+	variants := make([]service.FileVariant, 0)
+	for rows.Next() {
+		var variant service.FileVariant
+		if sErr := rows.Scan(&variant.ID, &variant.FileID, &variant.Name, &variant.Key, &variant.MimeType, &variant.Size,
+			&variant.Width, &variant.Height, &variant.CreatedAt); sErr != nil {
+			return nil, sErr
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+const uploadSessionFields = `id, uploader_id, filename, key, storage_upload_id, size, is_public, purpose, expires_at, created_at`
+
+const queryGetUploadSession = `SELECT ` + uploadSessionFields + `
+FROM upload_sessions
+WHERE id = $1
+LIMIT 1;`
+
+func (d *DB) GetUploadSession(ctx context.Context, uploadID types.ID) (service.UploadSession, error) {
+	const op = "repository.postgres.get.GetUploadSession"
+
+	session, sErr := scanUploadSession(d.conn.Conn().QueryRow(ctx, queryGetUploadSession, uploadID))
+	if sErr != nil {
+		return service.UploadSession{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return session, nil
+}
+
+const queryGetExpiredUploadSessions = `SELECT ` + uploadSessionFields + `
+FROM upload_sessions
+WHERE expires_at < $1 AND id > $2
+ORDER BY id
+LIMIT $3;`
+
+// GetExpiredUploadSessions returns the upload sessions that are expired before the time, the sessions are ordered
+// by id and start after afterID.
+func (d *DB) GetExpiredUploadSessions(ctx context.Context, expiredBefore time.Time, afterID types.ID,
+	limit int) ([]service.UploadSession, error) {
+	const op = "repository.postgres.get.GetExpiredUploadSessions"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetExpiredUploadSessions, expiredBefore, afterID, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	sessions := make([]service.UploadSession, 0)
+	for rows.Next() {
+		session, sErr := scanUploadSession(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return sessions, nil
+}
+
+// scanUploadSession scans a row selected with uploadSessionFields.
+func scanUploadSession(row pgx.Row) (service.UploadSession, error) {
+	var session service.UploadSession
+
+	if sErr := row.Scan(&session.ID, &session.UploaderID, &session.Filename, &session.Key, &session.StorageUploadID,
+		&session.Size, &session.IsPublic, &session.Purpose, &session.ExpiresAt, &session.CreatedAt); sErr != nil {
+		return service.UploadSession{}, sErr
+	}
+
+	return session, nil
+}
+
+const blobFields = `key, driver, bucket, sha256, size, is_public, ref_count, created_at, updated_at`
+
+const queryGetBlob = `SELECT ` + blobFields + `
+FROM file_blobs
+WHERE key = $1
+LIMIT 1;`
+
+func (d *DB) GetBlob(ctx context.Context, key string) (service.Blob, error) {
+	const op = "repository.postgres.get.GetBlob"
+
+	blob, sErr := scanBlob(d.conn.Conn().QueryRow(ctx, queryGetBlob, key))
+	if sErr != nil {
+		return service.Blob{}, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return blob, nil
+}
+
+const queryGetReleasedBlobs = `SELECT ` + blobFields + `
+FROM file_blobs
+WHERE ref_count = 0 AND key > $1
+ORDER BY key
+LIMIT $2;`
+
+// GetReleasedBlobs returns the blobs that are not used by any file, the blobs are ordered by key
+// and start after afterKey.
+func (d *DB) GetReleasedBlobs(ctx context.Context, afterKey string, limit int) ([]service.Blob, error) {
+	const op = "repository.postgres.get.GetReleasedBlobs"
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetReleasedBlobs, afterKey, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	blobs := make([]service.Blob, 0)
+	for rows.Next() {
+		blob, sErr := scanBlob(rows)
+		if sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		blobs = append(blobs, blob)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return blobs, nil
+}
+
+// scanBlob scans a row selected with blobFields.
+func scanBlob(row pgx.Row) (service.Blob, error) {
+	var blob service.Blob
+
+	if sErr := row.Scan(&blob.Key, &blob.Driver, &blob.Bucket, &blob.SHA256, &blob.Size, &blob.IsPublic, &blob.RefCount,
+		&blob.CreatedAt, &blob.UpdatedAt); sErr != nil {
+		return service.Blob{}, sErr
+	}
+
+	return blob, nil
+}
+
+const queryGetStorageUsages = `SELECT uploader_id, used_bytes, file_count, updated_at
+FROM storage_usages
+WHERE uploader_id = ANY($1);`
+
+// GetStorageUsages returns the usages of the uploaders that have a usage, the order of the usages is not specified.
+func (d *DB) GetStorageUsages(ctx context.Context, uploaderIDs []types.ID) ([]service.StorageUsage, error) {
+	const op = "repository.postgres.get.GetStorageUsages"
+
+	ids := make([]string, 0, len(uploaderIDs))
+	for _, uploaderID := range uploaderIDs {
+		ids = append(ids, string(uploaderID))
+	}
+
+	rows, qErr := d.conn.Conn().Query(ctx, queryGetStorageUsages, ids)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+	defer rows.Close()
+
+	usages := make([]service.StorageUsage, 0, len(uploaderIDs))
+	for rows.Next() {
+		var usage service.StorageUsage
+		if sErr := rows.Scan(&usage.UploaderID, &usage.UsedBytes, &usage.FileCount, &usage.UpdatedAt); sErr != nil {
+			return nil, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected)
+		}
+
+		usages = append(usages, usage)
+	}
+
+	if rErr := rows.Err(); rErr != nil {
+		return nil, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return usages, nil
+}
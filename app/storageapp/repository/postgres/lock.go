@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	queryTryAdvisoryLock = `SELECT pg_try_advisory_lock($1);`
+	queryAdvisoryUnlock  = `SELECT pg_advisory_unlock($1);`
+)
+
+// TryLock takes the session level advisory lock on a dedicated connection, so only one instance holds it.
+// release unlocks it and returns the connection to the pool, it is nil when the lock is not acquired.
+func (d *DB) TryLock(ctx context.Context, lockID int64) (func() error, bool, error) {
+	const op = "repository.postgres.lock.TryLock"
+
+	conn, aErr := d.conn.Conn().Acquire(ctx)
+	if aErr != nil {
+		return nil, false, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected)
+	}
+
+	var acquired bool
+	if qErr := conn.QueryRow(ctx, queryTryAdvisoryLock, lockID).Scan(&acquired); qErr != nil {
+		conn.Release()
+
+		return nil, false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if !acquired {
+		conn.Release()
+
+		return nil, false, nil
+	}
+
+	release := func() error {
+		if _, eErr := conn.Exec(context.Background(), queryAdvisoryUnlock, lockID); eErr != nil {
+			// closing the session releases its advisory locks, so the connection is not returned to the pool.
+			_ = conn.Hijack().Close(context.Background())
+
+			return richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected)
+		}
+
+		conn.Release()
+
+		return nil
+	}
+
+	return release, true, nil
+}
+
+// queryLeaseUpload takes the lease when it is expired and renews it when it is held by the token.
+const queryLeaseUpload = `INSERT INTO upload_leases (upload_id, token, locked_until)
+VALUES ($1, $2, NOW() + $3::interval)
+ON CONFLICT (upload_id) DO UPDATE
+SET token = EXCLUDED.token, locked_until = EXCLUDED.locked_until
+WHERE upload_leases.locked_until < NOW() OR upload_leases.token = EXCLUDED.token
+RETURNING upload_id;`
+
+// LeaseUpload takes or renews the lease of the upload for the token until ttl, it returns false when another token
+// holds the lease. The lease is a row, so no connection is held while it is taken.
+func (d *DB) LeaseUpload(ctx context.Context, uploadID types.ID, token string, ttl time.Duration) (bool, error) {
+	const op = "repository.postgres.lock.LeaseUpload"
+
+	var leasedID types.ID
+	if qErr := d.conn.Conn().QueryRow(ctx, queryLeaseUpload, uploadID, token, ttl).Scan(&leasedID); qErr != nil {
+		if errors.Is(qErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return true, nil
+}
+
+const queryReleaseUploadLease = `DELETE FROM upload_leases
+WHERE upload_id = $1 AND token = $2;`
+
+// ReleaseUploadLease removes the lease of the upload when it is still held by the token.
+func (d *DB) ReleaseUploadLease(ctx context.Context, uploadID types.ID, token string) error {
+	const op = "repository.postgres.lock.ReleaseUploadLease"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryReleaseUploadLease, uploadID, token); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteExpiredUploadLeases = `DELETE FROM upload_leases
+WHERE locked_until < $1;`
+
+// DeleteExpiredUploadLeases removes the leases that are not released, e.g. the instance is stopped while it holds them.
+func (d *DB) DeleteExpiredUploadLeases(ctx context.Context, before time.Time) error {
+	const op = "repository.postgres.lock.DeleteExpiredUploadLeases"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteExpiredUploadLeases, before); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package postgres
 import "database/sql"
 
 type nullableFields struct {
-	Bucket    sql.NullString
-	DeletedAt sql.NullTime
+	Bucket          sql.NullString
+	DeletedAt       sql.NullTime
+	SHA256          sql.NullString
+	VariantsRetryAt sql.NullTime
 }
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const queryPurgeByID = `WITH purged AS (
+	DELETE FROM files
+	WHERE id = $1
+	RETURNING key
+)
+UPDATE file_blobs
+SET ref_count = ref_count - 1, updated_at = NOW()
+WHERE key IN (SELECT key FROM purged) AND ref_count > 0;`
+
+// PurgeByID removes the row of the file and releases its reference of the blob, the object of a file without a blob
+// must be removed from the storage before.
+func (d *DB) PurgeByID(ctx context.Context, fileID types.ID) error {
+	const op = "repository.postgres.remove.PurgeByID"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryPurgeByID, fileID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryPurgeUnconfirmedByID = `WITH purged AS (
+	DELETE FROM files
+	WHERE id = $1 AND is_confirmed IS false AND deleted_at IS NULL
+	RETURNING key
+), released AS (
+	UPDATE file_blobs
+	SET ref_count = ref_count - 1, updated_at = NOW()
+	WHERE key IN (SELECT key FROM purged) AND ref_count > 0
+)
+SELECT COUNT(*) FROM purged;`
+
+// PurgeUnconfirmedByID is PurgeByID of a file that is still unconfirmed, it returns false when the file is confirmed
+// or deleted after it is selected by the janitor.
+func (d *DB) PurgeUnconfirmedByID(ctx context.Context, fileID types.ID) (bool, error) {
+	const op = "repository.postgres.remove.PurgeUnconfirmedByID"
+
+	var purged int
+	if qErr := d.conn.Conn().QueryRow(ctx, queryPurgeUnconfirmedByID, fileID).Scan(&purged); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return purged > 0, nil
+}
+
+const queryPurgeDeletedByID = `WITH purged AS (
+	DELETE FROM files
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING key
+), released AS (
+	UPDATE file_blobs
+	SET ref_count = ref_count - 1, updated_at = NOW()
+	WHERE key IN (SELECT key FROM purged) AND ref_count > 0
+)
+SELECT COUNT(*) FROM purged;`
+
+// PurgeDeletedByID is PurgeByID of a deleted file, it returns false when the file is not deleted.
+func (d *DB) PurgeDeletedByID(ctx context.Context, fileID types.ID) (bool, error) {
+	const op = "repository.postgres.remove.PurgeDeletedByID"
+
+	var purged int
+	if qErr := d.conn.Conn().QueryRow(ctx, queryPurgeDeletedByID, fileID).Scan(&purged); qErr != nil {
+		return false, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return purged > 0, nil
+}
+
+const queryDeleteUploadSession = `DELETE FROM upload_sessions
+WHERE id = $1;`
+
+func (d *DB) DeleteUploadSession(ctx context.Context, uploadID types.ID) error {
+	const op = "repository.postgres.remove.DeleteUploadSession"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteUploadSession, uploadID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteBlob = `DELETE FROM file_blobs
+WHERE key = $1 AND ref_count = 0;`
+
+// DeleteBlob removes the released blob, the object of the blob must be removed from the storage before.
+func (d *DB) DeleteBlob(ctx context.Context, key string) error {
+	const op = "repository.postgres.remove.DeleteBlob"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteBlob, key); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteStorageReservation = `DELETE FROM storage_reservations
+WHERE id = $1;`
+
+func (d *DB) DeleteStorageReservation(ctx context.Context, reservationID types.ID) error {
+	const op = "repository.postgres.remove.DeleteStorageReservation"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteStorageReservation, reservationID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteExpiredStorageReservations = `DELETE FROM storage_reservations
+WHERE expires_at < $1;`
+
+// DeleteExpiredStorageReservations removes the reservations that are not removed after their upload.
+func (d *DB) DeleteExpiredStorageReservations(ctx context.Context, before time.Time) error {
+	const op = "repository.postgres.remove.DeleteExpiredStorageReservations"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteExpiredStorageReservations, before); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteInstanceHeartbeat = `DELETE FROM storage_instances
+WHERE id = $1;`
+
+func (d *DB) DeleteInstanceHeartbeat(ctx context.Context, instanceID types.ID) error {
+	const op = "repository.postgres.remove.DeleteInstanceHeartbeat"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteInstanceHeartbeat, instanceID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryDeleteStaleInstanceHeartbeats = `DELETE FROM storage_instances
+WHERE seen_at < $1;`
+
+// DeleteStaleInstanceHeartbeats removes the heartbeats of the instances that are stopped without removing them.
+func (d *DB) DeleteStaleInstanceHeartbeats(ctx context.Context, before time.Time) error {
+	const op = "repository.postgres.remove.DeleteStaleInstanceHeartbeats"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryDeleteStaleInstanceHeartbeats, before); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
@@ -2,8 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
 	"github.com/syntaxfa/quick-connect/types"
 )
@@ -45,3 +50,213 @@ func (d *DB) ConfirmFile(ctx context.Context, fileID types.ID) error {
 
 	return nil
 }
+
+const queryMarkFileReady = `UPDATE files
+SET status = 'ready', mime_type = $2, size = $3, sha256 = $4, key = $5, variants_retry_at = $6
+WHERE id = $1 AND status = 'pending';`
+
+// MarkFileReady sets the detected type, the size and the checksum of a pending file, takes a reference of the blob
+// of its content and marks it ready. It returns the key of the file like SaveWithBlob.
+func (d *DB) MarkFileReady(ctx context.Context, file service.File) (string, error) {
+	const op = "repository.postgres.update.MarkFileReady"
+
+	tx, bErr := d.conn.Conn().Begin(ctx)
+	if bErr != nil {
+		return "", richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	key, aErr := acquireBlob(ctx, tx, file)
+	if aErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return "", richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return "", richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected)
+	}
+
+	var nullable nullableFields
+	if file.SHA256 != "" {
+		nullable.SHA256.String = file.SHA256
+		nullable.SHA256.Valid = true
+	}
+	if file.VariantsRetryAt != nil {
+		nullable.VariantsRetryAt.Time = *file.VariantsRetryAt
+		nullable.VariantsRetryAt.Valid = true
+	}
+
+	cmdTag, exErr := tx.Exec(ctx, queryMarkFileReady, file.ID, file.MimeType, file.Size, nullable.SHA256, key,
+		nullable.VariantsRetryAt)
+	if exErr != nil || cmdTag.RowsAffected() == 0 {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return "", richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		if exErr != nil {
+			return "", richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return "", richerror.New(op).WithKind(richerror.KindNotFound).WithMessage("pending file not found")
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return "", richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return key, nil
+}
+
+// queryClaimPendingVariants moves the retry of the claimed files to $2, so the files are claimed by one instance
+// until the retry is due again, it is not cleared when the instance is stopped before it generates the variants.
+const queryClaimPendingVariants = `UPDATE files
+SET variants_retry_at = $2
+WHERE id IN (
+    SELECT id FROM files
+    WHERE variants_retry_at <= $1 AND deleted_at IS NULL
+    ORDER BY variants_retry_at
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + fileFields + `;`
+
+func (d *DB) ClaimPendingVariants(ctx context.Context, now, retryAt time.Time, limit int) ([]service.File, error) {
+	const op = "repository.postgres.update.ClaimPendingVariants"
+
+	files, qErr := d.queryFiles(ctx, queryClaimPendingVariants, now, retryAt, limit)
+	if qErr != nil {
+		return nil, richerror.New(op).WithWrapError(qErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return files, nil
+}
+
+const queryCompleteVariants = `UPDATE files
+SET variants_retry_at = NULL
+WHERE id = $1;`
+
+func (d *DB) CompleteVariants(ctx context.Context, fileID types.ID) error {
+	const op = "repository.postgres.update.CompleteVariants"
+
+	if _, exErr := d.conn.Conn().Exec(ctx, queryCompleteVariants, fileID); exErr != nil {
+		return richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+const queryGetMovedBlob = `SELECT sha256, is_public, ref_count
+FROM file_blobs
+WHERE key = $1 AND driver = $2
+FOR UPDATE;`
+
+const queryGetIdenticalBlob = `SELECT key, ref_count
+FROM file_blobs
+WHERE driver = $1 AND bucket = $2 AND sha256 = $3 AND is_public = $4
+FOR UPDATE;`
+
+const queryMergeBlob = `UPDATE file_blobs
+SET ref_count = ref_count + $2, updated_at = NOW()
+WHERE key = $1;`
+
+const queryDeleteMergedBlob = `DELETE FROM file_blobs
+WHERE key = $1;`
+
+const queryMoveBlob = `UPDATE file_blobs
+SET key = $2, driver = $3, bucket = $4, updated_at = NOW()
+WHERE key = $1;`
+
+const queryMoveVariants = `UPDATE file_variants
+SET key = $4 || key
+WHERE file_id IN (SELECT id FROM files WHERE driver = $1 AND ($2 = '' OR bucket = $2) AND key = $3);`
+
+const queryMoveFiles = `UPDATE files
+SET driver = $4, bucket = $5, key = $6
+WHERE driver = $1 AND ($2 = '' OR bucket = $2) AND key = $3;`
+
+// MoveObject sets the driver, the bucket and the key of the files of the object and the keys of their variants,
+// the blob of the object is moved with them. The files use an identical blob of the target driver when it exists,
+// so the blob is merged into it. It returns the key of the moved files.
+func (d *DB) MoveObject(ctx context.Context, move service.ObjectMove) (string, error) {
+	const op = "repository.postgres.update.MoveObject"
+
+	tx, bErr := d.conn.Conn().Begin(ctx)
+	if bErr != nil {
+		return "", richerror.New(op).WithWrapError(bErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while begin a transaction")
+	}
+
+	key, mErr := moveObject(ctx, tx, move)
+	if mErr != nil {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return "", richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return "", richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if cErr := tx.Commit(ctx); cErr != nil {
+		return "", richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMessage("error while transaction commit")
+	}
+
+	return key, nil
+}
+
+func moveObject(ctx context.Context, tx pgx.Tx, move service.ObjectMove) (string, error) {
+	key := move.KeyPrefix + move.Key
+
+	var sha256 string
+	var isPublic bool
+	var refCount int
+	qErr := tx.QueryRow(ctx, queryGetMovedBlob, move.Key, move.FromDriver).Scan(&sha256, &isPublic, &refCount)
+	if qErr != nil && !errors.Is(qErr, pgx.ErrNoRows) {
+		return "", qErr
+	}
+
+	if qErr == nil {
+		var identicalKey string
+		var identicalRefCount int
+		iErr := tx.QueryRow(ctx, queryGetIdenticalBlob, move.ToDriver, move.ToBucket, sha256, isPublic).
+			Scan(&identicalKey, &identicalRefCount)
+
+		switch {
+		case iErr == nil && identicalRefCount == 0:
+			// the object of the released blob is being removed, the object is moved after the blob is purged.
+			return "", fmt.Errorf("identical blob %s of the target is released", identicalKey)
+		case iErr == nil:
+			if _, eErr := tx.Exec(ctx, queryMergeBlob, identicalKey, refCount); eErr != nil {
+				return "", eErr
+			}
+
+			if _, eErr := tx.Exec(ctx, queryDeleteMergedBlob, move.Key); eErr != nil {
+				return "", eErr
+			}
+
+			key = identicalKey
+		case errors.Is(iErr, pgx.ErrNoRows):
+			if _, eErr := tx.Exec(ctx, queryMoveBlob, move.Key, key, move.ToDriver, move.ToBucket); eErr != nil {
+				return "", eErr
+			}
+		default:
+			return "", iErr
+		}
+	}
+
+	if _, eErr := tx.Exec(ctx, queryMoveVariants, move.FromDriver, move.FromBucket, move.Key, move.KeyPrefix); eErr != nil {
+		return "", eErr
+	}
+
+	var nullable nullableFields
+	if move.ToBucket != "" {
+		nullable.Bucket.String = move.ToBucket
+		nullable.Bucket.Valid = true
+	}
+
+	if _, eErr := tx.Exec(ctx, queryMoveFiles, move.FromDriver, move.FromBucket, move.Key, move.ToDriver, nullable.Bucket,
+		key); eErr != nil {
+		return "", eErr
+	}
+
+	return key, nil
+}
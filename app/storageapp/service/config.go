@@ -1,7 +1,85 @@
 package service
 
+import (
+	"time"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// Config AllowedMimeTypes is the allow-list of the mime types of each upload purpose, an item can be a mime type,
+// such as application/pdf, or all the subtypes of a type, such as image/*. A purpose without a list rejects all types.
 type Config struct {
-	Driver      Driver
-	Bucket      string
-	MaxFileSize int64 `koanf:"max_file_size"`
+	Driver           Driver
+	Bucket           string
+	MaxFileSize      int64                      `koanf:"max_file_size"`
+	AllowedMimeTypes map[UploadPurpose][]string `koanf:"allowed_mime_types"`
+	Janitor          JanitorConfig              `koanf:"janitor"`
+	Image            ImageConfig                `koanf:"image"`
+	Resumable        ResumableConfig            `koanf:"resumable"`
+	DirectUpload     DirectUploadConfig         `koanf:"direct_upload"`
+	Quota            QuotaConfig                `koanf:"quota"`
+	Download         DownloadConfig             `koanf:"download"`
+}
+
+// JanitorConfig the unconfirmed files are purged UnconfirmedTTL after the upload and the deleted files are purged
+// DeletedGracePeriod after the deletion, they are a day and a week when they are not positive. In DryRun mode the files
+// are only logged and counted.
+type JanitorConfig struct {
+	Enabled            bool          `koanf:"enabled"`
+	Interval           time.Duration `koanf:"interval"`
+	UnconfirmedTTL     time.Duration `koanf:"unconfirmed_ttl"`
+	DeletedGracePeriod time.Duration `koanf:"deleted_grace_period"`
+	BatchSize          int           `koanf:"batch_size"`
+	DryRun             bool          `koanf:"dry_run"`
+}
+
+// ImageConfig the variants of the uploaded images are generated after the upload by Workers workers, MaxPixels
+// protects the service from decoding very large images.
+type ImageConfig struct {
+	Enabled   bool                 `koanf:"enabled"`
+	Workers   int                  `koanf:"workers"`
+	MaxPixels int                  `koanf:"max_pixels"`
+	Timeout   time.Duration        `koanf:"timeout"`
+	Variants  []ImageVariantConfig `koanf:"variants"`
+}
+
+// ImageVariantConfig the image is resized to fit in Width and Height, when Crop is true the image is cropped
+// from the center to fill them. Format is jpeg, png or webp, the format of the original image is used when it is empty.
+// Images smaller than the variant are not enlarged.
+type ImageVariantConfig struct {
+	Name    string      `koanf:"name"`
+	Width   int         `koanf:"width"`
+	Height  int         `koanf:"height"`
+	Crop    bool        `koanf:"crop"`
+	Format  ImageFormat `koanf:"format"`
+	Quality int         `koanf:"quality"`
+}
+
+// ResumableConfig MaxFileSize is the size limit of the resumable uploads, MaxFileSize of the service is used when
+// it is zero. The partial uploads are removed by the janitor Expire after their creation.
+type ResumableConfig struct {
+	MaxFileSize int64         `koanf:"max_file_size"`
+	Expire      time.Duration `koanf:"expire"`
+}
+
+// DirectUploadConfig MaxFileSize is the size limit of the direct uploads, MaxFileSize of the service is used when
+// it is zero. Expire is the lifetime of the presigned upload requests, the files that are not completed are purged
+// by the janitor like the unconfirmed files.
+type DirectUploadConfig struct {
+	MaxFileSize int64         `koanf:"max_file_size"`
+	Expire      time.Duration `koanf:"expire"`
+}
+
+// QuotaConfig Limits is the storage quota of the uploaders of each role in bytes, the quota of an uploader is the largest
+// limit of its roles, and the uploaders that have a role without a limit are not limited.
+type QuotaConfig struct {
+	Enabled bool                 `koanf:"enabled"`
+	Limits  map[types.Role]int64 `koanf:"limits"`
+}
+
+// DownloadConfig PublicMaxAge and PrivateMaxAge are the max-age of the Cache-Control of the downloads of the public
+// and the private files, the defaults are used when they are zero.
+type DownloadConfig struct {
+	PublicMaxAge  time.Duration `koanf:"public_max_age"`
+	PrivateMaxAge time.Duration `koanf:"private_max_age"`
 }
@@ -25,6 +25,10 @@ func (s Service) ConfirmFile(ctx context.Context, fileID types.ID) error {
 		return errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	if !file.IsReady() {
+		return richerror.New(op).WithMessage(servermsg.MsgFileNotUploaded).WithKind(richerror.KindBadRequest)
+	}
+
 	if file.IsConfirmed {
 		return richerror.New(op).WithMessage(servermsg.MsgFileAlreadyConfirmed).WithKind(richerror.KindConflict)
 	}
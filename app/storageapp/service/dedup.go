@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+// saveFile saves the uploaded file with a reference to the blob of its content, the file uses the key of an identical
+// object that is stored with the same driver, bucket and visibility, see useBlob.
+func (s Service) saveFile(ctx context.Context, file File) (File, error) {
+	key, sErr := s.repo.SaveWithBlob(ctx, file)
+	if sErr != nil {
+		return File{}, sErr
+	}
+
+	return s.useBlob(ctx, file, key), nil
+}
+
+// useBlob sets the key of the blob of the file and removes the uploaded object when the blob is another object,
+// the failed removal is only logged, because the file is saved with the key of the blob.
+func (s Service) useBlob(ctx context.Context, file File, key string) File {
+	const op = "service.dedup.useBlob"
+
+	if key == file.Key {
+		return file
+	}
+
+	if dErr := s.storage.Delete(ctx, file.Key); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"file_id": file.ID, "key": file.Key}), s.logger)
+	}
+
+	s.dedupMetrics.addDeduplicated(ctx, file.Driver, file.Size)
+	file.Key = key
+
+	return file
+}
+
+// purgeFile removes the row of the file by purgeRow and then the objects of its variants, the object of the file is
+// removed when the file owns it or when it is the last reference of its blob. The uploaded object of a pending direct
+// upload is removed too, because it is not copied to the key of the file. It returns false when purgeRow doesn't
+// remove the row, so the objects of a file that is changed after it is selected are kept.
+func (s Service) purgeFile(ctx context.Context, file File, purgeRow purgeFileFunc) (bool, error) {
+	variants, gErr := s.repo.GetVariantsByFileID(ctx, file.ID)
+	if gErr != nil {
+		return false, gErr
+	}
+
+	shared, eErr := s.repo.IsExistBlob(ctx, file.Key)
+	if eErr != nil {
+		return false, eErr
+	}
+
+	purged, pErr := purgeRow(ctx, file.ID)
+	if pErr != nil {
+		return false, pErr
+	}
+
+	if !purged {
+		return false, nil
+	}
+
+	// the row is removed, so the objects are not selected again and a failed removal is returned after the others.
+	var dErrs []error
+	for _, variant := range variants {
+		if dErr := s.storage.Delete(ctx, variant.Key); dErr != nil {
+			dErrs = append(dErrs, dErr)
+		}
+	}
+
+	if file.Status == FileStatusPending {
+		if dErr := s.storage.Delete(ctx, directUploadKey(file.Key)); dErr != nil {
+			dErrs = append(dErrs, dErr)
+		}
+	}
+
+	if !shared {
+		if dErr := s.storage.Delete(ctx, file.Key); dErr != nil {
+			dErrs = append(dErrs, dErr)
+		}
+
+		return true, errors.Join(dErrs...)
+	}
+
+	// a blob that can't be removed here is removed by the janitor with the other released blobs.
+	if _, rErr := s.deleteReleasedBlob(ctx, file.Key); rErr != nil {
+		dErrs = append(dErrs, rErr)
+	}
+
+	return true, errors.Join(dErrs...)
+}
+
+// deleteReleasedBlob removes the object and the row of the blob when no file uses it, it returns false when the
+// blob is still used.
+func (s Service) deleteReleasedBlob(ctx context.Context, key string) (bool, error) {
+	blob, gErr := s.repo.GetBlob(ctx, key)
+	if gErr != nil {
+		return false, gErr
+	}
+
+	if blob.RefCount > 0 {
+		return false, nil
+	}
+
+	if dErr := s.storage.Delete(ctx, blob.Key); dErr != nil {
+		return false, dErr
+	}
+
+	if dErr := s.repo.DeleteBlob(ctx, blob.Key); dErr != nil {
+		return false, dErr
+	}
+
+	return true, nil
+}
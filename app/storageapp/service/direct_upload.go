@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const defaultDirectUploadExpire = 15 * time.Minute
+
+// directUploadPrefix is the prefix of the keys that are presigned for the clients. The presigned request can be sent
+// again until it expires, so the object of the key is copied to the key of the file when the upload is completed
+// and only the copy is used, the objects that are uploaded after the completion are removed by a lifecycle rule
+// of the bucket on the prefix.
+const directUploadPrefix = "direct/"
+
+func directUploadKey(key string) string {
+	return directUploadPrefix + key
+}
+
+// DirectUploadMaxFileSize returns the size limit of the direct uploads.
+func (s Service) DirectUploadMaxFileSize() int64 {
+	if s.cfg.DirectUpload.MaxFileSize > 0 {
+		return s.cfg.DirectUpload.MaxFileSize
+	}
+
+	return s.cfg.MaxFileSize
+}
+
+func (s Service) directUploadExpire() time.Duration {
+	if s.cfg.DirectUpload.Expire > 0 {
+		return s.cfg.DirectUpload.Expire
+	}
+
+	return defaultDirectUploadExpire
+}
+
+// CreateDirectUpload creates a pending file and returns the presigned request that uploads the file to the storage,
+// so the content of the file doesn't pass through the service. The client completes the upload by CompleteDirectUpload.
+func (s Service) CreateDirectUpload(ctx context.Context, req CreateDirectUploadRequest) (DirectUploadResponse, error) {
+	const op = "service.direct_upload.CreateDirectUpload"
+
+	if s.directStorage == nil {
+		return DirectUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgDirectUploadNotSupported).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if req.Purpose == "" {
+		req.Purpose = UploadPurposeGeneral
+	}
+	if !IsValidUploadPurpose(req.Purpose) {
+		return DirectUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidUploadPurpose).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if strings.TrimSpace(req.Filename) == "" {
+		return DirectUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgFilenameRequired).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if req.Size <= 0 || req.Size > s.DirectUploadMaxFileSize() {
+		return DirectUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgFileSizeLimitExceeded).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	checksum := strings.ToLower(req.SHA256)
+	if checksum != "" && !isValidSHA256(checksum) {
+		return DirectUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidFileChecksum).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	ext := filepath.Ext(req.Filename)
+
+	// the content type is only a condition of the upload, the content of the file is checked when it is completed.
+	contentType := mime.TypeByExtension(ext)
+	if req.ContentType != "" {
+		mediaType, _, pErr := mime.ParseMediaType(req.ContentType)
+		if pErr != nil {
+			return DirectUploadResponse{}, richerror.New(op).WithWrapError(pErr).WithMessage(servermsg.MsgFileTypeNotAllowed).
+				WithKind(richerror.KindBadRequest)
+		}
+		contentType = mediaType
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if tErr := s.checkFileType(req.Purpose, contentType, req.Filename); tErr != nil {
+		return DirectUploadResponse{}, tErr
+	}
+
+	newID := types.ID(ulid.Make().String())
+	key := fmt.Sprintf("uploads/%s%s", newID, ext)
+
+	// the pending file is counted in the usage of the uploader when it is saved, so the reservation is only held until then.
+	if qErr := s.reserveQuota(ctx, newID, req.UploaderID, req.UploaderRoles, req.Size,
+		time.Now().Add(uploadReservationTTL)); qErr != nil {
+		return DirectUploadResponse{}, qErr
+	}
+	defer s.releaseQuota(context.WithoutCancel(ctx), newID)
+
+	upload, pErr := s.directStorage.PresignUpload(ctx, directUploadKey(key), PresignUploadOptions{
+		ContentType: contentType,
+		Size:        req.Size,
+		SHA256:      checksum,
+		IsPublic:    req.IsPublic,
+		Expire:      s.directUploadExpire(),
+	})
+	if pErr != nil {
+		return DirectUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(pErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	file := File{
+		ID:          newID,
+		UploaderID:  req.UploaderID,
+		Name:        req.Filename,
+		Key:         key,
+		MimeType:    contentType,
+		Size:        req.Size,
+		Driver:      s.cfg.Driver,
+		Bucket:      s.cfg.Bucket,
+		IsPublic:    req.IsPublic,
+		SHA256:      checksum,
+		Purpose:     req.Purpose,
+		Status:      FileStatusPending,
+		IsConfirmed: false,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		DeletedAt:   nil,
+	}
+
+	if sErr := s.repo.Save(ctx, file); sErr != nil {
+		return DirectUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(sErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return DirectUploadResponse{File: file, Upload: upload}, nil
+}
+
+// CompleteDirectUpload copies the uploaded object to the key of the pending file, checks the copy and marks the file
+// ready, the file is checked like the files of Upload, so a file that is not allowed is removed. The object is read
+// from the storage to calculate its checksum only when the storage doesn't have it.
+func (s Service) CompleteDirectUpload(ctx context.Context, fileID, uploaderID types.ID) (File, error) {
+	const op = "service.direct_upload.CompleteDirectUpload"
+
+	if s.directStorage == nil {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgDirectUploadNotSupported).WithKind(richerror.KindBadRequest)
+	}
+
+	release, lErr := s.lockUpload(ctx, fileID)
+	if lErr != nil {
+		return File{}, lErr
+	}
+	defer release()
+
+	exists, exErr := s.repo.IsExistByID(ctx, fileID)
+	if exErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	if !exists {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	file, gErr := s.repo.GetByID(ctx, fileID)
+	if gErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if file.UploaderID != uploaderID || file.IsDeleted() {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	if file.IsReady() {
+		return file, nil
+	}
+
+	// the copy is not found when a retried completion has copied and removed the uploaded object before.
+	uploadKey := directUploadKey(file.Key)
+	if cErr := s.directStorage.Copy(ctx, uploadKey, file.Key, file.IsPublic); cErr != nil && !errors.Is(cErr, ErrObjectNotFound) {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if dErr := s.storage.Delete(ctx, uploadKey); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"file_id": file.ID, "key": uploadKey}), s.logger)
+	}
+
+	info, sErr := s.directStorage.Stat(ctx, file.Key)
+	if sErr != nil {
+		if errors.Is(sErr, ErrObjectNotFound) {
+			return File{}, richerror.New(op).WithWrapError(sErr).WithMessage(servermsg.MsgFileNotUploaded).
+				WithKind(richerror.KindBadRequest)
+		}
+
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if info.Size != file.Size || (file.SHA256 != "" && info.SHA256 != "" && info.SHA256 != file.SHA256) {
+		s.discardDirectUpload(ctx, file)
+
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgUploadedFileMismatch).WithKind(richerror.KindBadRequest)
+	}
+
+	// the images are read into memory by processUploadedObject, so an image that is too large is rejected before its
+	// object is opened, the sniffed type of the object is checked against the size by processUploadedObject too.
+	_, isImage := imageMimeTypes[file.MimeType]
+	if _, ok := imageMimeTypes[info.ContentType]; (ok || isImage) && info.Size > s.maxImageSize() {
+		s.discardDirectUpload(ctx, file)
+
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgImageSizeLimitExceeded).WithKind(richerror.KindBadRequest)
+	}
+
+	// the declared checksum is only checked against the checksum of the copy, because the copy is the stored object.
+	file.SHA256 = info.SHA256
+
+	processed, imageData, pErr := s.processUploadedObject(ctx, file, file.SHA256 == "")
+	if pErr != nil {
+		var richErr richerror.RichError
+		if errors.As(pErr, &richErr) && richErr.Kind() == richerror.KindBadRequest {
+			s.discardDirectUpload(ctx, file)
+
+			return File{}, pErr
+		}
+
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	file = processed
+	file.VariantsRetryAt = s.pendingVariants(file.MimeType)
+
+	key, mErr := s.repo.MarkFileReady(ctx, file)
+	if mErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	file = s.useBlob(ctx, file, key)
+	file.Status = FileStatusReady
+
+	if file.VariantsRetryAt != nil {
+		s.enqueueImage(file, imageData)
+	}
+
+	return file, nil
+}
+
+// discardDirectUpload removes the copy of the uploaded object and the pending file that is not allowed, the errors
+// are only logged, because the rejection of the file is returned to the client.
+func (s Service) discardDirectUpload(ctx context.Context, file File) {
+	const op = "service.direct_upload.discardDirectUpload"
+
+	if dErr := s.storage.Delete(ctx, file.Key); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+
+		// the pending file is purged by the janitor with its object.
+		return
+	}
+
+	if pErr := s.repo.PurgeByID(ctx, file.ID); pErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+}
+
+func isValidSHA256(checksum string) bool {
+	if len(checksum) != hex.EncodedLen(32) {
+		return false
+	}
+
+	_, dErr := hex.DecodeString(checksum)
+
+	return dErr == nil
+}
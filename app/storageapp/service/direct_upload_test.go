@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+func TestCompleteDirectUploadImageSizeLimit(t *testing.T) {
+	const limit = 1024
+
+	tests := []struct {
+		name          string
+		mimeType      string
+		objectType    string
+		size          int64
+		expectedError string
+	}{
+		{name: "declared image over the limit", mimeType: "image/png", objectType: "image/png", size: limit + 1,
+			expectedError: servermsg.MsgImageSizeLimitExceeded},
+		{name: "stored image type over the limit", mimeType: "application/pdf", objectType: "image/jpeg", size: limit + 1,
+			expectedError: servermsg.MsgImageSizeLimitExceeded},
+		{name: "size mismatch is checked first", mimeType: "image/png", objectType: "image/png", size: limit * 2,
+			expectedError: servermsg.MsgUploadedFileMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := File{ID: "file-1", UploaderID: "user-1", Key: "image.png", Name: "image.png", MimeType: test.mimeType,
+				Size: limit + 1, Status: FileStatusPending, Purpose: UploadPurposeGeneral}
+
+			uploadKey := directUploadKey(file.Key)
+			storage := memDirectStorage{memStorage: newMemStorage(), contentTypes: map[string]string{uploadKey: test.objectType}}
+			storage.put(uploadKey, make([]byte, test.size))
+			repo := newMemRepository(file)
+			svc := New(Config{MaxFileSize: limit, AllowedMimeTypes: testAllowedMimeTypes}, storage, repo, discardLogger())
+
+			_, cErr := svc.CompleteDirectUpload(context.Background(), file.ID, file.UploaderID)
+
+			var richErr richerror.RichError
+			if !errors.As(cErr, &richErr) || richErr.Kind() != richerror.KindBadRequest || richErr.Message() != test.expectedError {
+				t.Fatalf("expected bad request %q, got %v", test.expectedError, cErr)
+			}
+
+			if storage.opens != 0 {
+				t.Fatalf("expected the object not to be opened, opened %d times", storage.opens)
+			}
+
+			for _, key := range []string{uploadKey, file.Key} {
+				if exists, _ := storage.Exists(context.Background(), key); exists {
+					t.Fatalf("expected the object %q of the rejected upload to be removed", key)
+				}
+			}
+
+			if len(repo.purged) != 1 || repo.purged[0] != file.ID {
+				t.Fatalf("expected the pending file to be purged, purged %v", repo.purged)
+			}
+		})
+	}
+}
+
+func TestCompleteDirectUploadCopiesUploadedObject(t *testing.T) {
+	content := []byte("the uploaded notes")
+
+	tests := []struct {
+		name     string
+		uploaded map[string][]byte
+		wantErr  string
+	}{
+		{name: "uploaded object is copied", uploaded: map[string][]byte{directUploadKey("uploads/notes.txt"): content}},
+		{name: "retry after the copy", uploaded: map[string][]byte{"uploads/notes.txt": content}},
+		{name: "object is not uploaded", uploaded: map[string][]byte{}, wantErr: servermsg.MsgFileNotUploaded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := File{ID: "file-1", UploaderID: "user-1", Key: "uploads/notes.txt", Name: "notes.txt",
+				MimeType: "text/plain", Size: int64(len(content)), Status: FileStatusPending, Purpose: UploadPurposeGeneral}
+
+			storage := memDirectStorage{memStorage: newMemStorage(), contentTypes: make(map[string]string)}
+			for key, data := range test.uploaded {
+				storage.put(key, data)
+			}
+			svc := New(Config{MaxFileSize: 1024, AllowedMimeTypes: testAllowedMimeTypes}, storage, newMemRepository(file), discardLogger())
+
+			completed, cErr := svc.CompleteDirectUpload(context.Background(), file.ID, file.UploaderID)
+			if test.wantErr != "" {
+				var richErr richerror.RichError
+				if !errors.As(cErr, &richErr) || richErr.Message() != test.wantErr {
+					t.Fatalf("expected %q, got %v", test.wantErr, cErr)
+				}
+
+				return
+			}
+			if cErr != nil {
+				t.Fatalf("unexpected error: %v", cErr)
+			}
+
+			if completed.Key != file.Key || completed.Status != FileStatusReady {
+				t.Fatalf("expected the ready file of %q, got %q %q", file.Key, completed.Key, completed.Status)
+			}
+
+			if _, ok := storage.get(directUploadKey(file.Key)); ok {
+				t.Fatal("expected the uploaded object to be removed")
+			}
+
+			// a request that is presigned before the completion doesn't change the object of the file.
+			storage.put(directUploadKey(file.Key), []byte("overwritten"))
+			if data, _ := storage.get(file.Key); string(data) != string(content) {
+				t.Fatalf("expected the object of the file to be %q, got %q", content, data)
+			}
+
+			if completed.SHA256 == "" {
+				t.Fatal("expected the checksum of the copy to be calculated")
+			}
+		})
+	}
+}
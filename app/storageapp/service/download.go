@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+const (
+	defaultPublicMaxAge  = 7 * 24 * time.Hour
+	defaultPrivateMaxAge = 15 * time.Minute
+)
+
+// GetDownload returns the download of the key without its content, the key is the key of a file or of a variant of
+// a file. The caller must check the access to the file before the download is opened by OpenDownload.
+func (s Service) GetDownload(ctx context.Context, key string) (Download, error) {
+	const op = "service.download.GetDownload"
+
+	file, gErr := s.GetFileByKey(ctx, key)
+	if gErr != nil {
+		return Download{}, gErr
+	}
+
+	if !file.IsReady() {
+		return Download{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	download := Download{
+		File:     file,
+		Key:      key,
+		MimeType: file.MimeType,
+		Size:     file.Size,
+		ETag:     file.SHA256,
+		ModTime:  file.CreatedAt,
+		MaxAge:   s.downloadMaxAge(file.IsPublic),
+	}
+
+	if key != file.Key {
+		variant, vErr := s.getVariantByKey(ctx, key)
+		if vErr != nil {
+			return Download{}, vErr
+		}
+
+		download.MimeType = variant.MimeType
+		download.Size = variant.Size
+		download.ETag = ""
+		download.ModTime = variant.CreatedAt
+	}
+
+	if download.ETag == "" {
+		sum := sha256.Sum256([]byte(key))
+		download.ETag = hex.EncodeToString(sum[:16])
+	}
+
+	return download, nil
+}
+
+// OpenDownload opens the object of the download, the caller must close Content of the download.
+func (s Service) OpenDownload(ctx context.Context, download Download) (Download, error) {
+	const op = "service.download.OpenDownload"
+
+	content, oErr := s.openContent(ctx, download.Key, download.Size)
+	if oErr != nil {
+		if errors.Is(oErr, ErrObjectNotFound) {
+			return Download{}, richerror.New(op).WithWrapError(oErr).WithMessage(servermsg.MsgFileNotFound).
+				WithKind(richerror.KindNotFound)
+		}
+
+		return Download{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(oErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	download.Content = content
+
+	return download, nil
+}
+
+func (s Service) getVariantByKey(ctx context.Context, key string) (FileVariant, error) {
+	const op = "service.download.getVariantByKey"
+
+	exists, exErr := s.repo.IsExistVariantByKey(ctx, key)
+	if exErr != nil {
+		return FileVariant{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	if !exists {
+		return FileVariant{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	variant, gErr := s.repo.GetVariantByKey(ctx, key)
+	if gErr != nil {
+		return FileVariant{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return variant, nil
+}
+
+func (s Service) downloadMaxAge(isPublic bool) time.Duration {
+	if isPublic {
+		if s.cfg.Download.PublicMaxAge > 0 {
+			return s.cfg.Download.PublicMaxAge
+		}
+
+		return defaultPublicMaxAge
+	}
+
+	if s.cfg.Download.PrivateMaxAge > 0 {
+		return s.cfg.Download.PrivateMaxAge
+	}
+
+	return defaultPrivateMaxAge
+}
+
+// openContent returns a reader of the object that reads it by ranges when the storage implements RangeStorage,
+// otherwise the object that is opened by the storage must be seekable, such as the files of the local driver.
+func (s Service) openContent(ctx context.Context, key string, size int64) (io.ReadSeekCloser, error) {
+	if s.rangeStorage != nil {
+		return &rangeReader{ctx: ctx, storage: s.rangeStorage, key: key, size: size}, nil
+	}
+
+	obj, oErr := s.storage.Open(ctx, key)
+	if oErr != nil {
+		return nil, oErr
+	}
+
+	content, ok := obj.(io.ReadSeekCloser)
+	if !ok {
+		if cErr := obj.Close(); cErr != nil {
+			return nil, cErr
+		}
+
+		return nil, fmt.Errorf("object %s of the storage is not seekable", key)
+	}
+
+	return content, nil
+}
+
+// rangeReader reads the object from its offset, the object is opened from the new offset after a seek, so the
+// skipped bytes are not read.
+type rangeReader struct {
+	ctx     context.Context
+	storage RangeStorage
+	key     string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		body, oErr := r.storage.OpenRange(r.ctx, r.key, r.offset)
+		if oErr != nil {
+			return 0, oErr
+		}
+
+		r.body = body
+	}
+
+	n, rErr := r.body.Read(p)
+	r.offset += int64(n)
+
+	return n, rErr
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = r.offset + offset
+	case io.SeekEnd:
+		position = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if position < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if position != r.offset && r.body != nil {
+		if cErr := r.body.Close(); cErr != nil {
+			return 0, cErr
+		}
+
+		r.body = nil
+	}
+
+	r.offset = position
+
+	return position, nil
+}
+
+func (r *rangeReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	return r.body.Close()
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+func TestDownload(t *testing.T) {
+	content := []byte("content of the private file")
+	file := File{ID: "file-1", Key: "2026/10/private.pdf", MimeType: "application/pdf", Size: int64(len(content)),
+		SHA256: "checksum", Status: FileStatusReady}
+
+	storage := newMemStorage()
+	storage.put(file.Key, content)
+	svc := New(Config{}, storage, newMemRepository(file), discardLogger())
+
+	download, gErr := svc.GetDownload(context.Background(), file.Key)
+	if gErr != nil {
+		t.Fatalf("unexpected error: %v", gErr)
+	}
+
+	if download.File.ID != file.ID || download.ETag != file.SHA256 || download.MaxAge != defaultPrivateMaxAge {
+		t.Fatalf("unexpected download %+v", download)
+	}
+
+	if storage.opens != 0 || download.Content != nil {
+		t.Fatal("expected the object not to be opened before the access is checked")
+	}
+
+	download, oErr := svc.OpenDownload(context.Background(), download)
+	if oErr != nil {
+		t.Fatalf("unexpected error: %v", oErr)
+	}
+	defer func() { _ = download.Content.Close() }()
+
+	if _, sErr := download.Content.Seek(8, io.SeekStart); sErr != nil {
+		t.Fatalf("unexpected seek error: %v", sErr)
+	}
+
+	data, rErr := io.ReadAll(download.Content)
+	if rErr != nil || string(data) != string(content[8:]) {
+		t.Fatalf("unexpected content %q, error %v", data, rErr)
+	}
+}
+
+func TestGetDownloadNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		file File
+	}{
+		{name: "missing file", file: File{ID: "file-1", Key: "other.pdf", Status: FileStatusReady}},
+		{name: "pending file", file: File{ID: "file-1", Key: "file.pdf", Status: FileStatusPending}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storage := newMemStorage()
+			svc := New(Config{}, storage, newMemRepository(test.file), discardLogger())
+
+			_, gErr := svc.GetDownload(context.Background(), "file.pdf")
+
+			var richErr richerror.RichError
+			if !errors.As(gErr, &richErr) || richErr.Kind() != richerror.KindNotFound {
+				t.Fatalf("expected not found, got %v", gErr)
+			}
+
+			if storage.opens != 0 {
+				t.Fatal("expected the object not to be opened")
+			}
+		})
+	}
+}
@@ -19,10 +19,80 @@ type File struct {
 	Bucket   string `json:"bucket"`
 	IsPublic bool   `json:"is_public"`
 
+	SHA256  string        `json:"sha256"`
+	Purpose UploadPurpose `json:"purpose"`
+	Status  FileStatus    `json:"status"`
+
 	IsConfirmed bool       `json:"is_confirmed"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+
+	// VariantsRetryAt is set while the variants of the image are pending, the variants are generated again after it,
+	// so the images of a full queue or a stopped instance get their variants. It is only saved with the file.
+	VariantsRetryAt *time.Time `json:"-"`
+}
+
+// FileVariant is an object derived from an image file, such as a thumbnail, Name is the name of the variant
+// in the image processing config.
+type FileVariant struct {
+	ID        types.ID  `json:"id"`
+	FileID    types.ID  `json:"file_id"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	MimeType  string    `json:"mime_type"`
+	Size      int64     `json:"size"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UploadSession is a resumable upload in progress, ID is also the id of the file when the upload is completed.
+// The uploaded size is kept by the storage, Size is the declared size of the file.
+type UploadSession struct {
+	ID              types.ID      `json:"id"`
+	UploaderID      types.ID      `json:"uploader_id"`
+	Filename        string        `json:"filename"`
+	Key             string        `json:"key"`
+	StorageUploadID string        `json:"-"`
+	Size            int64         `json:"size"`
+	IsPublic        bool          `json:"is_public"`
+	Purpose         UploadPurpose `json:"purpose"`
+	ExpiresAt       time.Time     `json:"expires_at"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// Blob is a stored object that is shared by the files with the same content, driver, bucket and visibility.
+// RefCount is the number of the files that use it, the object is removed when the last file is purged.
+// The files that don't have a blob, such as the files that are uploaded before the deduplication, own their object.
+type Blob struct {
+	Key       string
+	Driver    Driver
+	Bucket    string
+	SHA256    string
+	Size      int64
+	IsPublic  bool
+	RefCount  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// StorageUsage UsedBytes is the size of the files of the uploader that are not deleted, Limit is the quota of the
+// roles of the uploader and it is zero when the uploader is not limited.
+type StorageUsage struct {
+	UploaderID types.ID  `json:"uploader_id"`
+	UsedBytes  int64     `json:"used_bytes"`
+	FileCount  int64     `json:"file_count"`
+	Limit      int64     `json:"limit"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// StorageReservation holds the quota of an upload until its file is saved, it is ignored when it expires.
+type StorageReservation struct {
+	ID         types.ID
+	UploaderID types.ID
+	Size       int64
+	ExpiresAt  time.Time
 }
 
 type Driver string
@@ -32,10 +102,36 @@ const (
 	DriverLocal Driver = "local"
 )
 
+// UploadPurpose is where the file is used, each purpose has its own allow-list of mime types.
+type UploadPurpose string
+
+const (
+	UploadPurposeGeneral UploadPurpose = "general"
+	UploadPurposeStory   UploadPurpose = "story"
+	UploadPurposeChat    UploadPurpose = "chat"
+)
+
+// FileStatus a file of a direct upload is pending until the client uploads it to the storage and completes
+// the upload, the other files are ready when they are created.
+type FileStatus string
+
+const (
+	FileStatusPending FileStatus = "pending"
+	FileStatusReady   FileStatus = "ready"
+)
+
 func (f File) IsDeleted() bool {
 	return f.DeletedAt != nil
 }
 
+func (f File) IsReady() bool {
+	return f.Status == FileStatusReady
+}
+
 func IsValidDriver(driver Driver) bool {
 	return driver == DriverS3 || driver == DriverLocal
 }
+
+func IsValidUploadPurpose(purpose UploadPurpose) bool {
+	return purpose == UploadPurposeGeneral || purpose == UploadPurposeStory || purpose == UploadPurposeChat
+}
@@ -27,3 +27,27 @@ func (s Service) GetFileInfo(ctx context.Context, fileID types.ID) (File, error)
 
 	return file, nil
 }
+
+// GetFileByKey the key of a variant returns its parent file, the deleted files are not found.
+func (s Service) GetFileByKey(ctx context.Context, key string) (File, error) {
+	const op = "service.get_file.GetFileByKey"
+
+	exists, exErr := s.repo.IsExistByKey(ctx, key)
+	if exErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	if !exists {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	file, gErr := s.repo.GetByKey(ctx, key)
+	if gErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if file.IsDeleted() {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgFileNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	return file, nil
+}
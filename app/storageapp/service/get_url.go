@@ -9,7 +9,8 @@ import (
 	"github.com/syntaxfa/quick-connect/types"
 )
 
-func (s Service) GetPublicLink(ctx context.Context, fileID types.ID) (string, error) {
+// GetPublicLink returns the link of the variant when variant is not empty, see fileKey.
+func (s Service) GetPublicLink(ctx context.Context, fileID types.ID, variant string) (string, error) {
 	const op = "service.get_url.GetPublicLink"
 
 	exists, exErr := s.repo.IsExistByID(ctx, fileID)
@@ -25,11 +26,20 @@ func (s Service) GetPublicLink(ctx context.Context, fileID types.ID) (string, er
 		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gfErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	if !file.IsReady() {
+		return "", richerror.New(op).WithMessage(servermsg.MsgFileNotUploaded).WithKind(richerror.KindBadRequest)
+	}
+
 	if !file.IsPublic {
 		return "", richerror.New(op).WithMessage(servermsg.MsgFileInNotPublic)
 	}
 
-	url, gErr := s.storage.GetURL(ctx, file.Key)
+	key, kErr := s.fileKey(ctx, file, variant)
+	if kErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(kErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	url, gErr := s.storage.GetURL(ctx, key)
 	if gErr != nil {
 		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
@@ -37,7 +47,9 @@ func (s Service) GetPublicLink(ctx context.Context, fileID types.ID) (string, er
 	return url, nil
 }
 
-func (s Service) GetLink(ctx context.Context, fileID types.ID) (string, error) {
+// GetLink returns a presigned url for the private files, when userID is not empty the url is bound to the user
+// if the driver supports it. It returns the link of the variant when variant is not empty, see fileKey.
+func (s Service) GetLink(ctx context.Context, fileID, userID types.ID, variant string) (string, error) {
 	const op = "service.get_url.GetLink"
 
 	exists, exErr := s.repo.IsExistByID(ctx, fileID)
@@ -53,8 +65,17 @@ func (s Service) GetLink(ctx context.Context, fileID types.ID) (string, error) {
 		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gfErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	if !file.IsReady() {
+		return "", richerror.New(op).WithMessage(servermsg.MsgFileNotUploaded).WithKind(richerror.KindBadRequest)
+	}
+
+	key, kErr := s.fileKey(ctx, file, variant)
+	if kErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(kErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
 	if file.IsPublic {
-		url, gErr := s.storage.GetURL(ctx, file.Key)
+		url, gErr := s.storage.GetURL(ctx, key)
 		if gErr != nil {
 			return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
 		}
@@ -62,10 +83,35 @@ func (s Service) GetLink(ctx context.Context, fileID types.ID) (string, error) {
 		return url, nil
 	}
 
-	url, gErr := s.storage.GetPresignedURL(ctx, file.Key)
+	url, gErr := s.storage.GetPresignedURL(ctx, key, PresignOptions{UserID: userID})
 	if gErr != nil {
 		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
 	return url, nil
 }
+
+// fileKey returns the key of the variant of the file, the variants are generated after the upload, so the key
+// of the original file is returned until the variant is ready and for the files that don't have variants.
+func (s Service) fileKey(ctx context.Context, file File, variant string) (string, error) {
+	const op = "service.get_url.fileKey"
+
+	if variant == "" {
+		return file.Key, nil
+	}
+
+	exists, exErr := s.repo.IsExistVariant(ctx, file.ID, variant)
+	if exErr != nil {
+		return "", richerror.New(op).WithWrapError(exErr).WithKind(richerror.KindUnexpected)
+	}
+	if !exists {
+		return file.Key, nil
+	}
+
+	fileVariant, gErr := s.repo.GetVariant(ctx, file.ID, variant)
+	if gErr != nil {
+		return "", richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return fileVariant.Key, nil
+}
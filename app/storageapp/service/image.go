@@ -0,0 +1,411 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the gif decoder.
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the webp decoder.
+)
+
+type ImageFormat string
+
+const (
+	ImageFormatJPEG ImageFormat = "jpeg"
+	ImageFormatPNG  ImageFormat = "png"
+	ImageFormatWebP ImageFormat = "webp"
+)
+
+const (
+	defaultImageMaxPixels = 40_000_000
+	defaultImageTimeout   = time.Minute
+	defaultImageWorkers   = 2
+	defaultJPEGQuality    = 85
+	defaultMaxImageSize   = 25 << 20 // 25M
+)
+
+// the pending variants are claimed every imageRetryInterval, a claimed or a queued image is retried imageRetryDelay
+// after it, so it is longer than the queue wait and the processing of an image.
+const (
+	imageRetryInterval  = time.Minute
+	imageRetryDelay     = 10 * time.Minute
+	imageRetryBatchSize = 20
+)
+
+// imageJob is an image whose variants are generated by the image workers, data is nil when the image is read from
+// the storage, such as the retried images.
+type imageJob struct {
+	file File
+	data []byte
+}
+
+func imageWorkers(cfg ImageConfig) int {
+	if cfg.Workers > 0 {
+		return cfg.Workers
+	}
+
+	return defaultImageWorkers
+}
+
+func (s Service) imageTimeout() time.Duration {
+	if s.cfg.Image.Timeout > 0 {
+		return s.cfg.Image.Timeout
+	}
+
+	return defaultImageTimeout
+}
+
+// imageMimeTypes are the processable mime types and the format of their variants when the variant format is empty,
+// gif variants are png, because only the first frame of a gif is processed.
+var imageMimeTypes = map[string]ImageFormat{
+	"image/jpeg": ImageFormatJPEG,
+	"image/jpg":  ImageFormatJPEG,
+	"image/png":  ImageFormatPNG,
+	"image/gif":  ImageFormatPNG,
+	"image/webp": ImageFormatWebP,
+}
+
+func (f ImageFormat) mimeType() string {
+	return "image/" + string(f)
+}
+
+func (f ImageFormat) extension() string {
+	if f == ImageFormatJPEG {
+		return ".jpg"
+	}
+
+	return "." + string(f)
+}
+
+// maxImageSize is the size limit of the images that are read into memory to strip their metadata, it is MaxFileSize,
+// so the images of the resumable and the direct uploads are limited like the images of Upload.
+func (s Service) maxImageSize() int64 {
+	if s.cfg.MaxFileSize > 0 {
+		return s.cfg.MaxFileSize
+	}
+
+	return defaultMaxImageSize
+}
+
+// isProcessableImage reports whether the variants of the file are generated.
+func (s Service) isProcessableImage(contentType string) bool {
+	if !s.cfg.Image.Enabled || len(s.cfg.Image.Variants) == 0 {
+		return false
+	}
+
+	_, ok := imageMimeTypes[strings.ToLower(contentType)]
+
+	return ok
+}
+
+// pendingVariants returns the retry of the variants of a processable image, it is saved with the file.
+func (s Service) pendingVariants(mimeType string) *time.Time {
+	if !s.isProcessableImage(mimeType) {
+		return nil
+	}
+
+	retryAt := time.Now().Add(imageRetryDelay)
+
+	return &retryAt
+}
+
+// enqueueImage queues the image for the image workers without blocking the upload, the variants of an image that
+// doesn't fit in the queue are generated by the retry of the pending variants.
+func (s Service) enqueueImage(file File, data []byte) {
+	select {
+	case s.images <- imageJob{file: file, data: data}:
+	default:
+		s.logger.Warn("image queue is full, the variants are generated by the retry",
+			slog.String("file_id", string(file.ID)))
+	}
+}
+
+// RunImageWorkers generates the variants of the queued images by Image.Workers workers and queues the images whose
+// variants are pending again every imageRetryInterval until ctx is canceled. The variants of the images that are
+// in progress or queued when ctx is canceled are pending, so they are generated by the retry of another instance.
+func (s Service) RunImageWorkers(ctx context.Context) {
+	if !s.cfg.Image.Enabled || len(s.cfg.Image.Variants) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for range imageWorkers(s.cfg.Image) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case job := <-s.images:
+					s.processImage(ctx, job.file, job.data)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(imageRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.retryPendingVariants(ctx)
+		case <-ctx.Done():
+			wg.Wait()
+			s.logger.Info("stopping image workers")
+
+			return
+		}
+	}
+}
+
+// retryPendingVariants queues the images whose variants are due to retry, the images are read from the storage.
+func (s Service) retryPendingVariants(ctx context.Context) {
+	const op = "service.image.retryPendingVariants"
+
+	now := time.Now()
+	files, cErr := s.repo.ClaimPendingVariants(ctx, now, now.Add(imageRetryDelay), imageRetryBatchSize)
+	if cErr != nil {
+		if ctx.Err() == nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+
+		return
+	}
+
+	for _, file := range files {
+		select {
+		case s.images <- imageJob{file: file}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processImage generates the variants of the image file that don't exist, it runs after the upload, so the errors
+// are only logged. The variants stay pending when a variant is not generated, so it is retried, but an image that
+// can't be decoded is not retried.
+func (s Service) processImage(ctx context.Context, file File, data []byte) {
+	const op = "service.image.processImage"
+
+	ctx, cancel := context.WithTimeout(ctx, s.imageTimeout())
+	defer cancel()
+
+	if data == nil {
+		var rErr error
+		if data, rErr = s.readImage(ctx, file); rErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected).
+				WithMeta(map[string]interface{}{"file_id": file.ID}), s.logger)
+
+			return
+		}
+	}
+
+	src, dErr := s.decodeImage(data)
+	if dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"file_id": file.ID}), s.logger)
+		s.completeVariants(ctx, file)
+
+		return
+	}
+
+	variants, gErr := s.repo.GetVariantsByFileID(ctx, file.ID)
+	if gErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"file_id": file.ID}), s.logger)
+
+		return
+	}
+
+	created := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		created[variant.Name] = true
+	}
+
+	completed := true
+	for _, variantCfg := range s.cfg.Image.Variants {
+		if created[variantCfg.Name] {
+			continue
+		}
+
+		variant, vErr := s.createImageVariant(ctx, file, src, variantCfg)
+		if vErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(vErr).WithKind(richerror.KindUnexpected).
+				WithMeta(map[string]interface{}{"file_id": file.ID, "variant": variantCfg.Name}), s.logger)
+			completed = false
+
+			continue
+		}
+
+		s.logger.DebugContext(ctx, "image variant created", slog.String("file_id", string(file.ID)),
+			slog.String("variant", variant.Name))
+	}
+
+	if completed {
+		s.completeVariants(ctx, file)
+	}
+}
+
+// readImage reads the object of the image, the images larger than maxImageSize are rejected by the uploads.
+func (s Service) readImage(ctx context.Context, file File) ([]byte, error) {
+	object, oErr := s.storage.Open(ctx, file.Key)
+	if oErr != nil {
+		return nil, oErr
+	}
+	defer func() {
+		_ = object.Close()
+	}()
+
+	return io.ReadAll(io.LimitReader(object, s.maxImageSize()))
+}
+
+func (s Service) completeVariants(ctx context.Context, file File) {
+	const op = "service.image.completeVariants"
+
+	if cErr := s.repo.CompleteVariants(ctx, file.ID); cErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"file_id": file.ID}), s.logger)
+	}
+}
+
+func (s Service) decodeImage(data []byte) (image.Image, error) {
+	maxPixels := s.cfg.Image.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = defaultImageMaxPixels
+	}
+
+	imgCfg, _, cErr := image.DecodeConfig(bytes.NewReader(data))
+	if cErr != nil {
+		return nil, fmt.Errorf("can't decode image config: %w", cErr)
+	}
+
+	if imgCfg.Width <= 0 || imgCfg.Height <= 0 || imgCfg.Width*imgCfg.Height > maxPixels {
+		return nil, fmt.Errorf("image size %dx%d is not supported", imgCfg.Width, imgCfg.Height)
+	}
+
+	src, _, dErr := image.Decode(bytes.NewReader(data))
+	if dErr != nil {
+		return nil, fmt.Errorf("can't decode image: %w", dErr)
+	}
+
+	return src, nil
+}
+
+func (s Service) createImageVariant(ctx context.Context, file File, src image.Image, cfg ImageVariantConfig) (FileVariant, error) {
+	format := cfg.Format
+	if format == "" {
+		format = imageMimeTypes[strings.ToLower(file.MimeType)]
+	}
+
+	dst := resizeImage(src, cfg.Width, cfg.Height, cfg.Crop)
+
+	var buf bytes.Buffer
+	if eErr := encodeImage(&buf, dst, format, cfg.Quality); eErr != nil {
+		return FileVariant{}, eErr
+	}
+
+	variant := FileVariant{
+		ID:        types.ID(ulid.Make().String()),
+		FileID:    file.ID,
+		Name:      cfg.Name,
+		Key:       fmt.Sprintf("variants/%s/%s%s", file.ID, cfg.Name, format.extension()),
+		MimeType:  format.mimeType(),
+		Size:      int64(buf.Len()),
+		Width:     dst.Bounds().Dx(),
+		Height:    dst.Bounds().Dy(),
+		CreatedAt: time.Now(),
+	}
+
+	uploadKey, uErr := s.storage.Upload(ctx, &buf, variant.Size, variant.Key, variant.MimeType, file.IsPublic)
+	if uErr != nil {
+		return FileVariant{}, uErr
+	}
+	variant.Key = uploadKey
+
+	if sErr := s.repo.SaveVariant(ctx, variant); sErr != nil {
+		return FileVariant{}, sErr
+	}
+
+	return variant, nil
+}
+
+// resizeImage resizes the image to fit in the width and height, or to fill them when crop is true.
+// A zero width or height is calculated by the aspect ratio and the image is never enlarged.
+func resizeImage(src image.Image, width, height int, crop bool) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	if width <= 0 && height <= 0 {
+		return src
+	}
+	if width <= 0 {
+		width = srcWidth * height / srcHeight
+	}
+	if height <= 0 {
+		height = srcHeight * width / srcWidth
+	}
+
+	srcRect := bounds
+	dstWidth, dstHeight := width, height
+
+	if crop {
+		// the largest center part of the image with the aspect ratio of the variant.
+		cropWidth, cropHeight := srcWidth, srcWidth*height/width
+		if cropHeight > srcHeight {
+			cropWidth, cropHeight = srcHeight*width/height, srcHeight
+		}
+
+		x := bounds.Min.X + (srcWidth-cropWidth)/2
+		y := bounds.Min.Y + (srcHeight-cropHeight)/2
+		srcRect = image.Rect(x, y, x+cropWidth, y+cropHeight)
+
+		if cropWidth < dstWidth {
+			dstWidth, dstHeight = cropWidth, cropHeight
+		}
+	} else {
+		scale := min(float64(width)/float64(srcWidth), float64(height)/float64(srcHeight), 1)
+		dstWidth = max(int(math.Round(float64(srcWidth)*scale)), 1)
+		dstHeight = max(int(math.Round(float64(srcHeight)*scale)), 1)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, srcRect, draw.Src, nil)
+
+	return dst
+}
+
+func encodeImage(buf *bytes.Buffer, img image.Image, format ImageFormat, quality int) error {
+	switch format {
+	case ImageFormatJPEG:
+		if quality <= 0 || quality > 100 {
+			quality = defaultJPEGQuality
+		}
+
+		return jpeg.Encode(buf, img, &jpeg.Options{Quality: quality})
+	case ImageFormatPNG:
+		return png.Encode(buf, img)
+	case ImageFormatWebP:
+		return nativewebp.Encode(buf, img, nil)
+	default:
+		return fmt.Errorf("image format %s is not supported", format)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResizeImage(t *testing.T) {
+	// a 400x200 image whose center square is green and whose sides are red.
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for x := range 400 {
+		for y := range 200 {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 100 && x < 300 {
+				c = color.RGBA{G: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		src            image.Image
+		width, height  int
+		crop           bool
+		expectedWidth  int
+		expectedHeight int
+		centerOnly     bool
+	}{
+		{name: "fit in a square", src: src, width: 100, height: 100, expectedWidth: 100, expectedHeight: 50},
+		{name: "fit by width", src: src, width: 100, expectedWidth: 100, expectedHeight: 50},
+		{name: "fit by height", src: src, height: 50, expectedWidth: 100, expectedHeight: 50},
+		{name: "fit is not enlarged", src: src, width: 800, height: 800, expectedWidth: 400, expectedHeight: 200},
+		{name: "crop to a square", src: src, width: 100, height: 100, crop: true, expectedWidth: 100, expectedHeight: 100,
+			centerOnly: true},
+		{name: "crop is not enlarged", src: src, width: 800, height: 800, crop: true, expectedWidth: 200, expectedHeight: 200,
+			centerOnly: true},
+		{name: "crop of a sub image", src: src.SubImage(image.Rect(100, 0, 400, 200)), width: 100, height: 100, crop: true,
+			expectedWidth: 100, expectedHeight: 100},
+		{name: "no size", src: src, expectedWidth: 400, expectedHeight: 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := resizeImage(test.src, test.width, test.height, test.crop)
+
+			bounds := dst.Bounds()
+			if bounds.Dx() != test.expectedWidth || bounds.Dy() != test.expectedHeight {
+				t.Fatalf("expected %dx%d, got %dx%d", test.expectedWidth, test.expectedHeight, bounds.Dx(), bounds.Dy())
+			}
+
+			if !test.centerOnly {
+				return
+			}
+
+			for _, point := range []image.Point{bounds.Min, {X: bounds.Max.X - 1, Y: bounds.Max.Y - 1}} {
+				if r, g, _, _ := dst.At(point.X, point.Y).RGBA(); r > 0x1000 || g < 0xf000 {
+					t.Fatalf("expected the cropped image to be the center of the source, got %v at %v", dst.At(point.X, point.Y), point)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessImage(t *testing.T) {
+	var buf bytes.Buffer
+	if eErr := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); eErr != nil {
+		t.Fatalf("encode image: %v", eErr)
+	}
+	data := buf.Bytes()
+
+	variants := []ImageVariantConfig{
+		{Name: "thumbnail", Width: 100, Height: 100, Crop: true, Format: ImageFormatJPEG},
+		{Name: "medium", Width: 200},
+	}
+
+	tests := []struct {
+		name          string
+		variants      []ImageVariantConfig
+		data          []byte
+		stored        []byte
+		existing      []string
+		wantVariants  []string
+		wantCompleted bool
+	}{
+		{name: "uploaded image", variants: variants, data: data, wantVariants: []string{"thumbnail", "medium"},
+			wantCompleted: true},
+		{name: "retried image is read from the storage", variants: variants, stored: data,
+			wantVariants: []string{"thumbnail", "medium"}, wantCompleted: true},
+		{name: "existing variant is not generated again", variants: variants, data: data, existing: []string{"thumbnail"},
+			wantVariants: []string{"thumbnail", "medium"}, wantCompleted: true},
+		{name: "failed variant stays pending", data: data,
+			variants:     []ImageVariantConfig{variants[0], {Name: "gif", Width: 100, Format: "gif"}},
+			wantVariants: []string{"thumbnail"}},
+		{name: "missing object stays pending", variants: variants},
+		{name: "invalid image is not retried", variants: variants, data: []byte("not an image"), wantCompleted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := File{ID: "file-1", Key: "uploads/file-1.png", MimeType: "image/png"}
+
+			storage := newMemStorage()
+			if test.stored != nil {
+				storage.put(file.Key, test.stored)
+			}
+
+			repo := newMemRepository(file)
+			for _, name := range test.existing {
+				repo.variants[file.Key] = append(repo.variants[file.Key], FileVariant{FileID: file.ID, Name: name})
+			}
+
+			cfg := Config{Image: ImageConfig{Enabled: true, Variants: test.variants}}
+			svc := New(cfg, storage, repo, discardLogger())
+
+			svc.processImage(context.Background(), file, test.data)
+
+			var names []string
+			for _, variant := range repo.variants[file.Key] {
+				names = append(names, variant.Name)
+			}
+			sort.Strings(names)
+			wantVariants := append([]string(nil), test.wantVariants...)
+			sort.Strings(wantVariants)
+
+			if strings.Join(names, ",") != strings.Join(wantVariants, ",") {
+				t.Fatalf("expected variants %v, got %v", wantVariants, names)
+			}
+
+			if completed := len(repo.completed) == 1; completed != test.wantCompleted {
+				t.Fatalf("expected completed %t, got %v", test.wantCompleted, repo.completed)
+			}
+		})
+	}
+}
+
+func TestEnqueueImage(t *testing.T) {
+	cfg := Config{Image: ImageConfig{Enabled: true, Workers: 1, Variants: []ImageVariantConfig{{Name: "thumbnail"}}}}
+	svc := New(cfg, newMemStorage(), newMemRepository(), discardLogger())
+
+	// the image that doesn't fit in the queue is left to the retry of the pending variants.
+	svc.enqueueImage(File{ID: "file-1"}, nil)
+	svc.enqueueImage(File{ID: "file-2"}, nil)
+
+	if queued := len(svc.images); queued != 1 {
+		t.Fatalf("expected 1 queued image, got %d", queued)
+	}
+
+	if job := <-svc.images; job.file.ID != "file-1" {
+		t.Fatalf("expected the first image in the queue, got %s", job.file.ID)
+	}
+}
+
+func TestPendingVariants(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         ImageConfig
+		mimeType    string
+		wantPending bool
+	}{
+		{name: "processable image", cfg: ImageConfig{Enabled: true, Variants: []ImageVariantConfig{{Name: "thumbnail"}}},
+			mimeType: "image/png", wantPending: true},
+		{name: "not an image", cfg: ImageConfig{Enabled: true, Variants: []ImageVariantConfig{{Name: "thumbnail"}}},
+			mimeType: "text/plain"},
+		{name: "disabled", cfg: ImageConfig{Variants: []ImageVariantConfig{{Name: "thumbnail"}}}, mimeType: "image/png"},
+		{name: "without variants", cfg: ImageConfig{Enabled: true}, mimeType: "image/png"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := New(Config{Image: test.cfg}, newMemStorage(), newMemRepository(), discardLogger())
+
+			retryAt := svc.pendingVariants(test.mimeType)
+			if (retryAt != nil) != test.wantPending {
+				t.Fatalf("expected pending %t, got %v", test.wantPending, retryAt)
+			}
+
+			if retryAt != nil && !retryAt.After(time.Now()) {
+				t.Fatalf("expected the retry after the upload, got %s", retryAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// an instance is running while its heartbeat is not older than instanceHeartbeatTTL.
+const (
+	instanceHeartbeatInterval = 15 * time.Second
+	instanceHeartbeatTTL      = 3 * instanceHeartbeatInterval
+)
+
+// RunHeartbeat records that the instance is running every instanceHeartbeatInterval until ctx is canceled, then it
+// removes the heartbeat. MigrateDriver doesn't run while an instance is running, because the instances use the
+// storage of their driver for all the files.
+func (s Service) RunHeartbeat(ctx context.Context) {
+	const op = "service.instance.RunHeartbeat"
+
+	instanceID := types.ID(ulid.Make().String())
+
+	ticker := time.NewTicker(instanceHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		if sErr := s.repo.SaveInstanceHeartbeat(ctx, instanceID); sErr != nil && ctx.Err() == nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			if dErr := s.repo.DeleteInstanceHeartbeat(context.WithoutCancel(ctx), instanceID); dErr != nil {
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// janitorLockID is the postgres advisory lock of the janitor, only the instance that holds it runs the janitor.
+const janitorLockID int64 = 7_358_126_409
+
+const (
+	defaultJanitorInterval           = time.Hour
+	defaultJanitorBatchSize          = 100
+	defaultJanitorUnconfirmedTTL     = 24 * time.Hour
+	defaultJanitorDeletedGracePeriod = 7 * 24 * time.Hour
+)
+
+// janitorUnconfirmedTTL a non-positive ttl would purge the files that are just uploaded, so it is defaulted like
+// the grace period of the deleted files.
+func (s Service) janitorUnconfirmedTTL() time.Duration {
+	if s.cfg.Janitor.UnconfirmedTTL > 0 {
+		return s.cfg.Janitor.UnconfirmedTTL
+	}
+
+	return defaultJanitorUnconfirmedTTL
+}
+
+func (s Service) janitorDeletedGracePeriod() time.Duration {
+	if s.cfg.Janitor.DeletedGracePeriod > 0 {
+		return s.cfg.Janitor.DeletedGracePeriod
+	}
+
+	return defaultJanitorDeletedGracePeriod
+}
+
+// RunJanitor purges the unconfirmed files, the deleted files, the expired resumable uploads, the released blobs,
+// the expired upload leases, the expired quota reservations and the stale instance heartbeats from the storage and
+// the database every Janitor.Interval until ctx is canceled.
+func (s Service) RunJanitor(ctx context.Context) {
+	interval := s.cfg.Janitor.Interval
+	if interval <= 0 {
+		interval = defaultJanitorInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if rErr := s.runJanitor(ctx); rErr != nil {
+			errlog.WithoutErrContext(ctx, rErr, s.logger)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			s.logger.Info("stopping storage janitor")
+
+			return
+		}
+	}
+}
+
+func (s Service) runJanitor(ctx context.Context) error {
+	const op = "service.janitor.runJanitor"
+
+	release, acquired, lErr := s.repo.TryLock(ctx, janitorLockID)
+	if lErr != nil {
+		return richerror.New(op).WithWrapError(lErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if !acquired {
+		s.logger.DebugContext(ctx, "storage janitor is running on another instance")
+
+		return nil
+	}
+
+	defer func() {
+		if rErr := release(); rErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}()
+
+	now := time.Now()
+
+	if pErr := s.purgeFiles(ctx, purgeReasonUnconfirmed, now.Add(-s.janitorUnconfirmedTTL()),
+		s.repo.GetUnconfirmedFiles, s.repo.PurgeUnconfirmedByID); pErr != nil {
+		return richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if pErr := s.purgeFiles(ctx, purgeReasonDeleted, now.Add(-s.janitorDeletedGracePeriod()),
+		s.repo.GetDeletedFiles, s.repo.PurgeDeletedByID); pErr != nil {
+		return richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if pErr := s.purgeUploadSessions(ctx, now); pErr != nil {
+		return richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if pErr := s.purgeReleasedBlobs(ctx); pErr != nil {
+		return richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if dErr := s.repo.DeleteExpiredUploadLeases(ctx, now); dErr != nil {
+		return richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if dErr := s.repo.DeleteExpiredStorageReservations(ctx, now); dErr != nil {
+		return richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if dErr := s.repo.DeleteStaleInstanceHeartbeats(ctx, now.Add(-instanceHeartbeatTTL)); dErr != nil {
+		return richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected)
+	}
+
+	return nil
+}
+
+type getFilesFunc func(ctx context.Context, before time.Time, afterID types.ID, limit int) ([]File, error)
+
+// purgeFileFunc removes the row of the file when it still matches the purge, it returns false otherwise.
+type purgeFileFunc func(ctx context.Context, fileID types.ID) (bool, error)
+
+// purgeFiles removes the row of each file by purgeRow and then its objects from the storage, a file whose row can't
+// be removed is skipped and it is retried in the next run.
+func (s Service) purgeFiles(ctx context.Context, reason string, before time.Time, getFiles getFilesFunc, purgeRow purgeFileFunc) error {
+	const op = "service.janitor.purgeFiles"
+
+	batchSize := s.cfg.Janitor.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultJanitorBatchSize
+	}
+
+	var afterID types.ID
+	for {
+		files, gErr := getFiles(ctx, before, afterID, batchSize)
+		if gErr != nil {
+			return richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		for _, file := range files {
+			afterID = file.ID
+
+			if s.cfg.Janitor.DryRun {
+				s.logger.InfoContext(ctx, "storage janitor dry run, file would be purged", slog.String("file_id", string(file.ID)),
+					slog.String("key", file.Key), slog.String("reason", reason))
+				s.janitorMetrics.addPurged(ctx, reason, true, file.Size)
+
+				continue
+			}
+
+			purged, pErr := s.purgeFile(ctx, file, purgeRow)
+			if pErr != nil {
+				s.janitorMetrics.addFailure(ctx, reason)
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected).
+					WithMeta(map[string]interface{}{"file_id": file.ID, "key": file.Key}), s.logger)
+
+				continue
+			}
+
+			if !purged {
+				continue
+			}
+
+			s.logger.DebugContext(ctx, "file purged by storage janitor", slog.String("file_id", string(file.ID)),
+				slog.String("reason", reason))
+			s.janitorMetrics.addPurged(ctx, reason, false, file.Size)
+		}
+
+		if len(files) < batchSize {
+			return nil
+		}
+	}
+}
+
+// purgeUploadSessions aborts the resumable uploads that are expired before the time, an upload that is being written
+// is skipped. The size of the purged uploads is not counted, because it is only known by the storage.
+func (s Service) purgeUploadSessions(ctx context.Context, before time.Time) error {
+	const op = "service.janitor.purgeUploadSessions"
+
+	batchSize := s.cfg.Janitor.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultJanitorBatchSize
+	}
+
+	var afterID types.ID
+	for {
+		sessions, gErr := s.repo.GetExpiredUploadSessions(ctx, before, afterID, batchSize)
+		if gErr != nil {
+			return richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		for _, session := range sessions {
+			afterID = session.ID
+
+			if s.cfg.Janitor.DryRun {
+				s.logger.InfoContext(ctx, "storage janitor dry run, upload would be purged", slog.String("upload_id", string(session.ID)),
+					slog.String("key", session.Key))
+				s.janitorMetrics.addPurged(ctx, purgeReasonExpired, true, 0)
+
+				continue
+			}
+
+			purged, aErr := s.abortExpiredUpload(ctx, session)
+			if aErr != nil {
+				s.janitorMetrics.addFailure(ctx, purgeReasonExpired)
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected).
+					WithMeta(map[string]interface{}{"upload_id": session.ID, "key": session.Key}), s.logger)
+
+				continue
+			}
+
+			if !purged {
+				continue
+			}
+
+			s.logger.DebugContext(ctx, "upload purged by storage janitor", slog.String("upload_id", string(session.ID)))
+			s.janitorMetrics.addPurged(ctx, purgeReasonExpired, false, 0)
+		}
+
+		if len(sessions) < batchSize {
+			return nil
+		}
+	}
+}
+
+// abortExpiredUpload removes the chunks and the session of the upload, it returns false when the upload is being
+// written. The object of an upload that is completed without creating its file is removed too.
+func (s Service) abortExpiredUpload(ctx context.Context, session UploadSession) (bool, error) {
+	release, acquired, lErr := s.leaseUpload(ctx, session.ID)
+	if lErr != nil {
+		return false, lErr
+	}
+
+	if !acquired {
+		return false, nil
+	}
+	defer release()
+
+	if aErr := s.storage.AbortResumableUpload(ctx, session.Key, session.StorageUploadID); aErr != nil {
+		return false, aErr
+	}
+
+	exists, eErr := s.repo.IsExistByID(ctx, session.ID)
+	if eErr != nil {
+		return false, eErr
+	}
+
+	if !exists {
+		if dErr := s.storage.Delete(ctx, session.Key); dErr != nil {
+			return false, dErr
+		}
+	}
+
+	if dErr := s.repo.DeleteUploadSession(ctx, session.ID); dErr != nil {
+		return false, dErr
+	}
+
+	return true, nil
+}
+
+// purgeReleasedBlobs removes the blobs that are released by the purged files but their object is not removed,
+// e.g. the removal of the object is failed after the last file is purged.
+func (s Service) purgeReleasedBlobs(ctx context.Context) error {
+	const op = "service.janitor.purgeReleasedBlobs"
+
+	batchSize := s.cfg.Janitor.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultJanitorBatchSize
+	}
+
+	var afterKey string
+	for {
+		blobs, gErr := s.repo.GetReleasedBlobs(ctx, afterKey, batchSize)
+		if gErr != nil {
+			return richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		for _, blob := range blobs {
+			afterKey = blob.Key
+
+			if s.cfg.Janitor.DryRun {
+				s.logger.InfoContext(ctx, "storage janitor dry run, blob would be purged", slog.String("key", blob.Key))
+				s.janitorMetrics.addPurged(ctx, purgeReasonReleased, true, blob.Size)
+
+				continue
+			}
+
+			purged, dErr := s.deleteReleasedBlob(ctx, blob.Key)
+			if dErr != nil {
+				s.janitorMetrics.addFailure(ctx, purgeReasonReleased)
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+					WithMeta(map[string]interface{}{"key": blob.Key}), s.logger)
+
+				continue
+			}
+
+			if !purged {
+				continue
+			}
+
+			s.logger.DebugContext(ctx, "blob purged by storage janitor", slog.String("key", blob.Key))
+			s.janitorMetrics.addPurged(ctx, purgeReasonReleased, false, blob.Size)
+		}
+
+		if len(blobs) < batchSize {
+			return nil
+		}
+	}
+}
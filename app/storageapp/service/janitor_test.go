@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestPurgeFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      FileStatus
+		rowPurged   bool
+		wantPurged  bool
+		wantDeleted bool
+	}{
+		{name: "purged file", status: FileStatusReady, rowPurged: true, wantPurged: true, wantDeleted: true},
+		{name: "pending direct upload", status: FileStatusPending, rowPurged: true, wantPurged: true, wantDeleted: true},
+		{name: "file changed after it is selected", status: FileStatusReady, rowPurged: false, wantPurged: false,
+			wantDeleted: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := File{ID: "file-1", Key: "uploads/file-1.png", Status: test.status}
+			variant := FileVariant{ID: "variant-1", FileID: file.ID, Name: "thumbnail", Key: "variants/file-1.png"}
+
+			storage := newMemStorage()
+			keys := []string{file.Key, variant.Key, directUploadKey(file.Key)}
+			for _, key := range keys {
+				storage.put(key, []byte("content"))
+			}
+
+			repo := newMemRepository(file)
+			repo.variants[file.Key] = []FileVariant{variant}
+			svc := New(Config{}, storage, repo, discardLogger())
+
+			purged, pErr := svc.purgeFile(context.Background(), file, func(_ context.Context, _ types.ID) (bool, error) {
+				return test.rowPurged, nil
+			})
+			if pErr != nil {
+				t.Fatalf("unexpected error: %v", pErr)
+			}
+
+			if purged != test.wantPurged {
+				t.Fatalf("expected purged %t, got %t", test.wantPurged, purged)
+			}
+
+			for _, key := range keys {
+				// the uploaded object is only removed for the pending files.
+				wantDeleted := test.wantDeleted && (key != directUploadKey(file.Key) || test.status == FileStatusPending)
+				if _, ok := storage.get(key); ok == wantDeleted {
+					t.Fatalf("expected the object %q deleted %t", key, wantDeleted)
+				}
+			}
+		})
+	}
+}
+
+func TestJanitorDurations(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             JanitorConfig
+		wantTTL         time.Duration
+		wantGracePeriod time.Duration
+	}{
+		{name: "configured", cfg: JanitorConfig{UnconfirmedTTL: time.Hour, DeletedGracePeriod: 2 * time.Hour},
+			wantTTL: time.Hour, wantGracePeriod: 2 * time.Hour},
+		{name: "zero", cfg: JanitorConfig{}, wantTTL: defaultJanitorUnconfirmedTTL,
+			wantGracePeriod: defaultJanitorDeletedGracePeriod},
+		{name: "negative", cfg: JanitorConfig{UnconfirmedTTL: -time.Hour, DeletedGracePeriod: -time.Hour},
+			wantTTL: defaultJanitorUnconfirmedTTL, wantGracePeriod: defaultJanitorDeletedGracePeriod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := New(Config{Janitor: test.cfg}, newMemStorage(), newMemRepository(), discardLogger())
+
+			if ttl := svc.janitorUnconfirmedTTL(); ttl != test.wantTTL {
+				t.Fatalf("expected ttl %s, got %s", test.wantTTL, ttl)
+			}
+
+			if gracePeriod := svc.janitorDeletedGracePeriod(); gracePeriod != test.wantGracePeriod {
+				t.Fatalf("expected grace period %s, got %s", test.wantGracePeriod, gracePeriod)
+			}
+		})
+	}
+}
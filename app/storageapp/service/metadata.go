@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errInvalidImage = errors.New("image is not valid")
+
+// stripImageMetadata removes the EXIF, XMP and text metadata of the jpeg, png and webp images, such as the GPS
+// location and the camera details. The image data is not decoded, so the quality of the image does not change.
+// The other mime types are returned as they are.
+func stripImageMetadata(mimeType string, data []byte) ([]byte, error) {
+	switch mimeType {
+	case "image/jpeg":
+		return stripJPEGMetadata(data)
+	case "image/png":
+		return stripPNGMetadata(data)
+	case "image/webp":
+		return stripWebPMetadata(data)
+	default:
+		return data, nil
+	}
+}
+
+// JPEG markers, APP1 has the EXIF and XMP metadata and APP13 has the IPTC metadata.
+const (
+	jpegMarkerPrefix = 0xFF
+	jpegMarkerSOI    = 0xD8
+	jpegMarkerSOS    = 0xDA
+	jpegMarkerAPP1   = 0xE1
+	jpegMarkerAPP13  = 0xED
+	jpegMarkerTEM    = 0x01
+	jpegMarkerRST0   = 0xD0
+	jpegMarkerRST7   = 0xD7
+)
+
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != jpegMarkerPrefix || data[1] != jpegMarkerSOI {
+		return nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	for i := 2; i < len(data); {
+		if data[i] != jpegMarkerPrefix || i+1 >= len(data) {
+			return nil, errInvalidImage
+		}
+
+		marker := data[i+1]
+		if marker == jpegMarkerPrefix {
+			// fill bytes before a marker.
+			i++
+
+			continue
+		}
+
+		if marker == jpegMarkerTEM || (marker >= jpegMarkerRST0 && marker <= jpegMarkerRST7) {
+			out.Write(data[i : i+2])
+			i += 2
+
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, errInvalidImage
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			return nil, errInvalidImage
+		}
+
+		if marker == jpegMarkerSOS {
+			// the compressed image data and the rest of the file don't have metadata segments.
+			out.Write(data[i:])
+
+			return out.Bytes(), nil
+		}
+
+		if marker != jpegMarkerAPP1 && marker != jpegMarkerAPP13 {
+			out.Write(data[i:end])
+		}
+
+		i = end
+	}
+
+	return out.Bytes(), nil
+}
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+// pngMetadataChunks are the ancillary chunks that have metadata.
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	// each chunk is the length, the type, the data and the crc.
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, errInvalidImage
+		}
+
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:i+4]))
+		if end > len(data) || end < i {
+			return nil, errInvalidImage
+		}
+
+		if !pngMetadataChunks[string(data[i+4:i+8])] {
+			out.Write(data[i:end])
+		}
+
+		i = end
+	}
+
+	return out.Bytes(), nil
+}
+
+// VP8X flags of the metadata chunks.
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+
+	// each chunk is the FourCC, the little endian size and the data which is padded to an even size.
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, errInvalidImage
+		}
+
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if end > len(data) {
+			// some encoders don't write the padding byte of the last chunk.
+			end = i + 8 + size
+		}
+		if end > len(data) || end < i {
+			return nil, errInvalidImage
+		}
+
+		switch fourCC := string(data[i : i+4]); fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := bytes.Clone(data[i:end])
+			if len(chunk) > 8 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+
+		i = end
+	}
+
+	stripped := out.Bytes()
+	binary.LittleEndian.PutUint32(stripped[4:8], uint32(len(stripped)-8)) //nolint:gosec // G115: size of the uploaded files is limited
+
+	return stripped, nil
+}
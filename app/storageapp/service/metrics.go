@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const meterName = "github.com/syntaxfa/quick-connect/app/storageapp"
+
+// Reasons of the purged files.
+const (
+	purgeReasonUnconfirmed = "unconfirmed"
+	purgeReasonDeleted     = "deleted"
+	purgeReasonExpired     = "expired_upload"
+	purgeReasonReleased    = "released_blob"
+)
+
+// janitorMetrics uses the global meter provider, so it is a no-op until a provider is registered.
+type janitorMetrics struct {
+	purgedFiles metric.Int64Counter
+	purgedBytes metric.Int64Counter
+	failures    metric.Int64Counter
+}
+
+func newJanitorMetrics() janitorMetrics {
+	meter := otel.Meter(meterName)
+
+	purgedFiles, fErr := meter.Int64Counter("storage.janitor.purged_files",
+		metric.WithDescription("Number of files purged by the janitor"))
+	if fErr != nil {
+		purgedFiles = noop.Int64Counter{}
+	}
+
+	purgedBytes, bErr := meter.Int64Counter("storage.janitor.purged_bytes",
+		metric.WithDescription("Size of the files purged by the janitor"), metric.WithUnit("By"))
+	if bErr != nil {
+		purgedBytes = noop.Int64Counter{}
+	}
+
+	failures, eErr := meter.Int64Counter("storage.janitor.failures",
+		metric.WithDescription("Number of files the janitor failed to purge"))
+	if eErr != nil {
+		failures = noop.Int64Counter{}
+	}
+
+	return janitorMetrics{
+		purgedFiles: purgedFiles,
+		purgedBytes: purgedBytes,
+		failures:    failures,
+	}
+}
+
+func (m janitorMetrics) addPurged(ctx context.Context, reason string, dryRun bool, size int64) {
+	attrs := metric.WithAttributes(attribute.String("reason", reason), attribute.Bool("dry_run", dryRun))
+
+	m.purgedFiles.Add(ctx, 1, attrs)
+	m.purgedBytes.Add(ctx, size, attrs)
+}
+
+func (m janitorMetrics) addFailure(ctx context.Context, reason string) {
+	m.failures.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// dedupMetrics counts the uploads that reuse the object of an identical file and the storage they save.
+type dedupMetrics struct {
+	deduplicatedFiles metric.Int64Counter
+	savedBytes        metric.Int64Counter
+}
+
+func newDedupMetrics() dedupMetrics {
+	meter := otel.Meter(meterName)
+
+	deduplicatedFiles, fErr := meter.Int64Counter("storage.dedup.files",
+		metric.WithDescription("Number of uploaded files that reuse the object of an identical file"))
+	if fErr != nil {
+		deduplicatedFiles = noop.Int64Counter{}
+	}
+
+	savedBytes, bErr := meter.Int64Counter("storage.dedup.saved_bytes",
+		metric.WithDescription("Size of the uploaded files that are not stored because of the deduplication"), metric.WithUnit("By"))
+	if bErr != nil {
+		savedBytes = noop.Int64Counter{}
+	}
+
+	return dedupMetrics{
+		deduplicatedFiles: deduplicatedFiles,
+		savedBytes:        savedBytes,
+	}
+}
+
+func (m dedupMetrics) addDeduplicated(ctx context.Context, driver Driver, size int64) {
+	attrs := metric.WithAttributes(attribute.String("driver", string(driver)))
+
+	m.deduplicatedFiles.Add(ctx, 1, attrs)
+	m.savedBytes.Add(ctx, size, attrs)
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+const defaultMigrateBatchSize = 100
+
+// ErrJanitorRunning is returned by MigrateDriver when the janitor of a running instance holds its lock.
+var ErrJanitorRunning = errors.New("storage janitor is running")
+
+// ErrInstanceRunning is returned by MigrateDriver when a storage instance is running, see RunHeartbeat.
+var ErrInstanceRunning = errors.New("storage instance is running")
+
+// MigrateDriver copies the objects of the ready files of req.From from the storage of the service to target and moves
+// the files to req.To, each object is verified by its checksum before its files are moved. The migration is resumable,
+// the moved files are not selected again, so a failed object is retried by running it again.
+// The migration is refused while a storage instance is running, because the files that are uploaded during the migration
+// would be left on req.From and the moved files would be served and purged from the storage of the running instance.
+// The janitor lock is held during the migration too, so the objects are not purged while they are copied.
+func (s Service) MigrateDriver(ctx context.Context, target Storage, req MigrateDriverRequest) (MigrateDriverResult, error) {
+	const op = "service.migrate_driver.MigrateDriver"
+
+	if !IsValidDriver(req.From) || !IsValidDriver(req.To) || req.From == req.To {
+		return MigrateDriverResult{}, richerror.New(op).WithWrapError(fmt.Errorf("can't migrate from %s to %s", req.From, req.To)).
+			WithKind(richerror.KindInvalid)
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMigrateBatchSize
+	}
+
+	release, acquired, lErr := s.repo.TryLock(ctx, janitorLockID)
+	if lErr != nil {
+		return MigrateDriverResult{}, richerror.New(op).WithWrapError(lErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if !acquired {
+		return MigrateDriverResult{}, richerror.New(op).WithWrapError(ErrJanitorRunning).WithKind(richerror.KindConflict)
+	}
+
+	defer func() {
+		if rErr := release(); rErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}()
+
+	running, iErr := s.repo.IsExistLiveInstance(ctx, time.Now().Add(-instanceHeartbeatTTL))
+	if iErr != nil {
+		return MigrateDriverResult{}, richerror.New(op).WithWrapError(iErr).WithKind(richerror.KindUnexpected)
+	}
+
+	if running {
+		return MigrateDriverResult{}, richerror.New(op).WithWrapError(ErrInstanceRunning).WithKind(richerror.KindConflict)
+	}
+
+	var result MigrateDriverResult
+	var afterKey string
+	for {
+		files, gErr := s.repo.GetFilesByDriver(ctx, req.From, req.FromBucket, afterKey, batchSize)
+		if gErr != nil {
+			return result, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected)
+		}
+
+		for _, file := range files {
+			afterKey = file.Key
+
+			if cErr := ctx.Err(); cErr != nil {
+				return result, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected)
+			}
+
+			if mErr := s.migrateObject(ctx, target, file, req, &result); mErr != nil {
+				result.Failed++
+				errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected).
+					WithMeta(map[string]interface{}{"file_id": file.ID, "key": file.Key}), s.logger)
+			}
+		}
+
+		s.logger.InfoContext(ctx, "storage migration batch is done", slog.Int("objects", result.Objects),
+			slog.Int("failed", result.Failed), slog.String("after_key", afterKey))
+
+		if len(files) < batchSize {
+			return result, nil
+		}
+	}
+}
+
+// migrateObject copies the object of the file and the variants of the files that share it to target, then moves the
+// files. The copied object is removed when the files are merged into an identical blob of the target.
+func (s Service) migrateObject(ctx context.Context, target Storage, file File, req MigrateDriverRequest,
+	result *MigrateDriverResult) error {
+	const op = "service.migrate_driver.migrateObject"
+
+	variants, gErr := s.repo.GetVariantsByFileKey(ctx, req.From, file.Key)
+	if gErr != nil {
+		return gErr
+	}
+
+	if req.DryRun {
+		s.logger.InfoContext(ctx, "storage migration dry run, object would be migrated", slog.String("key", file.Key),
+			slog.Int("variants", len(variants)))
+		result.Objects++
+		result.Variants += len(variants)
+		result.Bytes += file.Size
+
+		return nil
+	}
+
+	key := req.KeyPrefix + file.Key
+	if cErr := s.copyObject(ctx, target, file.Key, key, file.MimeType, file.Size, file.IsPublic, file.SHA256); cErr != nil {
+		return cErr
+	}
+
+	for _, variant := range variants {
+		if cErr := s.copyObject(ctx, target, variant.Key, req.KeyPrefix+variant.Key, variant.MimeType, variant.Size,
+			file.IsPublic, ""); cErr != nil {
+			return cErr
+		}
+	}
+
+	movedKey, mErr := s.repo.MoveObject(ctx, ObjectMove{
+		Key:        file.Key,
+		FromDriver: req.From,
+		FromBucket: req.FromBucket,
+		ToDriver:   req.To,
+		ToBucket:   req.ToBucket,
+		KeyPrefix:  req.KeyPrefix,
+	})
+	if mErr != nil {
+		return mErr
+	}
+
+	result.Objects++
+	result.Variants += len(variants)
+	result.Bytes += file.Size
+
+	if movedKey != key {
+		result.Merged++
+
+		if dErr := target.Delete(ctx, key); dErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+				WithMeta(map[string]interface{}{"key": key}), s.logger)
+		}
+	}
+
+	if !req.DeleteSource {
+		return nil
+	}
+
+	// the files are moved, so a source object that can't be removed is only logged.
+	keys := []string{file.Key}
+	for _, variant := range variants {
+		keys = append(keys, variant.Key)
+	}
+
+	for _, sourceKey := range keys {
+		if dErr := s.storage.Delete(ctx, sourceKey); dErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+				WithMeta(map[string]interface{}{"key": sourceKey}), s.logger)
+		}
+	}
+
+	return nil
+}
+
+// copyObject copies the object to target and verifies the copy by reading it back, checksum is the recorded checksum
+// of the object and the source object is verified by it when it is not empty. A copy that doesn't match is removed.
+func (s Service) copyObject(ctx context.Context, target Storage, sourceKey, targetKey, contentType string, size int64,
+	isPublic bool, checksum string) error {
+	src, oErr := s.storage.Open(ctx, sourceKey)
+	if oErr != nil {
+		return oErr
+	}
+
+	hasher := sha256.New()
+	_, uErr := target.Upload(ctx, io.TeeReader(src, hasher), size, targetKey, contentType, isPublic)
+
+	if cErr := src.Close(); cErr != nil {
+		s.logger.ErrorContext(ctx, "can't close source object", slog.String("key", sourceKey), slog.String("error", cErr.Error()))
+	}
+
+	if uErr != nil {
+		return uErr
+	}
+
+	sourceChecksum := hex.EncodeToString(hasher.Sum(nil))
+
+	var vErr error
+	if checksum != "" && sourceChecksum != checksum {
+		vErr = fmt.Errorf("checksum of the source object %s is %s, expected %s", sourceKey, sourceChecksum, checksum)
+	} else {
+		vErr = s.verifyObject(ctx, target, targetKey, size, sourceChecksum)
+	}
+
+	if vErr != nil {
+		if dErr := target.Delete(ctx, targetKey); dErr != nil {
+			s.logger.ErrorContext(ctx, "can't remove copied object", slog.String("key", targetKey), slog.String("error", dErr.Error()))
+		}
+
+		return vErr
+	}
+
+	return nil
+}
+
+func (s Service) verifyObject(ctx context.Context, storage Storage, key string, size int64, checksum string) error {
+	obj, oErr := storage.Open(ctx, key)
+	if oErr != nil {
+		return oErr
+	}
+	defer func() {
+		if cErr := obj.Close(); cErr != nil {
+			s.logger.ErrorContext(ctx, "can't close copied object", slog.String("key", key), slog.String("error", cErr.Error()))
+		}
+	}()
+
+	hasher := sha256.New()
+	written, cErr := io.Copy(hasher, obj)
+	if cErr != nil {
+		return cErr
+	}
+
+	if written != size {
+		return fmt.Errorf("size of the copied object %s is %d, expected %d", key, written, size)
+	}
+
+	if copied := hex.EncodeToString(hasher.Sum(nil)); copied != checksum {
+		return fmt.Errorf("checksum of the copied object %s is %s, expected %s", key, copied, checksum)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+func TestMigrateDriver(t *testing.T) {
+	content := []byte("content of the migrated object")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	file := File{ID: "file-1", Key: "2026/10/file.png", MimeType: "image/png", Size: int64(len(content)), SHA256: checksum,
+		Status: FileStatusReady, Driver: DriverLocal}
+	variant := FileVariant{ID: "variant-1", FileID: file.ID, Name: "thumbnail", Key: "2026/10/file_thumbnail.png",
+		MimeType: "image/png", Size: 5}
+
+	tests := []struct {
+		name     string
+		req      MigrateDriverRequest
+		checksum string
+		merged   bool
+		expected MigrateDriverResult
+		copied   bool
+		variant  bool
+		moved    bool
+		deleted  bool
+	}{
+		{name: "copy is verified", req: MigrateDriverRequest{KeyPrefix: "migrated/"}, checksum: checksum,
+			expected: MigrateDriverResult{Objects: 1, Variants: 1, Bytes: file.Size}, copied: true, variant: true, moved: true},
+		{name: "checksum mismatch", req: MigrateDriverRequest{KeyPrefix: "migrated/"}, checksum: "0" + checksum[1:],
+			expected: MigrateDriverResult{Failed: 1}},
+		{name: "merged into an identical blob", req: MigrateDriverRequest{KeyPrefix: "migrated/"}, checksum: checksum,
+			merged: true, expected: MigrateDriverResult{Objects: 1, Variants: 1, Bytes: file.Size, Merged: 1}, variant: true,
+			moved: true},
+		{name: "dry run", req: MigrateDriverRequest{KeyPrefix: "migrated/", DryRun: true}, checksum: checksum,
+			expected: MigrateDriverResult{Objects: 1, Variants: 1, Bytes: file.Size}},
+		{name: "delete source", req: MigrateDriverRequest{KeyPrefix: "migrated/", DeleteSource: true}, checksum: checksum,
+			expected: MigrateDriverResult{Objects: 1, Variants: 1, Bytes: file.Size}, copied: true, variant: true, moved: true,
+			deleted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, target := newMemStorage(), newMemStorage()
+			source.put(file.Key, content)
+			source.put(variant.Key, []byte("thumb"))
+
+			migrated := file
+			migrated.SHA256 = test.checksum
+			repo := newMemRepository(migrated)
+			repo.variants[file.Key] = []FileVariant{variant}
+			if test.merged {
+				repo.mergedKeys[file.Key] = "existing/file.png"
+			}
+
+			req := test.req
+			req.From, req.To, req.ToBucket, req.BatchSize = DriverLocal, DriverS3, "bucket", 1
+			svc := New(Config{}, source, repo, discardLogger())
+
+			result, mErr := svc.MigrateDriver(context.Background(), target, req)
+			if mErr != nil {
+				t.Fatalf("unexpected error: %v", mErr)
+			}
+
+			if result != test.expected {
+				t.Fatalf("expected result %+v, got %+v", test.expected, result)
+			}
+
+			// the copied object of a merged file is removed, its variants are moved to their copies.
+			for key, expectedCopied := range map[string]bool{file.Key: test.copied, variant.Key: test.variant} {
+				data, copied := target.get(req.KeyPrefix + key)
+				if copied != expectedCopied {
+					t.Fatalf("expected copied %t for %s, got %t", expectedCopied, key, copied)
+				}
+
+				if copied {
+					if original, _ := source.get(key); !test.deleted && string(data) != string(original) {
+						t.Fatalf("copied object %s doesn't match the source", key)
+					}
+				}
+
+				if _, exists := source.get(key); exists == test.deleted {
+					t.Fatalf("expected source object %s deleted %t", key, test.deleted)
+				}
+			}
+
+			if moved := len(repo.moves) == 1; moved != test.moved {
+				t.Fatalf("expected moved %t, got %d moves", test.moved, len(repo.moves))
+			}
+
+			if test.moved && (repo.moves[0].ToDriver != DriverS3 || repo.moves[0].ToBucket != "bucket") {
+				t.Fatalf("unexpected move %+v", repo.moves[0])
+			}
+		})
+	}
+}
+
+func TestMigrateDriverJanitorRunning(t *testing.T) {
+	repo := newMemRepository()
+	repo.locked = true
+	svc := New(Config{}, newMemStorage(), repo, discardLogger())
+
+	_, mErr := svc.MigrateDriver(context.Background(), newMemStorage(), MigrateDriverRequest{From: DriverLocal, To: DriverS3})
+	var richErr richerror.RichError
+	if !errors.As(mErr, &richErr) || richErr.Kind() != richerror.KindConflict {
+		t.Fatalf("expected janitor running conflict, got %v", mErr)
+	}
+}
+
+func TestMigrateDriverInstanceRunning(t *testing.T) {
+	tests := []struct {
+		name         string
+		seenAt       time.Time
+		wantConflict bool
+	}{
+		{name: "running instance", seenAt: time.Now(), wantConflict: true},
+		{name: "stale heartbeat", seenAt: time.Now().Add(-2 * instanceHeartbeatTTL), wantConflict: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newMemRepository()
+			repo.heartbeats["instance-1"] = test.seenAt
+			svc := New(Config{}, newMemStorage(), repo, discardLogger())
+
+			_, mErr := svc.MigrateDriver(context.Background(), newMemStorage(), MigrateDriverRequest{From: DriverLocal, To: DriverS3})
+			if !test.wantConflict {
+				if mErr != nil {
+					t.Fatalf("unexpected error: %v", mErr)
+				}
+
+				return
+			}
+
+			var richErr richerror.RichError
+			if !errors.As(mErr, &richErr) || richErr.Kind() != richerror.KindConflict {
+				t.Fatalf("expected instance running conflict, got %v", mErr)
+			}
+		})
+	}
+}
+
+func TestRunHeartbeat(t *testing.T) {
+	repo := newMemRepository()
+	svc := New(Config{}, newMemStorage(), repo, discardLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		svc.RunHeartbeat(ctx)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if running, _ := repo.IsExistLiveInstance(ctx, time.Now().Add(-instanceHeartbeatTTL)); running {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected the heartbeat of the instance")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if running, _ := repo.IsExistLiveInstance(context.Background(), time.Time{}); running {
+		t.Fatal("expected the heartbeat to be removed when the instance stops")
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+// sniffLength is the number of bytes that are used to detect the mime type.
+const sniffLength = 512
+
+// mimeTypeExtensions are the extensions that a file of the mime type can have.
+var mimeTypeExtensions = map[string][]string{
+	"image/jpeg":                    {".jpg", ".jpeg", ".jfif"},
+	"image/png":                     {".png"},
+	"image/gif":                     {".gif"},
+	"image/webp":                    {".webp"},
+	"image/bmp":                     {".bmp"},
+	"image/heic":                    {".heic"},
+	"image/heif":                    {".heif", ".heic"},
+	"video/mp4":                     {".mp4", ".m4v", ".mov"},
+	"video/webm":                    {".webm"},
+	"video/quicktime":               {".mov"},
+	"video/avi":                     {".avi"},
+	"audio/mpeg":                    {".mp3"},
+	"audio/ogg":                     {".ogg", ".oga"},
+	"audio/wave":                    {".wav"},
+	"application/ogg":               {".ogg", ".ogv"},
+	"application/pdf":               {".pdf"},
+	"application/zip":               {".zip"},
+	"application/msword":            {".doc"},
+	"application/vnd.ms-excel":      {".xls"},
+	"application/vnd.ms-powerpoint": {".ppt"},
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   {".docx"},
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         {".xlsx"},
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": {".pptx"},
+	"text/plain": {".txt", ".csv", ".log", ".md", ".json"},
+}
+
+// zipBasedExtensions the office documents are zip files, so their mime type is detected by the extension.
+var zipBasedExtensions = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+// oleExtensions the legacy office documents are OLE compound files.
+var oleExtensions = map[string]string{
+	".doc": "application/msword",
+	".xls": "application/vnd.ms-excel",
+	".ppt": "application/vnd.ms-powerpoint",
+}
+
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// ftypBrands are the major brands of the ISO media files that are not detected by http.DetectContentType.
+var ftypBrands = map[string]string{
+	"qt  ": "video/quicktime",
+	"heic": "image/heic",
+	"heix": "image/heic",
+	"hevc": "image/heic",
+	"hevx": "image/heic",
+	"mif1": "image/heif",
+	"msf1": "image/heif",
+}
+
+// ftypMimeType returns the mime type of the major brand of the ftyp box at the start of the file.
+func ftypMimeType(head []byte) (string, bool) {
+	if len(head) < 12 || string(head[4:8]) != "ftyp" {
+		return "", false
+	}
+
+	mimeType, ok := ftypBrands[string(head[8:12])]
+
+	return mimeType, ok
+}
+
+// detectMimeType detects the mime type of the file by its first bytes, the client content type is not trusted.
+// The containers that are shared by several formats, such as zip for docx, are resolved by the extension.
+func detectMimeType(head []byte, filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if mimeType, ok := ftypMimeType(head); ok {
+		return mimeType
+	}
+
+	mimeType := http.DetectContentType(head)
+
+	switch {
+	case mimeType == "application/zip":
+		if zipMimeType, ok := zipBasedExtensions[ext]; ok {
+			return zipMimeType
+		}
+	case bytes.HasPrefix(head, oleSignature):
+		if oleMimeType, ok := oleExtensions[ext]; ok {
+			return oleMimeType
+		}
+	case mimeType == "video/mp4" && ext == ".mov":
+		return "video/quicktime"
+	}
+
+	return mimeType
+}
+
+// isExtensionAllowed reports whether the extension of the file matches the detected mime type. A file without
+// an extension is allowed, and an unknown extension is only allowed when the mime type has no known extensions.
+func isExtensionAllowed(mimeType, filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return true
+	}
+
+	baseType, _, _ := strings.Cut(mimeType, ";")
+	if extensions, ok := mimeTypeExtensions[baseType]; ok {
+		return slices.Contains(extensions, ext)
+	}
+
+	for _, extensions := range mimeTypeExtensions {
+		if slices.Contains(extensions, ext) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isMimeTypeAllowed reports whether the mime type is in the allow-list, an item of the list can be a type,
+// such as image/png, or all the subtypes of a type, such as image/*.
+func isMimeTypeAllowed(allowList []string, mimeType string) bool {
+	baseType, _, _ := strings.Cut(mimeType, ";")
+	mainType, _, _ := strings.Cut(baseType, "/")
+
+	for _, allowed := range allowList {
+		if allowed == baseType || allowed == mainType+"/*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkFileType checks the detected mime type against the allow-list of the purpose and the extension of the file,
+// the files of a purpose without an allow-list are rejected.
+func (s Service) checkFileType(purpose UploadPurpose, mimeType, filename string) error {
+	const op = "service.mime.checkFileType"
+
+	if !isMimeTypeAllowed(s.cfg.AllowedMimeTypes[purpose], mimeType) {
+		return richerror.New(op).WithMessage(servermsg.MsgFileTypeNotAllowed).WithKind(richerror.KindBadRequest).
+			WithMeta(map[string]interface{}{"mime_type": mimeType, "purpose": purpose})
+	}
+
+	if !isExtensionAllowed(mimeType, filename) {
+		return richerror.New(op).WithMessage(servermsg.MsgFileExtensionMismatch).WithKind(richerror.KindBadRequest).
+			WithMeta(map[string]interface{}{"mime_type": mimeType, "extension": filepath.Ext(filename)})
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+var (
+	pngHead  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHead = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	pdfHead  = []byte("%PDF-1.7\n")
+	zipHead  = []byte("PK\x03\x04\x14\x00\x06\x00")
+	oleHead  = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0x00, 0x00}
+	mp4Head  = []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+	qtHead   = []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00qt  ")
+	heicHead = []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic")
+	heifHead = []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00mif1heic")
+)
+
+func TestDetectMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     []byte
+		filename string
+		expected string
+	}{
+		{name: "png", head: pngHead, filename: "photo.png", expected: "image/png"},
+		{name: "png with a jpeg extension", head: pngHead, filename: "photo.jpg", expected: "image/png"},
+		{name: "jpeg", head: jpegHead, filename: "photo.jpeg", expected: "image/jpeg"},
+		{name: "pdf", head: pdfHead, filename: "report.pdf", expected: "application/pdf"},
+		{name: "docx", head: zipHead, filename: "report.DOCX",
+			expected: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{name: "xlsx", head: zipHead, filename: "sheet.xlsx",
+			expected: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{name: "zip", head: zipHead, filename: "archive.zip", expected: "application/zip"},
+		{name: "zip with a pdf extension", head: zipHead, filename: "report.pdf", expected: "application/zip"},
+		{name: "doc", head: oleHead, filename: "report.doc", expected: "application/msword"},
+		{name: "ole without an office extension", head: oleHead, filename: "report.bin",
+			expected: "application/octet-stream"},
+		{name: "mp4", head: mp4Head, filename: "video.mp4", expected: "video/mp4"},
+		{name: "mov", head: mp4Head, filename: "video.MOV", expected: "video/quicktime"},
+		{name: "quicktime", head: qtHead, filename: "video.mov", expected: "video/quicktime"},
+		{name: "heic", head: heicHead, filename: "photo.heic", expected: "image/heic"},
+		{name: "heif", head: heifHead, filename: "photo.heif", expected: "image/heif"},
+		{name: "truncated ftyp", head: []byte("\x00\x00\x00\x18ftyp"), filename: "video.mov",
+			expected: "application/octet-stream"},
+		{name: "text", head: []byte("hello, world"), filename: "notes.txt", expected: "text/plain; charset=utf-8"},
+		{name: "empty", head: nil, filename: "empty.txt", expected: "text/plain; charset=utf-8"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if mimeType := detectMimeType(test.head, test.filename); mimeType != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, mimeType)
+			}
+		})
+	}
+}
+
+func TestCheckFileType(t *testing.T) {
+	svc := Service{cfg: Config{AllowedMimeTypes: map[UploadPurpose][]string{
+		UploadPurposeStory:   {"image/*", "video/mp4"},
+		UploadPurposeChat:    {"image/png", "application/pdf"},
+		UploadPurposeGeneral: {"text/plain", "application/octet-stream"},
+	}}}
+
+	tests := []struct {
+		name     string
+		purpose  UploadPurpose
+		mimeType string
+		filename string
+		expected string
+	}{
+		{name: "purpose without an allow-list", purpose: "avatar", mimeType: "image/png", filename: "photo.png",
+			expected: servermsg.MsgFileTypeNotAllowed},
+		{name: "type not in the general allow-list", purpose: UploadPurposeGeneral, mimeType: "application/zip",
+			filename: "archive.zip", expected: servermsg.MsgFileTypeNotAllowed},
+		{name: "wildcard subtype", purpose: UploadPurposeStory, mimeType: "image/webp", filename: "photo.webp"},
+		{name: "exact type", purpose: UploadPurposeStory, mimeType: "video/mp4", filename: "video.mp4"},
+		{name: "type not in the allow-list", purpose: UploadPurposeStory, mimeType: "video/webm", filename: "video.webm",
+			expected: servermsg.MsgFileTypeNotAllowed},
+		{name: "subtype not in the allow-list", purpose: UploadPurposeChat, mimeType: "image/gif", filename: "photo.gif",
+			expected: servermsg.MsgFileTypeNotAllowed},
+		{name: "extension of another type", purpose: UploadPurposeChat, mimeType: "image/png", filename: "photo.jpg",
+			expected: servermsg.MsgFileExtensionMismatch},
+		{name: "executable disguised as a pdf", purpose: UploadPurposeGeneral, mimeType: "application/octet-stream",
+			filename: "invoice.pdf", expected: servermsg.MsgFileExtensionMismatch},
+		{name: "uppercase extension", purpose: UploadPurposeChat, mimeType: "application/pdf", filename: "REPORT.PDF"},
+		{name: "mime type parameters", purpose: UploadPurposeGeneral, mimeType: "text/plain; charset=utf-8",
+			filename: "notes.csv"},
+		{name: "file without an extension", purpose: UploadPurposeChat, mimeType: "image/png", filename: "photo"},
+		{name: "unknown extension of an unknown type", purpose: UploadPurposeGeneral,
+			mimeType: "application/octet-stream", filename: "data.bin"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cErr := svc.checkFileType(test.purpose, test.mimeType, test.filename)
+			if test.expected == "" {
+				if cErr != nil {
+					t.Fatalf("unexpected error: %v", cErr)
+				}
+
+				return
+			}
+
+			var richErr richerror.RichError
+			if !errors.As(cErr, &richErr) || richErr.Kind() != richerror.KindBadRequest || richErr.Message() != test.expected {
+				t.Fatalf("expected bad request %q, got %v", test.expected, cErr)
+			}
+		})
+	}
+}
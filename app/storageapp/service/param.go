@@ -2,15 +2,144 @@ package service
 
 import (
 	"io"
+	"time"
 
 	"github.com/syntaxfa/quick-connect/types"
 )
 
 type UploadRequest struct {
-	UploaderID  types.ID  `json:"-"`
-	File        io.Reader `json:"file"`
-	Filename    string    `json:"filename"`
-	Size        int64     `json:"size"`
-	ContentType string    `json:"content_type"`
-	IsPublic    bool      `json:"is_public"`
+	UploaderID    types.ID      `json:"-"`
+	UploaderRoles []types.Role  `json:"-"`
+	File          io.Reader     `json:"file"`
+	Filename      string        `json:"filename"`
+	Size          int64         `json:"size"`
+	IsPublic      bool          `json:"is_public"`
+	Purpose       UploadPurpose `json:"purpose"`
+}
+
+// PresignOptions UserID binds the presigned url to the user, it is only supported by the local driver and the storage proxy.
+type PresignOptions struct {
+	UserID types.ID
+}
+
+type CreateResumableUploadRequest struct {
+	UploaderID    types.ID      `json:"-"`
+	UploaderRoles []types.Role  `json:"-"`
+	Filename      string        `json:"filename"`
+	Size          int64         `json:"size"`
+	IsPublic      bool          `json:"is_public"`
+	Purpose       UploadPurpose `json:"purpose"`
+}
+
+// WriteChunkRequest Offset is the uploaded size that the client knows, the chunk is rejected when it is not
+// the uploaded size of the storage.
+type WriteChunkRequest struct {
+	UploadID   types.ID
+	UploaderID types.ID
+	Offset     int64
+	Chunk      io.Reader
+}
+
+// ResumableUploadResponse Offset is the uploaded size, File is set when the upload is completed.
+type ResumableUploadResponse struct {
+	UploadSession
+	Offset int64 `json:"offset"`
+	File   *File `json:"file,omitempty"`
+}
+
+// CreateDirectUploadRequest ContentType is sent by the client with the upload, it must be allowed for the purpose
+// and match the extension of Filename. SHA256 is optional, the storage verifies the uploaded object with it.
+type CreateDirectUploadRequest struct {
+	UploaderID    types.ID      `json:"-"`
+	UploaderRoles []types.Role  `json:"-"`
+	Filename      string        `json:"filename"`
+	Size          int64         `json:"size"`
+	ContentType   string        `json:"content_type"`
+	SHA256        string        `json:"sha256"`
+	IsPublic      bool          `json:"is_public"`
+	Purpose       UploadPurpose `json:"purpose"`
+}
+
+// PresignUploadOptions SHA256 is the hex encoded checksum of the object, it is not checked when it is empty.
+type PresignUploadOptions struct {
+	ContentType string
+	Size        int64
+	SHA256      string
+	IsPublic    bool
+	Expire      time.Duration
+}
+
+// PresignedUpload the client uploads the file with Method to URL and sends Headers with the request.
+type PresignedUpload struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ObjectInfo SHA256 is empty when the storage doesn't have the checksum of the object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+	SHA256      string
+}
+
+// DirectUploadResponse File is pending until the upload is completed.
+type DirectUploadResponse struct {
+	File   File            `json:"file"`
+	Upload PresignedUpload `json:"upload"`
+}
+
+// UsageUser is a user whose storage usage is requested, Roles are used to find the quota of the user.
+type UsageUser struct {
+	ID    types.ID
+	Roles []types.Role
+}
+
+// MigrateDriverRequest FromBucket is empty to migrate the files of all the buckets of From, KeyPrefix is prepended
+// to the keys of the migrated objects. The source objects are only deleted when DeleteSource is set.
+type MigrateDriverRequest struct {
+	From         Driver
+	FromBucket   string
+	To           Driver
+	ToBucket     string
+	KeyPrefix    string
+	BatchSize    int
+	DeleteSource bool
+	DryRun       bool
+}
+
+// MigrateDriverResult Objects is the number of the migrated objects of the files, an object is shared by the files
+// of a blob. Merged is the number of the objects whose files use an identical blob of the target instead.
+type MigrateDriverResult struct {
+	Objects  int   `json:"objects"`
+	Variants int   `json:"variants"`
+	Bytes    int64 `json:"bytes"`
+	Merged   int   `json:"merged"`
+	Failed   int   `json:"failed"`
+}
+
+// ObjectMove moves the files of the object of Key, and their variants, from a driver to another. The moved objects
+// are stored with KeyPrefix prepended to their keys. FromBucket is empty to move the files of all the buckets.
+type ObjectMove struct {
+	Key        string
+	FromDriver Driver
+	FromBucket string
+	ToDriver   Driver
+	ToBucket   string
+	KeyPrefix  string
+}
+
+// Download is an object that is served by the storage app, Content is seekable, so the ranges of the object are read
+// without reading the object from its start. The objects are not changed after they are stored, so ETag is the
+// checksum of the file, or a hash of the key when the checksum is not known.
+type Download struct {
+	File     File
+	Key      string
+	MimeType string
+	Size     int64
+	ETag     string
+	ModTime  time.Time
+	MaxAge   time.Duration
+	Content  io.ReadSeekCloser
 }
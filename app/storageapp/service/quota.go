@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// quotaLimit returns the storage quota of the roles, it is zero when the roles are not limited.
+func (s Service) quotaLimit(roles []types.Role) int64 {
+	if !s.cfg.Quota.Enabled || len(roles) == 0 {
+		return 0
+	}
+
+	var limit int64
+	for _, role := range roles {
+		roleLimit, ok := s.cfg.Quota.Limits[role]
+		if !ok || roleLimit <= 0 {
+			return 0
+		}
+
+		limit = max(limit, roleLimit)
+	}
+
+	return limit
+}
+
+// uploadReservationTTL is the quota reservation of an upload whose file is saved in the same request, the reservation
+// of a request that is stopped before it removes its reservation is ignored after it.
+const uploadReservationTTL = time.Hour
+
+// reserveQuota reserves the size of the upload until expiresAt, the upload is rejected when the storage usage of the
+// uploader with its reservations and the size exceeds its quota. The reservation is removed by releaseQuota when the
+// file of the upload is saved, so the concurrent uploads of an uploader can't exceed its quota.
+func (s Service) reserveQuota(ctx context.Context, reservationID, uploaderID types.ID, roles []types.Role, size int64,
+	expiresAt time.Time) error {
+	const op = "service.quota.reserveQuota"
+
+	limit := s.quotaLimit(roles)
+	if limit == 0 {
+		return nil
+	}
+
+	reserved, rErr := s.repo.ReserveStorage(ctx, StorageReservation{
+		ID:         reservationID,
+		UploaderID: uploaderID,
+		Size:       size,
+		ExpiresAt:  expiresAt,
+	}, limit)
+	if rErr != nil {
+		return errlog.ErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !reserved {
+		return richerror.New(op).WithMessage(servermsg.MsgStorageQuotaExceeded).WithKind(richerror.KindForbidden)
+	}
+
+	return nil
+}
+
+// releaseQuota removes the reservation of reserveQuota, the error is only logged, because the reservation is ignored
+// when it expires.
+func (s Service) releaseQuota(ctx context.Context, reservationID types.ID) {
+	const op = "service.quota.releaseQuota"
+
+	if !s.cfg.Quota.Enabled {
+		return
+	}
+
+	if dErr := s.repo.DeleteStorageReservation(ctx, reservationID); dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected).
+			WithMeta(map[string]interface{}{"reservation_id": reservationID}), s.logger)
+	}
+}
+
+// GetStorageUsage returns the storage usage of the uploader with the quota of its roles.
+func (s Service) GetStorageUsage(ctx context.Context, uploaderID types.ID, roles []types.Role) (StorageUsage, error) {
+	usages, gErr := s.GetStorageUsages(ctx, []UsageUser{{ID: uploaderID, Roles: roles}})
+	if gErr != nil {
+		return StorageUsage{}, gErr
+	}
+
+	return usages[0], nil
+}
+
+// GetStorageUsages returns the storage usage of the users in the order of the users, the usage of a user that
+// has not uploaded any file is zero.
+func (s Service) GetStorageUsages(ctx context.Context, users []UsageUser) ([]StorageUsage, error) {
+	const op = "service.quota.GetStorageUsages"
+
+	uploaderIDs := make([]types.ID, 0, len(users))
+	for _, user := range users {
+		uploaderIDs = append(uploaderIDs, user.ID)
+	}
+
+	stored, gErr := s.repo.GetStorageUsages(ctx, uploaderIDs)
+	if gErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	storedByID := make(map[types.ID]StorageUsage, len(stored))
+	for _, usage := range stored {
+		storedByID[usage.UploaderID] = usage
+	}
+
+	usages := make([]StorageUsage, 0, len(users))
+	for _, user := range users {
+		usage, ok := storedByID[user.ID]
+		if !ok {
+			usage = StorageUsage{UploaderID: user.ID}
+		}
+		usage.Limit = s.quotaLimit(user.Roles)
+
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
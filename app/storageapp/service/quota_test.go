@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+func TestUploadReservesQuota(t *testing.T) {
+	const (
+		role       types.Role = "user"
+		uploaderID types.ID   = "user-1"
+		uploads               = 10
+		fileSize              = 30
+	)
+
+	repo := newMemRepository()
+	quota := QuotaConfig{Enabled: true, Limits: map[types.Role]int64{role: 100}}
+	svc := New(Config{AllowedMimeTypes: testAllowedMimeTypes, Quota: quota}, newMemStorage(), repo, discardLogger())
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		uploaded int
+	)
+	for range uploads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, uErr := svc.Upload(context.Background(), UploadRequest{
+				UploaderID:    uploaderID,
+				UploaderRoles: []types.Role{role},
+				File:          strings.NewReader(strings.Repeat("a", fileSize)),
+				Filename:      "notes.txt",
+				Size:          fileSize,
+			})
+			if uErr == nil {
+				mu.Lock()
+				uploaded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if uploaded != 3 {
+		t.Fatalf("expected 3 uploads within the quota, got %d", uploaded)
+	}
+
+	if len(repo.reserved) != 0 {
+		t.Fatalf("expected the reservations to be removed, got %d", len(repo.reserved))
+	}
+}
+
+func TestCreateResumableUploadReservesQuota(t *testing.T) {
+	const role types.Role = "user"
+
+	repo := newMemRepository()
+	quota := QuotaConfig{Enabled: true, Limits: map[types.Role]int64{role: 100}}
+	svc := New(Config{MaxFileSize: 100, AllowedMimeTypes: testAllowedMimeTypes, Quota: quota}, newMemStorage(), repo,
+		discardLogger())
+
+	req := CreateResumableUploadRequest{UploaderID: "user-1", UploaderRoles: []types.Role{role}, Filename: "notes.txt",
+		Size: 60}
+
+	created, cErr := svc.CreateResumableUpload(context.Background(), req)
+	if cErr != nil {
+		t.Fatalf("unexpected error: %v", cErr)
+	}
+
+	// the open session holds its size, so a second session exceeds the quota.
+	if _, cErr = svc.CreateResumableUpload(context.Background(), req); cErr == nil {
+		t.Fatal("expected the second session to exceed the quota")
+	}
+
+	if aErr := svc.AbortResumableUpload(context.Background(), created.ID, req.UploaderID); aErr != nil {
+		t.Fatalf("unexpected error: %v", aErr)
+	}
+
+	if _, cErr = svc.CreateResumableUpload(context.Background(), req); cErr != nil {
+		t.Fatalf("expected the quota of the aborted session to be released, got %v", cErr)
+	}
+}
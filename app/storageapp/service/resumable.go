@@ -0,0 +1,525 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"path/filepath"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const defaultResumableExpire = 24 * time.Hour
+
+// ResumableMaxFileSize returns the size limit of the resumable uploads.
+func (s Service) ResumableMaxFileSize() int64 {
+	if s.cfg.Resumable.MaxFileSize > 0 {
+		return s.cfg.Resumable.MaxFileSize
+	}
+
+	return s.cfg.MaxFileSize
+}
+
+func (s Service) resumableExpire() time.Duration {
+	if s.cfg.Resumable.Expire > 0 {
+		return s.cfg.Resumable.Expire
+	}
+
+	return defaultResumableExpire
+}
+
+// the lease of an upload is renewed while it is held, so a lease of a stopped instance expires soon.
+const (
+	uploadLeaseTTL           = 30 * time.Second
+	uploadLeaseRenewInterval = 10 * time.Second
+)
+
+// CreateResumableUpload starts a resumable upload, the chunks of the file are written by WriteChunk.
+func (s Service) CreateResumableUpload(ctx context.Context, req CreateResumableUploadRequest) (ResumableUploadResponse, error) {
+	const op = "service.resumable.CreateResumableUpload"
+
+	if req.Purpose == "" {
+		req.Purpose = UploadPurposeGeneral
+	}
+	if !IsValidUploadPurpose(req.Purpose) {
+		return ResumableUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgInvalidUploadPurpose).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if req.Size <= 0 || req.Size > s.ResumableMaxFileSize() {
+		return ResumableUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgFileSizeLimitExceeded).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	ext := filepath.Ext(req.Filename)
+	newID := types.ID(ulid.Make().String())
+	key := fmt.Sprintf("uploads/%s%s", newID, ext)
+	now := time.Now()
+	expiresAt := now.Add(s.resumableExpire())
+
+	// the size of the upload is reserved while the session is open, so the open sessions of an uploader are counted
+	// in its quota, the reservation is removed when the file is saved or the session is removed.
+	if qErr := s.reserveQuota(ctx, newID, req.UploaderID, req.UploaderRoles, req.Size, expiresAt); qErr != nil {
+		return ResumableUploadResponse{}, qErr
+	}
+
+	// the content of the file is checked when the upload is completed, the object is created with the type of
+	// the extension, because the extension must match the content.
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	storageUploadID, cErr := s.storage.CreateResumableUpload(ctx, key, contentType, req.IsPublic)
+	if cErr != nil {
+		s.releaseQuota(ctx, newID)
+
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(cErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	session := UploadSession{
+		ID:              newID,
+		UploaderID:      req.UploaderID,
+		Filename:        req.Filename,
+		Key:             key,
+		StorageUploadID: storageUploadID,
+		Size:            req.Size,
+		IsPublic:        req.IsPublic,
+		Purpose:         req.Purpose,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       now,
+	}
+
+	if sErr := s.repo.SaveUploadSession(ctx, session); sErr != nil {
+		if aErr := s.storage.AbortResumableUpload(ctx, key, storageUploadID); aErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+		s.releaseQuota(ctx, newID)
+
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(sErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	return ResumableUploadResponse{UploadSession: session}, nil
+}
+
+// GetResumableUpload returns the upload session of the uploader with its uploaded size.
+func (s Service) GetResumableUpload(ctx context.Context, uploadID, uploaderID types.ID) (ResumableUploadResponse, error) {
+	const op = "service.resumable.GetResumableUpload"
+
+	session, gErr := s.getUploadSession(ctx, uploadID, uploaderID)
+	if gErr != nil {
+		return ResumableUploadResponse{}, gErr
+	}
+
+	offset, oErr := s.storage.GetResumableOffset(ctx, session.Key, session.StorageUploadID)
+	if oErr != nil {
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(oErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	resp := ResumableUploadResponse{UploadSession: session, Offset: offset}
+	if offset < session.Size {
+		return resp, nil
+	}
+
+	// the last chunk is written but the file is not created, e.g. the service is stopped before it.
+	release, lErr := s.lockUpload(ctx, session.ID)
+	if lErr != nil {
+		return ResumableUploadResponse{}, lErr
+	}
+	defer release()
+
+	file, cErr := s.completeResumableUpload(ctx, session)
+	if cErr != nil {
+		return ResumableUploadResponse{}, cErr
+	}
+	resp.File = &file
+
+	return resp, nil
+}
+
+// WriteChunk appends the chunk to the upload at req.Offset, the file is created when the last chunk is written.
+func (s Service) WriteChunk(ctx context.Context, req WriteChunkRequest) (ResumableUploadResponse, error) {
+	const op = "service.resumable.WriteChunk"
+
+	session, gErr := s.getUploadSession(ctx, req.UploadID, req.UploaderID)
+	if gErr != nil {
+		return ResumableUploadResponse{}, gErr
+	}
+
+	release, lErr := s.lockUpload(ctx, session.ID)
+	if lErr != nil {
+		return ResumableUploadResponse{}, lErr
+	}
+	defer release()
+
+	offset, oErr := s.storage.GetResumableOffset(ctx, session.Key, session.StorageUploadID)
+	if oErr != nil {
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(oErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if offset != req.Offset {
+		return ResumableUploadResponse{}, richerror.New(op).WithMessage(servermsg.MsgUploadOffsetMismatch).
+			WithKind(richerror.KindConflict)
+	}
+
+	chunk := bufio.NewReaderSize(io.LimitReader(req.Chunk, session.Size-offset), sniffLength)
+
+	// the type of the file is checked before the first chunk is stored when the chunk has enough bytes,
+	// it is checked again when the upload is completed.
+	if offset == 0 {
+		if head, pErr := chunk.Peek(int(min(sniffLength, session.Size))); pErr == nil {
+			if tErr := s.checkFileType(session.Purpose, detectMimeType(head, session.Filename), session.Filename); tErr != nil {
+				s.discardUpload(ctx, session, false)
+
+				return ResumableUploadResponse{}, tErr
+			}
+		}
+	}
+
+	wErr := s.storage.WriteChunk(ctx, session.Key, session.StorageUploadID, chunk)
+	if wErr != nil {
+		// an interrupted chunk is expected, the client resumes the upload from the stored part of the chunk.
+		if ctx.Err() != nil {
+			return ResumableUploadResponse{}, richerror.New(op).WithWrapError(wErr).WithKind(richerror.KindUnexpected)
+		}
+
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(wErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	offset, oErr = s.storage.GetResumableOffset(ctx, session.Key, session.StorageUploadID)
+	if oErr != nil {
+		return ResumableUploadResponse{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(oErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	resp := ResumableUploadResponse{UploadSession: session, Offset: offset}
+	if offset < session.Size {
+		return resp, nil
+	}
+
+	file, cErr := s.completeResumableUpload(ctx, session)
+	if cErr != nil {
+		return ResumableUploadResponse{}, cErr
+	}
+	resp.File = &file
+
+	return resp, nil
+}
+
+// AbortResumableUpload removes the upload session and its uploaded chunks.
+func (s Service) AbortResumableUpload(ctx context.Context, uploadID, uploaderID types.ID) error {
+	const op = "service.resumable.AbortResumableUpload"
+
+	session, gErr := s.getUploadSession(ctx, uploadID, uploaderID)
+	if gErr != nil {
+		return gErr
+	}
+
+	release, lErr := s.lockUpload(ctx, session.ID)
+	if lErr != nil {
+		return lErr
+	}
+	defer release()
+
+	if aErr := s.storage.AbortResumableUpload(ctx, session.Key, session.StorageUploadID); aErr != nil {
+		return errlog.ErrContext(ctx, richerror.New(op).WithWrapError(aErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if dErr := s.repo.DeleteUploadSession(ctx, session.ID); dErr != nil {
+		return errlog.ErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	s.releaseQuota(ctx, session.ID)
+
+	return nil
+}
+
+// getUploadSession returns the session when it belongs to the uploader and it is not expired.
+func (s Service) getUploadSession(ctx context.Context, uploadID, uploaderID types.ID) (UploadSession, error) {
+	const op = "service.resumable.getUploadSession"
+
+	exists, eErr := s.repo.IsExistUploadSession(ctx, uploadID)
+	if eErr != nil {
+		return UploadSession{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(eErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !exists {
+		return UploadSession{}, richerror.New(op).WithMessage(servermsg.MsgUploadNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	session, gErr := s.repo.GetUploadSession(ctx, uploadID)
+	if gErr != nil {
+		return UploadSession{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).
+			WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if session.UploaderID != uploaderID || !session.ExpiresAt.After(time.Now()) {
+		return UploadSession{}, richerror.New(op).WithMessage(servermsg.MsgUploadNotFound).WithKind(richerror.KindNotFound)
+	}
+
+	return session, nil
+}
+
+// lockUpload takes the lease of the upload, so the chunks of an upload are written one by one without holding
+// a database connection while the chunk is read from the client. The returned function releases it.
+func (s Service) lockUpload(ctx context.Context, uploadID types.ID) (func(), error) {
+	const op = "service.resumable.lockUpload"
+
+	release, acquired, lErr := s.leaseUpload(ctx, uploadID)
+	if lErr != nil {
+		return nil, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(lErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if !acquired {
+		return nil, richerror.New(op).WithMessage(servermsg.MsgUploadIsLocked).WithKind(richerror.KindConflict)
+	}
+
+	return release, nil
+}
+
+// leaseUpload takes the lease of the upload and renews it until the returned function releases it, it returns false
+// when the upload is leased by another request.
+func (s Service) leaseUpload(ctx context.Context, uploadID types.ID) (func(), bool, error) {
+	const op = "service.resumable.leaseUpload"
+
+	token := ulid.Make().String()
+
+	acquired, lErr := s.repo.LeaseUpload(ctx, uploadID, token, uploadLeaseTTL)
+	if lErr != nil || !acquired {
+		return nil, false, lErr
+	}
+
+	// the lease is released when the request is canceled too, e.g. the client is disconnected.
+	leaseCtx := context.WithoutCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(uploadLeaseRenewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				renewed, rErr := s.repo.LeaseUpload(leaseCtx, uploadID, token, uploadLeaseTTL)
+				if rErr != nil {
+					errlog.WithoutErrContext(leaseCtx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected),
+						s.logger)
+
+					continue
+				}
+
+				if !renewed {
+					s.logger.WarnContext(leaseCtx, "lease of the upload is lost", slog.String("upload_id", string(uploadID)))
+
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+
+		if rErr := s.repo.ReleaseUploadLease(leaseCtx, uploadID, token); rErr != nil {
+			errlog.WithoutErrContext(leaseCtx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}, true, nil
+}
+
+// completeResumableUpload assembles the chunks and creates the file, the file is checked like the files of Upload,
+// so a file that is not allowed is removed.
+func (s Service) completeResumableUpload(ctx context.Context, session UploadSession) (File, error) {
+	const op = "service.resumable.completeResumableUpload"
+
+	// the file is created by a previous request when only the removal of the session is failed.
+	exists, eErr := s.repo.IsExistByID(ctx, session.ID)
+	if eErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(eErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if exists {
+		file, gErr := s.repo.GetByID(ctx, session.ID)
+		if gErr != nil {
+			return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+
+		if dErr := s.repo.DeleteUploadSession(ctx, session.ID); dErr != nil {
+			return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+		s.releaseQuota(ctx, session.ID)
+
+		return file, nil
+	}
+
+	if cErr := s.storage.CompleteResumableUpload(ctx, session.Key, session.StorageUploadID); cErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	file := File{
+		ID:          session.ID,
+		UploaderID:  session.UploaderID,
+		Name:        session.Filename,
+		Key:         session.Key,
+		Size:        session.Size,
+		Driver:      s.cfg.Driver,
+		Bucket:      s.cfg.Bucket,
+		IsPublic:    session.IsPublic,
+		Purpose:     session.Purpose,
+		Status:      FileStatusReady,
+		IsConfirmed: false,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		DeletedAt:   nil,
+	}
+
+	file, imageData, pErr := s.processUploadedObject(ctx, file, true)
+	if pErr != nil {
+		var richErr richerror.RichError
+		if errors.As(pErr, &richErr) && richErr.Kind() == richerror.KindBadRequest {
+			s.discardUpload(ctx, session, true)
+
+			return File{}, pErr
+		}
+
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	file.VariantsRetryAt = s.pendingVariants(file.MimeType)
+
+	file, sErr := s.saveFile(ctx, file)
+	if sErr != nil {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	// the usage of the file is counted when it is saved.
+	s.releaseQuota(ctx, session.ID)
+
+	if dErr := s.repo.DeleteUploadSession(ctx, session.ID); dErr != nil {
+		// the file is created, the session is removed by the janitor when it is expired.
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if file.VariantsRetryAt != nil {
+		s.enqueueImage(file, imageData)
+	}
+
+	return file, nil
+}
+
+// processUploadedObject reads the object of the file from the storage to detect its type, the metadata of the images
+// is removed and the image is returned for the image processing. The size and the checksum of the file are calculated
+// from the content for the images and when hashContent is true, otherwise they are kept. The images that are larger than
+// maxImageSize are rejected, because they are read into memory.
+func (s Service) processUploadedObject(ctx context.Context, file File, hashContent bool) (File, []byte, error) {
+	const op = "service.resumable.processUploadedObject"
+
+	object, oErr := s.storage.Open(ctx, file.Key)
+	if oErr != nil {
+		return File{}, nil, oErr
+	}
+	defer func() {
+		if cErr := object.Close(); cErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+	}()
+
+	reader := bufio.NewReaderSize(object, sniffLength)
+	head, pErr := reader.Peek(sniffLength)
+	if pErr != nil && !errors.Is(pErr, io.EOF) {
+		return File{}, nil, pErr
+	}
+
+	file.MimeType = detectMimeType(head, file.Name)
+	if tErr := s.checkFileType(file.Purpose, file.MimeType, file.Name); tErr != nil {
+		return File{}, nil, tErr
+	}
+
+	if _, ok := imageMimeTypes[file.MimeType]; !ok {
+		if !hashContent {
+			return file, nil, nil
+		}
+
+		hash := sha256.New()
+		size, cErr := io.Copy(hash, reader)
+		if cErr != nil {
+			return File{}, nil, cErr
+		}
+		file.Size = size
+		file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+		return file, nil, nil
+	}
+
+	// the image is read into memory to strip its metadata, file.Size is the size of the completed upload, the reader
+	// is limited too, so an object that is larger than its file is not read into memory.
+	limit := s.maxImageSize()
+	if file.Size > limit {
+		return File{}, nil, richerror.New(op).WithMessage(servermsg.MsgImageSizeLimitExceeded).WithKind(richerror.KindBadRequest)
+	}
+
+	data, rErr := io.ReadAll(io.LimitReader(reader, limit+1))
+	if rErr != nil {
+		return File{}, nil, rErr
+	}
+
+	if int64(len(data)) > limit {
+		return File{}, nil, richerror.New(op).WithMessage(servermsg.MsgImageSizeLimitExceeded).WithKind(richerror.KindBadRequest)
+	}
+
+	stripped, sErr := stripImageMetadata(file.MimeType, data)
+	if sErr != nil {
+		return File{}, nil, richerror.New(op).WithWrapError(sErr).WithMessage(servermsg.MsgInvalidImage).
+			WithKind(richerror.KindBadRequest)
+	}
+
+	if len(stripped) != len(data) {
+		if _, uErr := s.storage.Upload(ctx, bytes.NewReader(stripped), int64(len(stripped)), file.Key, file.MimeType,
+			file.IsPublic); uErr != nil {
+			return File{}, nil, uErr
+		}
+	}
+
+	checksum := sha256.Sum256(stripped)
+	file.Size = int64(len(stripped))
+	file.SHA256 = hex.EncodeToString(checksum[:])
+
+	return file, stripped, nil
+}
+
+// discardUpload removes the upload that is not allowed, the errors are only logged, because the rejection
+// of the file is returned to the client. The object exists when the upload is completed.
+func (s Service) discardUpload(ctx context.Context, session UploadSession, completed bool) {
+	const op = "service.resumable.discardUpload"
+
+	var dErr error
+	if completed {
+		dErr = s.storage.Delete(ctx, session.Key)
+	} else {
+		dErr = s.storage.AbortResumableUpload(ctx, session.Key, session.StorageUploadID)
+	}
+	if dErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	if rErr := s.repo.DeleteUploadSession(ctx, session.ID); rErr != nil {
+		errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+	s.releaseQuota(ctx, session.ID)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+func TestProcessUploadedObjectImageSizeLimit(t *testing.T) {
+	var smallImage bytes.Buffer
+	if eErr := png.Encode(&smallImage, image.NewRGBA(image.Rect(0, 0, 4, 4))); eErr != nil {
+		t.Fatal(eErr)
+	}
+
+	// the png signature is enough to detect the type of the image, the padding makes it larger than the limit.
+	largeImage := append(append([]byte{}, smallImage.Bytes()[:16]...), make([]byte, 4096)...)
+
+	const limit = 1024
+
+	tests := []struct {
+		name     string
+		object   []byte
+		fileSize int64
+		rejected bool
+	}{
+		{name: "image under the limit", object: smallImage.Bytes(), fileSize: int64(smallImage.Len())},
+		{name: "image over the limit", object: largeImage, fileSize: int64(len(largeImage)), rejected: true},
+		{name: "object larger than its file", object: largeImage, fileSize: 100, rejected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storage := newMemStorage()
+			storage.put("image.png", test.object)
+			svc := New(Config{MaxFileSize: limit, AllowedMimeTypes: testAllowedMimeTypes}, storage, nil, discardLogger())
+
+			file := File{Key: "image.png", Name: "image.png", Size: test.fileSize, Purpose: UploadPurposeGeneral}
+			processed, data, pErr := svc.processUploadedObject(context.Background(), file, true)
+
+			if !test.rejected {
+				if pErr != nil {
+					t.Fatalf("unexpected error: %v", pErr)
+				}
+
+				if processed.MimeType != "image/png" || len(data) == 0 {
+					t.Fatalf("expected processed png image, got %q with %d bytes", processed.MimeType, len(data))
+				}
+
+				return
+			}
+
+			var richErr richerror.RichError
+			if !errors.As(pErr, &richErr) {
+				t.Fatalf("expected rich error, got %v", pErr)
+			}
+
+			if richErr.Kind() != richerror.KindBadRequest || richErr.Message() != servermsg.MsgImageSizeLimitExceeded {
+				t.Fatalf("expected image size limit error, got kind %d message %q", richErr.Kind(), richErr.Message())
+			}
+		})
+	}
+}
+
+func TestWriteChunk(t *testing.T) {
+	content := []byte("the notes of the resumable upload")
+
+	tests := []struct {
+		name          string
+		uploaded      int
+		offset        int64
+		chunk         []byte
+		leased        bool
+		expectedError string
+		wantOffset    int64
+		wantFile      bool
+	}{
+		{name: "first chunk", offset: 0, chunk: content[:10], wantOffset: 10},
+		{name: "last chunk", uploaded: 10, offset: 10, chunk: content[10:], wantOffset: int64(len(content)),
+			wantFile: true},
+		{name: "offset behind the upload", uploaded: 10, offset: 5, chunk: content[5:],
+			expectedError: servermsg.MsgUploadOffsetMismatch},
+		{name: "offset ahead of the upload", uploaded: 10, offset: 20, chunk: content[20:],
+			expectedError: servermsg.MsgUploadOffsetMismatch},
+		{name: "upload is written by another request", uploaded: 10, offset: 10, chunk: content[10:], leased: true,
+			expectedError: servermsg.MsgUploadIsLocked},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			session := UploadSession{ID: "upload-1", UploaderID: "user-1", Filename: "notes.txt", Key: "uploads/upload-1.txt",
+				StorageUploadID: "uploads/upload-1.txt", Size: int64(len(content)), Purpose: UploadPurposeGeneral,
+				ExpiresAt: time.Now().Add(time.Hour)}
+
+			storage := newMemStorage()
+			storage.uploads[session.StorageUploadID] = append([]byte{}, content[:test.uploaded]...)
+			repo := newMemRepository()
+			repo.sessions[session.ID] = session
+			if test.leased {
+				repo.leases[session.ID] = "another-request"
+			}
+			svc := New(Config{MaxFileSize: 1024, AllowedMimeTypes: testAllowedMimeTypes}, storage, repo, discardLogger())
+
+			resp, wErr := svc.WriteChunk(ctx, WriteChunkRequest{UploadID: session.ID, UploaderID: session.UploaderID,
+				Offset: test.offset, Chunk: bytes.NewReader(test.chunk)})
+			if test.expectedError != "" {
+				var richErr richerror.RichError
+				if !errors.As(wErr, &richErr) || richErr.Kind() != richerror.KindConflict || richErr.Message() != test.expectedError {
+					t.Fatalf("expected conflict %q, got %v", test.expectedError, wErr)
+				}
+
+				if offset, _ := storage.GetResumableOffset(ctx, session.Key, session.StorageUploadID); offset != int64(test.uploaded) {
+					t.Fatalf("expected the upload not to be written, offset %d", offset)
+				}
+
+				return
+			}
+			if wErr != nil {
+				t.Fatalf("unexpected error: %v", wErr)
+			}
+
+			if resp.Offset != test.wantOffset || (resp.File != nil) != test.wantFile {
+				t.Fatalf("expected offset %d with file %t, got %d with %v", test.wantOffset, test.wantFile, resp.Offset, resp.File)
+			}
+
+			if len(repo.leases) != 0 {
+				t.Fatalf("expected the lease to be released, got %v", repo.leases)
+			}
+
+			if test.wantFile {
+				if data, _ := storage.get(session.Key); !bytes.Equal(data, content) {
+					t.Fatalf("expected the object %q, got %q", content, data)
+				}
+
+				if _, ok := repo.sessions[session.ID]; ok {
+					t.Fatal("expected the session of the completed upload to be removed")
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
+	"time"
 
 	"github.com/syntaxfa/quick-connect/types"
 )
@@ -12,30 +14,119 @@ type Storage interface {
 	Upload(ctx context.Context, file io.Reader, size int64, key, contentType string, isPublic bool) (string, error)
 	Delete(ctx context.Context, key string) error
 	GetURL(ctx context.Context, key string) (string, error)
-	GetPresignedURL(ctx context.Context, key string) (string, error)
+	GetPresignedURL(ctx context.Context, key string, opts PresignOptions) (string, error)
 	Exists(ctx context.Context, key string) (bool, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	ResumableStorage
 }
 
+// ResumableStorage stores the chunks of the resumable uploads, uploadID is the id of the upload in the storage.
+// WriteChunk appends the chunk to the upload and keeps the stored part of the chunk when the chunk is interrupted,
+// so the uploaded size is read by GetResumableOffset after each chunk. GetResumableOffset and
+// CompleteResumableUpload also work after the upload is completed, so a failed completion can be retried.
+type ResumableStorage interface {
+	CreateResumableUpload(ctx context.Context, key, contentType string, isPublic bool) (string, error)
+	WriteChunk(ctx context.Context, key, uploadID string, chunk io.Reader) error
+	GetResumableOffset(ctx context.Context, key, uploadID string) (int64, error)
+	CompleteResumableUpload(ctx context.Context, key, uploadID string) error
+	AbortResumableUpload(ctx context.Context, key, uploadID string) error
+}
+
+// DirectUploadStorage is implemented by the storages that the clients upload to directly, such as S3.
+// PresignUpload returns the request that uploads the object of the key, the storage rejects the object when
+// its size or its content type doesn't match opts. Copy copies the object of srcKey to dstKey with the visibility,
+// Copy and Stat return ErrObjectNotFound when the object doesn't exist.
+type DirectUploadStorage interface {
+	PresignUpload(ctx context.Context, key string, opts PresignUploadOptions) (PresignedUpload, error)
+	Copy(ctx context.Context, srcKey, dstKey string, isPublic bool) error
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+}
+
+// RangeStorage is implemented by the storages whose objects are not seekable, such as S3. OpenRange reads the object
+// from offset to its end, so the downloads are read from the requested range.
+type RangeStorage interface {
+	OpenRange(ctx context.Context, key string, offset int64) (io.ReadCloser, error)
+}
+
+// ErrObjectNotFound is returned by the storages when the object of the key doesn't exist.
+var ErrObjectNotFound = errors.New("object not found")
+
 type Repository interface {
 	Save(ctx context.Context, file File) error
+	SaveWithBlob(ctx context.Context, file File) (string, error)
 	IsExistByID(ctx context.Context, fileID types.ID) (bool, error)
 	GetByID(ctx context.Context, fileID types.ID) (File, error)
+	IsExistByKey(ctx context.Context, key string) (bool, error)
+	GetByKey(ctx context.Context, key string) (File, error)
 	DeleteByID(ctx context.Context, fileID types.ID) error
 	ConfirmFile(ctx context.Context, fileID types.ID) error
+	MarkFileReady(ctx context.Context, file File) (string, error)
+	GetUnconfirmedFiles(ctx context.Context, createdBefore time.Time, afterID types.ID, limit int) ([]File, error)
+	GetDeletedFiles(ctx context.Context, deletedBefore time.Time, afterID types.ID, limit int) ([]File, error)
+	PurgeByID(ctx context.Context, fileID types.ID) error
+	PurgeUnconfirmedByID(ctx context.Context, fileID types.ID) (bool, error)
+	PurgeDeletedByID(ctx context.Context, fileID types.ID) (bool, error)
+	TryLock(ctx context.Context, lockID int64) (func() error, bool, error)
+	LeaseUpload(ctx context.Context, uploadID types.ID, token string, ttl time.Duration) (bool, error)
+	ReleaseUploadLease(ctx context.Context, uploadID types.ID, token string) error
+	DeleteExpiredUploadLeases(ctx context.Context, before time.Time) error
+	SaveVariant(ctx context.Context, variant FileVariant) error
+	IsExistVariant(ctx context.Context, fileID types.ID, name string) (bool, error)
+	GetVariant(ctx context.Context, fileID types.ID, name string) (FileVariant, error)
+	GetVariantsByFileID(ctx context.Context, fileID types.ID) ([]FileVariant, error)
+	IsExistVariantByKey(ctx context.Context, key string) (bool, error)
+	GetVariantByKey(ctx context.Context, key string) (FileVariant, error)
+	ClaimPendingVariants(ctx context.Context, now, retryAt time.Time, limit int) ([]File, error)
+	CompleteVariants(ctx context.Context, fileID types.ID) error
+	SaveUploadSession(ctx context.Context, session UploadSession) error
+	IsExistUploadSession(ctx context.Context, uploadID types.ID) (bool, error)
+	GetUploadSession(ctx context.Context, uploadID types.ID) (UploadSession, error)
+	GetExpiredUploadSessions(ctx context.Context, expiredBefore time.Time, afterID types.ID, limit int) ([]UploadSession, error)
+	DeleteUploadSession(ctx context.Context, uploadID types.ID) error
+	IsExistBlob(ctx context.Context, key string) (bool, error)
+	GetBlob(ctx context.Context, key string) (Blob, error)
+	GetReleasedBlobs(ctx context.Context, afterKey string, limit int) ([]Blob, error)
+	DeleteBlob(ctx context.Context, key string) error
+	GetStorageUsages(ctx context.Context, uploaderIDs []types.ID) ([]StorageUsage, error)
+	ReserveStorage(ctx context.Context, reservation StorageReservation, limit int64) (bool, error)
+	DeleteStorageReservation(ctx context.Context, reservationID types.ID) error
+	DeleteExpiredStorageReservations(ctx context.Context, before time.Time) error
+	GetFilesByDriver(ctx context.Context, driver Driver, bucket, afterKey string, limit int) ([]File, error)
+	GetVariantsByFileKey(ctx context.Context, driver Driver, key string) ([]FileVariant, error)
+	MoveObject(ctx context.Context, move ObjectMove) (string, error)
+	SaveInstanceHeartbeat(ctx context.Context, instanceID types.ID) error
+	DeleteInstanceHeartbeat(ctx context.Context, instanceID types.ID) error
+	DeleteStaleInstanceHeartbeats(ctx context.Context, before time.Time) error
+	IsExistLiveInstance(ctx context.Context, seenAfter time.Time) (bool, error)
 }
 
 type Service struct {
-	cfg     Config
-	storage Storage
-	repo    Repository
-	logger  *slog.Logger
+	cfg            Config
+	storage        Storage
+	directStorage  DirectUploadStorage
+	rangeStorage   RangeStorage
+	repo           Repository
+	logger         *slog.Logger
+	janitorMetrics janitorMetrics
+	dedupMetrics   dedupMetrics
+	images         chan imageJob
 }
 
+// New the direct uploads are enabled when the storage implements DirectUploadStorage, the downloads are read by
+// ranges when it implements RangeStorage.
 func New(cfg Config, storage Storage, repo Repository, logger *slog.Logger) Service {
+	directStorage, _ := storage.(DirectUploadStorage)
+	rangeStorage, _ := storage.(RangeStorage)
+
 	return Service{
-		cfg:     cfg,
-		storage: storage,
-		repo:    repo,
-		logger:  logger,
+		cfg:            cfg,
+		storage:        storage,
+		directStorage:  directStorage,
+		rangeStorage:   rangeStorage,
+		repo:           repo,
+		logger:         logger,
+		janitorMetrics: newJanitorMetrics(),
+		dedupMetrics:   newDedupMetrics(),
+		images:         make(chan imageJob, imageWorkers(cfg.Image)),
 	}
 }
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// memStorage is an in-memory Storage of the tests.
+type memStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string][]byte
+	opens   int
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{objects: make(map[string][]byte), uploads: make(map[string][]byte)}
+}
+
+func (m *memStorage) put(key string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = data
+}
+
+func (m *memStorage) get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.objects[key]
+
+	return data, ok
+}
+
+func (m *memStorage) Upload(_ context.Context, file io.Reader, _ int64, key, _ string, _ bool) (string, error) {
+	data, rErr := io.ReadAll(file)
+	if rErr != nil {
+		return "", rErr
+	}
+
+	m.put(key, data)
+
+	return key, nil
+}
+
+func (m *memStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, key)
+
+	return nil
+}
+
+func (m *memStorage) GetURL(_ context.Context, key string) (string, error) {
+	return "mem://" + key, nil
+}
+
+func (m *memStorage) GetPresignedURL(_ context.Context, key string, _ PresignOptions) (string, error) {
+	return "mem://" + key + "?signed", nil
+}
+
+func (m *memStorage) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := m.get(key)
+
+	return ok, nil
+}
+
+func (m *memStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	m.opens++
+	m.mu.Unlock()
+
+	data, ok := m.get(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+
+	return memObject{Reader: bytes.NewReader(data)}, nil
+}
+
+// memObject is a seekable object of memStorage, like the objects of the local driver.
+type memObject struct {
+	*bytes.Reader
+}
+
+func (memObject) Close() error {
+	return nil
+}
+
+// CreateResumableUpload the id of the upload is its key, the chunks are kept in memory until it is completed.
+func (m *memStorage) CreateResumableUpload(_ context.Context, key, _ string, _ bool) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.uploads[key] = []byte{}
+
+	return key, nil
+}
+
+func (m *memStorage) WriteChunk(_ context.Context, _, uploadID string, chunk io.Reader) error {
+	data, rErr := io.ReadAll(chunk)
+	if rErr != nil {
+		return rErr
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uploaded, ok := m.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("upload %s doesn't exist", uploadID)
+	}
+	m.uploads[uploadID] = append(uploaded, data...)
+
+	return nil
+}
+
+func (m *memStorage) GetResumableOffset(_ context.Context, key, uploadID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if uploaded, ok := m.uploads[uploadID]; ok {
+		return int64(len(uploaded)), nil
+	}
+
+	if object, ok := m.objects[key]; ok {
+		return int64(len(object)), nil
+	}
+
+	return 0, fmt.Errorf("upload %s doesn't exist", uploadID)
+}
+
+func (m *memStorage) CompleteResumableUpload(_ context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uploaded, ok := m.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("upload %s doesn't exist", uploadID)
+	}
+	m.objects[key] = uploaded
+	delete(m.uploads, uploadID)
+
+	return nil
+}
+
+func (m *memStorage) AbortResumableUpload(_ context.Context, _, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.uploads, uploadID)
+
+	return nil
+}
+
+// memDirectStorage is an in-memory DirectUploadStorage of the tests.
+type memDirectStorage struct {
+	*memStorage
+	contentTypes map[string]string
+}
+
+func (m memDirectStorage) PresignUpload(_ context.Context, key string, _ PresignUploadOptions) (PresignedUpload, error) {
+	return PresignedUpload{Method: "PUT", URL: "mem://" + key}, nil
+}
+
+func (m memDirectStorage) Copy(_ context.Context, srcKey, dstKey string, _ bool) error {
+	data, ok := m.get(srcKey)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrObjectNotFound, srcKey)
+	}
+
+	m.put(dstKey, data)
+	if contentType, ok := m.contentTypes[srcKey]; ok {
+		m.contentTypes[dstKey] = contentType
+	}
+
+	return nil
+}
+
+func (m memDirectStorage) Stat(_ context.Context, key string) (ObjectInfo, error) {
+	data, ok := m.get(key)
+	if !ok {
+		return ObjectInfo{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+
+	return ObjectInfo{Size: int64(len(data)), ContentType: m.contentTypes[key]}, nil
+}
+
+// testAllowedMimeTypes allows the general uploads of the tests, the purposes without an allow-list reject all files.
+var testAllowedMimeTypes = map[UploadPurpose][]string{UploadPurposeGeneral: {"image/*", "text/plain"}}
+
+// memRepository is an in-memory Repository of the tests, the methods that are not implemented panic.
+type memRepository struct {
+	Repository
+	mu         sync.Mutex
+	files      map[types.ID]File
+	purged     []types.ID
+	locked     bool
+	leases     map[types.ID]string
+	sessions   map[types.ID]UploadSession
+	variants   map[string][]FileVariant
+	mergedKeys map[string]string
+	moves      []ObjectMove
+	reserved   map[types.ID]StorageReservation
+	heartbeats map[types.ID]time.Time
+	completed  []types.ID
+}
+
+func newMemRepository(files ...File) *memRepository {
+	repo := &memRepository{files: make(map[types.ID]File), variants: make(map[string][]FileVariant),
+		mergedKeys: make(map[string]string), leases: make(map[types.ID]string),
+		sessions: make(map[types.ID]UploadSession), reserved: make(map[types.ID]StorageReservation),
+		heartbeats: make(map[types.ID]time.Time)}
+	for _, file := range files {
+		repo.files[file.ID] = file
+	}
+
+	return repo
+}
+
+func (m *memRepository) TryLock(_ context.Context, _ int64) (func() error, bool, error) {
+	return func() error { return nil }, !m.locked, nil
+}
+
+func (m *memRepository) LeaseUpload(_ context.Context, uploadID types.ID, token string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if holder, ok := m.leases[uploadID]; ok && holder != token {
+		return false, nil
+	}
+	m.leases[uploadID] = token
+
+	return true, nil
+}
+
+func (m *memRepository) ReleaseUploadLease(_ context.Context, uploadID types.ID, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.leases[uploadID] == token {
+		delete(m.leases, uploadID)
+	}
+
+	return nil
+}
+
+func (m *memRepository) IsExistByID(_ context.Context, fileID types.ID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.files[fileID]
+
+	return ok, nil
+}
+
+func (m *memRepository) GetByID(_ context.Context, fileID types.ID) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.files[fileID], nil
+}
+
+func (m *memRepository) IsExistByKey(ctx context.Context, key string) (bool, error) {
+	_, found := m.fileByKey(ctx, key)
+
+	return found, nil
+}
+
+func (m *memRepository) GetByKey(ctx context.Context, key string) (File, error) {
+	file, _ := m.fileByKey(ctx, key)
+
+	return file, nil
+}
+
+func (m *memRepository) fileByKey(_ context.Context, key string) (File, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, file := range m.files {
+		if file.Key == key {
+			return file, true
+		}
+	}
+
+	return File{}, false
+}
+
+func (m *memRepository) PurgeByID(_ context.Context, fileID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files, fileID)
+	m.purged = append(m.purged, fileID)
+
+	return nil
+}
+
+func (m *memRepository) GetVariantsByFileID(_ context.Context, fileID types.ID) ([]FileVariant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var variants []FileVariant
+	for _, fileVariants := range m.variants {
+		for _, variant := range fileVariants {
+			if variant.FileID == fileID {
+				variants = append(variants, variant)
+			}
+		}
+	}
+
+	return variants, nil
+}
+
+// SaveVariant the variants are kept by the key of their file, like the variants of GetVariantsByFileKey.
+func (m *memRepository) SaveVariant(_ context.Context, variant FileVariant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := m.files[variant.FileID].Key
+	m.variants[key] = append(m.variants[key], variant)
+
+	return nil
+}
+
+func (m *memRepository) CompleteVariants(_ context.Context, fileID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.completed = append(m.completed, fileID)
+
+	return nil
+}
+
+func (m *memRepository) IsExistBlob(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+func (m *memRepository) SaveWithBlob(_ context.Context, file File) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[file.ID] = file
+
+	return file.Key, nil
+}
+
+func (m *memRepository) SaveUploadSession(_ context.Context, session UploadSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.ID] = session
+
+	return nil
+}
+
+func (m *memRepository) IsExistUploadSession(_ context.Context, uploadID types.ID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.sessions[uploadID]
+
+	return ok, nil
+}
+
+func (m *memRepository) GetUploadSession(_ context.Context, uploadID types.ID) (UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.sessions[uploadID], nil
+}
+
+func (m *memRepository) DeleteUploadSession(_ context.Context, uploadID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, uploadID)
+
+	return nil
+}
+
+func (m *memRepository) MarkFileReady(_ context.Context, file File) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file.Status = FileStatusReady
+	m.files[file.ID] = file
+
+	return file.Key, nil
+}
+
+func (m *memRepository) GetFilesByDriver(_ context.Context, driver Driver, bucket, afterKey string, limit int) ([]File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var files []File
+	for _, file := range m.files {
+		if file.Driver == driver && (bucket == "" || file.Bucket == bucket) && file.Key > afterKey && file.IsReady() {
+			files = append(files, file)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Key < files[j].Key })
+	if len(files) > limit {
+		files = files[:limit]
+	}
+
+	return files, nil
+}
+
+func (m *memRepository) GetVariantsByFileKey(_ context.Context, _ Driver, key string) ([]FileVariant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.variants[key], nil
+}
+
+// MoveObject moves the files of the key, the files are merged into the key of mergedKeys when it is set.
+func (m *memRepository) MoveObject(_ context.Context, move ObjectMove) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.moves = append(m.moves, move)
+
+	movedKey := move.KeyPrefix + move.Key
+	if mergedKey, ok := m.mergedKeys[move.Key]; ok {
+		movedKey = mergedKey
+	}
+
+	for id, file := range m.files {
+		if file.Key == move.Key && file.Driver == move.FromDriver {
+			file.Key = movedKey
+			file.Driver = move.ToDriver
+			file.Bucket = move.ToBucket
+			m.files[id] = file
+		}
+	}
+
+	return movedKey, nil
+}
+
+// ReserveStorage the usage of the uploader is the size of its files that are not deleted, like the usage of postgres.
+func (m *memRepository) ReserveStorage(_ context.Context, reservation StorageReservation, limit int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	used := reservation.Size
+	for _, file := range m.files {
+		if file.UploaderID == reservation.UploaderID && !file.IsDeleted() {
+			used += file.Size
+		}
+	}
+	for _, reserved := range m.reserved {
+		if reserved.UploaderID == reservation.UploaderID {
+			used += reserved.Size
+		}
+	}
+
+	if used > limit {
+		return false, nil
+	}
+	m.reserved[reservation.ID] = reservation
+
+	return true, nil
+}
+
+func (m *memRepository) DeleteStorageReservation(_ context.Context, reservationID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.reserved, reservationID)
+
+	return nil
+}
+
+func (m *memRepository) SaveInstanceHeartbeat(_ context.Context, instanceID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.heartbeats[instanceID] = time.Now()
+
+	return nil
+}
+
+func (m *memRepository) DeleteInstanceHeartbeat(_ context.Context, instanceID types.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.heartbeats, instanceID)
+
+	return nil
+}
+
+func (m *memRepository) IsExistLiveInstance(_ context.Context, seenAfter time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, seenAt := range m.heartbeats {
+		if !seenAt.Before(seenAfter) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -1,14 +1,21 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/syntaxfa/quick-connect/pkg/errlog"
 	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
 	"github.com/syntaxfa/quick-connect/types"
 )
 
@@ -16,11 +23,62 @@ func (s Service) Upload(ctx context.Context, req UploadRequest) (File, error) {
 	const op = "service.upload.Upload"
 	ext := filepath.Ext(req.Filename)
 
+	if req.Purpose == "" {
+		req.Purpose = UploadPurposeGeneral
+	}
+	if !IsValidUploadPurpose(req.Purpose) {
+		return File{}, richerror.New(op).WithMessage(servermsg.MsgInvalidUploadPurpose).WithKind(richerror.KindBadRequest)
+	}
+
+	// the mime type is detected by the content of the file, the content type of the client is not trusted.
+	bufReader := bufio.NewReaderSize(req.File, sniffLength)
+	head, pErr := bufReader.Peek(sniffLength)
+	if pErr != nil && !errors.Is(pErr, io.EOF) {
+		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(pErr).WithKind(richerror.KindUnexpected), s.logger)
+	}
+
+	mimeType := detectMimeType(head, req.Filename)
+	if tErr := s.checkFileType(req.Purpose, mimeType, req.Filename); tErr != nil {
+		return File{}, tErr
+	}
+
 	newID := types.ID(ulid.Make().String())
 
+	if qErr := s.reserveQuota(ctx, newID, req.UploaderID, req.UploaderRoles, req.Size,
+		time.Now().Add(uploadReservationTTL)); qErr != nil {
+		return File{}, qErr
+	}
+	// the usage of the file is counted when it is saved.
+	defer s.releaseQuota(context.WithoutCancel(ctx), newID)
+
 	key := fmt.Sprintf("uploads/%s%s", newID, ext)
 
-	uploadKey, uErr := s.storage.Upload(ctx, req.File, req.Size, key, req.ContentType, req.IsPublic)
+	var reader io.Reader = bufReader
+	size := req.Size
+
+	// the images are kept in memory to strip their metadata and for the image processing,
+	// their size is limited by MaxFileSize.
+	var imageData []byte
+	if _, ok := imageMimeTypes[mimeType]; ok {
+		data, rErr := io.ReadAll(bufReader)
+		if rErr != nil {
+			return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), s.logger)
+		}
+
+		stripped, sErr := stripImageMetadata(mimeType, data)
+		if sErr != nil {
+			return File{}, richerror.New(op).WithWrapError(sErr).WithMessage(servermsg.MsgInvalidImage).
+				WithKind(richerror.KindBadRequest)
+		}
+
+		imageData = stripped
+		reader = bytes.NewReader(imageData)
+		size = int64(len(imageData))
+	}
+
+	hash := sha256.New()
+
+	uploadKey, uErr := s.storage.Upload(ctx, io.TeeReader(reader, hash), size, key, mimeType, req.IsPublic)
 	if uErr != nil {
 		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
@@ -30,20 +88,30 @@ func (s Service) Upload(ctx context.Context, req UploadRequest) (File, error) {
 		UploaderID:  req.UploaderID,
 		Name:        req.Filename,
 		Key:         uploadKey,
-		MimeType:    req.ContentType,
-		Size:        req.Size,
+		MimeType:    mimeType,
+		Size:        size,
 		Driver:      s.cfg.Driver,
 		Bucket:      s.cfg.Bucket,
 		IsPublic:    req.IsPublic,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		Purpose:     req.Purpose,
+		Status:      FileStatusReady,
 		IsConfirmed: false,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		DeletedAt:   nil,
+
+		VariantsRetryAt: s.pendingVariants(mimeType),
 	}
 
-	if sErr := s.repo.Save(ctx, file); sErr != nil {
+	file, sErr := s.saveFile(ctx, file)
+	if sErr != nil {
 		return File{}, errlog.ErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), s.logger)
 	}
 
+	if file.VariantsRetryAt != nil {
+		s.enqueueImage(file, imageData)
+	}
+
 	return file, nil
 }
@@ -38,6 +38,11 @@ func (s Service) AddStory(ctx context.Context, req AddStoryRequest, creatorID ty
 		return AddStoryResponse{}, richerror.New(op).WithMessage(servermsg.MsgStoryMediaRequirePublic).WithKind(richerror.KindBadRequest)
 	}
 
+	// the mime types of the story uploads are checked by the storage service.
+	if filePb.GetPurpose() != "story" {
+		return AddStoryResponse{}, richerror.New(op).WithMessage(servermsg.MsgStoryMediaInvalidPurpose).WithKind(richerror.KindBadRequest)
+	}
+
 	if _, cErr := s.storageSvc.ConfirmFile(ctxWithValue, &storagepb.ConfirmFileRequest{FileId: string(req.MediaFileID)}); cErr != nil {
 		return AddStoryResponse{}, errlog.ErrContext(ctxWithValue, richerror.New(op).WithWrapError(cErr).
 			WithKind(richerror.KindUnexpected), s.logger)
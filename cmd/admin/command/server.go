@@ -36,7 +36,7 @@ func (s Server) run(trap <-chan os.Signal) {
 		panic(tErr)
 	}
 
-	app := adminapp.Setup(s.cfg, s.logger, trap, t, nil, nil, nil)
+	app := adminapp.Setup(s.cfg, s.logger, trap, t, nil, nil, nil, nil, nil)
 
 	app.Start()
 }
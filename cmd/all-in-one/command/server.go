@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/syntaxfa/quick-connect/adapter/chat"
 	"github.com/syntaxfa/quick-connect/adapter/manager"
+	"github.com/syntaxfa/quick-connect/adapter/notification"
 	"github.com/syntaxfa/quick-connect/app/adminapp"
 	"github.com/syntaxfa/quick-connect/app/chatapp"
 	"github.com/syntaxfa/quick-connect/app/managerapp"
@@ -76,8 +77,8 @@ func (s Server) run(trap <-chan os.Signal) {
 		s.logger.ChatLog.Info("Chat App Stopped")
 	}()
 
-	notificationApp, _ := notificationapp.Setup(s.cfg.NotificationCfg, s.logger.NotificationLog, trapSvc.notificationTrap,
-		reFactory.newConnection(s.cfg.NotificationCfg.Redis), postgresAd.notificationPsqAd)
+	notificationApp, notificationSvc := notificationapp.Setup(s.cfg.NotificationCfg, s.logger.NotificationLog, trapSvc.notificationTrap,
+		reFactory.newConnection(s.cfg.NotificationCfg.Redis), postgresAd.notificationPsqAd, nil, authLocalAdapter)
 
 	wg.Add(1)
 	go func() {
@@ -88,8 +89,9 @@ func (s Server) run(trap <-chan os.Signal) {
 	}()
 
 	conversationLocalAd := chat.NewConversationLocalAdapter(chatSvc, t, s.logger.ChatLog, chatapp.SetupRoleManager(), chatJWTValidator)
+	notificationLocalAd := notification.NewTemplateLocalAdapter(&notificationSvc, t, s.logger.NotificationLog)
 	adminApp := adminapp.Setup(s.cfg.AdminCfg, s.logger.AdminLog, trapSvc.adminTrap, t, authLocalAdapter,
-		userLocalAd, conversationLocalAd)
+		userLocalAd, conversationLocalAd, notificationLocalAd, nil)
 
 	wg.Add(1)
 	go func() {
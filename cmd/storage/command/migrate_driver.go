@@ -0,0 +1,105 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/syntaxfa/quick-connect/adapter/postgres"
+	"github.com/syntaxfa/quick-connect/app/storageapp"
+	postgres2 "github.com/syntaxfa/quick-connect/app/storageapp/repository/postgres"
+	"github.com/syntaxfa/quick-connect/app/storageapp/service"
+)
+
+type MigrateDriver struct {
+	cfg    storageapp.Config
+	logger *slog.Logger
+	req    service.MigrateDriverRequest
+	from   string
+	to     string
+}
+
+func (m MigrateDriver) Command(cfg storageapp.Config, logger *slog.Logger) *cobra.Command {
+	m.cfg = cfg
+	m.logger = logger
+
+	cmd := &cobra.Command{
+		Use:   "migrate-driver",
+		Short: "copy the stored files from a storage driver to another",
+		Long: `Copies the objects of the files from the storage of --from to the storage of --to in batches, verifies each
+copied object by its sha256 checksum and moves the files to the new driver. Both storages are read from the storage
+config. The migration is resumable, running it again migrates only the files that are left on --from.
+Stop the storage app before the migration, it is refused while an instance is running. Set storage.driver to --to
+and start the storage app when the migration is done.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			m.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&m.from, "from", string(cfg.Storage.Driver), "driver of the files to migrate (s3 or local)")
+	cmd.Flags().StringVar(&m.to, "to", "", "driver to migrate the files to (s3 or local)")
+	cmd.Flags().StringVar(&m.req.FromBucket, "from-bucket", "", "only migrate the files of this bucket (default all the buckets)")
+	cmd.Flags().StringVar(&m.req.KeyPrefix, "key-prefix", "", "prefix of the keys of the migrated objects")
+	cmd.Flags().IntVar(&m.req.BatchSize, "batch-size", 100, "number of the files that are selected in each batch")
+	cmd.Flags().BoolVar(&m.req.DeleteSource, "delete-source", false, "delete the source objects after their files are migrated")
+	cmd.Flags().BoolVar(&m.req.DryRun, "dry-run", false, "only log the files that would be migrated")
+
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func (m MigrateDriver) run() {
+	m.req.From = service.Driver(m.from)
+	m.req.To = service.Driver(m.to)
+	if m.req.To == service.DriverS3 {
+		m.req.ToBucket = m.cfg.Storage.AWS.BucketName
+	}
+
+	if !service.IsValidDriver(m.req.From) || !service.IsValidDriver(m.req.To) || m.req.From == m.req.To {
+		m.logger.Error("invalid storage drivers given", slog.String("from", m.from), slog.String("to", m.to))
+
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	source, sErr := storageapp.NewStorage(ctx, m.req.From, m.cfg.Storage, m.logger)
+	if sErr != nil {
+		m.logger.Error("can't create source storage", slog.String("error", sErr.Error()))
+
+		return
+	}
+
+	target, tErr := storageapp.NewStorage(ctx, m.req.To, m.cfg.Storage, m.logger)
+	if tErr != nil {
+		m.logger.Error("can't create target storage", slog.String("error", tErr.Error()))
+
+		return
+	}
+
+	psqAdapter := postgres.New(m.cfg.Postgres, m.logger)
+	defer psqAdapter.Close()
+
+	svc := service.New(m.cfg.Service, source, postgres2.New(psqAdapter), m.logger)
+
+	result, mErr := svc.MigrateDriver(ctx, target, m.req)
+	if mErr != nil {
+		m.logger.Error("storage driver migration failed", slog.String("error", mErr.Error()), slog.Any("result", result))
+
+		return
+	}
+
+	if result.Failed > 0 {
+		m.logger.Warn("storage driver migration is done with failed files, run it again to retry them",
+			slog.Any("result", result))
+
+		return
+	}
+
+	m.logger.Info("storage driver migration is done", slog.Any("result", result), slog.Bool("dry_run", m.req.DryRun))
+}
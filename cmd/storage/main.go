@@ -52,6 +52,7 @@ func main() {
 	root.AddCommand(
 		command.Server{}.Command(cfg, log, trap),
 		command.Migrate{}.Command(cfg.Postgres, log),
+		command.MigrateDriver{}.Command(cfg, log),
 	)
 
 	if exErr := root.Execute(); exErr != nil {
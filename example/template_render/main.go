@@ -94,7 +94,7 @@ func main() {
 		Type:              service.NotificationTypeInfo,
 		Data:              nil,
 		TemplateName:      "otp_code",
-		DynamicBodyData:   map[string]string{"otp_code": "123458"},
+		DynamicBodyData:   service.DynamicData{"otp_code": "123458"},
 		DynamicTitleData:  nil,
 		IsRead:            false,
 		IsInApp:           false,
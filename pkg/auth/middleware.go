@@ -60,6 +60,23 @@ func (m *Middleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// OptionalAuth sets the user claims when the request has a valid bearer token, the other requests are passed
+// as anonymous requests.
+func (m *Middleware) OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tokenStr, err := extractToken(c.Request().Header.Get("Authorization"))
+		if err != nil || tokenStr == "" {
+			return next(c)
+		}
+
+		if claims, jErr := m.validator.ValidateToken(tokenStr); jErr == nil {
+			c.Set(string(types.UserContextKey), claims)
+		}
+
+		return next(c)
+	}
+}
+
 func (m *Middleware) RequireRole(roles []types.Role) func(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -0,0 +1,40 @@
+package identityresolver
+
+import "time"
+
+type Driver string
+
+const (
+	// DriverHTTP posts the token to the backend of the deployment, the resolved user ids are cached.
+	DriverHTTP Driver = "http"
+	// DriverJWT validates JWTs which are signed by the backend of the deployment with a shared secret or a JWKS.
+	DriverJWT Driver = "jwt"
+	// DriverManager validates the client tokens of the manager app, the resolved user id is the Quick Connect
+	// user id, so the notifications must be sent with it as their external user id.
+	DriverManager Driver = "manager"
+)
+
+type Config struct {
+	Driver Driver     `koanf:"driver"`
+	HTTP   HTTPConfig `koanf:"http"`
+	JWT    JWTConfig  `koanf:"jwt"`
+}
+
+type HTTPConfig struct {
+	// URL is required by the http driver.
+	URL     string        `koanf:"url"`
+	Timeout time.Duration `koanf:"timeout"`
+	// CacheTTL is the cache expiration of the resolved user ids, the cache is disabled when it is zero.
+	CacheTTL time.Duration `koanf:"cache_ttl"`
+}
+
+// JWTConfig one of Secret and JWKSURL must be set, Issuer and Audience are checked when they are set.
+type JWTConfig struct {
+	Secret              string        `koanf:"secret"`
+	JWKSURL             string        `koanf:"jwks_url"`
+	JWKSRefreshInterval time.Duration `koanf:"jwks_refresh_interval"`
+	Issuer              string        `koanf:"issuer"`
+	Audience            string        `koanf:"audience"`
+	// UserIDClaim is the claim which holds the external user id, default is sub.
+	UserIDClaim string `koanf:"user_id_claim"`
+}
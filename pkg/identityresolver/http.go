@@ -0,0 +1,133 @@
+package identityresolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+type httpResponse struct {
+	UserID string `json:"user_id"`
+}
+
+type cacheValue struct {
+	UserID string `json:"user_id"`
+}
+
+// HTTPResolver posts the token to the configured url and reads the user id of the response.
+type HTTPResolver struct {
+	cfg        HTTPConfig
+	cache      *cachemanager.CacheManager
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+func NewHTTPResolver(cfg HTTPConfig, cache *cachemanager.CacheManager, logger *slog.Logger) (*HTTPResolver, error) {
+	const op = "identityresolver.http.NewHTTPResolver"
+
+	if cfg.URL == "" {
+		return nil, richerror.New(op).WithMessage("http url must be set").WithKind(richerror.KindInvalid)
+	}
+
+	return &HTTPResolver{
+		cfg:        cfg,
+		cache:      cache,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		logger:     logger,
+	}, nil
+}
+
+func (r *HTTPResolver) Resolve(ctx context.Context, token string) (string, error) {
+	const op = "identityresolver.http.Resolve"
+
+	key := r.cacheKey(token)
+	if r.cfg.CacheTTL > 0 {
+		var value cacheValue
+		gErr := r.cache.Get(ctx, key, &value)
+		if gErr == nil {
+			return value.UserID, nil
+		}
+
+		if !errors.Is(gErr, cachemanager.ErrKeyNotFound) {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(gErr).WithKind(richerror.KindUnexpected), r.logger)
+		}
+	}
+
+	userID, fErr := r.fetchUserID(ctx, token)
+	if fErr != nil {
+		return "", fErr
+	}
+
+	if r.cfg.CacheTTL > 0 {
+		if sErr := r.cache.Set(ctx, key, cacheValue{UserID: userID}, r.cfg.CacheTTL); sErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(sErr).WithKind(richerror.KindUnexpected), r.logger)
+		}
+	}
+
+	return userID, nil
+}
+
+func (r *HTTPResolver) fetchUserID(ctx context.Context, token string) (string, error) {
+	const op = "identityresolver.http.fetchUserID"
+
+	jsonData, mErr := json.Marshal(map[string]string{"token": token})
+	if mErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(mErr).WithKind(richerror.KindUnexpected), r.logger)
+	}
+
+	// #nosec G107
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.URL, bytes.NewBuffer(jsonData))
+	if reqErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(reqErr).WithKind(richerror.KindUnexpected), r.logger)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, dErr := r.httpClient.Do(req)
+	if dErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(dErr).WithKind(richerror.KindUnexpected), r.logger)
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil {
+			errlog.WithoutErrContext(ctx, richerror.New(op).WithWrapError(cErr).WithKind(richerror.KindUnexpected), r.logger)
+		}
+	}()
+
+	body, rErr := io.ReadAll(resp.Body)
+	if rErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(rErr).WithKind(richerror.KindUnexpected), r.logger)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithKind(richerror.KindUnAuthorized)
+	}
+
+	var response httpResponse
+	if uErr := json.Unmarshal(body, &response); uErr != nil {
+		return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(uErr).WithKind(richerror.KindUnexpected), r.logger)
+	}
+
+	if response.UserID == "" {
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithKind(richerror.KindUnAuthorized)
+	}
+
+	return response.UserID, nil
+}
+
+// cacheKey tokens are hashed, so the cache does not hold usable tokens.
+func (r *HTTPResolver) cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return "identity:tokens:" + hex.EncodeToString(sum[:])
+}
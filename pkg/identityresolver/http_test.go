@@ -0,0 +1,150 @@
+package identityresolver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+// memCache is an in-memory cachemanager.CacheClient of the tests, only Set and Get are used by the resolver.
+type memCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (m *memCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+
+	return nil
+}
+
+func (m *memCache) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[key]
+	if !ok {
+		return nil, cachemanager.ErrKeyNotFound
+	}
+
+	return value, nil
+}
+
+func (m *memCache) MGet(_ context.Context, _ ...string) ([]interface{}, error) { return nil, nil }
+
+func (m *memCache) Delete(_ context.Context, _ ...string) error { return nil }
+
+func (m *memCache) GetTTL(_ context.Context, _ string) (time.Duration, error) { return 0, nil }
+
+func (m *memCache) Incr(_ context.Context, _ string) (int64, error) { return 0, nil }
+
+func (m *memCache) Decr(_ context.Context, _ string) (int64, error) { return 0, nil }
+
+func (m *memCache) Expire(_ context.Context, _ string, _ time.Duration) error { return nil }
+
+// userIDServer responds with the user id of the known tokens and 401 for the other tokens.
+type userIDServer struct {
+	mu       sync.Mutex
+	userIDs  map[string]string
+	requests int
+}
+
+func (s *userIDServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if dErr := json.NewDecoder(r.Body).Decode(&body); dErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	userID, ok := s.userIDs[body.Token]
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(httpResponse{UserID: userID})
+}
+
+func (s *userIDServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func TestNewHTTPResolver(t *testing.T) {
+	if _, nErr := NewHTTPResolver(HTTPConfig{}, nil, discardLogger()); nErr == nil {
+		t.Fatal("expected error of the empty url")
+	}
+
+	if _, nErr := New(Config{Driver: DriverHTTP}, nil, nil, discardLogger()); nErr == nil {
+		t.Fatal("expected error of the http driver without url")
+	}
+}
+
+func TestHTTPResolver(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheTTL     time.Duration
+		token        string
+		wantUserID   string
+		wantRequests int
+	}{
+		{name: "cached", cacheTTL: time.Minute, token: "token-1", wantUserID: "user-1", wantRequests: 1},
+		{name: "cache disabled", token: "token-1", wantUserID: "user-1", wantRequests: 3},
+		{name: "empty user id", cacheTTL: time.Minute, token: "token-2", wantRequests: 3},
+		{name: "invalid token", cacheTTL: time.Minute, token: "token-3", wantRequests: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &userIDServer{userIDs: map[string]string{"token-1": "user-1", "token-2": ""}}
+			httpServer := httptest.NewServer(server)
+			t.Cleanup(httpServer.Close)
+
+			cache := cachemanager.New(&memCache{values: make(map[string][]byte)}, discardLogger())
+			resolver, nErr := NewHTTPResolver(HTTPConfig{URL: httpServer.URL, Timeout: time.Second, CacheTTL: test.cacheTTL},
+				cache, discardLogger())
+			if nErr != nil {
+				t.Fatalf("unexpected error: %v", nErr)
+			}
+
+			// the rejected tokens are not cached.
+			for range 3 {
+				userID, rErr := resolver.Resolve(context.Background(), test.token)
+				if test.wantUserID == "" {
+					assertKind(t, rErr, richerror.KindUnAuthorized)
+
+					continue
+				}
+
+				if rErr != nil || userID != test.wantUserID {
+					t.Fatalf("expected user id %q, got %q %v", test.wantUserID, userID, rErr)
+				}
+			}
+
+			if requests := server.requestCount(); requests != test.wantRequests {
+				t.Fatalf("expected %d requests, got %d", test.wantRequests, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package identityresolver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	jwksFetchTimeout = 10 * time.Second
+	// jwksMinRefreshInterval limits the refreshes which are caused by the tokens with unknown keys.
+	jwksMinRefreshInterval = time.Minute
+	defaultJWKSRefresh     = time.Hour
+)
+
+var errKeyNotFound = errors.New("jwks key not found")
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	url             string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+	mu              sync.RWMutex
+	keys            map[string]crypto.PublicKey
+	fetchedAt       time.Time
+	// attemptedAt is the time of the last refresh, it is set when the refresh is started, so the failed refreshes
+	// are limited by jwksMinRefreshInterval too, refreshErr is the error of the last refresh.
+	attemptedAt time.Time
+	refreshErr  error
+}
+
+func newJWKS(url string, refreshInterval time.Duration) *jwks {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefresh
+	}
+
+	return &jwks{
+		url:             url,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: jwksFetchTimeout},
+		keys:            make(map[string]crypto.PublicKey),
+	}
+}
+
+// key returns the key of the kid, a token without kid can be used when the JWKS has only one key. The JWKS is refreshed
+// at most once every jwksMinRefreshInterval, so the tokens with unknown keys and an unavailable JWKS don't cause
+// a request to the JWKS for every token.
+func (j *jwks) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	now := time.Now()
+
+	j.mu.Lock()
+	key, ok := j.findKey(kid)
+	if (ok && now.Sub(j.fetchedAt) < j.refreshInterval) || now.Sub(j.attemptedAt) < jwksMinRefreshInterval {
+		refreshErr := j.refreshErr
+		j.mu.Unlock()
+
+		switch {
+		case ok:
+			return key, nil
+		case refreshErr != nil:
+			return nil, refreshErr
+		default:
+			return nil, errKeyNotFound
+		}
+	}
+	j.attemptedAt = now
+	j.mu.Unlock()
+
+	rErr := j.refresh(ctx)
+
+	j.mu.Lock()
+	j.refreshErr = rErr
+	j.mu.Unlock()
+
+	if rErr != nil {
+		// The keys which are already fetched are used until the JWKS is available again.
+		if ok {
+			return key, nil
+		}
+
+		return nil, rErr
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if key, ok = j.findKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, errKeyNotFound
+}
+
+func (j *jwks) findKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+
+	key, ok := j.keys[kid]
+
+	return key, ok
+}
+
+func (j *jwks) refresh(ctx context.Context) error {
+	req, rErr := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if rErr != nil {
+		return rErr
+	}
+
+	resp, dErr := j.httpClient.Do(req)
+	if dErr != nil {
+		return dErr
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks responded with status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if uErr := json.NewDecoder(resp.Body).Decode(&set); uErr != nil {
+		return uErr
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, pErr := parseJSONWebKey(jwk)
+		if pErr != nil {
+			return fmt.Errorf("jwks key %q: %w", jwk.Kid, pErr)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mu.Unlock()
+
+	return nil
+}
+
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, nErr := base64.RawURLEncoding.DecodeString(jwk.N)
+		if nErr != nil {
+			return nil, nErr
+		}
+
+		e, eErr := base64.RawURLEncoding.DecodeString(jwk.E)
+		if eErr != nil {
+			return nil, eErr
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("curve %q is not supported", jwk.Crv)
+		}
+
+		x, xErr := base64.RawURLEncoding.DecodeString(jwk.X)
+		if xErr != nil {
+			return nil, xErr
+		}
+
+		y, yErr := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if yErr != nil {
+			return nil, yErr
+		}
+
+		size := (curve.Params().BitSize + 7) / 8 //nolint:mnd // bits to bytes
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid ec key size")
+		}
+
+		point := make([]byte, 1+2*size)
+		point[0] = 4 // uncompressed point
+		new(big.Int).SetBytes(x).FillBytes(point[1 : 1+size])
+		new(big.Int).SetBytes(y).FillBytes(point[1+size:])
+
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("curve %q is not supported", jwk.Crv)
+		}
+
+		x, xErr := base64.RawURLEncoding.DecodeString(jwk.X)
+		if xErr != nil {
+			return nil, xErr
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("key type %q is not supported", jwk.Kty)
+	}
+}
@@ -0,0 +1,169 @@
+package identityresolver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jwksServer serves the keys of the JWKS, it responds with an error while failing is set.
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     map[string]*rsa.PublicKey
+	failing  bool
+	requests int
+}
+
+func newJWKSServer(t *testing.T) (*jwksServer, string) {
+	t.Helper()
+
+	server := &jwksServer{keys: make(map[string]*rsa.PublicKey)}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, httpServer.URL
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if s.failing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	for kid, key := range s.keys {
+		set.Keys = append(set.Keys, jsonWebKey{Kid: kid, Kty: "RSA", Use: "sig",
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())})
+	}
+
+	_ = json.NewEncoder(w).Encode(set)
+}
+
+func (s *jwksServer) setKey(kid string, key *rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[kid] = key
+}
+
+func (s *jwksServer) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failing = failing
+}
+
+func (s *jwksServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, gErr := rsa.GenerateKey(rand.Reader, 2048)
+	if gErr != nil {
+		t.Fatalf("generate key: %v", gErr)
+	}
+
+	return key
+}
+
+// allowRefresh moves the last refresh before jwksMinRefreshInterval, so the next unknown key refreshes the JWKS.
+func allowRefresh(set *jwks) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	set.attemptedAt = set.attemptedAt.Add(-jwksMinRefreshInterval)
+}
+
+func TestJWKSRefreshOfUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+	server, url := newJWKSServer(t)
+	server.setKey("key-1", &generateRSAKey(t).PublicKey)
+	set := newJWKS(url, time.Hour)
+
+	if _, kErr := set.key(ctx, "key-1"); kErr != nil {
+		t.Fatalf("unexpected error: %v", kErr)
+	}
+
+	// the unknown keys don't refresh the JWKS again until jwksMinRefreshInterval is passed.
+	server.setKey("key-2", &generateRSAKey(t).PublicKey)
+	for range 3 {
+		if _, kErr := set.key(ctx, "key-2"); !errors.Is(kErr, errKeyNotFound) {
+			t.Fatalf("expected errKeyNotFound, got %v", kErr)
+		}
+	}
+
+	if requests := server.requestCount(); requests != 1 {
+		t.Fatalf("expected 1 jwks request, got %d", requests)
+	}
+
+	allowRefresh(set)
+	if _, kErr := set.key(ctx, "key-2"); kErr != nil {
+		t.Fatalf("expected the new key after the refresh, got %v", kErr)
+	}
+
+	if requests := server.requestCount(); requests != 2 {
+		t.Fatalf("expected 2 jwks requests, got %d", requests)
+	}
+}
+
+func TestJWKSFailedRefresh(t *testing.T) {
+	ctx := context.Background()
+	server, url := newJWKSServer(t)
+	server.setKey("key-1", &generateRSAKey(t).PublicKey)
+	server.setFailing(true)
+	set := newJWKS(url, time.Hour)
+
+	// the failed refresh is recorded, so the tokens don't send a request to the unavailable JWKS.
+	for range 3 {
+		if _, kErr := set.key(ctx, "key-1"); kErr == nil || errors.Is(kErr, errKeyNotFound) {
+			t.Fatalf("expected the refresh error, got %v", kErr)
+		}
+	}
+
+	if requests := server.requestCount(); requests != 1 {
+		t.Fatalf("expected 1 jwks request, got %d", requests)
+	}
+
+	server.setFailing(false)
+	allowRefresh(set)
+	if _, kErr := set.key(ctx, "key-1"); kErr != nil {
+		t.Fatalf("unexpected error: %v", kErr)
+	}
+
+	// the fetched keys are used when a refresh of the expired JWKS fails.
+	server.setFailing(true)
+	set.mu.Lock()
+	set.fetchedAt = set.fetchedAt.Add(-time.Hour)
+	set.mu.Unlock()
+	allowRefresh(set)
+
+	if _, kErr := set.key(ctx, "key-1"); kErr != nil {
+		t.Fatalf("expected the fetched key, got %v", kErr)
+	}
+
+	if requests := server.requestCount(); requests != 3 {
+		t.Fatalf("expected 3 jwks requests, got %d", requests)
+	}
+}
@@ -0,0 +1,114 @@
+package identityresolver
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/syntaxfa/quick-connect/pkg/errlog"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+)
+
+const defaultUserIDClaim = "sub"
+
+var (
+	secretMethods = []string{"HS256", "HS384", "HS512"}
+	jwksMethods   = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
+// JWTResolver validates the tokens locally, the keys of the JWKS are fetched on the first use and refreshed
+// every JWKSRefreshInterval or when a token is signed with an unknown key.
+type JWTResolver struct {
+	cfg    JWTConfig
+	jwks   *jwks
+	logger *slog.Logger
+}
+
+func NewJWTResolver(cfg JWTConfig, logger *slog.Logger) (*JWTResolver, error) {
+	const op = "identityresolver.jwt.NewJWTResolver"
+
+	if (cfg.Secret == "") == (cfg.JWKSURL == "") {
+		return nil, richerror.New(op).WithMessage("one of jwt secret and jwks url must be set").
+			WithKind(richerror.KindInvalid)
+	}
+
+	if cfg.UserIDClaim == "" {
+		cfg.UserIDClaim = defaultUserIDClaim
+	}
+
+	resolver := &JWTResolver{cfg: cfg, logger: logger}
+	if cfg.JWKSURL != "" {
+		resolver.jwks = newJWKS(cfg.JWKSURL, cfg.JWKSRefreshInterval)
+	}
+
+	return resolver, nil
+}
+
+func (r *JWTResolver) Resolve(ctx context.Context, token string) (string, error) {
+	const op = "identityresolver.jwt.Resolve"
+
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if r.cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(r.cfg.Issuer))
+	}
+
+	if r.cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(r.cfg.Audience))
+	}
+
+	if r.jwks != nil {
+		options = append(options, jwt.WithValidMethods(jwksMethods))
+	} else {
+		options = append(options, jwt.WithValidMethods(secretMethods))
+	}
+
+	var keyErr error
+	claims := jwt.MapClaims{}
+	parsed, pErr := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if r.jwks == nil {
+			return []byte(r.cfg.Secret), nil
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		var key crypto.PublicKey
+		key, keyErr = r.jwks.key(ctx, kid)
+
+		return key, keyErr
+	}, options...)
+	if pErr != nil || !parsed.Valid {
+		// The unavailable JWKS is not the fault of the token.
+		if keyErr != nil && !errors.Is(keyErr, errKeyNotFound) {
+			return "", errlog.ErrContext(ctx, richerror.New(op).WithWrapError(keyErr).WithKind(richerror.KindUnexpected), r.logger)
+		}
+
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithWrapError(pErr).
+			WithKind(richerror.KindUnAuthorized)
+	}
+
+	userID := claimToString(claims[r.cfg.UserIDClaim])
+	if userID == "" {
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithKind(richerror.KindUnAuthorized).
+			WithMeta(map[string]interface{}{"claim": r.cfg.UserIDClaim})
+	}
+
+	return userID, nil
+}
+
+// claimToString JSON numbers are decoded as float64, user ids are usually integers.
+func claimToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,208 @@
+package identityresolver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+const testSecret = "test-secret"
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, sErr := token.SignedString(key)
+	if sErr != nil {
+		t.Fatalf("sign token: %v", sErr)
+	}
+
+	return signed
+}
+
+func assertKind(t *testing.T, err error, kind richerror.Kind) {
+	t.Helper()
+
+	var richErr richerror.RichError
+	if !errors.As(err, &richErr) || richErr.Kind() != kind {
+		t.Fatalf("expected error of kind %v, got %v", kind, err)
+	}
+}
+
+func TestNewJWTResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     JWTConfig
+		wantErr bool
+	}{
+		{name: "secret", cfg: JWTConfig{Secret: testSecret}},
+		{name: "jwks", cfg: JWTConfig{JWKSURL: "http://localhost/jwks"}},
+		{name: "none", cfg: JWTConfig{}, wantErr: true},
+		{name: "both", cfg: JWTConfig{Secret: testSecret, JWKSURL: "http://localhost/jwks"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, nErr := NewJWTResolver(test.cfg, discardLogger())
+			if (nErr != nil) != test.wantErr {
+				t.Fatalf("expected error %t, got %v", test.wantErr, nErr)
+			}
+		})
+	}
+}
+
+func TestJWTResolverWithSecret(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		cfg        JWTConfig
+		method     jwt.SigningMethod
+		key        interface{}
+		claims     jwt.MapClaims
+		wantUserID string
+	}{
+		{name: "valid", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodHS256, key: []byte(testSecret),
+			claims: jwt.MapClaims{"sub": "user-1", "exp": expiresAt}, wantUserID: "user-1"},
+		{name: "numeric user id claim", cfg: JWTConfig{Secret: testSecret, UserIDClaim: "user_id"},
+			method: jwt.SigningMethodHS512, key: []byte(testSecret),
+			claims: jwt.MapClaims{"user_id": 12345678901, "exp": expiresAt}, wantUserID: "12345678901"},
+		{name: "issuer and audience", cfg: JWTConfig{Secret: testSecret, Issuer: "backend", Audience: "quick-connect"},
+			method: jwt.SigningMethodHS256, key: []byte(testSecret),
+			claims:     jwt.MapClaims{"sub": "user-1", "iss": "backend", "aud": "quick-connect", "exp": expiresAt},
+			wantUserID: "user-1"},
+		{name: "wrong secret", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodHS256, key: []byte("other"),
+			claims: jwt.MapClaims{"sub": "user-1", "exp": expiresAt}},
+		{name: "expired", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodHS256, key: []byte(testSecret),
+			claims: jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{name: "without expiration", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodHS256,
+			key: []byte(testSecret), claims: jwt.MapClaims{"sub": "user-1"}},
+		{name: "wrong issuer", cfg: JWTConfig{Secret: testSecret, Issuer: "backend"}, method: jwt.SigningMethodHS256,
+			key: []byte(testSecret), claims: jwt.MapClaims{"sub": "user-1", "iss": "other", "exp": expiresAt}},
+		{name: "wrong audience", cfg: JWTConfig{Secret: testSecret, Audience: "quick-connect"},
+			method: jwt.SigningMethodHS256, key: []byte(testSecret),
+			claims: jwt.MapClaims{"sub": "user-1", "aud": "other", "exp": expiresAt}},
+		{name: "missing user id claim", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodHS256,
+			key: []byte(testSecret), claims: jwt.MapClaims{"exp": expiresAt}},
+		{name: "none algorithm", cfg: JWTConfig{Secret: testSecret}, method: jwt.SigningMethodNone,
+			key: jwt.UnsafeAllowNoneSignatureType, claims: jwt.MapClaims{"sub": "user-1", "exp": expiresAt}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolver, nErr := NewJWTResolver(test.cfg, discardLogger())
+			if nErr != nil {
+				t.Fatalf("unexpected error: %v", nErr)
+			}
+
+			userID, rErr := resolver.Resolve(context.Background(), signToken(t, test.method, test.key, "", test.claims))
+			if test.wantUserID == "" {
+				assertKind(t, rErr, richerror.KindUnAuthorized)
+
+				return
+			}
+
+			if rErr != nil {
+				t.Fatalf("unexpected error: %v", rErr)
+			}
+
+			if userID != test.wantUserID {
+				t.Fatalf("expected user id %q, got %q", test.wantUserID, userID)
+			}
+		})
+	}
+}
+
+func TestJWTResolverWithJWKS(t *testing.T) {
+	server, url := newJWKSServer(t)
+	key := generateRSAKey(t)
+	server.setKey("key-1", &key.PublicKey)
+
+	claims := jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name       string
+		token      func(t *testing.T) string
+		wantUserID string
+	}{
+		{name: "known key", wantUserID: "user-1",
+			token: func(t *testing.T) string { return signToken(t, jwt.SigningMethodRS256, key, "key-1", claims) }},
+		{name: "single key without kid", wantUserID: "user-1",
+			token: func(t *testing.T) string { return signToken(t, jwt.SigningMethodRS256, key, "", claims) }},
+		{name: "unknown key",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, generateRSAKey(t), "key-2", claims)
+			}},
+		{name: "other key with known kid",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, generateRSAKey(t), "key-1", claims)
+			}},
+		{name: "secret method",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "key-1", claims)
+			}},
+	}
+
+	resolver, nErr := NewJWTResolver(JWTConfig{JWKSURL: url}, discardLogger())
+	if nErr != nil {
+		t.Fatalf("unexpected error: %v", nErr)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userID, rErr := resolver.Resolve(context.Background(), test.token(t))
+			if test.wantUserID == "" {
+				assertKind(t, rErr, richerror.KindUnAuthorized)
+
+				return
+			}
+
+			if rErr != nil {
+				t.Fatalf("unexpected error: %v", rErr)
+			}
+
+			if userID != test.wantUserID {
+				t.Fatalf("expected user id %q, got %q", test.wantUserID, userID)
+			}
+		})
+	}
+}
+
+func TestJWTResolverWithUnavailableJWKS(t *testing.T) {
+	server, url := newJWKSServer(t)
+	key := generateRSAKey(t)
+	server.setKey("key-1", &key.PublicKey)
+	server.setFailing(true)
+
+	resolver, nErr := NewJWTResolver(JWTConfig{JWKSURL: url}, discardLogger())
+	if nErr != nil {
+		t.Fatalf("unexpected error: %v", nErr)
+	}
+
+	token := signToken(t, jwt.SigningMethodRS256, key, "key-1", jwt.MapClaims{"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix()})
+
+	// the unavailable JWKS is not the fault of the token, so it is not an unauthorized error.
+	for range 2 {
+		_, rErr := resolver.Resolve(context.Background(), token)
+		assertKind(t, rErr, richerror.KindUnexpected)
+	}
+
+	if requests := server.requestCount(); requests != 1 {
+		t.Fatalf("expected 1 jwks request, got %d", requests)
+	}
+}
@@ -0,0 +1,35 @@
+package identityresolver
+
+import (
+	"context"
+
+	"github.com/syntaxfa/quick-connect/pkg/jwtvalidator"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+	"github.com/syntaxfa/quick-connect/pkg/servermsg"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// ManagerResolver validates the client tokens which are issued by the manager app when a client is identified.
+type ManagerResolver struct {
+	jwtValidator *jwtvalidator.Validator
+}
+
+func NewManagerResolver(jwtValidator *jwtvalidator.Validator) *ManagerResolver {
+	return &ManagerResolver{jwtValidator: jwtValidator}
+}
+
+func (r *ManagerResolver) Resolve(_ context.Context, token string) (string, error) {
+	const op = "identityresolver.manager.Resolve"
+
+	claims, vErr := r.jwtValidator.ValidateToken(token)
+	if vErr != nil {
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithWrapError(vErr).
+			WithKind(richerror.KindUnAuthorized)
+	}
+
+	if claims.TokenType != types.TokenTypeClient || claims.UserID == "" {
+		return "", richerror.New(op).WithMessage(servermsg.MsgInvalidToken).WithKind(richerror.KindUnAuthorized)
+	}
+
+	return string(claims.UserID), nil
+}
@@ -0,0 +1,35 @@
+package identityresolver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/syntaxfa/quick-connect/pkg/cachemanager"
+	"github.com/syntaxfa/quick-connect/pkg/jwtvalidator"
+	"github.com/syntaxfa/quick-connect/pkg/richerror"
+)
+
+// Resolver resolves the external user id of an identify token, an invalid token returns
+// a richerror with richerror.KindUnAuthorized.
+type Resolver interface {
+	Resolve(ctx context.Context, token string) (string, error)
+}
+
+// New creates the resolver of the configured driver, jwtValidator is only used by the manager driver.
+func New(cfg Config, cache *cachemanager.CacheManager, jwtValidator *jwtvalidator.Validator,
+	logger *slog.Logger) (Resolver, error) {
+	const op = "identityresolver.New"
+
+	switch cfg.Driver {
+	case DriverHTTP, "":
+		return NewHTTPResolver(cfg.HTTP, cache, logger)
+	case DriverJWT:
+		return NewJWTResolver(cfg.JWT, logger)
+	case DriverManager:
+		return NewManagerResolver(jwtValidator), nil
+	default:
+		return nil, richerror.New(op).WithMessage(fmt.Sprintf("identity resolver driver %q is not supported", cfg.Driver)).
+			WithKind(richerror.KindInvalid)
+	}
+}
@@ -28,6 +28,50 @@ const (
 	MsgConflictTemplate                    = "template is already exists"
 	MsgTemplateNotFound                    = "this template does not exist"
 	MsgInvalidIgnoreChannel                = "invalid ignore channel"
+	MsgNotificationNotFound                = "this notification does not exist"
+	MsgNotificationIsNotScheduled          = "this notification is not scheduled"
+	MsgSendAtMustBeInFuture                = "send at must be in the future"
+	MsgInvalidNotificationStatus           = "invalid notification status"
+	MsgCampaignNotFound                    = "this campaign does not exist"
+	MsgCampaignIsNotCancelable             = "only queued or running campaigns can be canceled"
+	MsgCampaignHasNoRecipient              = "campaign has no valid recipient"
+	MsgCampaignRecipientsConflict          = "either external user ids or csv file id must be set"
+	MsgInvalidCampaignStatus               = "invalid campaign status"
+	MsgInvalidCampaignCSVFile              = "invalid campaign csv file"
+	MsgInvalidDigestPreference             = "invalid digest preference"
+	MsgInvalidDigestChannel                = "digest is only supported for email and sms channels"
+	MsgInvalidDigestFrequency              = "invalid digest frequency"
+	MsgConflictDigestPreference            = "digest preference channel and notification type has conflict"
+	MsgInvalidTimezone                     = "invalid timezone"
+	MsgInvalidQuietHours                   = "quiet hours start and end must be different times in 15:04 format"
+	MsgTemplateVersionNotFound             = "this template version does not exist"
+	MsgTemplateChannelNotFound             = "template does not have content for this channel"
+	MsgTemplateIsAlreadyInVersion          = "template is already in this version"
+	MsgInvalidTemplatePreview              = "template can't be rendered with this data"
+	MsgInvalidTemplateKind                 = "invalid template kind"
+	MsgTemplateKindCanNotChange            = "template kind can't be changed"
+	MsgInvalidTemplateSyntax               = "template content has invalid syntax"
+	MsgTemplateLayoutNotFound              = "template layout does not exist"
+	MsgTemplateIsNotSendable               = "layout and partial templates can't be sent"
+	MsgInvalidWebhookToken                 = "webhook token is not valid"
+	MsgInvalidTrackingSignature            = "tracking link is not valid"
+	MsgInvalidSendBulkSize                 = "number of bulk notifications is not valid"
+	MsgInvalidLengthOfCategory             = "the category must be less than 64 characters"
+	MsgInvalidDateRange                    = "from must be before to"
+	MsgInvalidLengthOfTopicName            = "the topic name must be between 1 and 64 characters"
+	MsgInvalidLengthOfTopicTitle           = "the topic title must be between 1 and 255 characters"
+	MsgInvalidLengthOfTopicDescription     = "the topic description must be less than 1024 characters"
+	MsgConflictTopic                       = "topic is already exists"
+	MsgTopicNotFound                       = "this topic does not exist"
+	MsgInvalidTopicPreference              = "invalid topic preference"
+	MsgConflictTopicPreference             = "topic preference topic and channel has conflict"
+	MsgInvalidUnsubscribeLink              = "unsubscribe link is not valid"
+	MsgUnsubscribed                        = "you are unsubscribed"
+	MsgUnsubscribeConfirmation             = "do you want to unsubscribe from these notifications?"
+	MsgUnsubscribe                         = "unsubscribe"
+	MsgInvalidLengthOfIdempotencyKey       = "the idempotency key must be less than 255 characters"
+	MsgIdempotencyKeyInProgress            = "a request with this idempotency key is in progress"
+	MsgInvalidDedupWindow                  = "the dedup window must be between 0 and 86400 seconds"
 
 	// Manager app.
 
@@ -42,6 +86,9 @@ const (
 	MsgUserUpdatedSuccessfully        = "user updated successfully"
 	MsgUserCreatedSuccessfully        = "user created successfully"
 	MsgProfileUpdatedSuccessfully     = "profile updated successfully"
+	MsgTemplateRolledBackSuccessfully = "template rolled back successfully"
+	MsgTestNotificationSent           = "test notification sent"
+	MsgServiceAccessDenied            = "you do not have access to this service"
 
 	// Chat App.
 
@@ -53,9 +100,24 @@ const (
 
 	// File App.
 
-	MsgFileInNotPublic      = "this file does not public"
-	MsgFileNotFound         = "this file does not exists"
-	MsgFileAlreadyConfirmed = "this file already confirmed!"
+	MsgFileInNotPublic          = "this file does not public"
+	MsgFileNotFound             = "this file does not exists"
+	MsgFileAlreadyConfirmed     = "this file already confirmed!"
+	MsgInvalidUploadPurpose     = "invalid upload purpose"
+	MsgFileTypeNotAllowed       = "this file type is not allowed"
+	MsgFileExtensionMismatch    = "the file extension does not match the file content"
+	MsgInvalidImage             = "this image is not valid"
+	MsgFileSizeLimitExceeded    = "file size limit exceeded"
+	MsgImageSizeLimitExceeded   = "image size limit exceeded"
+	MsgUploadNotFound           = "this upload does not exists"
+	MsgUploadOffsetMismatch     = "upload offset does not match the uploaded size"
+	MsgUploadIsLocked           = "this upload is being written by another request"
+	MsgDirectUploadNotSupported = "direct upload is not supported by the storage"
+	MsgFilenameRequired         = "filename is required"
+	MsgInvalidFileChecksum      = "sha256 checksum of the file is not valid"
+	MsgFileNotUploaded          = "this file is not uploaded yet"
+	MsgUploadedFileMismatch     = "the uploaded file does not match the upload request"
+	MsgStorageQuotaExceeded     = "storage quota exceeded"
 
 	// Story App.
 
@@ -64,5 +126,6 @@ const (
 	MsgInvalidLengthOfStoryLinkURL  = "the link url must be between 10 and 255 characters"
 	MsgInvalidLengthOfStoryLinkText = "the link text must be between 3 and 100 characters"
 	MsgStoryMediaRequirePublic      = "media Story must be public"
+	MsgStoryMediaInvalidPurpose     = "media Story must be uploaded with the story purpose"
 	MsgMediaAlreadyUse              = "this media has already been used."
 )
@@ -0,0 +1,114 @@
+package urlsigner
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+// Query parameters of the signed urls.
+const (
+	queryExpires   = "expires"
+	queryKeyID     = "kid"
+	queryUserID    = "uid"
+	querySignature = "sig"
+)
+
+var (
+	ErrInvalidSignature = errors.New("signature of the url is not valid")
+	ErrURLExpired       = errors.New("url is expired")
+	ErrUserNotAllowed   = errors.New("url is not signed for this user")
+)
+
+// Config SigningKeys are the keys of the signed urls by their id, new urls are signed by the key of SigningKeyID and
+// the other keys are only used to verify the urls that are signed before a key rotation.
+type Config struct {
+	BaseURL      string
+	SigningKeyID string
+	SigningKeys  map[string]string
+}
+
+// Signer signs the urls of the objects that are served by the storage app, a signed url is the url of the key
+// in BaseURL with the signature in its query.
+type Signer struct {
+	cfg Config
+}
+
+func New(cfg Config) (*Signer, error) {
+	if _, ok := cfg.SigningKeys[cfg.SigningKeyID]; !ok {
+		return nil, fmt.Errorf("signing key %s is not defined", cfg.SigningKeyID)
+	}
+
+	return &Signer{cfg: cfg}, nil
+}
+
+// URL returns the url of the key without a signature.
+func (s *Signer) URL(key string) string {
+	return fmt.Sprintf("%s/%s", s.cfg.BaseURL, key)
+}
+
+// SignedURL returns the url of the key which is valid until expiresAt, when userID is not empty
+// the url is only valid for the requests of the user.
+func (s *Signer) SignedURL(key string, expiresAt time.Time, userID types.ID) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set(queryExpires, expires)
+	query.Set(queryKeyID, s.cfg.SigningKeyID)
+	if userID != "" {
+		query.Set(queryUserID, string(userID))
+	}
+	query.Set(querySignature, sign(s.cfg.SigningKeys[s.cfg.SigningKeyID], key, expires, userID))
+
+	return fmt.Sprintf("%s?%s", s.URL(key), query.Encode())
+}
+
+// Verify verifies the query of a signed url of the key, userID is the user of the request
+// and it is empty for the anonymous requests.
+func (s *Signer) Verify(key string, query url.Values, userID types.ID) error {
+	secret, ok := s.cfg.SigningKeys[query.Get(queryKeyID)]
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	expires := query.Get(queryExpires)
+	expiresAt, pErr := strconv.ParseInt(expires, 10, 64)
+	if pErr != nil {
+		return ErrInvalidSignature
+	}
+
+	signedUserID := types.ID(query.Get(queryUserID))
+	signature, dErr := base64.RawURLEncoding.DecodeString(query.Get(querySignature))
+	if dErr != nil {
+		return ErrInvalidSignature
+	}
+
+	expected, _ := base64.RawURLEncoding.DecodeString(sign(secret, key, expires, signedUserID))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return ErrURLExpired
+	}
+
+	if signedUserID != "" && signedUserID != userID {
+		return ErrUserNotAllowed
+	}
+
+	return nil
+}
+
+func sign(secret, key, expires string, userID types.ID) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(key + "\n" + expires + "\n" + string(userID)))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,144 @@
+package urlsigner_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syntaxfa/quick-connect/pkg/urlsigner"
+	"github.com/syntaxfa/quick-connect/types"
+)
+
+const (
+	baseURL = "https://storage.example.com/files"
+	key     = "2026/10/report.pdf"
+)
+
+func newSigner(t *testing.T, signingKeyID string, keys map[string]string) *urlsigner.Signer {
+	t.Helper()
+
+	signer, nErr := urlsigner.New(urlsigner.Config{BaseURL: baseURL, SigningKeyID: signingKeyID, SigningKeys: keys})
+	if nErr != nil {
+		t.Fatalf("unexpected error: %v", nErr)
+	}
+
+	return signer
+}
+
+func signedQuery(t *testing.T, signedURL string) url.Values {
+	t.Helper()
+
+	parsed, pErr := url.Parse(signedURL)
+	if pErr != nil {
+		t.Fatalf("unexpected error: %v", pErr)
+	}
+
+	if !strings.HasPrefix(signedURL, baseURL+"/"+key+"?") {
+		t.Fatalf("unexpected signed url %s", signedURL)
+	}
+
+	return parsed.Query()
+}
+
+func TestNew(t *testing.T) {
+	if _, nErr := urlsigner.New(urlsigner.Config{SigningKeyID: "v2", SigningKeys: map[string]string{"v1": "secret"}}); nErr == nil {
+		t.Fatal("expected an error for an undefined signing key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	signer := newSigner(t, "v1", map[string]string{"v1": "secret-one"})
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		key       string
+		signedFor types.ID
+		userID    types.ID
+		expiresAt time.Time
+		tamper    func(query url.Values)
+		expected  error
+	}{
+		{name: "anonymous url", key: key, expiresAt: expiresAt},
+		{name: "anonymous url of a user", key: key, userID: "user-1", expiresAt: expiresAt},
+		{name: "url of the user", key: key, signedFor: "user-1", userID: "user-1", expiresAt: expiresAt},
+		{name: "url of another user", key: key, signedFor: "user-1", userID: "user-2", expiresAt: expiresAt,
+			expected: urlsigner.ErrUserNotAllowed},
+		{name: "url of a user without a user", key: key, signedFor: "user-1", expiresAt: expiresAt,
+			expected: urlsigner.ErrUserNotAllowed},
+		{name: "expired url", key: key, expiresAt: time.Now().Add(-time.Minute), expected: urlsigner.ErrURLExpired},
+		{name: "another key", key: "2026/10/other.pdf", expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature},
+		{name: "extended expiry", key: key, expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature,
+			tamper: func(query url.Values) { query.Set("expires", "4102444800") }},
+		{name: "removed user", key: key, signedFor: "user-1", expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature,
+			tamper: func(query url.Values) { query.Del("uid") }},
+		{name: "unknown signing key", key: key, expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature,
+			tamper: func(query url.Values) { query.Set("kid", "v9") }},
+		{name: "malformed signature", key: key, expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature,
+			tamper: func(query url.Values) { query.Set("sig", "%%%") }},
+		{name: "malformed expiry", key: key, expiresAt: expiresAt, expected: urlsigner.ErrInvalidSignature,
+			tamper: func(query url.Values) { query.Set("expires", "tomorrow") }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := signedQuery(t, signer.SignedURL(key, test.expiresAt, test.signedFor))
+			if test.tamper != nil {
+				test.tamper(query)
+			}
+
+			if vErr := signer.Verify(test.key, query, test.userID); !errors.Is(vErr, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, vErr)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyRotation(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	oldSigner := newSigner(t, "v1", map[string]string{"v1": "secret-one"})
+	rotatedSigner := newSigner(t, "v2", map[string]string{"v1": "secret-one", "v2": "secret-two"})
+	retiredSigner := newSigner(t, "v2", map[string]string{"v2": "secret-two"})
+	replacedSigner := newSigner(t, "v2", map[string]string{"v1": "another-secret", "v2": "secret-two"})
+
+	oldQuery := signedQuery(t, oldSigner.SignedURL(key, expiresAt, ""))
+	newQuery := signedQuery(t, rotatedSigner.SignedURL(key, expiresAt, ""))
+
+	tests := []struct {
+		name     string
+		signer   *urlsigner.Signer
+		query    url.Values
+		expected error
+	}{
+		{name: "old url after the rotation", signer: rotatedSigner, query: oldQuery},
+		{name: "new url after the rotation", signer: rotatedSigner, query: newQuery},
+		{name: "new url is signed by the new key", signer: retiredSigner, query: newQuery},
+		{name: "old url after the old key is retired", signer: retiredSigner, query: oldQuery,
+			expected: urlsigner.ErrInvalidSignature},
+		{name: "old url after the old key is replaced", signer: replacedSigner, query: oldQuery,
+			expected: urlsigner.ErrInvalidSignature},
+		{name: "new url before the rotation", signer: oldSigner, query: newQuery, expected: urlsigner.ErrInvalidSignature},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if vErr := test.signer.Verify(key, test.query, ""); !errors.Is(vErr, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, vErr)
+			}
+		})
+	}
+
+	if newQuery.Get("kid") != "v2" || oldQuery.Get("kid") != "v1" {
+		t.Fatalf("expected the urls to be signed by the current key, got %s and %s", oldQuery.Get("kid"), newQuery.Get("kid"))
+	}
+}
+
+func TestURL(t *testing.T) {
+	signer := newSigner(t, "v1", map[string]string{"v1": "secret-one"})
+
+	if u := signer.URL(key); u != baseURL+"/"+key {
+		t.Fatalf("unexpected url %s", u)
+	}
+}
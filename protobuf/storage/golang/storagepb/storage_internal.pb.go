@@ -24,8 +24,12 @@ const (
 )
 
 type GetLinkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	FileId string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	// user_id binds the link of a private file to the user, it is optional.
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// variant is the name of an image variant, such as thumbnail, the original file is returned when it does not exist.
+	Variant       string `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -67,6 +71,20 @@ func (x *GetLinkRequest) GetFileId() string {
 	return ""
 }
 
+func (x *GetLinkRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetLinkRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
 type GetLinkResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
@@ -170,6 +188,9 @@ type File struct {
 	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamp.Timestamp   `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	DeletedAt     *timestamp.Timestamp   `protobuf:"bytes,13,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Sha256        string                 `protobuf:"bytes,14,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Purpose       string                 `protobuf:"bytes,15,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	Status        string                 `protobuf:"bytes,16,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -295,6 +316,27 @@ func (x *File) GetDeletedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *File) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *File) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *File) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 type ConfirmFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
@@ -339,17 +381,229 @@ func (x *ConfirmFileRequest) GetFileId() string {
 	return ""
 }
 
+// StorageUser is a user whose storage usage is requested, roles are used to find the quota of the user.
+type StorageUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageUser) Reset() {
+	*x = StorageUser{}
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageUser) ProtoMessage() {}
+
+func (x *StorageUser) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageUser.ProtoReflect.Descriptor instead.
+func (*StorageUser) Descriptor() ([]byte, []int) {
+	return file_storage_proto_storage_internal_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StorageUser) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StorageUser) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type GetStorageUsagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*StorageUser         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorageUsagesRequest) Reset() {
+	*x = GetStorageUsagesRequest{}
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorageUsagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorageUsagesRequest) ProtoMessage() {}
+
+func (x *GetStorageUsagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorageUsagesRequest.ProtoReflect.Descriptor instead.
+func (*GetStorageUsagesRequest) Descriptor() ([]byte, []int) {
+	return file_storage_proto_storage_internal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStorageUsagesRequest) GetUsers() []*StorageUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// StorageUsage limit is the storage quota of the user in bytes, it is zero when the user is not limited.
+type StorageUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UsedBytes     int64                  `protobuf:"varint,2,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	FileCount     int64                  `protobuf:"varint,3,opt,name=file_count,json=fileCount,proto3" json:"file_count,omitempty"`
+	Limit         int64                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageUsage) Reset() {
+	*x = StorageUsage{}
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageUsage) ProtoMessage() {}
+
+func (x *StorageUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageUsage.ProtoReflect.Descriptor instead.
+func (*StorageUsage) Descriptor() ([]byte, []int) {
+	return file_storage_proto_storage_internal_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StorageUsage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StorageUsage) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetFileCount() int64 {
+	if x != nil {
+		return x.FileCount
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetStorageUsagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usages        []*StorageUsage        `protobuf:"bytes,1,rep,name=usages,proto3" json:"usages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorageUsagesResponse) Reset() {
+	*x = GetStorageUsagesResponse{}
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorageUsagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorageUsagesResponse) ProtoMessage() {}
+
+func (x *GetStorageUsagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_storage_internal_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorageUsagesResponse.ProtoReflect.Descriptor instead.
+func (*GetStorageUsagesResponse) Descriptor() ([]byte, []int) {
+	return file_storage_proto_storage_internal_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetStorageUsagesResponse) GetUsages() []*StorageUsage {
+	if x != nil {
+		return x.Usages
+	}
+	return nil
+}
+
 var File_storage_proto_storage_internal_proto protoreflect.FileDescriptor
 
 const file_storage_proto_storage_internal_proto_rawDesc = "" +
 	"\n" +
-	"$storage/proto/storage_internal.proto\x12\astorage\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\")\n" +
+	"$storage/proto/storage_internal.proto\x12\astorage\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\\\n" +
 	"\x0eGetLinkRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"#\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariant\"#\n" +
 	"\x0fGetLinkResponse\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\"-\n" +
 	"\x12GetFileInfoRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\xaf\x03\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\xf9\x03\n" +
 	"\x04File\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vuploader_id\x18\x02 \x01(\tR\n" +
@@ -368,13 +622,31 @@ const file_storage_proto_storage_internal_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
 	"\n" +
-	"deleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"-\n" +
+	"deleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x16\n" +
+	"\x06sha256\x18\x0e \x01(\tR\x06sha256\x12\x18\n" +
+	"\apurpose\x18\x0f \x01(\tR\apurpose\x12\x16\n" +
+	"\x06status\x18\x10 \x01(\tR\x06status\"-\n" +
 	"\x12ConfirmFileRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId2\xd5\x01\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"<\n" +
+	"\vStorageUser\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"E\n" +
+	"\x17GetStorageUsagesRequest\x12*\n" +
+	"\x05users\x18\x01 \x03(\v2\x14.storage.StorageUserR\x05users\"{\n" +
+	"\fStorageUsage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x02 \x01(\x03R\tusedBytes\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\x03 \x01(\x03R\tfileCount\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\"I\n" +
+	"\x18GetStorageUsagesResponse\x12-\n" +
+	"\x06usages\x18\x01 \x03(\v2\x15.storage.StorageUsageR\x06usages2\xae\x02\n" +
 	"\x16StorageInternalService\x12<\n" +
 	"\aGetLink\x12\x17.storage.GetLinkRequest\x1a\x18.storage.GetLinkResponse\x129\n" +
 	"\vGetFileInfo\x12\x1b.storage.GetFileInfoRequest\x1a\r.storage.File\x12B\n" +
-	"\vConfirmFile\x12\x1b.storage.ConfirmFileRequest\x1a\x16.google.protobuf.EmptyB#Z!protobuf/storage/golang/storagepbb\x06proto3"
+	"\vConfirmFile\x12\x1b.storage.ConfirmFileRequest\x1a\x16.google.protobuf.Empty\x12W\n" +
+	"\x10GetStorageUsages\x12 .storage.GetStorageUsagesRequest\x1a!.storage.GetStorageUsagesResponseB#Z!protobuf/storage/golang/storagepbb\x06proto3"
 
 var (
 	file_storage_proto_storage_internal_proto_rawDescOnce sync.Once
@@ -388,31 +660,39 @@ func file_storage_proto_storage_internal_proto_rawDescGZIP() []byte {
 	return file_storage_proto_storage_internal_proto_rawDescData
 }
 
-var file_storage_proto_storage_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_storage_proto_storage_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_storage_proto_storage_internal_proto_goTypes = []any{
-	(*GetLinkRequest)(nil),      // 0: storage.GetLinkRequest
-	(*GetLinkResponse)(nil),     // 1: storage.GetLinkResponse
-	(*GetFileInfoRequest)(nil),  // 2: storage.GetFileInfoRequest
-	(*File)(nil),                // 3: storage.File
-	(*ConfirmFileRequest)(nil),  // 4: storage.ConfirmFileRequest
-	(*timestamp.Timestamp)(nil), // 5: google.protobuf.Timestamp
-	(*empty.Empty)(nil),         // 6: google.protobuf.Empty
+	(*GetLinkRequest)(nil),           // 0: storage.GetLinkRequest
+	(*GetLinkResponse)(nil),          // 1: storage.GetLinkResponse
+	(*GetFileInfoRequest)(nil),       // 2: storage.GetFileInfoRequest
+	(*File)(nil),                     // 3: storage.File
+	(*ConfirmFileRequest)(nil),       // 4: storage.ConfirmFileRequest
+	(*StorageUser)(nil),              // 5: storage.StorageUser
+	(*GetStorageUsagesRequest)(nil),  // 6: storage.GetStorageUsagesRequest
+	(*StorageUsage)(nil),             // 7: storage.StorageUsage
+	(*GetStorageUsagesResponse)(nil), // 8: storage.GetStorageUsagesResponse
+	(*timestamp.Timestamp)(nil),      // 9: google.protobuf.Timestamp
+	(*empty.Empty)(nil),              // 10: google.protobuf.Empty
 }
 var file_storage_proto_storage_internal_proto_depIdxs = []int32{
-	5, // 0: storage.File.created_at:type_name -> google.protobuf.Timestamp
-	5, // 1: storage.File.updated_at:type_name -> google.protobuf.Timestamp
-	5, // 2: storage.File.deleted_at:type_name -> google.protobuf.Timestamp
-	0, // 3: storage.StorageInternalService.GetLink:input_type -> storage.GetLinkRequest
-	2, // 4: storage.StorageInternalService.GetFileInfo:input_type -> storage.GetFileInfoRequest
-	4, // 5: storage.StorageInternalService.ConfirmFile:input_type -> storage.ConfirmFileRequest
-	1, // 6: storage.StorageInternalService.GetLink:output_type -> storage.GetLinkResponse
-	3, // 7: storage.StorageInternalService.GetFileInfo:output_type -> storage.File
-	6, // 8: storage.StorageInternalService.ConfirmFile:output_type -> google.protobuf.Empty
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	9,  // 0: storage.File.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: storage.File.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: storage.File.deleted_at:type_name -> google.protobuf.Timestamp
+	5,  // 3: storage.GetStorageUsagesRequest.users:type_name -> storage.StorageUser
+	7,  // 4: storage.GetStorageUsagesResponse.usages:type_name -> storage.StorageUsage
+	0,  // 5: storage.StorageInternalService.GetLink:input_type -> storage.GetLinkRequest
+	2,  // 6: storage.StorageInternalService.GetFileInfo:input_type -> storage.GetFileInfoRequest
+	4,  // 7: storage.StorageInternalService.ConfirmFile:input_type -> storage.ConfirmFileRequest
+	6,  // 8: storage.StorageInternalService.GetStorageUsages:input_type -> storage.GetStorageUsagesRequest
+	1,  // 9: storage.StorageInternalService.GetLink:output_type -> storage.GetLinkResponse
+	3,  // 10: storage.StorageInternalService.GetFileInfo:output_type -> storage.File
+	10, // 11: storage.StorageInternalService.ConfirmFile:output_type -> google.protobuf.Empty
+	8,  // 12: storage.StorageInternalService.GetStorageUsages:output_type -> storage.GetStorageUsagesResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_storage_proto_storage_internal_proto_init() }
@@ -426,7 +706,7 @@ func file_storage_proto_storage_internal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_storage_proto_storage_internal_proto_rawDesc), len(file_storage_proto_storage_internal_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,9 +20,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StorageInternalService_GetLink_FullMethodName     = "/storage.StorageInternalService/GetLink"
-	StorageInternalService_GetFileInfo_FullMethodName = "/storage.StorageInternalService/GetFileInfo"
-	StorageInternalService_ConfirmFile_FullMethodName = "/storage.StorageInternalService/ConfirmFile"
+	StorageInternalService_GetLink_FullMethodName          = "/storage.StorageInternalService/GetLink"
+	StorageInternalService_GetFileInfo_FullMethodName      = "/storage.StorageInternalService/GetFileInfo"
+	StorageInternalService_ConfirmFile_FullMethodName      = "/storage.StorageInternalService/ConfirmFile"
+	StorageInternalService_GetStorageUsages_FullMethodName = "/storage.StorageInternalService/GetStorageUsages"
 )
 
 // StorageInternalServiceClient is the client API for StorageInternalService service.
@@ -32,6 +33,7 @@ type StorageInternalServiceClient interface {
 	GetLink(ctx context.Context, in *GetLinkRequest, opts ...grpc.CallOption) (*GetLinkResponse, error)
 	GetFileInfo(ctx context.Context, in *GetFileInfoRequest, opts ...grpc.CallOption) (*File, error)
 	ConfirmFile(ctx context.Context, in *ConfirmFileRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetStorageUsages(ctx context.Context, in *GetStorageUsagesRequest, opts ...grpc.CallOption) (*GetStorageUsagesResponse, error)
 }
 
 type storageInternalServiceClient struct {
@@ -72,6 +74,16 @@ func (c *storageInternalServiceClient) ConfirmFile(ctx context.Context, in *Conf
 	return out, nil
 }
 
+func (c *storageInternalServiceClient) GetStorageUsages(ctx context.Context, in *GetStorageUsagesRequest, opts ...grpc.CallOption) (*GetStorageUsagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStorageUsagesResponse)
+	err := c.cc.Invoke(ctx, StorageInternalService_GetStorageUsages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StorageInternalServiceServer is the server API for StorageInternalService service.
 // All implementations must embed UnimplementedStorageInternalServiceServer
 // for forward compatibility.
@@ -79,6 +91,7 @@ type StorageInternalServiceServer interface {
 	GetLink(context.Context, *GetLinkRequest) (*GetLinkResponse, error)
 	GetFileInfo(context.Context, *GetFileInfoRequest) (*File, error)
 	ConfirmFile(context.Context, *ConfirmFileRequest) (*empty.Empty, error)
+	GetStorageUsages(context.Context, *GetStorageUsagesRequest) (*GetStorageUsagesResponse, error)
 	mustEmbedUnimplementedStorageInternalServiceServer()
 }
 
@@ -98,6 +111,9 @@ func (UnimplementedStorageInternalServiceServer) GetFileInfo(context.Context, *G
 func (UnimplementedStorageInternalServiceServer) ConfirmFile(context.Context, *ConfirmFileRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConfirmFile not implemented")
 }
+func (UnimplementedStorageInternalServiceServer) GetStorageUsages(context.Context, *GetStorageUsagesRequest) (*GetStorageUsagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageUsages not implemented")
+}
 func (UnimplementedStorageInternalServiceServer) mustEmbedUnimplementedStorageInternalServiceServer() {
 }
 func (UnimplementedStorageInternalServiceServer) testEmbeddedByValue() {}
@@ -174,6 +190,24 @@ func _StorageInternalService_ConfirmFile_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StorageInternalService_GetStorageUsages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageUsagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageInternalServiceServer).GetStorageUsages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageInternalService_GetStorageUsages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageInternalServiceServer).GetStorageUsages(ctx, req.(*GetStorageUsagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StorageInternalService_ServiceDesc is the grpc.ServiceDesc for StorageInternalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -193,6 +227,10 @@ var StorageInternalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ConfirmFile",
 			Handler:    _StorageInternalService_ConfirmFile_Handler,
 		},
+		{
+			MethodName: "GetStorageUsages",
+			Handler:    _StorageInternalService_GetStorageUsages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "storage/proto/storage_internal.proto",